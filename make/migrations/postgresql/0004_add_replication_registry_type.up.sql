@@ -0,0 +1,6 @@
+/*
+registry_type is the kind of the adaptor which is used to talk with the target registry,
+e.g. "Harbor" or "DockerRegistry"
+*/
+ALTER TABLE replication_target ADD COLUMN registry_type varchar(32) NOT NULL DEFAULT 'Harbor';
+UPDATE replication_target SET registry_type = 'DockerRegistry' WHERE target_type = 1;
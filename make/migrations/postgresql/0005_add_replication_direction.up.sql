@@ -0,0 +1,5 @@
+/*
+direction indicates whether the images are pushed from the local projects
+to the targets or pulled from the targets into the local projects
+*/
+ALTER TABLE replication_policy ADD COLUMN direction varchar(16) NOT NULL DEFAULT 'push';
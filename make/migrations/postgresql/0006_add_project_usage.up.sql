@@ -0,0 +1,13 @@
+/*
+project_usage caches the resources consumed by each project, it is recalculated
+from the registry storage and used to enforce the quotas of projects
+*/
+create table project_usage (
+ project_id int NOT NULL,
+ storage bigint NOT NULL DEFAULT 0,
+ repository_count int NOT NULL DEFAULT 0,
+ tag_count int NOT NULL DEFAULT 0,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (project_id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
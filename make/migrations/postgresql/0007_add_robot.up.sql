@@ -0,0 +1,26 @@
+/*
+robot accounts are scoped to one project, they authenticate with the
+generated token which is stored as salted hash, the name is only unique
+in the project
+*/
+create table robot (
+ id SERIAL NOT NULL,
+ name varchar(255) NOT NULL,
+ description varchar(1024),
+ project_id int NOT NULL,
+ token varchar(40) NOT NULL,
+ salt varchar(40) NOT NULL,
+/*
+comma separated actions the robot can perform, e.g. "pull,push"
+*/
+ access varchar(64) NOT NULL,
+ disabled boolean DEFAULT false NOT NULL,
+ expires_at timestamp NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_robot UNIQUE (name, project_id)
+);
+
+CREATE TRIGGER robot_update_time_at_modtime BEFORE UPDATE ON robot FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,39 @@
+/*
+retention_policy holds the tag retention rules of a project, the rules are
+stored as JSON and the schedule is the same as the one of GC
+*/
+create table retention_policy (
+ id SERIAL NOT NULL,
+ project_id int NOT NULL,
+ rules text NOT NULL,
+ schedule varchar(256),
+ job_uuid varchar(64),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_retention_project UNIQUE (project_id)
+);
+
+CREATE TRIGGER retention_policy_update_time_at_modtime BEFORE UPDATE ON retention_policy FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+/*
+retention_execution records each run of the retention policy, the report
+contains the deleted tags and digests
+*/
+create table retention_execution (
+ id SERIAL NOT NULL,
+ policy_id int NOT NULL,
+ job_uuid varchar(64),
+ dry_run boolean DEFAULT false NOT NULL,
+ status varchar(64) NOT NULL,
+ report text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (policy_id) REFERENCES retention_policy(id)
+);
+
+CREATE INDEX retention_execution_uuid ON retention_execution (job_uuid);
+
+CREATE TRIGGER retention_execution_update_time_at_modtime BEFORE UPDATE ON retention_execution FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,18 @@
+/*
+immutable_tag_rule protects the tags of a project from being overwritten
+or deleted, the repository pattern is matched against the repository name
+without the project name
+*/
+create table immutable_tag_rule (
+ id SERIAL NOT NULL,
+ project_id int NOT NULL,
+ repo_pattern varchar(256) NOT NULL,
+ tag_pattern varchar(256) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_immutable_tag_rule UNIQUE (project_id, repo_pattern, tag_pattern)
+);
+
+CREATE TRIGGER immutable_tag_rule_update_time_at_modtime BEFORE UPDATE ON immutable_tag_rule FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,42 @@
+/*
+webhook_policy sends the events of the project to the target URL, the
+event types are separated by comma and the secret is encrypted
+*/
+create table webhook_policy (
+ id SERIAL NOT NULL,
+ name varchar(256) NOT NULL,
+ project_id int NOT NULL,
+ target_url varchar(1024) NOT NULL,
+ secret varchar(256),
+ skip_cert_verify boolean DEFAULT false NOT NULL,
+ event_types varchar(512) NOT NULL,
+ enabled boolean DEFAULT true NOT NULL,
+ creator varchar(256),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ CONSTRAINT unique_webhook_policy UNIQUE (project_id, name)
+);
+
+CREATE TRIGGER webhook_policy_update_time_at_modtime BEFORE UPDATE ON webhook_policy FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+/*
+webhook_execution records each delivery of the event
+*/
+create table webhook_execution (
+ id SERIAL NOT NULL,
+ policy_id int NOT NULL,
+ event_type varchar(64) NOT NULL,
+ payload text NOT NULL,
+ job_uuid varchar(64),
+ status varchar(64) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (policy_id) REFERENCES webhook_policy(id)
+);
+
+CREATE INDEX webhook_execution_policy ON webhook_execution (policy_id);
+
+CREATE TRIGGER webhook_execution_update_time_at_modtime BEFORE UPDATE ON webhook_execution FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,19 @@
+/*
+oidc_user links the user onboarded from the OIDC provider to the subject and
+issuer of the ID token, the CLI secret and the token are encrypted
+*/
+create table oidc_user (
+ id SERIAL NOT NULL,
+ user_id int NOT NULL,
+ subiss varchar(512) NOT NULL,
+ secret varchar(255) NOT NULL,
+ token text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ FOREIGN KEY (user_id) REFERENCES harbor_user(user_id),
+ CONSTRAINT unique_oidc_user UNIQUE (user_id),
+ CONSTRAINT unique_oidc_subiss UNIQUE (subiss)
+);
+
+CREATE TRIGGER oidc_user_update_time_at_modtime BEFORE UPDATE ON oidc_user FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
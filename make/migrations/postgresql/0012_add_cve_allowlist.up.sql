@@ -0,0 +1,17 @@
+/*
+cve_allowlist holds the CVE IDs ignored by the vulnerability check, the
+allowlist with project_id 0 is the system allowlist which applies to all
+projects, expires_at is a unix timestamp and 0 means never expires
+*/
+create table cve_allowlist (
+ id SERIAL NOT NULL,
+ project_id int NOT NULL,
+ expires_at bigint DEFAULT 0 NOT NULL,
+ items text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_cve_allowlist_project_id UNIQUE (project_id)
+);
+
+CREATE TRIGGER cve_allowlist_update_time_at_modtime BEFORE UPDATE ON cve_allowlist FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,41 @@
+/*
+scanner_registration holds the scanner endpoints registered to Harbor, adapter
+is the implementation used to talk with the endpoint, e.g. "clair" or "generic",
+at most one registration is the default one used by the projects which don't
+choose a scanner
+*/
+create table scanner_registration (
+ id SERIAL NOT NULL,
+ name varchar(255) NOT NULL,
+ description text,
+ url varchar(512) NOT NULL,
+ adapter varchar(64) NOT NULL,
+ is_default boolean DEFAULT false NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_scanner_registration_name UNIQUE (name)
+);
+
+CREATE TRIGGER scanner_registration_update_time_at_modtime BEFORE UPDATE ON scanner_registration FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
+
+/*
+scan_report holds the vulnerability reports of images per scanner, the report
+is the json string of the scanner-neutral report, the img_scan_overview of the
+image keeps the summary of the latest report
+*/
+create table scan_report (
+ id SERIAL NOT NULL,
+ digest varchar(128) NOT NULL,
+ scanner varchar(255) NOT NULL,
+ job_id int NOT NULL,
+ /* 0 indicates none, the higher the number, the more severe the status */
+ severity int NOT NULL default 0,
+ report text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_scan_report UNIQUE (digest, scanner)
+);
+
+CREATE TRIGGER scan_report_update_time_at_modtime BEFORE UPDATE ON scan_report FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
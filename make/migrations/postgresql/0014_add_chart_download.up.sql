@@ -0,0 +1,17 @@
+/*
+chart_download holds the download count of each chart version, the chart
+is the name of the chart without the project name as prefix
+*/
+create table chart_download (
+ id SERIAL NOT NULL,
+ project_id int NOT NULL,
+ chart varchar(255) NOT NULL,
+ version varchar(255) NOT NULL,
+ download_count int NOT NULL default 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_chart_download UNIQUE (project_id, chart, version)
+);
+
+CREATE TRIGGER chart_download_update_time_at_modtime BEFORE UPDATE ON chart_download FOR EACH ROW EXECUTE PROCEDURE update_update_time_at_column();
@@ -0,0 +1,20 @@
+/*
+artifact_blob records the blobs referenced by the manifests pushed to the registry,
+the garbage collection uses it to find out the blobs that are not referenced
+by any manifest anymore and to sum up the sizes of the blobs it deletes
+*/
+create table artifact_blob (
+ id SERIAL NOT NULL,
+ repository_name varchar(255) NOT NULL,
+ digest_af varchar(255) NOT NULL,
+ digest_blob varchar(255) NOT NULL,
+ size bigint NOT NULL default 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_artifact_blob UNIQUE (repository_name, digest_af, digest_blob)
+);
+
+CREATE INDEX artifact_blob_digest_blob ON artifact_blob (digest_blob);
+
+/* job_report holds the report of the admin job, e.g. the result of the garbage collection */
+ALTER TABLE admin_job ADD COLUMN job_report text;
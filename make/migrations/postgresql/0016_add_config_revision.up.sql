@@ -0,0 +1,16 @@
+/*
+config_revision records every change of the system configurations as a revision,
+the diff holds the changed keys and the snapshot holds all the configurations after
+the change, the values of the secret keys are masked in the diff and encrypted in the snapshot
+*/
+create table config_revision (
+ id SERIAL NOT NULL,
+ operator varchar(255) NOT NULL,
+ comment varchar(1024),
+ diff text NOT NULL,
+ snapshot text NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id)
+);
+
+CREATE INDEX config_revision_operator ON config_revision (operator);
@@ -28,6 +28,11 @@ func handleBadRequestError(w http.ResponseWriter, error string) {
 	http.Error(w, error, http.StatusBadRequest)
 }
 
+func handleNotFoundError(w http.ResponseWriter) {
+	http.Error(w, http.StatusText(http.StatusNotFound),
+		http.StatusNotFound)
+}
+
 func handleUnauthorized(w http.ResponseWriter) {
 	http.Error(w, http.StatusText(http.StatusUnauthorized),
 		http.StatusUnauthorized)
@@ -38,7 +38,7 @@ func ListCfgs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// UpdateCfgs updates configurations
+// UpdateCfgs updates configurations, the operator is specified by the query parameter "operator"
 func UpdateCfgs(w http.ResponseWriter, r *http.Request) {
 	b, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -52,7 +52,7 @@ func UpdateCfgs(w http.ResponseWriter, r *http.Request) {
 		handleBadRequestError(w, err.Error())
 		return
 	}
-	if err = systemcfg.CfgStore.Write(m); err != nil {
+	if err = systemcfg.WriteCfgs(m, getOperator(r)); err != nil {
 		log.Errorf("failed to update system configurations: %v", err)
 		handleInternalServerError(w)
 		return
@@ -67,9 +67,25 @@ func ResetCfgs(w http.ResponseWriter, r *http.Request) {
 		handleInternalServerError(w)
 		return
 	}
-	if err := systemcfg.CfgStore.Write(cfgs); err != nil {
+	if err := systemcfg.WriteCfgs(cfgs, getOperator(r)); err != nil {
 		log.Errorf("failed to write system configurations to storage: %v", err)
 		handleInternalServerError(w)
 		return
 	}
 }
+
+// ReencryptCfgs re-encrypts the stored secret configurations with the current key,
+// it should be called after the key is rotated so that the old keys can be removed
+func ReencryptCfgs(w http.ResponseWriter, r *http.Request) {
+	count, err := systemcfg.Reencrypt()
+	if err != nil {
+		log.Errorf("failed to re-encrypt system configurations: %v", err)
+		handleInternalServerError(w)
+		return
+	}
+	log.Infof("%d secret configurations re-encrypted", count)
+	if err = writeJSON(w, map[string]int{"count": count}); err != nil {
+		log.Errorf("failed to write response: %v", err)
+		return
+	}
+}
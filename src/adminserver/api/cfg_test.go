@@ -154,6 +154,46 @@ func TestResetCfgs(t *testing.T) {
 	assert.Equal(t, value, store.cfgs[common.LDAPURL])
 }
 
+type fakeEncryptedCfgStore struct {
+	fakeCfgStore
+	count int
+}
+
+func (f *fakeEncryptedCfgStore) Reencrypt() (int, error) {
+	return f.count, f.err
+}
+
+func TestReencryptCfgs(t *testing.T) {
+	// 500, not supported
+	systemcfg.CfgStore = &fakeCfgStore{}
+	w := httptest.NewRecorder()
+	ReencryptCfgs(w, nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 500
+	systemcfg.CfgStore = &fakeEncryptedCfgStore{
+		fakeCfgStore: fakeCfgStore{
+			err: errors.New("error"),
+		},
+	}
+	w = httptest.NewRecorder()
+	ReencryptCfgs(w, nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 200
+	systemcfg.CfgStore = &fakeEncryptedCfgStore{
+		count: 2,
+	}
+	w = httptest.NewRecorder()
+	ReencryptCfgs(w, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	result, err := parse(w.Body)
+	if err != nil {
+		t.Fatalf("failed to parse response body: %v", err)
+	}
+	assert.Equal(t, float64(2), result["count"])
+}
+
 func parse(reader io.Reader) (map[string]interface{}, error) {
 	b, err := ioutil.ReadAll(reader)
 	if err != nil {
@@ -0,0 +1,206 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/adminserver/systemcfg"
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store"
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/revision"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize int64 = 10
+	maxPageSize     int64 = 100
+)
+
+// ListCfgRevisions lists the revisions of the configurations, the revisions can be
+// filtered by the operator and the changed key
+func ListCfgRevisions(w http.ResponseWriter, r *http.Request) {
+	driver, ok := revisionDriver(w)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	page, err := parseInt64(q.Get("page"), 1)
+	if err != nil || page <= 0 {
+		handleBadRequestError(w, "invalid page: "+q.Get("page"))
+		return
+	}
+	size, err := parseInt64(q.Get("page_size"), defaultPageSize)
+	if err != nil || size <= 0 || size > maxPageSize {
+		handleBadRequestError(w, "invalid page_size: "+q.Get("page_size"))
+		return
+	}
+
+	revisions, total, err := driver.Revisions(&models.ConfigRevisionQuery{
+		Operator: q.Get("operator"),
+		Key:      q.Get("key"),
+		Pagination: models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	})
+	if err != nil {
+		log.Errorf("failed to list the revisions of system configurations: %v", err)
+		handleInternalServerError(w)
+		return
+	}
+	if err = writeJSON(w, &models.ConfigRevisionList{
+		Total:     total,
+		Revisions: revisions,
+	}); err != nil {
+		log.Errorf("failed to write response: %v", err)
+		return
+	}
+}
+
+// GetCfgRevision returns the revision specified by ID
+func GetCfgRevision(w http.ResponseWriter, r *http.Request) {
+	driver, ok := revisionDriver(w)
+	if !ok {
+		return
+	}
+	id, ok := revisionID(w, r)
+	if !ok {
+		return
+	}
+
+	rev, err := driver.Revision(id)
+	if err != nil {
+		log.Errorf("failed to get the revision %d of system configurations: %v", id, err)
+		handleInternalServerError(w)
+		return
+	}
+	if rev == nil {
+		handleNotFoundError(w)
+		return
+	}
+	if err = writeJSON(w, rev); err != nil {
+		log.Errorf("failed to write response: %v", err)
+		return
+	}
+}
+
+// GetCfgRevisionSnapshot returns the configurations recorded by the revision specified by ID
+func GetCfgRevisionSnapshot(w http.ResponseWriter, r *http.Request) {
+	driver, ok := revisionDriver(w)
+	if !ok {
+		return
+	}
+	id, ok := revisionID(w, r)
+	if !ok {
+		return
+	}
+
+	snapshot, err := driver.Snapshot(id)
+	if err != nil {
+		log.Errorf("failed to get the snapshot of revision %d of system configurations: %v", id, err)
+		handleInternalServerError(w)
+		return
+	}
+	if snapshot == nil {
+		handleNotFoundError(w)
+		return
+	}
+	if err = writeJSON(w, snapshot); err != nil {
+		log.Errorf("failed to write response: %v", err)
+		return
+	}
+}
+
+// RollbackCfgs restores the configurations recorded by the revision specified by ID,
+// only the keys in the request body are restored
+func RollbackCfgs(w http.ResponseWriter, r *http.Request) {
+	driver, ok := revisionDriver(w)
+	if !ok {
+		return
+	}
+	id, ok := revisionID(w, r)
+	if !ok {
+		return
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Errorf("failed to read request body: %v", err)
+		handleInternalServerError(w)
+		return
+	}
+	keys := []string{}
+	if err = json.Unmarshal(b, &keys); err != nil {
+		handleBadRequestError(w, err.Error())
+		return
+	}
+	if len(keys) == 0 {
+		handleBadRequestError(w, "no keys to restore")
+		return
+	}
+
+	if err = driver.Rollback(id, getOperator(r), keys); err != nil {
+		if err == revision.ErrRevisionNotFound {
+			handleNotFoundError(w)
+			return
+		}
+		log.Errorf("failed to rollback system configurations to revision %d: %v", id, err)
+		handleInternalServerError(w)
+		return
+	}
+}
+
+// revisionDriver returns the storage driver which records the revisions,
+// a 501 error is written if the driver doesn't support
+func revisionDriver(w http.ResponseWriter) (store.RevisionDriver, bool) {
+	driver, ok := systemcfg.CfgStore.(store.RevisionDriver)
+	if !ok {
+		http.Error(w, "the revisions of configurations are not supported by the storage driver",
+			http.StatusNotImplemented)
+		return nil, false
+	}
+	return driver, true
+}
+
+func revisionID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	str := mux.Vars(r)["id"]
+	id, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || id <= 0 {
+		handleBadRequestError(w, "invalid revision ID: "+str)
+		return 0, false
+	}
+	return id, true
+}
+
+// getOperator returns the operator specified in the query of the request
+func getOperator(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+	return r.URL.Query().Get("operator")
+}
+
+func parseInt64(str string, def int64) (int64, error) {
+	if len(str) == 0 {
+		return def, nil
+	}
+	return strconv.ParseInt(str, 10, 64)
+}
@@ -0,0 +1,209 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goharbor/harbor/src/adminserver/systemcfg"
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/revision"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRevisionStore struct {
+	fakeCfgStore
+	query      *models.ConfigRevisionQuery
+	revisions  []*models.ConfigRevision
+	operator   string
+	rollbackID int64
+	keys       []string
+}
+
+func (f *fakeRevisionStore) WriteBy(cfgs map[string]interface{}, operator, comment string) error {
+	f.operator = operator
+	return f.Write(cfgs)
+}
+
+func (f *fakeRevisionStore) Revisions(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error) {
+	f.query = query
+	return f.revisions, int64(len(f.revisions)), nil
+}
+
+func (f *fakeRevisionStore) Revision(id int64) (*models.ConfigRevision, error) {
+	for _, rev := range f.revisions {
+		if rev.ID == id {
+			return rev, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeRevisionStore) Snapshot(id int64) (map[string]interface{}, error) {
+	rev, _ := f.Revision(id)
+	if rev == nil {
+		return nil, nil
+	}
+	return map[string]interface{}{"auth_mode": "db_auth"}, nil
+}
+
+func (f *fakeRevisionStore) Rollback(id int64, operator string, keys []string) error {
+	rev, _ := f.Revision(id)
+	if rev == nil {
+		return revision.ErrRevisionNotFound
+	}
+	f.rollbackID = id
+	f.operator = operator
+	f.keys = keys
+	return nil
+}
+
+func serve(method, url, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/configurations", UpdateCfgs).Methods("PUT")
+	router.HandleFunc("/api/configurations/revisions", ListCfgRevisions).Methods("GET")
+	router.HandleFunc("/api/configurations/revisions/{id:[0-9]+}", GetCfgRevision).Methods("GET")
+	router.HandleFunc("/api/configurations/revisions/{id:[0-9]+}/snapshot", GetCfgRevisionSnapshot).Methods("GET")
+	router.HandleFunc("/api/configurations/revisions/{id:[0-9]+}/rollback", RollbackCfgs).Methods("POST")
+
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCfgRevisionsNotSupported(t *testing.T) {
+	systemcfg.CfgStore = &fakeCfgStore{}
+	w := serve(http.MethodGet, "/api/configurations/revisions", "")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestListCfgRevisions(t *testing.T) {
+	store := &fakeRevisionStore{
+		revisions: []*models.ConfigRevision{
+			{
+				ID:       1,
+				Operator: "admin",
+				Changes: []*models.ConfigChange{
+					{Key: "auth_mode", Old: "db_auth", New: "ldap_auth"},
+				},
+			},
+		},
+	}
+	systemcfg.CfgStore = store
+
+	// 400
+	w := serve(http.MethodGet, "/api/configurations/revisions?page=a", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(http.MethodGet, "/api/configurations/revisions?page_size=1000", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 200
+	w = serve(http.MethodGet, "/api/configurations/revisions?operator=admin&key=auth_mode&page=2&page_size=5", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &models.ConfigRevisionQuery{
+		Operator: "admin",
+		Key:      "auth_mode",
+		Pagination: models.Pagination{
+			Page: 2,
+			Size: 5,
+		},
+	}, store.query)
+	list := &models.ConfigRevisionList{}
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), list))
+	assert.Equal(t, int64(1), list.Total)
+	require.Equal(t, 1, len(list.Revisions))
+	assert.Equal(t, "ldap_auth", list.Revisions[0].Changes[0].New)
+}
+
+func TestGetCfgRevision(t *testing.T) {
+	systemcfg.CfgStore = &fakeRevisionStore{
+		revisions: []*models.ConfigRevision{
+			{ID: 1, Operator: "admin"},
+		},
+	}
+
+	// 404
+	w := serve(http.MethodGet, "/api/configurations/revisions/2", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 200
+	w = serve(http.MethodGet, "/api/configurations/revisions/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	rev := &models.ConfigRevision{}
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), rev))
+	assert.Equal(t, "admin", rev.Operator)
+}
+
+func TestRollbackCfgs(t *testing.T) {
+	store := &fakeRevisionStore{
+		revisions: []*models.ConfigRevision{
+			{ID: 1, Operator: "admin"},
+		},
+	}
+	systemcfg.CfgStore = store
+
+	// 400
+	w := serve(http.MethodPost, "/api/configurations/revisions/1/rollback?operator=auditor", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(http.MethodPost, "/api/configurations/revisions/1/rollback?operator=auditor", `[]`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 404
+	w = serve(http.MethodPost, "/api/configurations/revisions/2/rollback?operator=auditor", `["auth_mode"]`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 200
+	w = serve(http.MethodPost, "/api/configurations/revisions/1/rollback?operator=auditor", `["auth_mode"]`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(1), store.rollbackID)
+	assert.Equal(t, "auditor", store.operator)
+	assert.Equal(t, []string{"auth_mode"}, store.keys)
+}
+
+func TestGetCfgRevisionSnapshot(t *testing.T) {
+	systemcfg.CfgStore = &fakeRevisionStore{
+		revisions: []*models.ConfigRevision{
+			{ID: 1, Operator: "admin"},
+		},
+	}
+
+	// 404
+	w := serve(http.MethodGet, "/api/configurations/revisions/2/snapshot", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 200
+	w = serve(http.MethodGet, "/api/configurations/revisions/1/snapshot", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	snapshot := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	assert.Equal(t, "db_auth", snapshot["auth_mode"])
+}
+
+func TestUpdateCfgsWithOperator(t *testing.T) {
+	store := &fakeRevisionStore{}
+	systemcfg.CfgStore = store
+
+	w := serve(http.MethodPut, "/api/configurations?operator=admin", `{"auth_mode":"ldap_auth"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin", store.operator)
+	assert.Equal(t, "ldap_auth", store.cfgs["auth_mode"])
+}
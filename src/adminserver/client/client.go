@@ -15,10 +15,14 @@
 package client
 
 import (
+	"fmt"
+	neturl "net/url"
+	"strconv"
 	"strings"
 
 	"github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
 )
@@ -29,10 +33,18 @@ type Client interface {
 	Ping() error
 	// GetCfgs returns system configurations
 	GetCfgs() (map[string]interface{}, error)
-	// UpdateCfgs updates system configurations
-	UpdateCfgs(map[string]interface{}) error
+	// UpdateCfgs updates system configurations, the changes are recorded with the operator
+	UpdateCfgs(cfgs map[string]interface{}, operator string) error
 	// ResetCfgs resets system configuratoins form environment variables
-	ResetCfgs() error
+	ResetCfgs(operator string) error
+	// ListCfgRevisions lists the revisions of system configurations
+	ListCfgRevisions(query *models.ConfigRevisionQuery) (*models.ConfigRevisionList, error)
+	// GetCfgRevision returns the revision of system configurations specified by ID
+	GetCfgRevision(id int64) (*models.ConfigRevision, error)
+	// GetCfgRevisionSnapshot returns system configurations recorded by the revision specified by ID
+	GetCfgRevisionSnapshot(id int64) (map[string]interface{}, error)
+	// RollbackCfgs restores the keys of system configurations to the revision specified by ID
+	RollbackCfgs(id int64, operator string, keys []string) error
 	// Capacity returns the capacity of image storage
 	Capacity() (*imagestorage.Capacity, error)
 }
@@ -83,17 +95,79 @@ func (c *client) GetCfgs() (map[string]interface{}, error) {
 }
 
 // UpdateCfgs ...
-func (c *client) UpdateCfgs(cfgs map[string]interface{}) error {
-	url := c.baseURL + "/api/configurations"
+func (c *client) UpdateCfgs(cfgs map[string]interface{}, operator string) error {
+	url := c.baseURL + "/api/configurations" + operatorQuery(operator)
 	return c.client.Put(url, cfgs)
 }
 
 // ResetCfgs ...
-func (c *client) ResetCfgs() error {
-	url := c.baseURL + "/api/configurations/reset"
+func (c *client) ResetCfgs(operator string) error {
+	url := c.baseURL + "/api/configurations/reset" + operatorQuery(operator)
 	return c.client.Post(url)
 }
 
+// ListCfgRevisions ...
+func (c *client) ListCfgRevisions(query *models.ConfigRevisionQuery) (*models.ConfigRevisionList, error) {
+	url := c.baseURL + "/api/configurations/revisions"
+	if query != nil {
+		params := neturl.Values{}
+		if len(query.Operator) > 0 {
+			params.Set("operator", query.Operator)
+		}
+		if len(query.Key) > 0 {
+			params.Set("key", query.Key)
+		}
+		if query.Page > 0 {
+			params.Set("page", strconv.FormatInt(query.Page, 10))
+		}
+		if query.Size > 0 {
+			params.Set("page_size", strconv.FormatInt(query.Size, 10))
+		}
+		if len(params) > 0 {
+			url += "?" + params.Encode()
+		}
+	}
+	list := &models.ConfigRevisionList{}
+	if err := c.client.Get(url, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetCfgRevision ...
+func (c *client) GetCfgRevision(id int64) (*models.ConfigRevision, error) {
+	url := fmt.Sprintf("%s/api/configurations/revisions/%d", c.baseURL, id)
+	revision := &models.ConfigRevision{}
+	if err := c.client.Get(url, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// GetCfgRevisionSnapshot ...
+func (c *client) GetCfgRevisionSnapshot(id int64) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/api/configurations/revisions/%d/snapshot", c.baseURL, id)
+	snapshot := map[string]interface{}{}
+	if err := c.client.Get(url, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RollbackCfgs ...
+func (c *client) RollbackCfgs(id int64, operator string, keys []string) error {
+	url := fmt.Sprintf("%s/api/configurations/revisions/%d/rollback%s", c.baseURL, id, operatorQuery(operator))
+	return c.client.Post(url, keys)
+}
+
+// the operator is passed by the query as the http client can't set the headers
+func operatorQuery(operator string) string {
+	if len(operator) == 0 {
+		return ""
+	}
+	return "?operator=" + neturl.QueryEscape(operator)
+}
+
 // Capacity ...
 func (c *client) Capacity() (*imagestorage.Capacity, error) {
 	url := c.baseURL + "/api/systeminfo/capacity"
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -57,15 +58,47 @@ func TestUpdateCfgs(t *testing.T) {
 	cfgs := map[string]interface{}{
 		common.AUTHMode: common.LDAPAuth,
 	}
-	err := c.UpdateCfgs(cfgs)
+	err := c.UpdateCfgs(cfgs, "admin")
 	if !assert.Nil(t, err, "unexpected error") {
 		return
 	}
 }
 
 func TestResetCfgs(t *testing.T) {
-	err := c.ResetCfgs()
+	err := c.ResetCfgs("admin")
 	if !assert.Nil(t, err, "unexpected error") {
 		return
 	}
 }
+
+func TestListCfgRevisions(t *testing.T) {
+	list, err := c.ListCfgRevisions(&models.ConfigRevisionQuery{
+		Key: common.AUTHMode,
+	})
+	if !assert.Nil(t, err, "unexpected error") {
+		return
+	}
+	assert.Equal(t, int64(1), list.Total)
+	assert.Equal(t, common.AUTHMode, list.Revisions[0].Changes[0].Key)
+}
+
+func TestGetCfgRevision(t *testing.T) {
+	revision, err := c.GetCfgRevision(1)
+	if !assert.Nil(t, err, "unexpected error") {
+		return
+	}
+	assert.Equal(t, int64(1), revision.ID)
+}
+
+func TestGetCfgRevisionSnapshot(t *testing.T) {
+	snapshot, err := c.GetCfgRevisionSnapshot(1)
+	if !assert.Nil(t, err, "unexpected error") {
+		return
+	}
+	assert.Equal(t, common.DBAuth, snapshot[common.AUTHMode])
+}
+
+func TestRollbackCfgs(t *testing.T) {
+	err := c.RollbackCfgs(1, "admin", []string{common.AUTHMode})
+	assert.Nil(t, err, "unexpected error")
+}
@@ -26,6 +26,11 @@ func newRouter() http.Handler {
 	r.HandleFunc("/api/configurations", api.UpdateCfgs).Methods("PUT")
 	r.HandleFunc("/api/configs", api.ListCfgs).Methods("GET")
 	r.HandleFunc("/api/configurations/reset", api.ResetCfgs).Methods("POST")
+	r.HandleFunc("/api/configurations/reencrypt", api.ReencryptCfgs).Methods("POST")
+	r.HandleFunc("/api/configurations/revisions", api.ListCfgRevisions).Methods("GET")
+	r.HandleFunc("/api/configurations/revisions/{id:[0-9]+}", api.GetCfgRevision).Methods("GET")
+	r.HandleFunc("/api/configurations/revisions/{id:[0-9]+}/snapshot", api.GetCfgRevisionSnapshot).Methods("GET")
+	r.HandleFunc("/api/configurations/revisions/{id:[0-9]+}/rollback", api.RollbackCfgs).Methods("POST")
 	r.HandleFunc("/api/ping", api.Ping).Methods("GET")
 	return r
 }
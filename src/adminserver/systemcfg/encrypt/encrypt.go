@@ -15,10 +15,21 @@
 package encrypt
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	comcfg "github.com/goharbor/harbor/src/common/config"
 	"github.com/goharbor/harbor/src/common/utils"
 )
 
+const (
+	// the ID of the key is prefixed to the ciphertext if the key provider is versioned,
+	// e.g. <enc-key:2><enc-v1>ciphertext
+	keyIDHeaderPrefix = "<enc-key:"
+	keyIDHeaderSuffix = ">"
+)
+
 // Encryptor encrypts or decrypts a strings
 type Encryptor interface {
 	// Encrypt encrypts plaintext
@@ -41,20 +52,54 @@ func NewAESEncryptor(keyProvider comcfg.KeyProvider,
 	}
 }
 
-// Encrypt ...
+// Encrypt encrypts the plaintext with the current key, the ID of the key
+// is prefixed to the ciphertext if the key provider is versioned
 func (a *AESEncryptor) Encrypt(plaintext string) (string, error) {
 	key, err := a.keyProvider.Get(a.keyParams)
 	if err != nil {
 		return "", err
 	}
-	return utils.ReversibleEncrypt(plaintext, key)
+	ciphertext, err := utils.ReversibleEncrypt(plaintext, key)
+	if err != nil {
+		return "", err
+	}
+	if p, ok := a.keyProvider.(comcfg.VersionedKeyProvider); ok && len(p.CurrentID()) > 0 {
+		ciphertext = keyIDHeaderPrefix + p.CurrentID() + keyIDHeaderSuffix + ciphertext
+	}
+	return ciphertext, nil
 }
 
-// Decrypt ...
+// Decrypt decrypts the ciphertext with the key specified by the ID prefixed to
+// the ciphertext, the legacy key is used if no ID is prefixed
 func (a *AESEncryptor) Decrypt(ciphertext string) (string, error) {
-	key, err := a.keyProvider.Get(a.keyParams)
+	id, ciphertext, err := parseKeyID(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	var key string
+	if p, ok := a.keyProvider.(comcfg.VersionedKeyProvider); ok {
+		key, err = p.GetByID(id)
+	} else if len(id) > 0 {
+		return "", fmt.Errorf("the ciphertext is encrypted with key %s, but the key provider isn't versioned", id)
+	} else {
+		key, err = a.keyProvider.Get(a.keyParams)
+	}
 	if err != nil {
 		return "", err
 	}
 	return utils.ReversibleDecrypt(ciphertext, key)
 }
+
+// parseKeyID splits the ciphertext into the key ID and the ciphertext encrypted by utils
+func parseKeyID(ciphertext string) (string, string, error) {
+	if !strings.HasPrefix(ciphertext, keyIDHeaderPrefix) {
+		return "", ciphertext, nil
+	}
+	str := ciphertext[len(keyIDHeaderPrefix):]
+	i := strings.Index(str, keyIDHeaderSuffix)
+	if i <= 0 {
+		return "", "", errors.New("invalid key ID header of the ciphertext")
+	}
+	return str[:i], str[i+len(keyIDHeaderSuffix):], nil
+}
@@ -16,10 +16,13 @@ package encrypt
 
 import (
 	"errors"
+	"os"
+	"strings"
 	"testing"
 
 	comcfg "github.com/goharbor/harbor/src/common/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type fakeKeyProvider struct {
@@ -90,3 +93,48 @@ func TestDecrypt(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyRotation(t *testing.T) {
+	plaintext := "text"
+	legacy := &fakeKeyProvider{"1234567890123456", nil}
+
+	// encrypted with the legacy key
+	legacyCiphertext, err := NewAESEncryptor(legacy, nil).Encrypt(plaintext)
+	require.Nil(t, err)
+	assert.False(t, strings.HasPrefix(legacyCiphertext, keyIDHeaderPrefix))
+
+	os.Setenv("TEST_ENCRYPTION_KEY_1", "1111111111111111")
+	os.Setenv("TEST_ENCRYPTION_KEY_2", "2222222222222222")
+	defer os.Unsetenv("TEST_ENCRYPTION_KEY_1")
+	defer os.Unsetenv("TEST_ENCRYPTION_KEY_2")
+
+	// encrypted with key 1
+	encryptor := NewAESEncryptor(comcfg.NewEnvKeyProvider("TEST_ENCRYPTION_KEY_", "1", legacy), nil)
+	ciphertext1, err := encryptor.Encrypt(plaintext)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext1, "<enc-key:1><enc-v1>"))
+
+	// rotated to key 2, the values encrypted with the old keys can still be decrypted
+	encryptor = NewAESEncryptor(comcfg.NewEnvKeyProvider("TEST_ENCRYPTION_KEY_", "2", legacy), nil)
+	ciphertext2, err := encryptor.Encrypt(plaintext)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext2, "<enc-key:2><enc-v1>"))
+	for _, ciphertext := range []string{legacyCiphertext, ciphertext1, ciphertext2} {
+		str, err := encryptor.Decrypt(ciphertext)
+		require.Nil(t, err)
+		assert.Equal(t, plaintext, str)
+	}
+
+	// the key is removed
+	os.Unsetenv("TEST_ENCRYPTION_KEY_1")
+	_, err = encryptor.Decrypt(ciphertext1)
+	assert.NotNil(t, err)
+
+	// the key provider isn't versioned
+	_, err = NewAESEncryptor(legacy, nil).Decrypt(ciphertext2)
+	assert.NotNil(t, err)
+
+	// invalid header
+	_, err = encryptor.Decrypt("<enc-key:2")
+	assert.NotNil(t, err)
+}
@@ -14,6 +14,10 @@
 
 package store
 
+import (
+	"github.com/goharbor/harbor/src/common/models"
+)
+
 // Driver defines methods that a configuration store driver must implement
 type Driver interface {
 	// Name returns a human-readable name of the driver
@@ -24,3 +28,26 @@ type Driver interface {
 	// part of all
 	Write(map[string]interface{}) error
 }
+
+// RevisionDriver is a Driver which records every change of the configurations as a revision
+type RevisionDriver interface {
+	Driver
+	// WriteBy writes the configurations changed by the operator and records the revision
+	WriteBy(cfgs map[string]interface{}, operator, comment string) error
+	// Revisions lists the revisions according to the query, the total count is returned as well
+	Revisions(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error)
+	// Revision returns the revision specified by ID, nil is returned if it doesn't exist
+	Revision(id int64) (*models.ConfigRevision, error)
+	// Snapshot returns the configurations recorded by the revision with the values of the
+	// secret keys decrypted, nil is returned if the revision doesn't exist
+	Snapshot(id int64) (map[string]interface{}, error)
+	// Rollback restores the keys of the configurations recorded by the revision
+	Rollback(id int64, operator string, keys []string) error
+}
+
+// Reencrypter is implemented by the drivers storing the encrypted values, it re-encrypts
+// the values with the current key so that the retired keys can be removed
+type Reencrypter interface {
+	// Reencrypt returns the count of the re-encrypted values
+	Reencrypt() (int, error)
+}
@@ -95,3 +95,36 @@ func (c *cfgStore) Write(m map[string]interface{}) error {
 	}
 	return c.store.Write(m)
 }
+
+// Reencrypt decrypts the values with the keys they were encrypted with and
+// encrypts them with the current key
+func (c *cfgStore) Reencrypt() (int, error) {
+	m, err := c.store.Read()
+	if err != nil {
+		return 0, err
+	}
+
+	cfgs := map[string]interface{}{}
+	for _, key := range c.keys {
+		str, ok := m[key].(string)
+		if !ok {
+			continue
+		}
+		text, err := c.encryptor.Decrypt(str)
+		if err != nil {
+			return 0, err
+		}
+		ciphertext, err := c.encryptor.Encrypt(text)
+		if err != nil {
+			return 0, err
+		}
+		cfgs[key] = ciphertext
+	}
+	if len(cfgs) == 0 {
+		return 0, nil
+	}
+	if err = c.store.Write(cfgs); err != nil {
+		return 0, err
+	}
+	return len(cfgs), nil
+}
@@ -17,6 +17,7 @@ package encrypt
 import (
 	"testing"
 
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,3 +80,19 @@ func TestWrite(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "encryptedvalue", store.cfgs["key"])
 }
+
+func TestReencrypt(t *testing.T) {
+	keys := []string{"key", "int_key", "absent_key"}
+	fakeStore := &fakeCfgStore{
+		cfgs: map[string]interface{}{
+			"key":     "value",
+			"int_key": 1,
+		},
+	}
+	driver := NewCfgStore(&fakeEncryptor{}, keys, fakeStore)
+
+	n, err := driver.(store.Reencrypter).Reencrypt()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "encrypteddecryptedvalue", fakeStore.cfgs["key"])
+}
@@ -0,0 +1,371 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/encrypt"
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	name = "revision"
+
+	// the page size of listing the revisions when re-encrypting the snapshots
+	reencryptPageSize = 100
+
+	// SystemOperator is the operator of the changes not made by users,
+	// e.g. loading the configurations from environment variables
+	SystemOperator = "system"
+)
+
+// ErrRevisionNotFound is returned when rolling back to a revision which doesn't exist
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Recorder persists the revisions
+type Recorder interface {
+	// Add adds the revision and returns its ID
+	Add(revision *models.ConfigRevision) (int64, error)
+	// Get returns the revision specified by ID, nil is returned if it doesn't exist
+	Get(id int64) (*models.ConfigRevision, error)
+	// List lists the revisions according to the query and returns the total count
+	List(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error)
+	// UpdateSnapshot updates the snapshot of the revision
+	UpdateSnapshot(id int64, snapshot string) error
+}
+
+type cfgStore struct {
+	// attrs need to be masked in the diffs and encrypted in the snapshots
+	keys      []string
+	encryptor encrypt.Encryptor
+	store     store.Driver
+	recorder  Recorder
+	lock      sync.Mutex
+}
+
+// NewCfgStore returns an instance of cfgStore which records the changes of the
+// configurations stored in the store as revisions. The store must return the
+// plaintext of the keys, the recorder persists the revisions in database if it is nil
+func NewCfgStore(encryptor encrypt.Encryptor, keys []string,
+	store store.Driver, recorder Recorder) store.RevisionDriver {
+	if recorder == nil {
+		recorder = &dbRecorder{}
+	}
+	return &cfgStore{
+		keys:      keys,
+		encryptor: encryptor,
+		store:     store,
+		recorder:  recorder,
+	}
+}
+
+func (c *cfgStore) Name() string {
+	return name
+}
+
+func (c *cfgStore) Read() (map[string]interface{}, error) {
+	return c.store.Read()
+}
+
+// Write writes the configurations changed by the system
+func (c *cfgStore) Write(cfgs map[string]interface{}) error {
+	return c.WriteBy(cfgs, SystemOperator, "")
+}
+
+func (c *cfgStore) WriteBy(cfgs map[string]interface{}, operator, comment string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	current, err := c.store.Read()
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		current = map[string]interface{}{}
+	}
+
+	changes, err := c.diff(current, cfgs)
+	if err != nil {
+		return err
+	}
+	snapshot := map[string]interface{}{}
+	for k, v := range current {
+		snapshot[k] = v
+	}
+	for k, v := range cfgs {
+		snapshot[k] = v
+	}
+	// the snapshot is encoded before writing as the store may encrypt the values in place
+	data, err := c.encode(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err = c.store.Write(cfgs); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if len(operator) == 0 {
+		operator = SystemOperator
+	}
+	id, err := c.recorder.Add(&models.ConfigRevision{
+		Operator: operator,
+		Comment:  comment,
+		Changes:  changes,
+		Snapshot: data,
+	})
+	if err != nil {
+		log.Errorf("failed to record the revision of the configurations changed by %s: %v", operator, err)
+		return err
+	}
+	log.Infof("revision %d of the configurations recorded, %d keys changed by %s", id, len(changes), operator)
+	return nil
+}
+
+func (c *cfgStore) Revisions(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error) {
+	return c.recorder.List(query)
+}
+
+func (c *cfgStore) Revision(id int64) (*models.ConfigRevision, error) {
+	return c.recorder.Get(id)
+}
+
+func (c *cfgStore) Snapshot(id int64) (map[string]interface{}, error) {
+	revision, err := c.recorder.Get(id)
+	if err != nil || revision == nil {
+		return nil, err
+	}
+
+	cfgs := map[string]interface{}{}
+	if err = json.Unmarshal([]byte(revision.Snapshot), &cfgs); err != nil {
+		return nil, err
+	}
+	for _, key := range c.keys {
+		str, ok := cfgs[key].(string)
+		if !ok {
+			continue
+		}
+		text, err := c.encryptor.Decrypt(str)
+		if err != nil {
+			return nil, err
+		}
+		cfgs[key] = text
+	}
+	return cfgs, nil
+}
+
+// Rollback restores only the specified keys, so the keys which can't be changed
+// by users, e.g. the database settings, are not overwritten by the stale values
+func (c *cfgStore) Rollback(id int64, operator string, keys []string) error {
+	snapshot, err := c.Snapshot(id)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return ErrRevisionNotFound
+	}
+
+	cfgs := map[string]interface{}{}
+	for _, key := range keys {
+		if v, ok := snapshot[key]; ok {
+			cfgs[key] = v
+		}
+	}
+	return c.WriteBy(cfgs, operator, fmt.Sprintf("rollback to revision %d", id))
+}
+
+// Reencrypt re-encrypts the values in the wrapped store and the secrets in the
+// snapshots of the revisions with the current key
+func (c *cfgStore) Reencrypt() (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	count := 0
+	if r, ok := c.store.(store.Reencrypter); ok {
+		n, err := r.Reencrypt()
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+
+	query := &models.ConfigRevisionQuery{
+		Pagination: models.Pagination{
+			Page: 1,
+			Size: reencryptPageSize,
+		},
+	}
+	for {
+		revisions, _, err := c.recorder.List(query)
+		if err != nil {
+			return count, err
+		}
+		for _, revision := range revisions {
+			n, err := c.reencryptSnapshot(revision)
+			if err != nil {
+				return count, fmt.Errorf("failed to re-encrypt the snapshot of revision %d: %v", revision.ID, err)
+			}
+			count += n
+		}
+		if len(revisions) < reencryptPageSize {
+			break
+		}
+		query.Page++
+	}
+	return count, nil
+}
+
+func (c *cfgStore) reencryptSnapshot(revision *models.ConfigRevision) (int, error) {
+	cfgs := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(revision.Snapshot), &cfgs); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, key := range c.keys {
+		str, ok := cfgs[key].(string)
+		if !ok {
+			continue
+		}
+		text, err := c.encryptor.Decrypt(str)
+		if err != nil {
+			return 0, err
+		}
+		cfgs[key] = text
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	data, err := c.encode(cfgs)
+	if err != nil {
+		return 0, err
+	}
+	if err = c.recorder.UpdateSnapshot(revision.ID, data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// diff returns the changes of the keys in cfgs, the values of the secret keys are omitted
+func (c *cfgStore) diff(current, cfgs map[string]interface{}) ([]*models.ConfigChange, error) {
+	secrets := map[string]bool{}
+	for _, key := range c.keys {
+		secrets[key] = true
+	}
+
+	keys := []string{}
+	for k := range cfgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	changes := []*models.ConfigChange{}
+	for _, key := range keys {
+		old, exist := current[key]
+		if exist {
+			same, err := equal(old, cfgs[key])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+
+		change := &models.ConfigChange{
+			Key: key,
+		}
+		if secrets[key] {
+			change.Secret = true
+		} else {
+			change.Old = old
+			change.New = cfgs[key]
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// encode encodes the configurations with the values of the secret keys encrypted
+func (c *cfgStore) encode(cfgs map[string]interface{}) (string, error) {
+	for _, key := range c.keys {
+		str, ok := cfgs[key].(string)
+		if !ok {
+			continue
+		}
+		ciphertext, err := c.encryptor.Encrypt(str)
+		if err != nil {
+			return "", err
+		}
+		cfgs[key] = ciphertext
+	}
+	data, err := json.Marshal(cfgs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// equal compares the values in JSON as the numbers read from the stores are float64
+// while the ones loaded from environment variables are int
+func equal(a, b interface{}) (bool, error) {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(x) == string(y), nil
+}
+
+// dbRecorder persists the revisions in database
+type dbRecorder struct{}
+
+func (d *dbRecorder) Add(revision *models.ConfigRevision) (int64, error) {
+	return dao.AddConfigRevision(revision)
+}
+
+func (d *dbRecorder) Get(id int64) (*models.ConfigRevision, error) {
+	return dao.GetConfigRevision(id)
+}
+
+func (d *dbRecorder) UpdateSnapshot(id int64, snapshot string) error {
+	return dao.UpdateConfigRevisionSnapshot(id, snapshot)
+}
+
+func (d *dbRecorder) List(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error) {
+	total, err := dao.GetTotalOfConfigRevisions(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	revisions, err := dao.ListConfigRevisions(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
@@ -0,0 +1,224 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCfgStore struct {
+	cfgs map[string]interface{}
+}
+
+func (f *fakeCfgStore) Name() string {
+	return "fake"
+}
+
+func (f *fakeCfgStore) Read() (map[string]interface{}, error) {
+	cfgs := map[string]interface{}{}
+	for k, v := range f.cfgs {
+		cfgs[k] = v
+	}
+	return cfgs, nil
+}
+
+func (f *fakeCfgStore) Write(cfgs map[string]interface{}) error {
+	for k, v := range cfgs {
+		f.cfgs[k] = v
+	}
+	return nil
+}
+
+type fakeEncryptor struct {
+}
+
+func (f *fakeEncryptor) Encrypt(plaintext string) (string, error) {
+	return "encrypted:" + plaintext, nil
+}
+
+func (f *fakeEncryptor) Decrypt(ciphertext string) (string, error) {
+	return ciphertext[len("encrypted:"):], nil
+}
+
+type fakeRecorder struct {
+	revisions []*models.ConfigRevision
+}
+
+func (f *fakeRecorder) Add(revision *models.ConfigRevision) (int64, error) {
+	revision.ID = int64(len(f.revisions) + 1)
+	f.revisions = append(f.revisions, revision)
+	return revision.ID, nil
+}
+
+func (f *fakeRecorder) Get(id int64) (*models.ConfigRevision, error) {
+	if id <= 0 || id > int64(len(f.revisions)) {
+		return nil, nil
+	}
+	return f.revisions[id-1], nil
+}
+
+func (f *fakeRecorder) UpdateSnapshot(id int64, snapshot string) error {
+	f.revisions[id-1].Snapshot = snapshot
+	return nil
+}
+
+func (f *fakeRecorder) List(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, int64, error) {
+	return f.revisions, int64(len(f.revisions)), nil
+}
+
+func TestName(t *testing.T) {
+	driver := NewCfgStore(nil, nil, nil, nil)
+	assert.Equal(t, name, driver.Name())
+}
+
+func TestWriteBy(t *testing.T) {
+	store := &fakeCfgStore{
+		cfgs: map[string]interface{}{
+			"auth_mode":   "db_auth",
+			"email_port":  float64(25),
+			"ldap_passwd": "old_password",
+		},
+	}
+	recorder := &fakeRecorder{}
+	driver := NewCfgStore(&fakeEncryptor{}, []string{"ldap_passwd"}, store, recorder)
+
+	// nothing changed
+	require.Nil(t, driver.WriteBy(map[string]interface{}{"auth_mode": "db_auth", "email_port": 25}, "admin", ""))
+	assert.Equal(t, 0, len(recorder.revisions))
+
+	require.Nil(t, driver.WriteBy(map[string]interface{}{
+		"auth_mode":   "ldap_auth",
+		"email_port":  25,
+		"ldap_passwd": "new_password",
+		"ldap_url":    "ldap://ldap.com",
+	}, "admin", ""))
+	require.Equal(t, 1, len(recorder.revisions))
+	revision := recorder.revisions[0]
+	assert.Equal(t, "admin", revision.Operator)
+	assert.Equal(t, []*models.ConfigChange{
+		{Key: "auth_mode", Old: "db_auth", New: "ldap_auth"},
+		{Key: "ldap_passwd", Secret: true},
+		{Key: "ldap_url", New: "ldap://ldap.com"},
+	}, revision.Changes)
+	// the secrets are encrypted in the snapshot
+	assert.NotContains(t, revision.Snapshot, `"new_password"`)
+	assert.Contains(t, revision.Snapshot, "encrypted:new_password")
+	assert.Equal(t, "new_password", store.cfgs["ldap_passwd"])
+
+	// the changes made by the system
+	require.Nil(t, driver.Write(map[string]interface{}{"email_port": 587}))
+	require.Equal(t, 2, len(recorder.revisions))
+	assert.Equal(t, SystemOperator, recorder.revisions[1].Operator)
+}
+
+func TestRollback(t *testing.T) {
+	store := &fakeCfgStore{
+		cfgs: map[string]interface{}{
+			"auth_mode":   "db_auth",
+			"ldap_passwd": "password1",
+		},
+	}
+	recorder := &fakeRecorder{}
+	driver := NewCfgStore(&fakeEncryptor{}, []string{"ldap_passwd"}, store, recorder)
+
+	require.Nil(t, driver.WriteBy(map[string]interface{}{"auth_mode": "ldap_auth", "ldap_passwd": "password2"}, "admin", ""))
+	require.Nil(t, driver.WriteBy(map[string]interface{}{"auth_mode": "oidc_auth", "ldap_passwd": "password3"}, "admin", ""))
+	require.Nil(t, driver.Write(map[string]interface{}{"postgresql_host": "postgresql"}))
+
+	// the secrets in the snapshot are decrypted
+	snapshot, err := driver.Snapshot(1)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"auth_mode": "ldap_auth", "ldap_passwd": "password2"}, snapshot)
+	snapshot, err = driver.Snapshot(10)
+	require.Nil(t, err)
+	assert.Nil(t, snapshot)
+
+	keys := []string{"auth_mode", "ldap_passwd", "postgresql_host"}
+	assert.Equal(t, ErrRevisionNotFound, driver.Rollback(10, "admin", keys))
+
+	// the keys not in the snapshot are left unchanged
+	require.Nil(t, driver.Rollback(1, "auditor", keys))
+	assert.Equal(t, "ldap_auth", store.cfgs["auth_mode"])
+	assert.Equal(t, "password2", store.cfgs["ldap_passwd"])
+	assert.Equal(t, "postgresql", store.cfgs["postgresql_host"])
+
+	revisions, total, err := driver.Revisions(nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(4), total)
+	assert.Equal(t, "auditor", revisions[3].Operator)
+	assert.Equal(t, "rollback to revision 1", revisions[3].Comment)
+	assert.Equal(t, []*models.ConfigChange{
+		{Key: "auth_mode", Old: "oidc_auth", New: "ldap_auth"},
+		{Key: "ldap_passwd", Secret: true},
+	}, revisions[3].Changes)
+
+	revision, err := driver.Revision(4)
+	require.Nil(t, err)
+	assert.Equal(t, revisions[3], revision)
+
+	// only the specified keys are restored
+	require.Nil(t, driver.Rollback(3, "auditor", []string{"auth_mode"}))
+	assert.Equal(t, "oidc_auth", store.cfgs["auth_mode"])
+	assert.Equal(t, "password2", store.cfgs["ldap_passwd"])
+}
+
+type fakeEncryptedCfgStore struct {
+	fakeCfgStore
+	reencrypted bool
+}
+
+func (f *fakeEncryptedCfgStore) Reencrypt() (int, error) {
+	f.reencrypted = true
+	return 1, nil
+}
+
+type rotatedEncryptor struct {
+	fakeEncryptor
+}
+
+func (r *rotatedEncryptor) Encrypt(plaintext string) (string, error) {
+	return "rotated:" + plaintext, nil
+}
+
+func TestReencrypt(t *testing.T) {
+	fakeStore := &fakeEncryptedCfgStore{
+		fakeCfgStore: fakeCfgStore{
+			cfgs: map[string]interface{}{
+				"ldap_passwd": "password1",
+			},
+		},
+	}
+	recorder := &fakeRecorder{}
+	driver := NewCfgStore(&fakeEncryptor{}, []string{"ldap_passwd"}, fakeStore, recorder)
+	require.Nil(t, driver.WriteBy(map[string]interface{}{"ldap_passwd": "password2"}, "admin", ""))
+	require.Nil(t, driver.WriteBy(map[string]interface{}{"auth_mode": "ldap_auth"}, "admin", ""))
+	assert.Contains(t, recorder.revisions[0].Snapshot, "encrypted:password2")
+
+	// rotate the key
+	driver = NewCfgStore(&rotatedEncryptor{}, []string{"ldap_passwd"}, fakeStore, recorder)
+	n, err := driver.(store.Reencrypter).Reencrypt()
+	require.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.True(t, fakeStore.reencrypted)
+	for _, revision := range recorder.revisions {
+		assert.Contains(t, revision.Snapshot, "rotated:password2")
+		assert.NotContains(t, revision.Snapshot, "encrypted:")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/database"
 	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/encrypt"
 	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/json"
+	"github.com/goharbor/harbor/src/adminserver/systemcfg/store/revision"
 	"github.com/goharbor/harbor/src/common"
 	comcfg "github.com/goharbor/harbor/src/common/config"
 	"github.com/goharbor/harbor/src/common/dao"
@@ -38,6 +39,8 @@ const (
 	defaultJSONCfgStorePath string = "/etc/adminserver/config/config.json"
 	defaultKeyPath          string = "/etc/adminserver/key"
 	ldapScopeKey            string = "ldap_scope"
+	// the prefix of the environment variables containing the versioned keys
+	keyEnvPrefix string = "ENCRYPTION_KEY_"
 )
 
 var (
@@ -53,6 +56,7 @@ var (
 		common.AdminInitialPassword,
 		common.ClairDBPassword,
 		common.UAAClientSecret,
+		common.OIDCClientSecret,
 	}
 
 	// all configurations need read from environment variables
@@ -155,6 +159,16 @@ var (
 			env:   "UAA_VERIFY_CERT",
 			parse: parseStringToBool,
 		},
+		common.OIDCName:         "OIDC_NAME",
+		common.OIDCEndpoint:     "OIDC_ENDPOINT",
+		common.OIDCClientID:     "OIDC_CLIENT_ID",
+		common.OIDCClientSecret: "OIDC_CLIENT_SECRET",
+		common.OIDCScope:        "OIDC_SCOPE",
+		common.OIDCVerifyCert: &parser{
+			env:   "OIDC_VERIFY_CERT",
+			parse: parseStringToBool,
+		},
+		common.OIDCGroupsClaim:             "OIDC_GROUPS_CLAIM",
 		common.CoreURL:                     "CORE_URL",
 		common.JobServiceURL:               "JOBSERVICE_URL",
 		common.TokenServiceURL:             "TOKEN_SERVICE_URL",
@@ -219,6 +233,16 @@ var (
 			env:   "UAA_VERIFY_CERT",
 			parse: parseStringToBool,
 		},
+		common.OIDCName:         "OIDC_NAME",
+		common.OIDCEndpoint:     "OIDC_ENDPOINT",
+		common.OIDCClientID:     "OIDC_CLIENT_ID",
+		common.OIDCClientSecret: "OIDC_CLIENT_SECRET",
+		common.OIDCScope:        "OIDC_SCOPE",
+		common.OIDCVerifyCert: &parser{
+			env:   "OIDC_VERIFY_CERT",
+			parse: parseStringToBool,
+		},
+		common.OIDCGroupsClaim:             "OIDC_GROUPS_CLAIM",
 		common.RegistryStorageProviderName: "REGISTRY_STORAGE_PROVIDER_NAME",
 		common.CoreURL:                     "CORE_URL",
 		common.JobServiceURL:               "JOBSERVICE_URL",
@@ -359,13 +383,59 @@ func initCfgStore() (err error) {
 	}
 	log.Infof("the path of key used by key provider: %s", kp)
 
-	encryptor := enpt.NewAESEncryptor(
-		comcfg.NewFileKeyProvider(kp), nil)
+	keyProvider, err := newKeyProvider(kp)
+	if err != nil {
+		return err
+	}
+	encryptor := enpt.NewAESEncryptor(keyProvider, nil)
 
 	CfgStore = encrypt.NewCfgStore(encryptor, attrs, CfgStore)
+	// record the changes as revisions, the secrets are encrypted in the snapshots
+	CfgStore = revision.NewCfgStore(encryptor, attrs, CfgStore, nil)
 	return nil
 }
 
+// newKeyProvider returns the provider of the key used to encrypt the secret configurations.
+// The key read from keyPath is used if no KEY_ID is set, otherwise the versioned keys are
+// read from the files in KEY_DIR or the environment variables ENCRYPTION_KEY_<ID>, and the
+// key specified by KEY_ID is used to encrypt. The key read from keyPath is kept to decrypt
+// the values encrypted before the rotation
+func newKeyProvider(keyPath string) (comcfg.KeyProvider, error) {
+	legacy := comcfg.NewFileKeyProvider(keyPath)
+	id := os.Getenv("KEY_ID")
+	if len(id) == 0 {
+		return legacy, nil
+	}
+	if err := comcfg.ValidateKeyID(id); err != nil {
+		return nil, err
+	}
+	if dir := os.Getenv("KEY_DIR"); len(dir) > 0 {
+		log.Infof("the key %s read from directory %s is used to encrypt configurations", id, dir)
+		return comcfg.NewDirKeyProvider(dir, id, legacy), nil
+	}
+	log.Infof("the key %s read from environment variable %s%s is used to encrypt configurations", id, keyEnvPrefix, id)
+	return comcfg.NewEnvKeyProvider(keyEnvPrefix, id, legacy), nil
+}
+
+// Reencrypt re-encrypts the stored secret configurations with the current key,
+// the count of the re-encrypted values is returned
+func Reencrypt() (int, error) {
+	r, ok := CfgStore.(store.Reencrypter)
+	if !ok {
+		return 0, fmt.Errorf("re-encryption isn't supported by the storage driver %s", CfgStore.Name())
+	}
+	return r.Reencrypt()
+}
+
+// WriteCfgs writes the configurations changed by the operator, the changes are
+// recorded as a revision if the storage driver supports
+func WriteCfgs(cfgs map[string]interface{}, operator string) error {
+	if driver, ok := CfgStore.(store.RevisionDriver); ok {
+		return driver.WriteBy(cfgs, operator, "")
+	}
+	return CfgStore.Write(cfgs)
+}
+
 // LoadFromEnv loads the configurations from allEnvs, if all is false, it just loads
 // the repeatLoadEnvs and the env which is absent in cfgs
 func LoadFromEnv(cfgs map[string]interface{}, all bool) error {
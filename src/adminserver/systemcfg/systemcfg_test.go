@@ -19,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/common"
+	comcfg "github.com/goharbor/harbor/src/common/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseStringToInt(t *testing.T) {
@@ -73,6 +75,33 @@ func TestInitCfgStore(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestNewKeyProvider(t *testing.T) {
+	os.Clearenv()
+	provider, err := newKeyProvider("/tmp/key")
+	require.Nil(t, err)
+	_, ok := provider.(comcfg.VersionedKeyProvider)
+	assert.False(t, ok)
+
+	os.Setenv("KEY_ID", "../key")
+	_, err = newKeyProvider("/tmp/key")
+	assert.NotNil(t, err)
+
+	os.Setenv("KEY_ID", "2")
+	os.Setenv("ENCRYPTION_KEY_2", "1234567890123456")
+	provider, err = newKeyProvider("/tmp/key")
+	require.Nil(t, err)
+	key, err := provider.Get(nil)
+	require.Nil(t, err)
+	assert.Equal(t, "1234567890123456", key)
+
+	os.Setenv("KEY_DIR", "/tmp/keys")
+	provider, err = newKeyProvider("/tmp/key")
+	require.Nil(t, err)
+	_, err = provider.Get(nil)
+	assert.NotNil(t, err)
+	os.Clearenv()
+}
+
 func TestLoadFromEnv(t *testing.T) {
 	os.Clearenv()
 	ldapURL := "ldap://ldap.com"
@@ -10,10 +10,13 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver"
+	"github.com/ghodss/yaml"
 
 	"github.com/goharbor/harbor/src/common/models"
 	hlog "github.com/goharbor/harbor/src/common/utils/log"
+	"golang.org/x/crypto/openpgp/clearsign"
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	helm_repo "k8s.io/helm/pkg/repo"
 )
 
@@ -25,7 +28,8 @@ const (
 // ChartVersion extends the helm ChartVersion with additional labels
 type ChartVersion struct {
 	helm_repo.ChartVersion
-	Labels []*models.Label `json:"labels"`
+	Labels        []*models.Label `json:"labels"`
+	DownloadCount int64           `json:"download_count"`
 }
 
 // ChartVersions is an array of extended ChartVersion
@@ -39,6 +43,21 @@ type ChartVersionDetails struct {
 	Files        map[string]string       `json:"files"`
 	Security     *SecurityReport         `json:"security"`
 	Labels       []*models.Label         `json:"labels"`
+	Images       []*ChartImage           `json:"images"`
+	// The download count of the chart version
+	DownloadCount int64 `json:"download_count"`
+}
+
+// ChartImage keeps the info of the image referenced in the values of the chart
+type ChartImage struct {
+	// The key prefix of the image in the values, e.g: "image" for "image.repository"
+	Path       string `json:"path"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	// Whether the image is found in the local repositories
+	Resolved     bool                    `json:"resolved"`
+	Digest       string                  `json:"digest,omitempty"`
+	ScanOverview *models.ImgScanOverview `json:"scan_overview,omitempty"`
 }
 
 // SecurityReport keeps the info related with security
@@ -118,11 +137,52 @@ func (cho *ChartOperator) GetChartDetails(content []byte) (*ChartVersionDetails,
 		Dependencies: requirements.Dependencies,
 		Values:       values,
 		Files:        files,
+		Images:       getChartImages(values),
 	}
 
 	return theChart, nil
 }
 
+// GetChartMetadata parse the metadata like name and version from the provided content bytes
+func (cho *ChartOperator) GetChartMetadata(content []byte) (*chart.Metadata, error) {
+	if len(content) == 0 {
+		return nil, errors.New("zero content")
+	}
+
+	chartData, err := chartutil.LoadArchive(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if chartData.Metadata == nil {
+		return nil, errors.New("no metadata found in the chart")
+	}
+
+	return chartData.Metadata, nil
+}
+
+// GetProvMetadata returns the metadata of the chart signed in the provenance file
+func (cho *ChartOperator) GetProvMetadata(content []byte) (*chart.Metadata, error) {
+	if len(content) == 0 {
+		return nil, errors.New("zero content")
+	}
+
+	block, _ := clearsign.Decode(content)
+	if block == nil {
+		return nil, errors.New("no signed message found in the provenance file")
+	}
+	// The message is the metadata of the chart followed by the checksums of the files
+	parts := bytes.Split(block.Plaintext, []byte("\n...\n"))
+	metadata := &chart.Metadata{}
+	if err := yaml.Unmarshal(parts[0], metadata); err != nil {
+		return nil, err
+	}
+	if len(metadata.Name) == 0 || len(metadata.Version) == 0 {
+		return nil, errors.New("no chart name or version found in the provenance file")
+	}
+
+	return metadata, nil
+}
+
 // GetChartList returns a reorganized chart list
 func (cho *ChartOperator) GetChartList(content []byte) ([]*ChartInfo, error) {
 	if content == nil || len(content) == 0 {
@@ -252,3 +312,81 @@ func readValue(values map[string]interface{}, keyPrefix string, valueMap map[str
 		}
 	}
 }
+
+// Extract the image references from the flattened values. Both the
+// "<prefix>.repository" + "<prefix>.tag" (with the optional "<prefix>.registry")
+// style and the "<prefix>.image" = "repo:tag" style are supported.
+func getChartImages(values map[string]interface{}) []*ChartImage {
+	images := make([]*ChartImage, 0)
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok || len(strings.TrimSpace(str)) == 0 {
+			continue
+		}
+
+		if key == "repository" || strings.HasSuffix(key, ".repository") {
+			prefix := strings.TrimSuffix(key, "repository")
+			repository := strings.TrimSpace(str)
+			if registry := valueString(values, prefix+"registry"); len(registry) > 0 {
+				repository = fmt.Sprintf("%s/%s", strings.TrimSuffix(registry, "/"), repository)
+			}
+			tag := valueString(values, prefix+"tag")
+			if len(tag) == 0 {
+				repository, tag = parseImageReference(repository)
+			}
+			images = append(images, &ChartImage{
+				Path:       strings.TrimSuffix(prefix, "."),
+				Repository: repository,
+				Tag:        tag,
+			})
+			continue
+		}
+
+		if key == "image" || strings.HasSuffix(key, ".image") {
+			repository, tag := parseImageReference(strings.TrimSpace(str))
+			images = append(images, &ChartImage{
+				Path:       key,
+				Repository: repository,
+				Tag:        tag,
+			})
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return strings.Compare(images[i].Path, images[j].Path) < 0
+	})
+
+	return images
+}
+
+// Read the value as string, the numeric tags like 1.0 are supported
+func valueString(values map[string]interface{}, key string) string {
+	value, ok := values[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Split the image reference into repository and tag, the tag is "latest"
+// if it isn't specified
+func parseImageReference(reference string) (string, string) {
+	if i := strings.Index(reference, "@"); i > 0 {
+		return reference[:i], reference[i+1:]
+	}
+
+	i := strings.LastIndex(reference, ":")
+	if i > 0 && !strings.Contains(reference[i+1:], "/") {
+		return reference[:i], reference[i+1:]
+	}
+
+	return reference, "latest"
+}
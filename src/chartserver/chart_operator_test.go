@@ -1,9 +1,12 @@
 package chartserver
 
 import (
+	"bytes"
 	"testing"
 
 	htesting "github.com/goharbor/harbor/src/testing"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
 )
 
 func TestGetChartDetails(t *testing.T) {
@@ -24,6 +27,61 @@ func TestGetChartDetails(t *testing.T) {
 	if chartDetails.Values["adminserver.adminPassword"] != "Harbor12345" {
 		t.Fatalf("The value of 'adminserver.adminPassword' should be 'Harbor12345' but we got '%s' now", chartDetails.Values["adminserver.adminPassword"])
 	}
+
+	if len(chartDetails.Images) == 0 {
+		t.Fatal("At least 1 image existing, but we got 0 now")
+	}
+}
+
+func TestGetChartMetadata(t *testing.T) {
+	chartOpr := ChartOperator{}
+	if _, err := chartOpr.GetChartMetadata(nil); err == nil {
+		t.Fatal("expect non nil error for empty content but got nil")
+	}
+
+	metadata, err := chartOpr.GetChartMetadata(htesting.HelmChartContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata.GetName() != "harbor" {
+		t.Fatalf("expect chart name 'harbor' but got '%s'", metadata.GetName())
+	}
+
+	if metadata.GetVersion() != "0.2.0" {
+		t.Fatalf("expect chart version '0.2.0' but got '%s'", metadata.GetVersion())
+	}
+}
+
+func TestGetProvMetadata(t *testing.T) {
+	chartOpr := ChartOperator{}
+	if _, err := chartOpr.GetProvMetadata([]byte("not signed")); err == nil {
+		t.Fatal("expect non nil error for unsigned content but got nil")
+	}
+
+	entity, err := openpgp.NewEntity("harbor", "", "harbor@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov := &bytes.Buffer{}
+	w, err := clearsign.Encode(prov, entity.PrivateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("apiVersion: v1\nname: harbor\nversion: 0.2.0\n\n...\nfiles:\n  harbor-0.2.0.tgz: sha256:abc\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := chartOpr.GetProvMetadata(prov.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.GetName() != "harbor" || metadata.GetVersion() != "0.2.0" {
+		t.Fatalf("expect chart 'harbor:0.2.0' but got '%s:%s'", metadata.GetName(), metadata.GetVersion())
+	}
 }
 
 func TestGetChartList(t *testing.T) {
@@ -46,3 +104,34 @@ func TestGetChartList(t *testing.T) {
 		t.Fatalf("Expect latest version '0.2.0' but got '%s'", firstInSortedList.LatestVersion)
 	}
 }
+
+func TestGetChartImages(t *testing.T) {
+	values := map[string]interface{}{
+		"adminserver.image.repository": "library/adminserver",
+		"adminserver.image.tag":        "v1.6.0",
+		"mysql.image.registry":         "harbor.example.com",
+		"mysql.image.repository":       "library/mysql",
+		"mysql.image.tag":              5.7,
+		"redis.image.repository":       "library/redis:4.0",
+		"ui.image":                     "harbor.example.com:8443/library/ui",
+		"notary.image":                 "library/notary@sha256:abc",
+		"ui.replicas":                  1,
+	}
+
+	images := getChartImages(values)
+	expected := []*ChartImage{
+		{Path: "adminserver.image", Repository: "library/adminserver", Tag: "v1.6.0"},
+		{Path: "mysql.image", Repository: "harbor.example.com/library/mysql", Tag: "5.7"},
+		{Path: "notary.image", Repository: "library/notary", Tag: "sha256:abc"},
+		{Path: "redis.image", Repository: "library/redis", Tag: "4.0"},
+		{Path: "ui.image", Repository: "harbor.example.com:8443/library/ui", Tag: "latest"},
+	}
+	if len(images) != len(expected) {
+		t.Fatalf("expect %d images but got %d", len(expected), len(images))
+	}
+	for i, image := range images {
+		if *image != *expected[i] {
+			t.Errorf("expect image %+v but got %+v", expected[i], image)
+		}
+	}
+}
@@ -1,14 +1,19 @@
 package chartserver
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
 )
 
 const (
@@ -24,6 +29,15 @@ type ChartClient struct {
 
 	// Auth info
 	credentail *Credential
+
+	// Authorize the requests if no credential provided
+	authorizer modifier.Modifier
+}
+
+// ChartFile keeps the name and content of the chart or prov file
+type ChartFile struct {
+	Name    string
+	Content []byte
 }
 
 // NewChartClient is constructor of ChartClient
@@ -43,6 +57,18 @@ func NewChartClient(credentail *Credential) *ChartClient { // Create http client
 	}
 }
 
+// NewChartClientWithAuthorizer is constructor of ChartClient which sends the requests
+// through the transport and authorizes them with the authorizer
+func NewChartClientWithAuthorizer(transport http.RoundTripper, authorizer modifier.Modifier) *ChartClient {
+	return &ChartClient{
+		httpClient: &http.Client{
+			Timeout:   clientTimeout,
+			Transport: transport,
+		},
+		authorizer: authorizer,
+	}
+}
+
 // GetContent get the bytes from the specified url
 func (cc *ChartClient) GetContent(addr string) ([]byte, error) {
 	response, err := cc.sendRequest(addr, http.MethodGet, nil, []int{http.StatusOK})
@@ -65,8 +91,48 @@ func (cc *ChartClient) DeleteContent(addr string) error {
 	return err
 }
 
-// sendRequest sends requests to the addr with the specified spec
-func (cc *ChartClient) sendRequest(addr string, method string, body io.Reader, expectedCodes []int) (*http.Response, error) {
+// UploadChart uploads the chart and its prov file to the addr as a multipart form,
+// which is accepted by the chart uploading API of Harbor. prov can be nil
+func (cc *ChartClient) UploadChart(addr string, chart *ChartFile, prov *ChartFile) error {
+	if chart == nil {
+		return errors.New("no chart file specified")
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	files := map[string]*ChartFile{
+		"chart": chart,
+	}
+	if prov != nil {
+		files["prov"] = prov
+	}
+	for field, file := range files {
+		fw, err := writer.CreateFormFile(field, file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(file.Content); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	response, err := cc.sendRequest(addr, http.MethodPost, body, []int{http.StatusCreated, http.StatusOK},
+		writer.FormDataContentType())
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return nil
+}
+
+// sendRequest sends requests to the addr with the specified spec, the optional
+// contentType is set as the content type of request body
+func (cc *ChartClient) sendRequest(addr string, method string, body io.Reader, expectedCodes []int,
+	contentType ...string) (*http.Response, error) {
 	if len(strings.TrimSpace(addr)) == 0 {
 		return nil, errors.New("empty url is not allowed")
 	}
@@ -80,10 +146,17 @@ func (cc *ChartClient) sendRequest(addr string, method string, body io.Reader, e
 	if err != nil {
 		return nil, err
 	}
+	if len(contentType) > 0 {
+		request.Header.Set("Content-Type", contentType[0])
+	}
 
 	// Set basic auth
 	if cc.credentail != nil {
 		request.SetBasicAuth(cc.credentail.Username, cc.credentail.Password)
+	} else if cc.authorizer != nil {
+		if err = cc.authorizer.Modify(request); err != nil {
+			return nil, err
+		}
 	}
 
 	response, err := cc.httpClient.Do(request)
@@ -106,11 +179,18 @@ func (cc *ChartClient) sendRequest(addr string, method string, body io.Reader, e
 		}
 		defer response.Body.Close()
 
-		if err := extractError(content); err != nil {
-			return nil, err
+		err = extractError(content)
+		if err == nil {
+			err = fmt.Errorf("%s '%s' failed with error: %s", method, fullURI.Path, content)
 		}
-
-		return nil, fmt.Errorf("%s '%s' failed with error: %s", method, fullURI.Path, content)
+		// keep the status code for the callers to tell the missing content from the failures
+		if response.StatusCode == http.StatusNotFound {
+			return nil, &common_http.Error{
+				Code:    response.StatusCode,
+				Message: err.Error(),
+			}
+		}
+		return nil, err
 	}
 
 	return response, nil
@@ -0,0 +1,89 @@
+package chartserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadChart(t *testing.T) {
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Harbor-Secret secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		for _, field := range []string{"chart", "prov"} {
+			file, header, err := r.FormFile(field)
+			if err != nil {
+				continue
+			}
+			content, _ := ioutil.ReadAll(file)
+			files[field] = header.Filename + ":" + string(content)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewChartClientWithAuthorizer(http.DefaultTransport, auth.NewSecretAuthorizer("secret"))
+
+	// no chart file
+	assert.NotNil(t, client.UploadChart(server.URL, nil, nil))
+
+	// chart only
+	err := client.UploadChart(server.URL, &ChartFile{
+		Name:    "harbor-0.1.0.tgz",
+		Content: []byte("chart"),
+	}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"chart": "harbor-0.1.0.tgz:chart"}, files)
+
+	// chart and prov
+	err = client.UploadChart(server.URL, &ChartFile{
+		Name:    "harbor-0.1.0.tgz",
+		Content: []byte("chart"),
+	}, &ChartFile{
+		Name:    "harbor-0.1.0.tgz.prov",
+		Content: []byte("prov"),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "harbor-0.1.0.tgz.prov:prov", files["prov"])
+
+	// unauthorized
+	client = NewChartClient(nil)
+	assert.NotNil(t, client.UploadChart(server.URL, &ChartFile{
+		Name:    "harbor-0.1.0.tgz",
+		Content: []byte("chart"),
+	}, nil))
+}
+
+func TestGetContentNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/library/charts/harbor/0.1.0" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"chart not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewChartClient(nil)
+
+	_, err := client.GetContent(server.URL + "/api/library/charts/harbor/0.1.0")
+	e, ok := err.(*common_http.Error)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, e.Code)
+	assert.Equal(t, "chart not found", e.Message)
+
+	_, err = client.GetContent(server.URL + "/api/library/charts/harbor/0.2.0")
+	require.NotNil(t, err)
+	_, ok = err.(*common_http.Error)
+	assert.False(t, ok)
+}
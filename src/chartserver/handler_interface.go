@@ -49,6 +49,17 @@ type ServiceHandler interface {
 	// otherwise, a non-nil error will be got.
 	SearchChart(q string, namespaces []string) ([]*search.Result, error)
 
+	// QueryCharts queries the charts in the specified namespaces with the conditions like
+	// keyword, app version constraint, maintainer, deprecated flag and candidate versions.
+	// For each chart, only the latest matched version will be shown in the result list.
+	//
+	// query *ChartQuery   : the query conditions and the sorting key
+	// namespaces []string : the query namespace scope
+	//
+	// If succeed, a sorted chart version list with nil error will be returned;
+	// otherwise, a non-nil error will be got.
+	QueryCharts(query *ChartQuery, namespaces []string) ([]*helm_repo.ChartVersion, error)
+
 	// GetIndexFile will read the index.yaml under all namespaces and merge them as a single one
 	// Please be aware that, to support this function, the backend chart repository server should
 	// enable multi-tenancies
@@ -4,17 +4,58 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/Masterminds/semver"
 	hlog "github.com/goharbor/harbor/src/common/utils/log"
 	"k8s.io/helm/cmd/helm/search"
+	helm_repo "k8s.io/helm/pkg/repo"
 )
 
 const (
 	maxDeletionThreads = 10
+
+	// The supported sorting keys of chart query, prefix "-" means descending
+	querySortByName    = "name"
+	querySortByCreated = "created"
 )
 
+// ChartQuery defines the conditions of querying charts
+type ChartQuery struct {
+	// Match the name, description and keywords of the chart, case insensitive
+	Keyword string
+	// The semver constraint of the app version, e.g: ">=1.0.0, <2.0.0"
+	AppVersion string
+	// Match the name or email of the maintainers, case insensitive
+	Maintainer string
+	// Filter by the deprecated flag if it's set
+	Deprecated *bool
+	// The full names(namespace/chart:version) of the chart versions which
+	// the result is limited to, nil means no limitation
+	Candidates map[string]struct{}
+	// The sorting key: "name" or "created", the prefix "-" means descending
+	Sort string
+}
+
+// Valid checks whether the query is valid
+func (q *ChartQuery) Valid() error {
+	if len(q.AppVersion) > 0 {
+		if _, err := semver.NewConstraint(q.AppVersion); err != nil {
+			return fmt.Errorf("invalid app version constraint %s: %v", q.AppVersion, err)
+		}
+	}
+
+	switch strings.TrimPrefix(q.Sort, "-") {
+	case "", querySortByName, querySortByCreated:
+	default:
+		return fmt.Errorf("unsupported sorting key %s", q.Sort)
+	}
+
+	return nil
+}
+
 // GetCountOfCharts calculates and returns the total count of charts under the specified namespaces.
 // See @ServiceHandler.GetCountOfCharts
 func (c *Controller) GetCountOfCharts(namespaces []string) (uint64, error) {
@@ -214,6 +255,144 @@ func (c *Controller) SearchChart(q string, namespaces []string) ([]*search.Resul
 	return results, nil
 }
 
+// QueryCharts queries the charts under the specified namespaces with the conditions.
+// For each chart, only the latest matched version will be shown in the result list.
+// See @ServiceHandler.QueryCharts
+func (c *Controller) QueryCharts(query *ChartQuery, namespaces []string) ([]*helm_repo.ChartVersion, error) {
+	if query == nil {
+		query = &ChartQuery{}
+	}
+	if err := query.Valid(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*helm_repo.ChartVersion, 0)
+	if len(namespaces) == 0 {
+		return results, nil
+	}
+
+	// Get the merged index yaml file of the namespaces
+	ind, err := c.getIndexYaml(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	var constraint *semver.Constraints
+	if len(query.AppVersion) > 0 {
+		// Already validated
+		constraint, _ = semver.NewConstraint(query.AppVersion)
+	}
+
+	for _, chartVersions := range ind.Entries {
+		var latest *helm_repo.ChartVersion
+		for _, chartVersion := range chartVersions {
+			if !matchChartVersion(query, constraint, chartVersion) {
+				continue
+			}
+			if latest == nil || isNewerChartVersion(chartVersion, latest) {
+				latest = chartVersion
+			}
+		}
+
+		if latest != nil {
+			results = append(results, latest)
+		}
+	}
+
+	sortChartVersions(results, query.Sort)
+
+	return results, nil
+}
+
+// Check whether the chart version matches all the conditions of the query
+func matchChartVersion(query *ChartQuery, constraint *semver.Constraints, chartVersion *helm_repo.ChartVersion) bool {
+	if chartVersion.Metadata == nil {
+		return false
+	}
+
+	if query.Candidates != nil {
+		fullName := fmt.Sprintf("%s:%s", chartVersion.Name, chartVersion.Version)
+		if _, ok := query.Candidates[fullName]; !ok {
+			return false
+		}
+	}
+
+	if query.Deprecated != nil && *query.Deprecated != chartVersion.Deprecated {
+		return false
+	}
+
+	if constraint != nil {
+		appVersion, err := semver.NewVersion(chartVersion.AppVersion)
+		if err != nil || !constraint.Check(appVersion) {
+			return false
+		}
+	}
+
+	if len(query.Maintainer) > 0 {
+		matched := false
+		for _, maintainer := range chartVersion.Maintainers {
+			if containsIgnoreCase(maintainer.Name, query.Maintainer) ||
+				containsIgnoreCase(maintainer.Email, query.Maintainer) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(query.Keyword) > 0 {
+		if containsIgnoreCase(chartVersion.Name, query.Keyword) ||
+			containsIgnoreCase(chartVersion.Description, query.Keyword) {
+			return true
+		}
+		for _, keyword := range chartVersion.Keywords {
+			if containsIgnoreCase(keyword, query.Keyword) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// Check whether the version of chart version a is newer than b,
+// fall back to compare the created time if the version is malformed
+func isNewerChartVersion(a, b *helm_repo.ChartVersion) bool {
+	va, errA := semver.NewVersion(a.Version)
+	vb, errB := semver.NewVersion(b.Version)
+	if errA != nil || errB != nil {
+		return a.Created.After(b.Created)
+	}
+
+	return va.GreaterThan(vb)
+}
+
+// Sort the chart versions by the sorting key, sorted by name if no key specified
+func sortChartVersions(chartVersions []*helm_repo.ChartVersion, sortKey string) {
+	desc := strings.HasPrefix(sortKey, "-")
+	key := strings.TrimPrefix(sortKey, "-")
+
+	sort.SliceStable(chartVersions, func(i, j int) bool {
+		a, b := chartVersions[i], chartVersions[j]
+		if desc {
+			a, b = b, a
+		}
+
+		if key == querySortByCreated && !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+
+		return strings.Compare(a.Name, b.Name) < 0
+	})
+}
+
+func containsIgnoreCase(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
 // Get the content bytes of the chart version
 func (c *Controller) getChartVersionContent(namespace string, subPath string) ([]byte, error) {
 	url := path.Join(namespace, subPath)
@@ -1,6 +1,7 @@
 package chartserver
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -113,3 +114,57 @@ func TestChartSearching(t *testing.T) {
 		t.Fatalf("expect 2 results but got %d", len(results))
 	}
 }
+
+// Test the function QueryCharts
+func TestQueryCharts(t *testing.T) {
+	s, c, err := createMockObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	namespaces := []string{"repo1", "repo2"}
+	deprecated := true
+	cases := []struct {
+		query    *ChartQuery
+		expected []string
+	}{
+		{nil, []string{"repo1/harbor", "repo1/hello-helm", "repo1/myhelm", "repo2/harbor", "repo2/hello-helm"}},
+		{&ChartQuery{Keyword: "HARBOR"}, []string{"repo1/harbor", "repo2/harbor"}},
+		{&ChartQuery{Keyword: "registry", Sort: "-name"}, []string{"repo2/harbor", "repo1/harbor"}},
+		{&ChartQuery{AppVersion: ">=1.2"}, []string{"repo1/harbor", "repo1/myhelm", "repo2/harbor", "repo2/hello-helm"}},
+		{&ChartQuery{Maintainer: "vmware.com"}, []string{"repo1/harbor", "repo2/harbor"}},
+		{&ChartQuery{Deprecated: &deprecated}, []string{}},
+		{&ChartQuery{Candidates: map[string]struct{}{"repo2/hello-helm:0.1.0": {}}}, []string{"repo2/hello-helm"}},
+	}
+
+	for _, cs := range cases {
+		results, err := c.QueryCharts(cs.query, namespaces)
+		if err != nil {
+			t.Fatalf("expect nil error but got '%s'", err)
+		}
+		names := []string{}
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(cs.expected, ",") {
+			t.Errorf("expect %v but got %v for query %+v", cs.expected, names, cs.query)
+		}
+	}
+
+	// The latest matched version is returned
+	results, err := c.QueryCharts(&ChartQuery{AppVersion: "<1.2"}, []string{"repo2"})
+	if err != nil {
+		t.Fatalf("expect nil error but got '%s'", err)
+	}
+	if len(results) != 1 || results[0].Version != "0.1.0" {
+		t.Fatalf("expect the chart version 0.1.0 but got %v", results)
+	}
+
+	if _, err := c.QueryCharts(&ChartQuery{AppVersion: "not-a-constraint"}, namespaces); err == nil {
+		t.Fatal("expect non-nil error for invalid app version constraint but got nil")
+	}
+	if _, err := c.QueryCharts(&ChartQuery{Sort: "size"}, namespaces); err == nil {
+		t.Fatal("expect non-nil error for unsupported sorting key but got nil")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/astaxie/beego/cache"
 	"github.com/goharbor/harbor/src/adminserver/client"
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
 )
 
 // Manager manages configurations
@@ -79,9 +80,9 @@ func (m *Manager) Load() (map[string]interface{}, error) {
 	return c, nil
 }
 
-// Reset configurations
-func (m *Manager) Reset() error {
-	return m.client.ResetCfgs()
+// Reset configurations, the changes are recorded with the operator
+func (m *Manager) Reset(operator string) error {
+	return m.client.ResetCfgs(operator)
 }
 
 func getCfgExpiration(m map[string]interface{}) (int, error) {
@@ -109,7 +110,27 @@ func (m *Manager) Get() (map[string]interface{}, error) {
 	return m.Load()
 }
 
-// Upload configurations
-func (m *Manager) Upload(cfgs map[string]interface{}) error {
-	return m.client.UpdateCfgs(cfgs)
+// Upload configurations, the changes are recorded with the operator
+func (m *Manager) Upload(cfgs map[string]interface{}, operator string) error {
+	return m.client.UpdateCfgs(cfgs, operator)
+}
+
+// Revisions lists the revisions of configurations
+func (m *Manager) Revisions(query *models.ConfigRevisionQuery) (*models.ConfigRevisionList, error) {
+	return m.client.ListCfgRevisions(query)
+}
+
+// Revision returns the revision of configurations specified by ID
+func (m *Manager) Revision(id int64) (*models.ConfigRevision, error) {
+	return m.client.GetCfgRevision(id)
+}
+
+// RevisionSnapshot returns the configurations recorded by the revision specified by ID
+func (m *Manager) RevisionSnapshot(id int64) (map[string]interface{}, error) {
+	return m.client.GetCfgRevisionSnapshot(id)
+}
+
+// Rollback restores the keys of configurations to the revision specified by ID
+func (m *Manager) Rollback(id int64, operator string, keys []string) error {
+	return m.client.RollbackCfgs(id, operator, keys)
 }
@@ -15,9 +15,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 )
 
+// the key ID is used as the file name or the suffix of the environment variable
+var keyIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // KeyProvider provides the key used to encrypt and decrypt attrs
 type KeyProvider interface {
 	// Get returns the key
@@ -46,3 +54,86 @@ func (f *FileKeyProvider) Get(params map[string]interface{}) (string, error) {
 	}
 	return string(b), nil
 }
+
+// VersionedKeyProvider provides the keys identified by IDs, which enables rotating the key:
+// the values are encrypted with the current key and decrypted with the key specified by the ID
+// recorded along with the ciphertext. The empty ID refers to the legacy key without ID
+type VersionedKeyProvider interface {
+	KeyProvider
+	// CurrentID returns the ID of the key returned by Get
+	CurrentID() string
+	// GetByID returns the key specified by the ID
+	GetByID(id string) (string, error)
+}
+
+// ValidateKeyID checks whether the key ID only contains letters, digits, "_" and "-"
+func ValidateKeyID(id string) error {
+	if !keyIDRegexp.MatchString(id) {
+		return fmt.Errorf("invalid key ID %q, only letters, digits, \"_\" and \"-\" are allowed", id)
+	}
+	return nil
+}
+
+type versionedKeyProvider struct {
+	current string
+	// provides the key without ID, can be nil
+	legacy KeyProvider
+	lookup func(id string) (string, error)
+}
+
+func (v *versionedKeyProvider) Get(params map[string]interface{}) (string, error) {
+	return v.GetByID(v.current)
+}
+
+func (v *versionedKeyProvider) CurrentID() string {
+	return v.current
+}
+
+func (v *versionedKeyProvider) GetByID(id string) (string, error) {
+	if len(id) == 0 {
+		if v.legacy == nil {
+			return "", errors.New("no legacy key is provided")
+		}
+		return v.legacy.Get(nil)
+	}
+	if err := ValidateKeyID(id); err != nil {
+		return "", err
+	}
+	return v.lookup(id)
+}
+
+// NewDirKeyProvider returns a VersionedKeyProvider which reads the keys from the files
+// in the directory, e.g. a mounted secret, the file names are the key IDs.
+// current: the ID of the key used to encrypt, the legacy key is used if it's empty
+// legacy: provides the key without ID, can be nil
+func NewDirKeyProvider(dir, current string, legacy KeyProvider) VersionedKeyProvider {
+	return &versionedKeyProvider{
+		current: current,
+		legacy:  legacy,
+		lookup: func(id string) (string, error) {
+			b, err := ioutil.ReadFile(filepath.Join(dir, id))
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		},
+	}
+}
+
+// NewEnvKeyProvider returns a VersionedKeyProvider which reads the keys from the environment
+// variables named with the prefix followed by the key ID, e.g. CONFIG_ENCRYPTION_KEY_2.
+// current: the ID of the key used to encrypt, the legacy key is used if it's empty
+// legacy: provides the key without ID, can be nil
+func NewEnvKeyProvider(prefix, current string, legacy KeyProvider) VersionedKeyProvider {
+	return &versionedKeyProvider{
+		current: current,
+		legacy:  legacy,
+		lookup: func(id string) (string, error) {
+			key, ok := os.LookupEnv(prefix + id)
+			if !ok || len(key) == 0 {
+				return "", fmt.Errorf("key %s not found in environment variable %s", id, prefix+id)
+			}
+			return key, nil
+		},
+	}
+}
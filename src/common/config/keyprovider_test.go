@@ -17,7 +17,11 @@ package config
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetOfFileKeyProvider(t *testing.T) {
@@ -42,3 +46,58 @@ func TestGetOfFileKeyProvider(t *testing.T) {
 		return
 	}
 }
+
+func TestDirKeyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keys")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "2"), []byte("key2"), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	legacyPath := filepath.Join(dir, "legacy")
+	if err := ioutil.WriteFile(legacyPath, []byte("legacy_key"), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	provider := NewDirKeyProvider(dir, "2", NewFileKeyProvider(legacyPath))
+	assert.Equal(t, "2", provider.CurrentID())
+	key, err := provider.Get(nil)
+	require.Nil(t, err)
+	assert.Equal(t, "key2", key)
+	key, err = provider.GetByID("")
+	require.Nil(t, err)
+	assert.Equal(t, "legacy_key", key)
+
+	// not found
+	_, err = provider.GetByID("3")
+	assert.NotNil(t, err)
+	// the path out of the directory
+	_, err = provider.GetByID("../key")
+	assert.NotNil(t, err)
+	// no legacy key
+	_, err = NewDirKeyProvider(dir, "2", nil).GetByID("")
+	assert.NotNil(t, err)
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	os.Setenv("TEST_KEY_1", "key1")
+	defer os.Unsetenv("TEST_KEY_1")
+
+	provider := NewEnvKeyProvider("TEST_KEY_", "1", nil)
+	assert.Equal(t, "1", provider.CurrentID())
+	key, err := provider.Get(nil)
+	require.Nil(t, err)
+	assert.Equal(t, "key1", key)
+
+	_, err = provider.GetByID("2")
+	assert.NotNil(t, err)
+}
+
+func TestValidateKeyID(t *testing.T) {
+	assert.Nil(t, ValidateKeyID("2019-10_a"))
+	assert.NotNil(t, ValidateKeyID(""))
+	assert.NotNil(t, ValidateKeyID("../a"))
+	assert.NotNil(t, ValidateKeyID("a>"))
+}
@@ -19,6 +19,7 @@ const (
 	DBAuth              = "db_auth"
 	LDAPAuth            = "ldap_auth"
 	UAAAuth             = "uaa_auth"
+	OIDCAuth            = "oidc_auth"
 	ProCrtRestrEveryone = "everyone"
 	ProCrtRestrAdmOnly  = "adminonly"
 	LDAPScopeBase       = 0
@@ -94,6 +95,13 @@ const (
 	UAAClientID                       = "uaa_client_id"
 	UAAClientSecret                   = "uaa_client_secret"
 	UAAVerifyCert                     = "uaa_verify_cert"
+	OIDCName                          = "oidc_name"
+	OIDCEndpoint                      = "oidc_endpoint"
+	OIDCClientID                      = "oidc_client_id"
+	OIDCClientSecret                  = "oidc_client_secret"
+	OIDCScope                         = "oidc_scope"
+	OIDCVerifyCert                    = "oidc_verify_cert"
+	OIDCGroupsClaim                   = "oidc_groups_claim"
 	DefaultClairEndpoint              = "http://clair:6060"
 	CfgDriverDB                       = "db"
 	CfgDriverJSON                     = "json"
@@ -109,6 +117,7 @@ const (
 	DefaultCoreEndpoint               = "http://core:8080"
 	DefaultNotaryEndpoint             = "http://notary-server:4443"
 	LdapGroupType                     = 1
+	OIDCGroupType                     = 2
 	ReloadKey                         = "reload_key"
 	LdapGroupAdminDn                  = "ldap_group_admin_dn"
 	DefaultRegistryControllerEndpoint = "http://registryctl:8080"
@@ -153,6 +162,13 @@ var (
 		UAAClientSecret,
 		UAAEndpoint,
 		UAAVerifyCert,
+		OIDCName,
+		OIDCEndpoint,
+		OIDCClientID,
+		OIDCClientSecret,
+		OIDCScope,
+		OIDCVerifyCert,
+		OIDCGroupsClaim,
 		ReadOnly,
 	}
 
@@ -177,6 +193,11 @@ var (
 		ProjectCreationRestriction: ProCrtRestrEveryone,
 		UAAClientID:                "",
 		UAAEndpoint:                "",
+		OIDCName:                   "",
+		OIDCEndpoint:               "",
+		OIDCClientID:               "",
+		OIDCScope:                  "openid,profile,email,offline_access",
+		OIDCGroupsClaim:            "",
 	}
 
 	HarborNumKeysMap = map[string]int{
@@ -193,6 +214,7 @@ var (
 		SelfRegistration: true,
 		LDAPVerifyCert:   true,
 		UAAVerifyCert:    true,
+		OIDCVerifyCert:   true,
 		ReadOnly:         false,
 	}
 
@@ -200,5 +222,6 @@ var (
 		EmailPassword,
 		LDAPSearchPwd,
 		UAAClientSecret,
+		OIDCClientSecret,
 	}
 )
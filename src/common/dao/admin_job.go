@@ -88,6 +88,20 @@ func SetAdminJobUUID(id int64, uuid string) error {
 	return err
 }
 
+// SetAdminJobReport sets the report of the admin job
+func SetAdminJobReport(id int64, report string) error {
+	o := GetOrmer()
+	j := models.AdminJob{
+		ID:     id,
+		Report: report,
+	}
+	n, err := o.Update(&j, "Report")
+	if n == 0 {
+		log.Warningf("no records are updated when updating admin job %d", id)
+	}
+	return err
+}
+
 // GetTop10AdminJobsOfName ...
 func GetTop10AdminJobsOfName(name string) ([]*models.AdminJob, error) {
 	o := GetOrmer()
@@ -57,6 +57,13 @@ func TestAddAdminJob(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, job3.UUID, "f5ef34f4cb3588d663176132")
 
+	// set report
+	err = SetAdminJobReport(id, `{"blobs_deleted":1}`)
+	require.Nil(t, err)
+	job4, err := GetAdminJob(id)
+	require.Nil(t, err)
+	assert.Equal(t, job4.Report, `{"blobs_deleted":1}`)
+
 	// get admin jobs
 	_, err = AddAdminJob(job)
 	require.Nil(t, err)
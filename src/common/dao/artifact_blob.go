@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddArtifactBlobs records the blobs referenced by the manifests, the existing records are ignored
+func AddArtifactBlobs(blobs []*models.ArtifactBlob) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	sql := `insert into artifact_blob (repository_name, digest_af, digest_blob, size)
+		values (?, ?, ?, ?)
+		on conflict (repository_name, digest_af, digest_blob) do nothing`
+	p, err := GetOrmer().Raw(sql).Prepare()
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	for _, blob := range blobs {
+		if _, err := p.Exec(blob.Repository, blob.DigestAF, blob.DigestBlob, blob.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetArtifactBlobs returns the blobs referenced by the manifests under the repository,
+// the records of all repositories are returned if the repository is empty
+func GetArtifactBlobs(repository string) ([]*models.ArtifactBlob, error) {
+	blobs := []*models.ArtifactBlob{}
+	qs := GetOrmer().QueryTable(&models.ArtifactBlob{})
+	if len(repository) > 0 {
+		qs = qs.Filter("Repository", repository)
+	}
+	_, err := qs.OrderBy("id").All(&blobs)
+	return blobs, err
+}
+
+// GetArtifactBlobsByDigest returns the records referencing the blob in the repository
+func GetArtifactBlobsByDigest(repository, digest string) ([]*models.ArtifactBlob, error) {
+	blobs := []*models.ArtifactBlob{}
+	_, err := GetOrmer().QueryTable(&models.ArtifactBlob{}).
+		Filter("Repository", repository).
+		Filter("DigestBlob", digest).
+		All(&blobs)
+	return blobs, err
+}
+
+// ArtifactBlobExistsInProject checks whether the blob is referenced by the manifests
+// of any repository under the project
+func ArtifactBlobExistsInProject(project, digest string) (bool, error) {
+	n, err := GetOrmer().QueryTable(&models.ArtifactBlob{}).
+		Filter("Repository__startswith", project+"/").
+		Filter("DigestBlob", digest).
+		Count()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetTotalOfArtifactBlobSize returns the sum of the sizes of the blobs referenced
+// by the manifests, the blobs shared by different manifests are counted only once
+func GetTotalOfArtifactBlobSize() (int64, error) {
+	sql := `select coalesce(sum(size), 0) from
+		(select max(size) as size from artifact_blob group by digest_blob) as blobs`
+	var total int64
+	if err := GetOrmer().Raw(sql).QueryRow(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// DeleteArtifactBlobs deletes the records of the manifest under the repository
+func DeleteArtifactBlobs(repository, digestAF string) error {
+	_, err := GetOrmer().QueryTable(&models.ArtifactBlob{}).
+		Filter("Repository", repository).
+		Filter("DigestAF", digestAF).
+		Delete()
+	return err
+}
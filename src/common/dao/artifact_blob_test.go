@@ -0,0 +1,75 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactBlobDaoMethods(t *testing.T) {
+	repository := "library/artifact_blob_test"
+	defer DeleteArtifactBlobs(repository, "sha256:manifest_a")
+	defer DeleteArtifactBlobs(repository, "sha256:manifest_b")
+
+	blobs := []*models.ArtifactBlob{
+		{Repository: repository, DigestAF: "sha256:manifest_a", DigestBlob: "sha256:config_a", Size: 10},
+		{Repository: repository, DigestAF: "sha256:manifest_a", DigestBlob: "sha256:layer", Size: 100},
+		{Repository: repository, DigestAF: "sha256:manifest_b", DigestBlob: "sha256:config_b", Size: 20},
+		{Repository: repository, DigestAF: "sha256:manifest_b", DigestBlob: "sha256:layer", Size: 100},
+	}
+	total, err := GetTotalOfArtifactBlobSize()
+	require.Nil(t, err)
+	require.Nil(t, AddArtifactBlobs(blobs))
+	// the existing records are ignored
+	require.Nil(t, AddArtifactBlobs(blobs[:1]))
+
+	// get the blobs of the repository
+	records, err := GetArtifactBlobs(repository)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(records))
+	assert.Equal(t, "sha256:config_a", records[0].DigestBlob)
+	assert.Equal(t, int64(10), records[0].Size)
+
+	// get the records referencing the blob
+	records, err = GetArtifactBlobsByDigest(repository, "sha256:layer")
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+
+	// the shared layer is counted once
+	n, err := GetTotalOfArtifactBlobSize()
+	require.Nil(t, err)
+	assert.Equal(t, total+130, n)
+
+	// check whether the blob is referenced in the project
+	exist, err := ArtifactBlobExistsInProject("library", "sha256:layer")
+	require.Nil(t, err)
+	assert.True(t, exist)
+	exist, err = ArtifactBlobExistsInProject("non_existing_project", "sha256:layer")
+	require.Nil(t, err)
+	assert.False(t, exist)
+
+	// delete the records of the manifest
+	require.Nil(t, DeleteArtifactBlobs(repository, "sha256:manifest_a"))
+	records, err = GetArtifactBlobs(repository)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(records))
+	for _, record := range records {
+		assert.Equal(t, "sha256:manifest_b", record.DigestAF)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// IncreaseChartDownloadCount increases the download count of the chart version,
+// the record is created if it doesn't exist
+func IncreaseChartDownloadCount(projectID int64, chart, version string) error {
+	sql := `insert into chart_download (project_id, chart, version, download_count)
+		values (?, ?, ?, 1)
+		on conflict (project_id, chart, version)
+		do update set download_count = chart_download.download_count + 1`
+	_, err := GetOrmer().Raw(sql, projectID, chart, version).Exec()
+	return err
+}
+
+// GetChartDownloads returns the download counts of the versions of the chart
+// in the project, the key of the returned map is the version
+func GetChartDownloads(projectID int64, chart string) (map[string]int64, error) {
+	downloads := []*models.ChartDownload{}
+	_, err := GetOrmer().QueryTable(&models.ChartDownload{}).
+		Filter("ProjectID", projectID).
+		Filter("Chart", chart).
+		All(&downloads)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{}
+	for _, download := range downloads {
+		counts[download.Version] = download.DownloadCount
+	}
+	return counts, nil
+}
+
+// GetTopCharts returns the most downloaded charts whose project ID is in projectIDs
+func GetTopCharts(projectIDs []int64, n int) ([]*models.ChartDownloadSummary, error) {
+	charts := []*models.ChartDownloadSummary{}
+	if len(projectIDs) == 0 {
+		return charts, nil
+	}
+
+	sql := fmt.Sprintf(`select project_id, chart, sum(download_count) as download_count
+		from chart_download
+		where project_id in (%s)
+		group by project_id, chart
+		order by download_count desc, chart asc
+		limit ?`, paramPlaceholder(len(projectIDs)))
+	_, err := GetOrmer().Raw(sql, projectIDs, n).QueryRows(&charts)
+	return charts, err
+}
+
+// GetTotalOfChartDownloads returns the total download count of the charts
+// whose project ID is in projectIDs, all charts are counted if no project ID specified
+func GetTotalOfChartDownloads(projectIDs ...int64) (int64, error) {
+	sql := `select coalesce(sum(download_count), 0) from chart_download`
+	params := []interface{}{}
+	if len(projectIDs) > 0 {
+		sql += fmt.Sprintf(` where project_id in (%s)`, paramPlaceholder(len(projectIDs)))
+		params = append(params, projectIDs)
+	}
+
+	var total int64
+	if err := GetOrmer().Raw(sql, params).QueryRow(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// DeleteChartDownloads deletes the download counts of the chart in the project,
+// only the specified version is deleted if the version isn't empty
+func DeleteChartDownloads(projectID int64, chart, version string) error {
+	qs := GetOrmer().QueryTable(&models.ChartDownload{}).
+		Filter("ProjectID", projectID).
+		Filter("Chart", chart)
+	if len(version) > 0 {
+		qs = qs.Filter("Version", version)
+	}
+	_, err := qs.Delete()
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartDownloadDaoMethods(t *testing.T) {
+	var projectID int64 = 1
+	defer DeleteChartDownloads(projectID, "chart_download_a", "")
+	defer DeleteChartDownloads(projectID, "chart_download_b", "")
+
+	require.Nil(t, IncreaseChartDownloadCount(projectID, "chart_download_a", "0.1.0"))
+	require.Nil(t, IncreaseChartDownloadCount(projectID, "chart_download_a", "0.1.0"))
+	require.Nil(t, IncreaseChartDownloadCount(projectID, "chart_download_a", "0.2.0"))
+	require.Nil(t, IncreaseChartDownloadCount(projectID, "chart_download_b", "1.0.0"))
+
+	// get downloads of chart versions
+	counts, err := GetChartDownloads(projectID, "chart_download_a")
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), counts["0.1.0"])
+	assert.Equal(t, int64(1), counts["0.2.0"])
+
+	// get top charts
+	charts, err := GetTopCharts([]int64{projectID}, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(charts))
+	assert.Equal(t, "chart_download_a", charts[0].Chart)
+	assert.Equal(t, int64(3), charts[0].DownloadCount)
+
+	charts, err = GetTopCharts([]int64{}, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(charts))
+
+	// get total of downloads
+	total, err := GetTotalOfChartDownloads(projectID)
+	require.Nil(t, err)
+	assert.Equal(t, int64(4), total)
+
+	// delete the downloads of one version
+	require.Nil(t, DeleteChartDownloads(projectID, "chart_download_a", "0.1.0"))
+	counts, err = GetChartDownloads(projectID, "chart_download_a")
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(counts))
+	assert.Equal(t, int64(1), counts["0.2.0"])
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddConfigRevision adds a revision of the configurations
+func AddConfigRevision(revision *models.ConfigRevision) (int64, error) {
+	if revision.Changes != nil {
+		data, err := json.Marshal(revision.Changes)
+		if err != nil {
+			return 0, err
+		}
+		revision.Diff = string(data)
+	}
+	return GetOrmer().Insert(revision)
+}
+
+// GetConfigRevision returns the revision specified by ID, nil is returned if it doesn't exist
+func GetConfigRevision(id int64) (*models.ConfigRevision, error) {
+	revision := &models.ConfigRevision{
+		ID: id,
+	}
+	if err := GetOrmer().Read(revision); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := parseConfigChanges(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// UpdateConfigRevisionSnapshot updates the snapshot of the revision, it's used to
+// re-encrypt the secrets in the snapshot with a new key
+func UpdateConfigRevisionSnapshot(id int64, snapshot string) error {
+	_, err := GetOrmer().Update(&models.ConfigRevision{
+		ID:       id,
+		Snapshot: snapshot,
+	}, "Snapshot")
+	return err
+}
+
+// GetTotalOfConfigRevisions returns the total count of the revisions
+func GetTotalOfConfigRevisions(query *models.ConfigRevisionQuery) (int64, error) {
+	return getConfigRevisionQuerySetter(query).Count()
+}
+
+// ListConfigRevisions lists the revisions according to the query conditions, the latest first
+func ListConfigRevisions(query *models.ConfigRevisionQuery) ([]*models.ConfigRevision, error) {
+	qs := getConfigRevisionQuerySetter(query)
+	if query != nil && query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	revisions := []*models.ConfigRevision{}
+	if _, err := qs.OrderBy("-ID").All(&revisions); err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		if err := parseConfigChanges(revision); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+func getConfigRevisionQuerySetter(query *models.ConfigRevisionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.ConfigRevision{})
+	if query == nil {
+		return qs
+	}
+	if len(query.Operator) > 0 {
+		qs = qs.Filter("Operator", query.Operator)
+	}
+	if len(query.Key) > 0 {
+		qs = qs.Filter("Diff__contains", fmt.Sprintf(`"key":"%s"`, query.Key))
+	}
+	return qs
+}
+
+func parseConfigChanges(revision *models.ConfigRevision) error {
+	revision.Changes = []*models.ConfigChange{}
+	if len(revision.Diff) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(revision.Diff), &revision.Changes)
+}
@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRevisionDaoMethods(t *testing.T) {
+	operator := "config_revision_test"
+	defer GetOrmer().Raw("delete from config_revision where operator = ?", operator).Exec()
+
+	id1, err := AddConfigRevision(&models.ConfigRevision{
+		Operator: operator,
+		Changes: []*models.ConfigChange{
+			{Key: "auth_mode", Old: "db_auth", New: "ldap_auth"},
+			{Key: "ldap_search_password", Secret: true},
+		},
+		Snapshot: `{"auth_mode":"ldap_auth"}`,
+	})
+	require.Nil(t, err)
+	id2, err := AddConfigRevision(&models.ConfigRevision{
+		Operator: operator,
+		Comment:  "rollback",
+		Changes: []*models.ConfigChange{
+			{Key: "self_registration", Old: true, New: false},
+		},
+		Snapshot: `{"self_registration":false}`,
+	})
+	require.Nil(t, err)
+
+	// get
+	revision, err := GetConfigRevision(id1)
+	require.Nil(t, err)
+	require.NotNil(t, revision)
+	assert.Equal(t, operator, revision.Operator)
+	require.Equal(t, 2, len(revision.Changes))
+	assert.Equal(t, "ldap_auth", revision.Changes[0].New)
+	assert.True(t, revision.Changes[1].Secret)
+	assert.Nil(t, revision.Changes[1].New)
+
+	// update the snapshot
+	require.Nil(t, UpdateConfigRevisionSnapshot(id1, `{"auth_mode":"oidc_auth"}`))
+	revision, err = GetConfigRevision(id1)
+	require.Nil(t, err)
+	assert.Equal(t, `{"auth_mode":"oidc_auth"}`, revision.Snapshot)
+	assert.Equal(t, 2, len(revision.Changes))
+
+	revision, err = GetConfigRevision(0)
+	require.Nil(t, err)
+	assert.Nil(t, revision)
+
+	// list, the latest first
+	query := &models.ConfigRevisionQuery{Operator: operator}
+	total, err := GetTotalOfConfigRevisions(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	revisions, err := ListConfigRevisions(query)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(revisions))
+	assert.Equal(t, id2, revisions[0].ID)
+	assert.Equal(t, id1, revisions[1].ID)
+
+	// list the revisions changing the key
+	query.Key = "auth_mode"
+	revisions, err = ListConfigRevisions(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(revisions))
+	assert.Equal(t, id1, revisions[0].ID)
+
+	// paging
+	query = &models.ConfigRevisionQuery{
+		Operator: operator,
+		Pagination: models.Pagination{
+			Page: 2,
+			Size: 1,
+		},
+	}
+	revisions, err = ListConfigRevisions(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(revisions))
+	assert.Equal(t, id1, revisions[0].ID)
+}
@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// GetCVEAllowlist returns the CVE allowlist of the project, the system allowlist
+// is returned if the project ID is 0, nil is returned if the allowlist is not set
+func GetCVEAllowlist(projectID int64) (*models.CVEAllowlist, error) {
+	allowlist := &models.CVEAllowlist{}
+	err := GetOrmer().QueryTable(&models.CVEAllowlist{}).
+		Filter("ProjectID", projectID).One(allowlist)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err = allowlist.Unmarshal(); err != nil {
+		return nil, err
+	}
+	return allowlist, nil
+}
+
+// SetCVEAllowlist creates or updates the CVE allowlist of the project
+func SetCVEAllowlist(allowlist *models.CVEAllowlist) error {
+	if err := allowlist.Marshal(); err != nil {
+		return err
+	}
+	current, err := GetCVEAllowlist(allowlist.ProjectID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	allowlist.UpdateTime = now
+	if current == nil {
+		allowlist.CreationTime = now
+		allowlist.ID, err = GetOrmer().Insert(allowlist)
+		return err
+	}
+	allowlist.ID = current.ID
+	_, err = GetOrmer().Update(allowlist, "ExpiresAt", "ItemsDB", "UpdateTime")
+	return err
+}
+
+// DeleteCVEAllowlist deletes the CVE allowlist of the project
+func DeleteCVEAllowlist(projectID int64) error {
+	_, err := GetOrmer().QueryTable(&models.CVEAllowlist{}).
+		Filter("ProjectID", projectID).Delete()
+	return err
+}
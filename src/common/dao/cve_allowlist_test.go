@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCVEAllowlistDaoMethods(t *testing.T) {
+	// the allowlist is not set
+	l, err := GetCVEAllowlist(1)
+	require.Nil(t, err)
+	assert.Nil(t, l)
+
+	// test create
+	allowlist := &models.CVEAllowlist{
+		ProjectID: 1,
+		Items: []models.CVEAllowlistItem{
+			{CVEID: "CVE-2019-1234"},
+		},
+	}
+	require.Nil(t, SetCVEAllowlist(allowlist))
+	defer DeleteCVEAllowlist(1)
+	l, err = GetCVEAllowlist(1)
+	require.Nil(t, err)
+	require.NotNil(t, l)
+	require.Equal(t, 1, len(l.Items))
+	assert.Equal(t, "CVE-2019-1234", l.Items[0].CVEID)
+	assert.Equal(t, int64(0), l.ExpiresAt)
+
+	// test update
+	allowlist = &models.CVEAllowlist{
+		ProjectID: 1,
+		ExpiresAt: 1893456000,
+		Items: []models.CVEAllowlistItem{
+			{CVEID: "CVE-2019-1234"},
+			{CVEID: "CVE-2019-5678"},
+		},
+	}
+	require.Nil(t, SetCVEAllowlist(allowlist))
+	l, err = GetCVEAllowlist(1)
+	require.Nil(t, err)
+	require.NotNil(t, l)
+	assert.Equal(t, allowlist.ID, l.ID)
+	assert.Equal(t, 2, len(l.Items))
+	assert.Equal(t, int64(1893456000), l.ExpiresAt)
+
+	// test delete
+	require.Nil(t, DeleteCVEAllowlist(1))
+	l, err = GetCVEAllowlist(1)
+	require.Nil(t, err)
+	assert.Nil(t, l)
+}
@@ -140,17 +140,18 @@ func OnBoardUserGroup(g *models.UserGroup, keyAttribute string, combinedKeyAttri
 	return nil
 }
 
-// GetGroupDNQueryCondition get the part of IN ('XXX', 'XXX') condition
+// GetGroupDNQueryCondition get the part of IN ('XXX', 'XXX') condition, both LDAP groups and
+// OIDC groups are keyed by the ldap_group_dn column
 func GetGroupDNQueryCondition(userGroupList []*models.UserGroup) string {
 	result := make([]string, 0)
 	count := 0
 	for _, userGroup := range userGroupList {
-		if userGroup.GroupType == common.LdapGroupType {
-			result = append(result, "'"+userGroup.LdapGroupDN+"'")
+		if userGroup.GroupType == common.LdapGroupType || userGroup.GroupType == common.OIDCGroupType {
+			result = append(result, "'"+strings.Replace(userGroup.LdapGroupDN, "'", "''", -1)+"'")
 			count++
 		}
 	}
-	// No LDAP Group found
+	// No group found
 	if count == 0 {
 		return ""
 	}
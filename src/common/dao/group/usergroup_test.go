@@ -273,6 +273,22 @@ func TestGetGroupDNQueryCondition(t *testing.T) {
 	if groupQueryConditions != expectedConditions {
 		t.Errorf("Failed to GetGroupDNQueryCondition, expected %v, actual %v", expectedConditions, groupQueryConditions)
 	}
+	oidcGroupList := []*models.UserGroup{
+		{
+			GroupName:   "dev",
+			GroupType:   common.OIDCGroupType,
+			LdapGroupDN: "dev",
+		},
+		{
+			GroupName:   "o'ops",
+			GroupType:   common.OIDCGroupType,
+			LdapGroupDN: "o'ops",
+		},
+	}
+	oidcConditions := GetGroupDNQueryCondition(oidcGroupList)
+	if expected := `'dev','o''ops'`; oidcConditions != expected {
+		t.Errorf("Failed to GetGroupDNQueryCondition, expected %v, actual %v", expected, oidcConditions)
+	}
 	var userGroupList2 []*models.UserGroup
 	groupQueryCondition2 := GetGroupDNQueryCondition(userGroupList2)
 	if len(groupQueryCondition2) > 0 {
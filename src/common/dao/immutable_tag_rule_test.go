@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableTagRuleDaoMethods(t *testing.T) {
+	rule := &models.ImmutableTagRule{
+		ProjectID:   1,
+		RepoPattern: "*",
+		TagPattern:  "v*",
+	}
+	// test add
+	id, err := AddImmutableTagRule(rule)
+	require.Nil(t, err)
+	defer DeleteImmutableTagRule(id)
+
+	// test get
+	r, err := GetImmutableTagRule(id)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, "v*", r.TagPattern)
+
+	// test list
+	rules, err := ListImmutableTagRules(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(rules))
+	assert.Equal(t, id, rules[0].ID)
+
+	// test update
+	rule.TagPattern = "release-*"
+	require.Nil(t, UpdateImmutableTagRule(rule))
+	r, err = GetImmutableTagRule(id)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, "release-*", r.TagPattern)
+
+	// test delete
+	require.Nil(t, DeleteImmutableTagRulesOfProject(1))
+	r, err = GetImmutableTagRule(id)
+	require.Nil(t, err)
+	assert.Nil(t, r)
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddOIDCUser links the user to the subject of the OIDC provider
+func AddOIDCUser(user *models.OIDCUser) (int64, error) {
+	now := time.Now()
+	user.CreationTime = now
+	user.UpdateTime = now
+	return GetOrmer().Insert(user)
+}
+
+// GetOIDCUserByUserID returns the OIDC user linked to the Harbor user
+func GetOIDCUserByUserID(userID int) (*models.OIDCUser, error) {
+	return getOIDCUser("UserID", userID)
+}
+
+// GetOIDCUserBySubIss returns the OIDC user specified by the subject and issuer
+func GetOIDCUserBySubIss(subiss string) (*models.OIDCUser, error) {
+	return getOIDCUser("SubIss", subiss)
+}
+
+func getOIDCUser(field string, value interface{}) (*models.OIDCUser, error) {
+	user := &models.OIDCUser{}
+	err := GetOrmer().QueryTable(&models.OIDCUser{}).Filter(field, value).One(user)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateOIDCUser updates the specified columns of the OIDC user
+func UpdateOIDCUser(user *models.OIDCUser, cols ...string) error {
+	user.UpdateTime = time.Now()
+	if len(cols) > 0 {
+		cols = append(cols, "UpdateTime")
+	}
+	_, err := GetOrmer().Update(user, cols...)
+	return err
+}
+
+// DeleteOIDCUser deletes the OIDC user
+func DeleteOIDCUser(id int64) error {
+	_, err := GetOrmer().Delete(&models.OIDCUser{
+		ID: id,
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCUserDaoMethods(t *testing.T) {
+	user := &models.OIDCUser{
+		UserID: 1,
+		SubIss: "subject" + "https://oidc.test",
+		Secret: "secret",
+	}
+	// test add
+	id, err := AddOIDCUser(user)
+	require.Nil(t, err)
+	defer DeleteOIDCUser(id)
+
+	// test get by user ID
+	u, err := GetOIDCUserByUserID(1)
+	require.Nil(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, id, u.ID)
+
+	// test get by subject and issuer
+	u, err = GetOIDCUserBySubIss("subjecthttps://oidc.test")
+	require.Nil(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, 1, u.UserID)
+
+	// test update
+	user.Secret = "new-secret"
+	require.Nil(t, UpdateOIDCUser(user, "Secret"))
+	u, err = GetOIDCUserByUserID(1)
+	require.Nil(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, "new-secret", u.Secret)
+
+	// test delete
+	require.Nil(t, DeleteOIDCUser(id))
+	u, err = GetOIDCUserBySubIss("subjecthttps://oidc.test")
+	require.Nil(t, err)
+	assert.Nil(t, u)
+}
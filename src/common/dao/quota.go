@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// GetProjectUsage returns the cached usage of the project,
+// nil will be returned if the usage has not been calculated
+func GetProjectUsage(projectID int64) (*models.ProjectUsage, error) {
+	usage := &models.ProjectUsage{
+		ProjectID: projectID,
+	}
+	if err := GetOrmer().Read(usage); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return usage, nil
+}
+
+// SetProjectUsage creates or updates the usage of the project
+func SetProjectUsage(usage *models.ProjectUsage) error {
+	_, err := GetOrmer().InsertOrUpdate(usage, "project_id")
+	return err
+}
+
+// DeleteProjectUsage deletes the usage of the project
+func DeleteProjectUsage(projectID int64) error {
+	_, err := GetOrmer().Delete(&models.ProjectUsage{
+		ProjectID: projectID,
+	})
+	return err
+}
+
+// IncreaseProjectUsage adds the deltas to the usage of the project, the negative deltas
+// decrease the usage which never goes below 0. Nothing is changed if the usage of the
+// project has not been calculated
+func IncreaseProjectUsage(projectID, storage, repositoryCount, tagCount int64) error {
+	sql := `update project_usage set
+		storage = greatest(storage + ?, 0),
+		repository_count = greatest(repository_count + ?, 0),
+		tag_count = greatest(tag_count + ?, 0),
+		update_time = ?
+		where project_id = ?`
+	_, err := GetOrmer().Raw(sql, storage, repositoryCount, tagCount, time.Now(), projectID).Exec()
+	return err
+}
+
+// ReserveProjectUsage adds the positive deltas to the usage of the project only when the
+// usage doesn't go over the quota after that, the check and the update are done in one
+// statement so that the concurrent reservations, even from different core instances,
+// can't go over the quota together. The negative quota means unlimited. False is returned
+// if nothing is updated, either the quota is exceeded or the usage doesn't exist
+func ReserveProjectUsage(projectID, storage, repositoryCount, tagCount int64, quota *models.ProjectQuota) (bool, error) {
+	sql := `update project_usage set
+		storage = storage + ?,
+		repository_count = repository_count + ?,
+		tag_count = tag_count + ?,
+		update_time = ?
+		where project_id = ?
+		and (? <= 0 or ? < 0 or storage + ? <= ?)
+		and (? <= 0 or ? < 0 or repository_count + ? <= ?)
+		and (? <= 0 or ? < 0 or tag_count + ? <= ?)`
+	result, err := GetOrmer().Raw(sql, storage, repositoryCount, tagCount, time.Now(), projectID,
+		storage, quota.Storage, storage, quota.Storage,
+		repositoryCount, quota.RepositoryCount, repositoryCount, quota.RepositoryCount,
+		tagCount, quota.TagCount, tagCount, quota.TagCount).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,101 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectUsageDaoMethods(t *testing.T) {
+	// not calculated
+	usage, err := GetProjectUsage(1)
+	require.Nil(t, err)
+	assert.Nil(t, usage)
+
+	// test create
+	require.Nil(t, SetProjectUsage(&models.ProjectUsage{
+		ProjectID:       1,
+		Storage:         1024,
+		RepositoryCount: 1,
+		TagCount:        2,
+		UpdateTime:      time.Now(),
+	}))
+	defer func() {
+		// clean up
+		require.Nil(t, DeleteProjectUsage(1))
+	}()
+	usage, err = GetProjectUsage(1)
+	require.Nil(t, err)
+	require.NotNil(t, usage)
+	assert.Equal(t, int64(1024), usage.Storage)
+
+	// test update
+	require.Nil(t, SetProjectUsage(&models.ProjectUsage{
+		ProjectID:       1,
+		Storage:         2048,
+		RepositoryCount: 1,
+		TagCount:        3,
+		UpdateTime:      time.Now(),
+	}))
+	usage, err = GetProjectUsage(1)
+	require.Nil(t, err)
+	require.NotNil(t, usage)
+	assert.Equal(t, int64(2048), usage.Storage)
+	assert.Equal(t, int64(3), usage.TagCount)
+
+	// increase and decrease
+	require.Nil(t, IncreaseProjectUsage(1, 1024, 1, 1))
+	usage, err = GetProjectUsage(1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(3072), usage.Storage)
+	assert.Equal(t, int64(2), usage.RepositoryCount)
+	assert.Equal(t, int64(4), usage.TagCount)
+	require.Nil(t, IncreaseProjectUsage(1, -4096, 0, -1))
+	usage, err = GetProjectUsage(1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), usage.Storage)
+	assert.Equal(t, int64(3), usage.TagCount)
+
+	// reserve within and over the quota
+	quota := &models.ProjectQuota{
+		Storage:         1024,
+		RepositoryCount: models.QuotaUnlimited,
+		TagCount:        4,
+	}
+	reserved, err := ReserveProjectUsage(1, 1024, 1, 1, quota)
+	require.Nil(t, err)
+	assert.True(t, reserved)
+	reserved, err = ReserveProjectUsage(1, 0, 1, 1, quota)
+	require.Nil(t, err)
+	assert.False(t, reserved)
+	reserved, err = ReserveProjectUsage(1, 0, 1, 0, quota)
+	require.Nil(t, err)
+	assert.True(t, reserved)
+	usage, err = GetProjectUsage(1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1024), usage.Storage)
+	assert.Equal(t, int64(4), usage.RepositoryCount)
+	assert.Equal(t, int64(4), usage.TagCount)
+
+	// the usage doesn't exist
+	reserved, err = ReserveProjectUsage(10000, 1, 0, 0, quota)
+	require.Nil(t, err)
+	assert.False(t, reserved)
+}
@@ -28,10 +28,15 @@ import (
 func AddRepTarget(target models.RepTarget) (int64, error) {
 	o := GetOrmer()
 
-	sql := "insert into replication_target (name, url, username, password, insecure, target_type) values (?, ?, ?, ?, ?, ?) RETURNING id"
+	sql := "insert into replication_target (name, url, username, password, insecure, target_type, registry_type) values (?, ?, ?, ?, ?, ?, ?) RETURNING id"
+
+	registryType := target.RegistryType
+	if len(registryType) == 0 {
+		registryType = models.RegistryTypeHarbor
+	}
 
 	var targetID int64
-	err := o.Raw(sql, target.Name, target.URL, target.Username, target.Password, target.Insecure, target.Type).QueryRow(&targetID)
+	err := o.Raw(sql, target.Name, target.URL, target.Username, target.Password, target.Insecure, target.Type, registryType).QueryRow(&targetID)
 	if err != nil {
 		return 0, err
 	}
@@ -85,10 +90,15 @@ func UpdateRepTarget(target models.RepTarget) error {
 	o := GetOrmer()
 
 	sql := `update replication_target 
-	set url = ?, name = ?, username = ?, password = ?, insecure = ?, update_time = ?
+	set url = ?, name = ?, username = ?, password = ?, insecure = ?, registry_type = ?, update_time = ?
 	where id = ?`
 
-	_, err := o.Raw(sql, target.URL, target.Name, target.Username, target.Password, target.Insecure, time.Now(), target.ID).Exec()
+	registryType := target.RegistryType
+	if len(registryType) == 0 {
+		registryType = models.RegistryTypeHarbor
+	}
+
+	_, err := o.Raw(sql, target.URL, target.Name, target.Username, target.Password, target.Insecure, registryType, time.Now(), target.ID).Exec()
 
 	return err
 }
@@ -118,14 +128,14 @@ func FilterRepTargets(name string) ([]*models.RepTarget, error) {
 // AddRepPolicy ...
 func AddRepPolicy(policy models.RepPolicy) (int64, error) {
 	o := GetOrmer()
-	sql := `insert into replication_policy (name, project_id, target_id, enabled, description, cron_str, creation_time, update_time, filters, replicate_deletion) 
-				values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	sql := `insert into replication_policy (name, project_id, target_id, enabled, description, cron_str, creation_time, update_time, filters, replicate_deletion, direction) 
+				values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
 	params := []interface{}{}
 	now := time.Now()
 
 	params = append(params, policy.Name, policy.ProjectID, policy.TargetID, true,
 		policy.Description, policy.Trigger, now, now, policy.Filters,
-		policy.ReplicateDeletion)
+		policy.ReplicateDeletion, repPolicyDirection(policy))
 
 	var policyID int64
 	err := o.Raw(sql, params...).QueryRow(&policyID)
@@ -276,14 +286,22 @@ func UpdateRepPolicy(policy *models.RepPolicy) error {
 	o := GetOrmer()
 
 	sql := `update replication_policy 
-		set project_id = ?, target_id = ?, name = ?, description = ?, cron_str = ?, filters = ?, replicate_deletion = ?, update_time = ? 
+		set project_id = ?, target_id = ?, name = ?, description = ?, cron_str = ?, filters = ?, replicate_deletion = ?, direction = ?, update_time = ? 
 		where id = ?`
 
-	_, err := o.Raw(sql, policy.ProjectID, policy.TargetID, policy.Name, policy.Description, policy.Trigger, policy.Filters, policy.ReplicateDeletion, time.Now(), policy.ID).Exec()
+	_, err := o.Raw(sql, policy.ProjectID, policy.TargetID, policy.Name, policy.Description, policy.Trigger, policy.Filters, policy.ReplicateDeletion, repPolicyDirection(*policy), time.Now(), policy.ID).Exec()
 
 	return err
 }
 
+// the policies are push-based if the direction isn't specified
+func repPolicyDirection(policy models.RepPolicy) string {
+	if len(policy.Direction) == 0 {
+		return models.RepDirectionPush
+	}
+	return policy.Direction
+}
+
 // DeleteRepPolicy ...
 func DeleteRepPolicy(id int64) error {
 	_, err := GetOrmer().Delete(&models.RepPolicy{
@@ -0,0 +1,170 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRetentionPolicy creates the tag retention policy
+func AddRetentionPolicy(policy *models.RetentionPolicy) (int64, error) {
+	now := time.Now()
+	policy.CreationTime = now
+	policy.UpdateTime = now
+	return GetOrmer().Insert(policy)
+}
+
+// GetRetentionPolicy returns the tag retention policy specified by ID
+func GetRetentionPolicy(id int64) (*models.RetentionPolicy, error) {
+	policy := &models.RetentionPolicy{
+		ID: id,
+	}
+	if err := GetOrmer().Read(policy); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetRetentionPolicyByProject returns the tag retention policy of the project,
+// nil will be returned if the project doesn't have one
+func GetRetentionPolicyByProject(projectID int64) (*models.RetentionPolicy, error) {
+	policy := &models.RetentionPolicy{
+		ProjectID: projectID,
+	}
+	if err := GetOrmer().Read(policy, "ProjectID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateRetentionPolicy updates the rules, schedule and job UUID of the tag retention policy
+func UpdateRetentionPolicy(policy *models.RetentionPolicy) error {
+	policy.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(policy, "Rules", "Schedule", "UUID", "UpdateTime")
+	return err
+}
+
+// DeleteRetentionPolicy deletes the tag retention policy and its executions
+func DeleteRetentionPolicy(id int64) error {
+	if _, err := GetOrmer().QueryTable(&models.RetentionExecution{}).
+		Filter("PolicyID", id).Delete(); err != nil {
+		return err
+	}
+	_, err := GetOrmer().Delete(&models.RetentionPolicy{ID: id})
+	return err
+}
+
+// AddRetentionExecution creates the execution of tag retention policy
+func AddRetentionExecution(execution *models.RetentionExecution) (int64, error) {
+	now := time.Now()
+	execution.CreationTime = now
+	execution.UpdateTime = now
+	return GetOrmer().Insert(execution)
+}
+
+// GetRetentionExecution returns the execution specified by ID
+func GetRetentionExecution(id int64) (*models.RetentionExecution, error) {
+	execution := &models.RetentionExecution{
+		ID: id,
+	}
+	if err := GetOrmer().Read(execution); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return execution, nil
+}
+
+// GetRetentionExecutionByUUID returns the execution specified by the UUID of job
+func GetRetentionExecutionByUUID(uuid string) (*models.RetentionExecution, error) {
+	execution := &models.RetentionExecution{
+		UUID: uuid,
+	}
+	if err := GetOrmer().Read(execution, "UUID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return execution, nil
+}
+
+// GetTotalOfRetentionExecutions returns the total count of executions
+func GetTotalOfRetentionExecutions(query *models.RetentionExecutionQuery) (int64, error) {
+	return getRetentionExecutionQuerySetter(query).Count()
+}
+
+// ListRetentionExecutions lists the executions according to the query conditions
+func ListRetentionExecutions(query *models.RetentionExecutionQuery) ([]*models.RetentionExecution, error) {
+	qs := getRetentionExecutionQuerySetter(query)
+	if query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	qs = qs.OrderBy("-CreationTime")
+
+	executions := []*models.RetentionExecution{}
+	_, err := qs.All(&executions)
+	return executions, err
+}
+
+func getRetentionExecutionQuerySetter(query *models.RetentionExecutionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.RetentionExecution{})
+	if query.PolicyID != 0 {
+		qs = qs.Filter("PolicyID", query.PolicyID)
+	}
+	return qs
+}
+
+// UpdateRetentionExecution updates the specified columns of the execution
+func UpdateRetentionExecution(execution *models.RetentionExecution, cols ...string) error {
+	execution.UpdateTime = time.Now()
+	cols = append(cols, "UpdateTime")
+	_, err := GetOrmer().Update(execution, cols...)
+	return err
+}
+
+// GetLatestOperationTimes returns the latest time of the operation on each tag of
+// the project recorded in access log, the key of the map is "repository:tag"
+func GetLatestOperationTimes(projectID int64, operation string) (map[string]time.Time, error) {
+	records := []*struct {
+		RepoName string    `orm:"column(repo_name)"`
+		RepoTag  string    `orm:"column(repo_tag)"`
+		OpTime   time.Time `orm:"column(op_time)"`
+	}{}
+	_, err := GetOrmer().Raw(`select repo_name, repo_tag, max(op_time) as op_time
+		from access_log where project_id = ? and operation = ?
+		group by repo_name, repo_tag`, projectID, operation).QueryRows(&records)
+	if err != nil {
+		return nil, err
+	}
+	times := map[string]time.Time{}
+	for _, record := range records {
+		times[record.RepoName+":"+record.RepoTag] = record.OpTime
+	}
+	return times, nil
+}
@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionDaoMethods(t *testing.T) {
+	policy := &models.RetentionPolicy{
+		ProjectID: 1,
+		Rules:     "[]",
+	}
+	// test add policy
+	policyID, err := AddRetentionPolicy(policy)
+	require.Nil(t, err)
+
+	// test get policy
+	p, err := GetRetentionPolicyByProject(1)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, policyID, p.ID)
+
+	p, err = GetRetentionPolicyByProject(10000)
+	require.Nil(t, err)
+	assert.Nil(t, p)
+
+	// test update policy
+	policy.Rules = `[{"kind":"latest_pushed","count":1}]`
+	policy.UUID = "uuid"
+	require.Nil(t, UpdateRetentionPolicy(policy))
+	p, err = GetRetentionPolicy(policyID)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, policy.Rules, p.Rules)
+	assert.Equal(t, "uuid", p.UUID)
+
+	// test add execution
+	execution := &models.RetentionExecution{
+		PolicyID: policyID,
+		UUID:     "execution_uuid",
+		DryRun:   true,
+		Status:   models.JobPending,
+	}
+	executionID, err := AddRetentionExecution(execution)
+	require.Nil(t, err)
+
+	// test get execution
+	e, err := GetRetentionExecution(executionID)
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.True(t, e.DryRun)
+
+	e, err = GetRetentionExecutionByUUID("execution_uuid")
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, executionID, e.ID)
+
+	// test list executions
+	query := &models.RetentionExecutionQuery{
+		PolicyID: policyID,
+	}
+	total, err := GetTotalOfRetentionExecutions(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	executions, err := ListRetentionExecutions(query)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(executions))
+
+	// test update execution
+	execution.Status = models.JobFinished
+	execution.Report = `{"total":1}`
+	require.Nil(t, UpdateRetentionExecution(execution, "Status", "Report"))
+	e, err = GetRetentionExecution(executionID)
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, models.JobFinished, e.Status)
+	assert.Equal(t, `{"total":1}`, e.Report)
+
+	// test delete
+	require.Nil(t, DeleteRetentionPolicy(policyID))
+	p, err = GetRetentionPolicy(policyID)
+	require.Nil(t, err)
+	assert.Nil(t, p)
+	e, err = GetRetentionExecution(executionID)
+	require.Nil(t, err)
+	assert.Nil(t, e)
+}
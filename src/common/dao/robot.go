@@ -0,0 +1,123 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddRobot creates a robot account
+func AddRobot(robot *models.Robot) (int64, error) {
+	now := time.Now()
+	robot.CreationTime = now
+	robot.UpdateTime = now
+	return GetOrmer().Insert(robot)
+}
+
+// GetRobot returns the robot account specified by ID
+func GetRobot(id int64) (*models.Robot, error) {
+	robot := &models.Robot{
+		ID: id,
+	}
+	if err := GetOrmer().Read(robot); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return robot, nil
+}
+
+// GetRobotByName returns the robot account specified by name in the project
+func GetRobotByName(projectID int64, name string) (*models.Robot, error) {
+	robot := &models.Robot{
+		Name:      name,
+		ProjectID: projectID,
+	}
+	if err := GetOrmer().Read(robot, "Name", "ProjectID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return robot, nil
+}
+
+// GetTotalOfRobots returns the total count of robot accounts
+func GetTotalOfRobots(query *models.RobotQuery) (int64, error) {
+	qs := getRobotQuerySetter(query)
+	return qs.Count()
+}
+
+// ListRobots list robot accounts according to the query conditions
+func ListRobots(query *models.RobotQuery) ([]*models.Robot, error) {
+	qs := getRobotQuerySetter(query)
+	if query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	qs = qs.OrderBy("Name")
+
+	robots := []*models.Robot{}
+	_, err := qs.All(&robots)
+	return robots, err
+}
+
+func getRobotQuerySetter(query *models.RobotQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.Robot{})
+	if len(query.Name) > 0 {
+		qs = qs.Filter("Name__icontains", query.Name)
+	}
+	if query.ProjectID != 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if query.Disabled != nil {
+		qs = qs.Filter("Disabled", *query.Disabled)
+	}
+	return qs
+}
+
+// DisableRobot disables or enables the robot account
+func DisableRobot(id int64, disabled bool) error {
+	robot := &models.Robot{
+		ID:         id,
+		Disabled:   disabled,
+		UpdateTime: time.Now(),
+	}
+	_, err := GetOrmer().Update(robot, "Disabled", "UpdateTime")
+	return err
+}
+
+// DeleteRobot revokes the robot account by deleting it
+func DeleteRobot(id int64) error {
+	_, err := GetOrmer().Delete(&models.Robot{
+		ID: id,
+	})
+	return err
+}
+
+// DeleteRobotsOfProject revokes all robot accounts of the project
+func DeleteRobotsOfProject(projectID int64) error {
+	_, err := GetOrmer().QueryTable(&models.Robot{}).
+		Filter("ProjectID", projectID).Delete()
+	return err
+}
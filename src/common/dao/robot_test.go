@@ -0,0 +1,87 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobotDaoMethods(t *testing.T) {
+	robot := &models.Robot{
+		Name:      "dao_test_robot",
+		ProjectID: 1,
+		Token:     "token",
+		Salt:      "salt",
+		Access:    "pull,push",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	// test add
+	id, err := AddRobot(robot)
+	require.Nil(t, err)
+	defer DeleteRobot(id)
+
+	// test get
+	r, err := GetRobot(id)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, robot.Name, r.Name)
+	assert.Equal(t, []string{"pull", "push"}, r.Actions())
+
+	r, err = GetRobotByName(robot.ProjectID, robot.Name)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, id, r.ID)
+
+	// the name is only unique in the project
+	r, err = GetRobotByName(robot.ProjectID+1, robot.Name)
+	require.Nil(t, err)
+	assert.Nil(t, r)
+
+	// test list
+	total, err := GetTotalOfRobots(&models.RobotQuery{
+		ProjectID: 1,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	robots, err := ListRobots(&models.RobotQuery{
+		Name: "dao_test",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(robots))
+
+	// test disable
+	require.Nil(t, DisableRobot(id, true))
+	r, err = GetRobot(id)
+	require.Nil(t, err)
+	assert.True(t, r.Disabled)
+	disabled := false
+	robots, err = ListRobots(&models.RobotQuery{
+		ProjectID: 1,
+		Disabled:  &disabled,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(robots))
+
+	// test delete
+	require.Nil(t, DeleteRobot(id))
+	r, err = GetRobot(id)
+	require.Nil(t, err)
+	assert.Nil(t, r)
+}
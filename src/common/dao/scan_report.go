@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// SetScanReport creates or updates the report of the image generated by the scanner
+func SetScanReport(report *models.ScanReport) error {
+	if err := report.Marshal(); err != nil {
+		return err
+	}
+	current, err := GetScanReport(report.Digest, report.Scanner)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	report.UpdateTime = now
+	if current == nil {
+		report.CreationTime = now
+		report.ID, err = GetOrmer().Insert(report)
+		return err
+	}
+	report.ID = current.ID
+	_, err = GetOrmer().Update(report, "JobID", "Sev", "ReportDB", "UpdateTime")
+	return err
+}
+
+// GetScanReport returns the report of the image generated by the scanner,
+// nil is returned if the image isn't scanned by the scanner
+func GetScanReport(digest, scanner string) (*models.ScanReport, error) {
+	return getScanReport(GetOrmer().QueryTable(&models.ScanReport{}).
+		Filter("Digest", digest).Filter("Scanner", scanner))
+}
+
+// GetLatestScanReport returns the latest updated report of the image
+func GetLatestScanReport(digest string) (*models.ScanReport, error) {
+	return getScanReport(GetOrmer().QueryTable(&models.ScanReport{}).
+		Filter("Digest", digest).OrderBy("-UpdateTime", "-ID").Limit(1))
+}
+
+func getScanReport(qs orm.QuerySeter) (*models.ScanReport, error) {
+	report := &models.ScanReport{}
+	if err := qs.One(report); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := report.Unmarshal(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListScanReports returns the reports of the image generated by all scanners
+func ListScanReports(digest string) ([]*models.ScanReport, error) {
+	reports := []*models.ScanReport{}
+	_, err := GetOrmer().QueryTable(&models.ScanReport{}).
+		Filter("Digest", digest).OrderBy("Scanner").All(&reports)
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range reports {
+		if err = report.Unmarshal(); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// DeleteScanReports deletes the reports of the image generated by all scanners
+func DeleteScanReports(digest string) error {
+	_, err := GetOrmer().QueryTable(&models.ScanReport{}).
+		Filter("Digest", digest).Delete()
+	return err
+}
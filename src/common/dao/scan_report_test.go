@@ -0,0 +1,88 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanReportDaoMethods(t *testing.T) {
+	digest := "sha256:0204dc6e09fa57ab99ac40e415eb637d62c8b2571ecbbc9ca0eb5e2ad2b5c56f"
+	defer DeleteScanReports(digest)
+
+	// the image is not scanned
+	r, err := GetScanReport(digest, "scanner1")
+	require.Nil(t, err)
+	assert.Nil(t, r)
+
+	// test create
+	report := &models.ScanReport{
+		Digest:  digest,
+		Scanner: "scanner1",
+		JobID:   1,
+		Sev:     int(models.SevHigh),
+		Report: &models.VulnerabilityReport{
+			Components: 1,
+			Vulnerabilities: []*models.VulnerabilityItem{
+				{ID: "CVE-2019-0001", Pkg: "openssl", Severity: models.SevHigh},
+			},
+		},
+	}
+	require.Nil(t, SetScanReport(report))
+	r, err = GetScanReport(digest, "scanner1")
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, int(models.SevHigh), r.Sev)
+	require.Equal(t, 1, len(r.Report.Vulnerabilities))
+	assert.Equal(t, "CVE-2019-0001", r.Report.Vulnerabilities[0].ID)
+
+	// test update
+	report.JobID = 2
+	report.Sev = int(models.SevNone)
+	report.Report = &models.VulnerabilityReport{Components: 1}
+	require.Nil(t, SetScanReport(report))
+	r, err = GetScanReport(digest, "scanner1")
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, report.ID, r.ID)
+	assert.Equal(t, int64(2), r.JobID)
+	assert.Equal(t, 0, len(r.Report.Vulnerabilities))
+
+	// the report of another scanner
+	require.Nil(t, SetScanReport(&models.ScanReport{
+		Digest:  digest,
+		Scanner: "scanner2",
+		JobID:   3,
+		Sev:     int(models.SevLow),
+	}))
+	r, err = GetLatestScanReport(digest)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, "scanner2", r.Scanner)
+	reports, err := ListScanReports(digest)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(reports))
+	assert.Equal(t, "scanner1", reports[0].Scanner)
+
+	// test delete
+	require.Nil(t, DeleteScanReports(digest))
+	reports, err = ListScanReports(digest)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(reports))
+}
@@ -0,0 +1,128 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddScannerRegistration registers the scanner
+func AddScannerRegistration(registration *models.ScannerRegistration) (int64, error) {
+	now := time.Now()
+	registration.CreationTime = now
+	registration.UpdateTime = now
+	id, err := GetOrmer().Insert(registration)
+	if err != nil {
+		return 0, err
+	}
+	if registration.IsDefault {
+		if err = SetDefaultScannerRegistration(id); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// GetScannerRegistration returns the scanner registration specified by ID
+func GetScannerRegistration(id int64) (*models.ScannerRegistration, error) {
+	registration := &models.ScannerRegistration{
+		ID: id,
+	}
+	if err := GetOrmer().Read(registration); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return registration, nil
+}
+
+// GetScannerRegistrationByName returns the scanner registration specified by name
+func GetScannerRegistrationByName(name string) (*models.ScannerRegistration, error) {
+	return getScannerRegistration("Name", name)
+}
+
+// GetDefaultScannerRegistration returns the default scanner registration,
+// nil is returned if no registration is set as the default one
+func GetDefaultScannerRegistration() (*models.ScannerRegistration, error) {
+	return getScannerRegistration("IsDefault", true)
+}
+
+func getScannerRegistration(key string, value interface{}) (*models.ScannerRegistration, error) {
+	registration := &models.ScannerRegistration{}
+	err := GetOrmer().QueryTable(&models.ScannerRegistration{}).
+		Filter(key, value).One(registration)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return registration, nil
+}
+
+// ListScannerRegistrations returns all scanner registrations
+func ListScannerRegistrations() ([]*models.ScannerRegistration, error) {
+	registrations := []*models.ScannerRegistration{}
+	_, err := GetOrmer().QueryTable(&models.ScannerRegistration{}).
+		OrderBy("ID").All(&registrations)
+	return registrations, err
+}
+
+// CountScannerRegistrations returns the number of the scanner registrations
+func CountScannerRegistrations() (int64, error) {
+	return GetOrmer().QueryTable(&models.ScannerRegistration{}).Count()
+}
+
+// UpdateScannerRegistration updates the scanner registration
+func UpdateScannerRegistration(registration *models.ScannerRegistration) error {
+	registration.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(registration, "Name", "Description", "URL",
+		"Adapter", "IsDefault", "UpdateTime")
+	if err != nil {
+		return err
+	}
+	if registration.IsDefault {
+		return SetDefaultScannerRegistration(registration.ID)
+	}
+	return nil
+}
+
+// SetDefaultScannerRegistration sets the scanner registration as the default one
+// and unsets the others
+func SetDefaultScannerRegistration(id int64) error {
+	qs := GetOrmer().QueryTable(&models.ScannerRegistration{})
+	if _, err := qs.Exclude("ID", id).Update(orm.Params{
+		"is_default": false,
+	}); err != nil {
+		return err
+	}
+	_, err := qs.Filter("ID", id).Update(orm.Params{
+		"is_default":  true,
+		"update_time": time.Now(),
+	})
+	return err
+}
+
+// DeleteScannerRegistration deletes the scanner registration
+func DeleteScannerRegistration(id int64) error {
+	_, err := GetOrmer().Delete(&models.ScannerRegistration{
+		ID: id,
+	})
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerRegistrationDaoMethods(t *testing.T) {
+	n, err := CountScannerRegistrations()
+	require.Nil(t, err)
+
+	// test add
+	r1 := &models.ScannerRegistration{
+		Name:      "scanner1",
+		URL:       "http://scanner1:8080",
+		Adapter:   "generic",
+		IsDefault: true,
+	}
+	id1, err := AddScannerRegistration(r1)
+	require.Nil(t, err)
+	defer DeleteScannerRegistration(id1)
+	r2 := &models.ScannerRegistration{
+		Name:    "scanner2",
+		URL:     "http://scanner2:8080",
+		Adapter: "generic",
+	}
+	id2, err := AddScannerRegistration(r2)
+	require.Nil(t, err)
+	defer DeleteScannerRegistration(id2)
+
+	// test get
+	r, err := GetScannerRegistration(id1)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, "scanner1", r.Name)
+	r, err = GetScannerRegistrationByName("scanner2")
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, id2, r.ID)
+	r, err = GetScannerRegistrationByName("non-exist")
+	require.Nil(t, err)
+	assert.Nil(t, r)
+	r, err = GetDefaultScannerRegistration()
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, id1, r.ID)
+
+	// test list and count
+	registrations, err := ListScannerRegistrations()
+	require.Nil(t, err)
+	assert.Equal(t, int(n)+2, len(registrations))
+	count, err := CountScannerRegistrations()
+	require.Nil(t, err)
+	assert.Equal(t, n+2, count)
+
+	// test update, the default registration is switched
+	r2.ID = id2
+	r2.URL = "http://scanner2:8081"
+	r2.IsDefault = true
+	require.Nil(t, UpdateScannerRegistration(r2))
+	r, err = GetDefaultScannerRegistration()
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, id2, r.ID)
+	assert.Equal(t, "http://scanner2:8081", r.URL)
+
+	// test delete
+	require.Nil(t, DeleteScannerRegistration(id1))
+	r, err = GetScannerRegistration(id1)
+	require.Nil(t, err)
+	assert.Nil(t, r)
+}
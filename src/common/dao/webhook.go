@@ -0,0 +1,187 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AddWebhookPolicy creates the webhook policy
+func AddWebhookPolicy(policy *models.WebhookPolicy) (int64, error) {
+	now := time.Now()
+	policy.CreationTime = now
+	policy.UpdateTime = now
+	policy.Marshal()
+	return GetOrmer().Insert(policy)
+}
+
+// GetWebhookPolicy returns the webhook policy specified by ID
+func GetWebhookPolicy(id int64) (*models.WebhookPolicy, error) {
+	policy := &models.WebhookPolicy{
+		ID: id,
+	}
+	if err := GetOrmer().Read(policy); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	policy.Unmarshal()
+	return policy, nil
+}
+
+// GetTotalOfWebhookPolicies returns the total count of webhook policies
+func GetTotalOfWebhookPolicies(query *models.WebhookPolicyQuery) (int64, error) {
+	return getWebhookPolicyQuerySetter(query).Count()
+}
+
+// ListWebhookPolicies lists webhook policies according to the query conditions
+func ListWebhookPolicies(query *models.WebhookPolicyQuery) ([]*models.WebhookPolicy, error) {
+	qs := getWebhookPolicyQuerySetter(query)
+	if query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	qs = qs.OrderBy("Name")
+
+	policies := []*models.WebhookPolicy{}
+	if _, err := qs.All(&policies); err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		policy.Unmarshal()
+	}
+	return policies, nil
+}
+
+func getWebhookPolicyQuerySetter(query *models.WebhookPolicyQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.WebhookPolicy{})
+	if len(query.Name) > 0 {
+		qs = qs.Filter("Name__icontains", query.Name)
+	}
+	if query.ProjectID != 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	return qs
+}
+
+// UpdateWebhookPolicy updates the webhook policy
+func UpdateWebhookPolicy(policy *models.WebhookPolicy) error {
+	policy.UpdateTime = time.Now()
+	policy.Marshal()
+	_, err := GetOrmer().Update(policy, "Name", "TargetURL", "Secret",
+		"SkipCertVerify", "EventTypesDB", "Enabled", "UpdateTime")
+	return err
+}
+
+// DeleteWebhookPolicy deletes the webhook policy and its executions
+func DeleteWebhookPolicy(id int64) error {
+	if _, err := GetOrmer().QueryTable(&models.WebhookExecution{}).
+		Filter("PolicyID", id).Delete(); err != nil {
+		return err
+	}
+	_, err := GetOrmer().Delete(&models.WebhookPolicy{
+		ID: id,
+	})
+	return err
+}
+
+// DeleteWebhookPoliciesOfProject deletes all webhook policies of the project
+func DeleteWebhookPoliciesOfProject(projectID int64) error {
+	policies, err := ListWebhookPolicies(&models.WebhookPolicyQuery{
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if err = DeleteWebhookPolicy(policy.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddWebhookExecution creates the delivery record of webhook
+func AddWebhookExecution(execution *models.WebhookExecution) (int64, error) {
+	now := time.Now()
+	execution.CreationTime = now
+	execution.UpdateTime = now
+	return GetOrmer().Insert(execution)
+}
+
+// GetWebhookExecution returns the delivery record specified by ID
+func GetWebhookExecution(id int64) (*models.WebhookExecution, error) {
+	execution := &models.WebhookExecution{
+		ID: id,
+	}
+	if err := GetOrmer().Read(execution); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return execution, nil
+}
+
+// GetTotalOfWebhookExecutions returns the total count of delivery records
+func GetTotalOfWebhookExecutions(query *models.WebhookExecutionQuery) (int64, error) {
+	return getWebhookExecutionQuerySetter(query).Count()
+}
+
+// ListWebhookExecutions lists the delivery records, the latest one is the first
+func ListWebhookExecutions(query *models.WebhookExecutionQuery) ([]*models.WebhookExecution, error) {
+	qs := getWebhookExecutionQuerySetter(query)
+	if query.Size > 0 {
+		qs = qs.Limit(query.Size)
+		if query.Page > 0 {
+			qs = qs.Offset((query.Page - 1) * query.Size)
+		}
+	}
+	qs = qs.OrderBy("-CreationTime", "-ID")
+
+	executions := []*models.WebhookExecution{}
+	_, err := qs.All(&executions)
+	return executions, err
+}
+
+func getWebhookExecutionQuerySetter(query *models.WebhookExecutionQuery) orm.QuerySeter {
+	qs := GetOrmer().QueryTable(&models.WebhookExecution{})
+	if query.PolicyID != 0 {
+		qs = qs.Filter("PolicyID", query.PolicyID)
+	}
+	if len(query.EventType) > 0 {
+		qs = qs.Filter("EventType", query.EventType)
+	}
+	if len(query.Status) > 0 {
+		qs = qs.Filter("Status", query.Status)
+	}
+	return qs
+}
+
+// UpdateWebhookExecution updates the specified columns of the delivery record
+func UpdateWebhookExecution(execution *models.WebhookExecution, cols ...string) error {
+	execution.UpdateTime = time.Now()
+	cols = append(cols, "UpdateTime")
+	_, err := GetOrmer().Update(execution, cols...)
+	return err
+}
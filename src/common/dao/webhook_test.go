@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDaoMethods(t *testing.T) {
+	policy := &models.WebhookPolicy{
+		Name:       "dao_test_webhook",
+		ProjectID:  1,
+		TargetURL:  "http://127.0.0.1/webhook",
+		EventTypes: []string{models.WebhookEventPushImage, models.WebhookEventDeleteImage},
+		Enabled:    true,
+	}
+	// test add policy
+	policyID, err := AddWebhookPolicy(policy)
+	require.Nil(t, err)
+	defer DeleteWebhookPolicy(policyID)
+
+	// test get policy
+	p, err := GetWebhookPolicy(policyID)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, policy.EventTypes, p.EventTypes)
+
+	// test list policies
+	query := &models.WebhookPolicyQuery{
+		ProjectID: 1,
+	}
+	total, err := GetTotalOfWebhookPolicies(query)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	policies, err := ListWebhookPolicies(query)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(policies))
+	assert.True(t, policies[0].Subscribes(models.WebhookEventPushImage))
+
+	// test update policy
+	policy.EventTypes = []string{models.WebhookEventPullImage}
+	policy.Enabled = false
+	require.Nil(t, UpdateWebhookPolicy(policy))
+	p, err = GetWebhookPolicy(policyID)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, []string{models.WebhookEventPullImage}, p.EventTypes)
+	assert.False(t, p.Enabled)
+
+	// test add execution
+	execution := &models.WebhookExecution{
+		PolicyID:  policyID,
+		EventType: models.WebhookEventPullImage,
+		Payload:   "{}",
+		Status:    models.JobPending,
+	}
+	executionID, err := AddWebhookExecution(execution)
+	require.Nil(t, err)
+
+	// test list executions
+	executionQuery := &models.WebhookExecutionQuery{
+		PolicyID: policyID,
+	}
+	total, err = GetTotalOfWebhookExecutions(executionQuery)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	executions, err := ListWebhookExecutions(executionQuery)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(executions))
+	assert.Equal(t, executionID, executions[0].ID)
+
+	// test update execution
+	execution.Status = models.JobFinished
+	require.Nil(t, UpdateWebhookExecution(execution, "Status"))
+	e, err := GetWebhookExecution(executionID)
+	require.Nil(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, models.JobFinished, e.Status)
+
+	// test delete
+	require.Nil(t, DeleteWebhookPoliciesOfProject(1))
+	p, err = GetWebhookPolicy(policyID)
+	require.Nil(t, err)
+	assert.Nil(t, p)
+	e, err = GetWebhookExecution(executionID)
+	require.Nil(t, err)
+	assert.Nil(t, e)
+}
@@ -11,8 +11,14 @@ const (
 	ImageDelete = "IMAGE_DELETE"
 	// ImageReplicate : the name of image replicate job in job service
 	ImageReplicate = "IMAGE_REPLICATE"
+	// ChartTransfer : the name of chart transfer job in job service
+	ChartTransfer = "CHART_TRANSFER"
 	// ImageGC the name of image garbage collection job in job service
 	ImageGC = "IMAGE_GC"
+	// TagRetention the name of tag retention job in job service
+	TagRetention = "TAG_RETENTION"
+	// WebhookDelivery the name of webhook delivery job in job service
+	WebhookDelivery = "WEBHOOK_DELIVERY"
 
 	// JobKindGeneric : Kind of generic job
 	JobKindGeneric = "Generic"
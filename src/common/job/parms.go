@@ -6,4 +6,9 @@ type ScanJobParms struct {
 	Repository string `json:"repository"`
 	Tag        string `json:"tag"`
 	Digest     string `json:"digest"`
+	// the scanner used to scan the image, the Clair deployed with Harbor
+	// is used if they are empty
+	Scanner        string `json:"scanner,omitempty"`
+	ScannerAdapter string `json:"scanner_adapter,omitempty"`
+	ScannerURL     string `json:"scanner_url,omitempty"`
 }
@@ -33,6 +33,7 @@ type AdminJob struct {
 	Status       string    `orm:"column(status)"  json:"job_status"`
 	UUID         string    `orm:"column(job_uuid)" json:"-"`
 	Deleted      bool      `orm:"column(deleted)" json:"deleted"`
+	Report       string    `orm:"column(job_report)" json:"job_report,omitempty"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
@@ -52,3 +53,33 @@ type AdminJobQuery struct {
 	Deleted bool
 	Pagination
 }
+
+// GCReport is the report of the garbage collection, it's stored
+// as JSON with the admin job record
+type GCReport struct {
+	DryRun bool `json:"dry_run"`
+	// the count of the blobs deleted by the garbage collection of registry
+	BlobsDeleted int `json:"blobs_deleted"`
+	// the total size of the blobs deleted by the garbage collection of registry,
+	// the blobs whose sizes are not tracked by Harbor are not counted
+	BytesFreed int64 `json:"bytes_freed"`
+	// the count of the blobs unlinked from the repositories by the pre-pass
+	BlobsUnlinked int `json:"blobs_unlinked"`
+	// the count of the manifests deleted, including the untagged ones
+	ManifestsDeleted int `json:"manifests_deleted"`
+	// the count of the artifacts purged as they exist only in registry
+	ArtifactsPurged    int       `json:"artifacts_purged"`
+	RepositoriesPurged []string  `json:"repositories_purged,omitempty"`
+	StartTime          time.Time `json:"start_time"`
+	EndTime            time.Time `json:"end_time"`
+	// the time taken in seconds
+	Duration float64 `json:"duration"`
+}
+
+// OrphanPurgeResult is the result of purging the artifacts which exist only
+// in registry and have no repository record in Harbor
+type OrphanPurgeResult struct {
+	DryRun          bool     `json:"dry_run"`
+	Repositories    []string `json:"repositories"`
+	ArtifactsPurged int      `json:"artifacts_purged"`
+}
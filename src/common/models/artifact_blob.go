@@ -0,0 +1,37 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ArtifactBlobTable is the name of the table whose data is mapped by ArtifactBlob struct.
+const ArtifactBlobTable = "artifact_blob"
+
+// ArtifactBlob records a blob referenced by the manifest of an artifact in the repository
+type ArtifactBlob struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Repository   string    `orm:"column(repository_name)" json:"repository_name"`
+	DigestAF     string    `orm:"column(digest_af)" json:"digest_af"`
+	DigestBlob   string    `orm:"column(digest_blob)" json:"digest_blob"`
+	Size         int64     `orm:"column(size)" json:"size"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (a *ArtifactBlob) TableName() string {
+	return ArtifactBlobTable
+}
@@ -37,5 +37,19 @@ func init() {
 		new(ResourceLabel),
 		new(UserGroup),
 		new(AdminJob),
-		new(JobLog))
+		new(JobLog),
+		new(ProjectUsage),
+		new(Robot),
+		new(RetentionPolicy),
+		new(RetentionExecution),
+		new(ImmutableTagRule),
+		new(WebhookPolicy),
+		new(WebhookExecution),
+		new(OIDCUser),
+		new(CVEAllowlist),
+		new(ScannerRegistration),
+		new(ScanReport),
+		new(ChartDownload),
+		new(ArtifactBlob),
+		new(ConfigRevision))
 }
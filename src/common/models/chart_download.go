@@ -0,0 +1,45 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ChartDownloadTable is the name of the table whose data is mapped by ChartDownload struct.
+const ChartDownloadTable = "chart_download"
+
+// ChartDownload keeps the download count of a chart version
+type ChartDownload struct {
+	ID            int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID     int64     `orm:"column(project_id)" json:"project_id"`
+	Chart         string    `orm:"column(chart)" json:"chart"`
+	Version       string    `orm:"column(version)" json:"version"`
+	DownloadCount int64     `orm:"column(download_count)" json:"download_count"`
+	CreationTime  time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime    time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (c *ChartDownload) TableName() string {
+	return ChartDownloadTable
+}
+
+// ChartDownloadSummary is the total download count of all the versions of a chart
+type ChartDownloadSummary struct {
+	ProjectID     int64  `orm:"column(project_id)" json:"project_id"`
+	Chart         string `orm:"column(chart)" json:"chart"`
+	DownloadCount int64  `orm:"column(download_count)" json:"download_count"`
+}
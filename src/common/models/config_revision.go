@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// ConfigRevisionTable is the table name for config revision
+const ConfigRevisionTable = "config_revision"
+
+// ConfigRevision records a change of the system configurations
+type ConfigRevision struct {
+	ID       int64  `orm:"pk;auto;column(id)" json:"id"`
+	Operator string `orm:"column(operator)" json:"operator"`
+	Comment  string `orm:"column(comment)" json:"comment,omitempty"`
+	// the JSON encoded changes, the values of the secret keys are masked
+	Diff string `orm:"column(diff)" json:"-"`
+	// the JSON encoded configurations after the change, the values of
+	// the secret keys are encrypted
+	Snapshot     string          `orm:"column(snapshot)" json:"-"`
+	Changes      []*ConfigChange `orm:"-" json:"changes"`
+	CreationTime time.Time       `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by by beego orm to map ConfigRevision to table config_revision
+func (c *ConfigRevision) TableName() string {
+	return ConfigRevisionTable
+}
+
+// ConfigChange is the change of a configuration key, the values are
+// omitted for the secret keys
+type ConfigChange struct {
+	Key    string      `json:"key"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+	Secret bool        `json:"secret,omitempty"`
+}
+
+// ConfigRevisionQuery : query parameters for config revision
+type ConfigRevisionQuery struct {
+	Operator string
+	// the revisions changing the key
+	Key string
+	Pagination
+}
+
+// ConfigRevisionList holds a page of the revisions and the total count
+type ConfigRevisionList struct {
+	Total     int64             `json:"total"`
+	Revisions []*ConfigRevision `json:"revisions"`
+}
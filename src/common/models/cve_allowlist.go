@@ -0,0 +1,123 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// CVEAllowlistTable is the table name for the CVE allowlists
+const CVEAllowlistTable = "cve_allowlist"
+
+// CVEAllowlist contains the CVE IDs ignored by the vulnerability check of the project,
+// the allowlist whose project ID is 0 is the system allowlist which applies to all projects
+type CVEAllowlist struct {
+	ID        int64 `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID int64 `orm:"column(project_id)" json:"project_id"`
+	// the unix timestamp when the allowlist expires, 0 means never expires
+	ExpiresAt    int64              `orm:"column(expires_at)" json:"expires_at"`
+	ItemsDB      string             `orm:"column(items)" json:"-"`
+	Items        []CVEAllowlistItem `orm:"-" json:"items"`
+	CreationTime time.Time          `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time          `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// CVEAllowlistItem is one CVE in the allowlist
+type CVEAllowlistItem struct {
+	CVEID string `json:"cve_id"`
+}
+
+// TableName ...
+func (c *CVEAllowlist) TableName() string {
+	return CVEAllowlistTable
+}
+
+// Valid ...
+func (c *CVEAllowlist) Valid(v *validation.Validation) {
+	if c.ExpiresAt < 0 {
+		v.SetError("expires_at", "cannot be negative")
+	}
+	ids := map[string]bool{}
+	for _, item := range c.Items {
+		if len(item.CVEID) == 0 || strings.ContainsAny(item.CVEID, " \t\r\n") {
+			v.SetError("items", fmt.Sprintf("invalid CVE ID: %q", item.CVEID))
+			continue
+		}
+		if ids[item.CVEID] {
+			v.SetError("items", fmt.Sprintf("duplicate CVE ID: %s", item.CVEID))
+		}
+		ids[item.CVEID] = true
+	}
+}
+
+// IsExpired returns whether the allowlist is expired
+func (c *CVEAllowlist) IsExpired() bool {
+	return c.ExpiresAt > 0 && time.Now().Unix() >= c.ExpiresAt
+}
+
+// CVESet returns the CVE IDs in the allowlist, it's empty when the allowlist is expired
+func (c *CVEAllowlist) CVESet() CVESet {
+	set := CVESet{}
+	if c.IsExpired() {
+		return set
+	}
+	for _, item := range c.Items {
+		set[item.CVEID] = struct{}{}
+	}
+	return set
+}
+
+// Marshal converts the items to the string stored in database
+func (c *CVEAllowlist) Marshal() error {
+	if c.Items == nil {
+		c.Items = []CVEAllowlistItem{}
+	}
+	data, err := json.Marshal(c.Items)
+	if err != nil {
+		return err
+	}
+	c.ItemsDB = string(data)
+	return nil
+}
+
+// Unmarshal converts the items stored in database to the slice
+func (c *CVEAllowlist) Unmarshal() error {
+	c.Items = []CVEAllowlistItem{}
+	if len(c.ItemsDB) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(c.ItemsDB), &c.Items)
+}
+
+// CVESet is a set of CVE IDs
+type CVESet map[string]struct{}
+
+// Contains returns whether the CVE ID is in the set
+func (c CVESet) Contains(cveID string) bool {
+	_, ok := c[cveID]
+	return ok
+}
+
+// Merge adds the CVE IDs of the other set into the set
+func (c CVESet) Merge(other CVESet) {
+	for id := range other {
+		c[id] = struct{}{}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCVEAllowlistValid(t *testing.T) {
+	cases := []struct {
+		allowlist *CVEAllowlist
+		valid     bool
+	}{
+		{&CVEAllowlist{}, true},
+		{&CVEAllowlist{Items: []CVEAllowlistItem{{CVEID: "CVE-2019-1234"}}}, true},
+		{&CVEAllowlist{ExpiresAt: -1}, false},
+		{&CVEAllowlist{Items: []CVEAllowlistItem{{CVEID: ""}}}, false},
+		{&CVEAllowlist{Items: []CVEAllowlistItem{{CVEID: "CVE 2019"}}}, false},
+		{&CVEAllowlist{Items: []CVEAllowlistItem{{CVEID: "CVE-2019-1234"}, {CVEID: "CVE-2019-1234"}}}, false},
+	}
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.allowlist.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors())
+	}
+}
+
+func TestCVEAllowlistCVESet(t *testing.T) {
+	allowlist := &CVEAllowlist{
+		Items: []CVEAllowlistItem{{CVEID: "CVE-2019-1234"}},
+	}
+	assert.True(t, allowlist.CVESet().Contains("CVE-2019-1234"))
+	assert.False(t, allowlist.CVESet().Contains("CVE-2019-5678"))
+
+	allowlist.ExpiresAt = time.Now().Add(time.Hour).Unix()
+	assert.True(t, allowlist.CVESet().Contains("CVE-2019-1234"))
+
+	allowlist.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	assert.True(t, allowlist.IsExpired())
+	assert.Equal(t, 0, len(allowlist.CVESet()))
+
+	set := CVESet{"CVE-2019-5678": struct{}{}}
+	set.Merge(CVESet{"CVE-2019-1234": struct{}{}})
+	assert.Equal(t, 2, len(set))
+}
+
+func TestCVEAllowlistMarshal(t *testing.T) {
+	allowlist := &CVEAllowlist{}
+	require.Nil(t, allowlist.Marshal())
+	assert.Equal(t, "[]", allowlist.ItemsDB)
+
+	allowlist.ItemsDB = `[{"cve_id":"CVE-2019-1234"}]`
+	require.Nil(t, allowlist.Unmarshal())
+	require.Equal(t, 1, len(allowlist.Items))
+	assert.Equal(t, "CVE-2019-1234", allowlist.Items[0].CVEID)
+}
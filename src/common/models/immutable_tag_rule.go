@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ImmutableTagRuleTable is the table name for immutable tag rules
+const ImmutableTagRuleTable = "immutable_tag_rule"
+
+// ImmutableTagRule protects the matched tags of the project from being
+// overwritten or deleted, the repository pattern is matched against the
+// repository name without the project name, e.g. "*" matches "library/hello"
+type ImmutableTagRule struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	RepoPattern  string    `orm:"column(repo_pattern)" json:"repository"`
+	TagPattern   string    `orm:"column(tag_pattern)" json:"tag"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (i *ImmutableTagRule) TableName() string {
+	return ImmutableTagRuleTable
+}
+
+// Valid ...
+func (i *ImmutableTagRule) Valid(v *validation.Validation) {
+	validatePattern(v, "repository", i.RepoPattern)
+	validatePattern(v, "tag", i.TagPattern)
+}
+
+func validatePattern(v *validation.Validation, key, pattern string) {
+	if len(pattern) == 0 {
+		v.SetError(key, "cannot be empty")
+		return
+	}
+	if len(pattern) > 256 {
+		v.SetError(key, "max length is 256")
+		return
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		v.SetError(key, fmt.Sprintf("invalid pattern %s: %v", pattern, err))
+	}
+}
@@ -25,11 +25,20 @@ const (
 	ProMetaPreventVul         = "prevent_vul" // prevent vulnerable images from being pulled
 	ProMetaSeverity           = "severity"
 	ProMetaAutoScan           = "auto_scan"
+	ProMetaStorageQuota       = "storage_quota"    // the max bytes of storage the project can consume
+	ProMetaRepositoryQuota    = "repository_quota" // the max count of repositories the project can contain
+	ProMetaTagQuota           = "tag_quota"        // the max count of tags the project can contain
+	ProMetaScanner            = "scanner"          // the name of the scanner registration used to scan the images
+	ProMetaChartOverwrite     = "chart_overwrite"  // whether the existing chart versions can be uploaded again
 	SeverityNone              = "negligible"
 	SeverityLow               = "low"
 	SeverityMedium            = "medium"
 	SeverityHigh              = "high"
 	SeverityCritical          = "critical"
+
+	ChartOverwriteAllow     = "allow"      // the existing chart versions can be overwritten by developers
+	ChartOverwriteAdminOnly = "admin_only" // only project admins can overwrite the existing chart versions
+	ChartOverwriteDeny      = "deny"       // the existing chart versions can't be overwritten
 )
 
 // ProjectMetadata holds the metadata of a project.
@@ -15,6 +15,7 @@
 package models
 
 import (
+	"strconv"
 	"strings"
 	"time"
 )
@@ -101,6 +102,44 @@ func (p *Project) AutoScan() bool {
 	return isTrue(auto)
 }
 
+// Scanner returns the name of the scanner registration chosen by the project,
+// it's empty if the project doesn't choose one
+func (p *Project) Scanner() string {
+	scanner, _ := p.GetMetadata(ProMetaScanner)
+	return scanner
+}
+
+// ChartOverwrite returns whether the existing chart versions of the project can be
+// overwritten, the value is one of "allow", "admin_only" and "deny", default is "allow"
+func (p *Project) ChartOverwrite() string {
+	overwrite, exist := p.GetMetadata(ProMetaChartOverwrite)
+	if !exist || len(overwrite) == 0 {
+		return ChartOverwriteAllow
+	}
+	return overwrite
+}
+
+// Quota returns the quota of the project, the unset quotas are unlimited
+func (p *Project) Quota() *ProjectQuota {
+	return &ProjectQuota{
+		Storage:         p.quotaOf(ProMetaStorageQuota),
+		RepositoryCount: p.quotaOf(ProMetaRepositoryQuota),
+		TagCount:        p.quotaOf(ProMetaTagQuota),
+	}
+}
+
+func (p *Project) quotaOf(key string) int64 {
+	value, exist := p.GetMetadata(key)
+	if !exist {
+		return QuotaUnlimited
+	}
+	quota, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || quota < 0 {
+		return QuotaUnlimited
+	}
+	return quota
+}
+
 func isTrue(value string) bool {
 	return strings.ToLower(value) == "true" ||
 		strings.ToLower(value) == "1"
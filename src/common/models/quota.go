@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// ProjectUsageTable is the table name for project usage
+	ProjectUsageTable = "project_usage"
+	// QuotaUnlimited means there is no limitation on the resource
+	QuotaUnlimited int64 = -1
+)
+
+// ProjectQuota holds the limitations on the resources of a project
+type ProjectQuota struct {
+	Storage         int64 `json:"storage"`
+	RepositoryCount int64 `json:"repository_count"`
+	TagCount        int64 `json:"tag_count"`
+}
+
+// ProjectUsage holds the resources consumed by a project
+type ProjectUsage struct {
+	ProjectID       int64     `orm:"pk;column(project_id)" json:"-"`
+	Storage         int64     `orm:"column(storage)" json:"storage"`
+	RepositoryCount int64     `orm:"column(repository_count)" json:"repository_count"`
+	TagCount        int64     `orm:"column(tag_count)" json:"tag_count"`
+	UpdateTime      time.Time `orm:"column(update_time)" json:"update_time"`
+}
+
+// TableName is required by by beego orm to map ProjectUsage to table project_usage
+func (p *ProjectUsage) TableName() string {
+	return ProjectUsageTable
+}
+
+// ProjectSummary is the quota and usage of a project
+type ProjectSummary struct {
+	Quota *ProjectQuota `json:"quota"`
+	Usage *ProjectUsage `json:"usage"`
+}
@@ -34,6 +34,12 @@ const (
 	RepJobTable = "replication_job"
 	// RepPolicyTable is table name for replication policies
 	RepPolicyTable = "replication_policy"
+	// RegistryTypeHarbor is the default registry type of replication targets, it
+	// must be kept the same as the kind of the Harbor replication adaptor
+	RegistryTypeHarbor = "Harbor"
+	// RepDirectionPush is the default direction of replication policies, it
+	// must be kept the same as the push direction defined in replication
+	RepDirectionPush = "push"
 )
 
 // RepPolicy is the model for a replication policy, which associate to a project and a target (destination)
@@ -46,6 +52,7 @@ type RepPolicy struct {
 	Trigger           string    `orm:"column(cron_str)"`
 	Filters           string    `orm:"column(filters)"`
 	ReplicateDeletion bool      `orm:"column(replicate_deletion)"`
+	Direction         string    `orm:"column(direction)"`
 	CreationTime      time.Time `orm:"column(creation_time);auto_now_add"`
 	UpdateTime        time.Time `orm:"column(update_time);auto_now"`
 	Deleted           bool      `orm:"column(deleted)"`
@@ -76,6 +83,7 @@ type RepTarget struct {
 	Password     string    `orm:"column(password)" json:"password"`
 	Type         int       `orm:"column(target_type)" json:"type"`
 	Insecure     bool      `orm:"column(insecure)" json:"insecure"`
+	RegistryType string    `orm:"column(registry_type)" json:"registry_type"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
@@ -90,6 +98,10 @@ func (r *RepTarget) Valid(v *validation.Validation) {
 		v.SetError("name", "max length is 64")
 	}
 
+	if len(r.RegistryType) > 32 {
+		v.SetError("registry_type", "max length is 32")
+	}
+
 	url, err := utils.ParseEndpoint(r.URL)
 	if err != nil {
 		v.SetError("endpoint", err.Error())
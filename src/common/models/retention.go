@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// RetentionPolicyTable is the table name for tag retention policy
+	RetentionPolicyTable = "retention_policy"
+	// RetentionExecutionTable is the table name for the executions of tag retention policy
+	RetentionExecutionTable = "retention_execution"
+)
+
+// RetentionPolicy holds the tag retention rules of a project
+type RetentionPolicy struct {
+	ID        int64 `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID int64 `orm:"column(project_id)" json:"project_id"`
+	// the JSON array of retention rules
+	Rules string `orm:"column(rules)" json:"-"`
+	// the JSON string of schedule
+	Schedule     string    `orm:"column(schedule)" json:"-"`
+	UUID         string    `orm:"column(job_uuid)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RetentionPolicy) TableName() string {
+	return RetentionPolicyTable
+}
+
+// RetentionExecution records one run of the tag retention policy
+type RetentionExecution struct {
+	ID       int64  `orm:"pk;auto;column(id)" json:"id"`
+	PolicyID int64  `orm:"column(policy_id)" json:"policy_id"`
+	UUID     string `orm:"column(job_uuid)" json:"-"`
+	DryRun   bool   `orm:"column(dry_run)" json:"dry_run"`
+	Status   string `orm:"column(status)" json:"status"`
+	// the JSON string of the report
+	Report       string    `orm:"column(report)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *RetentionExecution) TableName() string {
+	return RetentionExecutionTable
+}
+
+// RetentionExecutionQuery : query parameters for the executions of tag retention policy
+type RetentionExecutionQuery struct {
+	PolicyID int64
+	Pagination
+}
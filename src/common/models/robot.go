@@ -0,0 +1,161 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// RobotTable is the table name for robot accounts
+	RobotTable = "robot"
+	// RobotPrefix is prepended to the name of robot account to build the
+	// username used in basic auth, which can never conflict with the users
+	RobotPrefix = "robot$"
+	// RobotProjectSeparator separates the project name and the robot name in
+	// the username, it is never part of a valid project name
+	RobotProjectSeparator = "+"
+	// RobotActionPull allows the robot account to pull images
+	RobotActionPull = "pull"
+	// RobotActionPush allows the robot account to push images
+	RobotActionPush = "push"
+	// RobotDefaultDuration is the lifetime of robot account if the expiry isn't specified
+	RobotDefaultDuration = 30 * 24 * time.Hour
+)
+
+// Robot holds the details of a robot account
+type Robot struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Description  string    `orm:"column(description)" json:"description"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	Token        string    `orm:"column(token)" json:"-"`
+	Salt         string    `orm:"column(salt)" json:"-"`
+	Access       string    `orm:"column(access)" json:"-"`
+	Disabled     bool      `orm:"column(disabled)" json:"disabled"`
+	ExpiresAt    time.Time `orm:"column(expires_at)" json:"expires_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (r *Robot) TableName() string {
+	return RobotTable
+}
+
+// Username returns the username the robot account uses to authenticate,
+// the names of robot accounts are only unique in the project, so the name
+// of the project is included: "robot$<project>+<name>"
+func (r *Robot) Username(projectName string) string {
+	return RobotPrefix + projectName + RobotProjectSeparator + r.Name
+}
+
+// ParseRobotUsername splits the username of robot account into the project
+// name and the robot name, ok is false if it isn't a robot account username
+func ParseRobotUsername(username string) (projectName, name string, ok bool) {
+	if !strings.HasPrefix(username, RobotPrefix) {
+		return "", "", false
+	}
+	strs := strings.SplitN(strings.TrimPrefix(username, RobotPrefix), RobotProjectSeparator, 2)
+	if len(strs) != 2 || len(strs[0]) == 0 || len(strs[1]) == 0 {
+		return "", "", false
+	}
+	return strs[0], strs[1], true
+}
+
+// Actions returns the actions the robot account can perform
+func (r *Robot) Actions() []string {
+	actions := []string{}
+	for _, action := range strings.Split(r.Access, ",") {
+		action = strings.TrimSpace(action)
+		if len(action) > 0 {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Can returns whether the robot account is permitted to perform the action
+func (r *Robot) Can(action string) bool {
+	for _, a := range r.Actions() {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns whether the robot account is expired
+func (r *Robot) IsExpired() bool {
+	return !r.ExpiresAt.After(time.Now())
+}
+
+// IsValid returns whether the robot account can be used to authenticate
+func (r *Robot) IsValid() bool {
+	return !r.Disabled && !r.IsExpired()
+}
+
+// RobotQuery : query parameters for robot accounts
+type RobotQuery struct {
+	Name      string
+	ProjectID int64
+	Disabled  *bool
+	Pagination
+}
+
+// RobotReq is the request to create a robot account
+type RobotReq struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Access      []string `json:"access"`
+	// unix timestamp in seconds, zero means the default duration
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// Valid ...
+func (r *RobotReq) Valid(v *validation.Validation) {
+	if len(r.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+	}
+	if len(r.Name) > 255 {
+		v.SetError("name", "max length is 255")
+	}
+	if strings.ContainsAny(r.Name, ",$:/ ") {
+		v.SetError("name", "contains illegal characters")
+	}
+	if len(r.Access) == 0 {
+		v.SetError("access", "cannot be empty")
+	}
+	for _, action := range r.Access {
+		if action != RobotActionPull && action != RobotActionPush {
+			v.SetError("access", fmt.Sprintf("invalid action: %s", action))
+		}
+	}
+	if r.ExpiresAt != 0 && r.ExpiresAt <= time.Now().Unix() {
+		v.SetError("expires_at", "must be in the future")
+	}
+}
+
+// RobotRep is the response of creating robot account, the token is
+// only returned once
+type RobotRep struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidOfRobotReq(t *testing.T) {
+	cases := []struct {
+		req      *RobotReq
+		hasError bool
+	}{
+		{
+			req:      &RobotReq{},
+			hasError: true,
+		},
+		{
+			req: &RobotReq{
+				Name:   "robot$ci",
+				Access: []string{RobotActionPull},
+			},
+			hasError: true,
+		},
+		{
+			req: &RobotReq{
+				Name:   "ci",
+				Access: []string{"delete"},
+			},
+			hasError: true,
+		},
+		{
+			req: &RobotReq{
+				Name:      "ci",
+				Access:    []string{RobotActionPull},
+				ExpiresAt: time.Now().Add(-1 * time.Hour).Unix(),
+			},
+			hasError: true,
+		},
+		{
+			req: &RobotReq{
+				Name:   "ci",
+				Access: []string{RobotActionPull, RobotActionPush},
+			},
+			hasError: false,
+		},
+	}
+
+	for _, c := range cases {
+		v := &validation.Validation{}
+		c.req.Valid(v)
+		assert.Equal(t, c.hasError, v.HasErrors())
+	}
+}
+
+func TestRobot(t *testing.T) {
+	robot := &Robot{
+		Name:      "ci",
+		Access:    "pull, push",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	assert.Equal(t, "robot$library+ci", robot.Username("library"))
+	assert.Equal(t, []string{RobotActionPull, RobotActionPush}, robot.Actions())
+	assert.True(t, robot.Can(RobotActionPush))
+	assert.False(t, robot.Can("delete"))
+	assert.True(t, robot.IsValid())
+
+	robot.ExpiresAt = time.Now().Add(-1 * time.Hour)
+	assert.True(t, robot.IsExpired())
+	assert.False(t, robot.IsValid())
+}
+
+func TestParseRobotUsername(t *testing.T) {
+	cases := []struct {
+		username    string
+		projectName string
+		name        string
+		ok          bool
+	}{
+		{"admin", "", "", false},
+		{"robot$ci", "", "", false},
+		{"robot$+ci", "", "", false},
+		{"robot$library+", "", "", false},
+		{"robot$library+ci", "library", "ci", true},
+		{"robot$library+ci+nightly", "library", "ci+nightly", true},
+	}
+
+	for _, c := range cases {
+		projectName, name, ok := ParseRobotUsername(c.username)
+		assert.Equal(t, c.ok, ok, c.username)
+		assert.Equal(t, c.projectName, projectName, c.username)
+		assert.Equal(t, c.name, name, c.username)
+	}
+}
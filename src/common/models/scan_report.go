@@ -0,0 +1,161 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScanReportTable is the name of the table whose data is mapped by ScanReport struct.
+const ScanReportTable = "scan_report"
+
+// ScanReport is the vulnerability report of an image generated by a scanner,
+// one image has at most one report per scanner.
+type ScanReport struct {
+	ID           int64                `orm:"pk;auto;column(id)" json:"id"`
+	Digest       string               `orm:"column(digest)" json:"digest"`
+	Scanner      string               `orm:"column(scanner)" json:"scanner"`
+	JobID        int64                `orm:"column(job_id)" json:"job_id"`
+	Sev          int                  `orm:"column(severity)" json:"severity"`
+	ReportDB     string               `orm:"column(report)" json:"-"`
+	Report       *VulnerabilityReport `orm:"-" json:"report"`
+	CreationTime time.Time            `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time            `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (s *ScanReport) TableName() string {
+	return ScanReportTable
+}
+
+// Marshal converts the report to the string stored in database
+func (s *ScanReport) Marshal() error {
+	if s.Report == nil {
+		s.Report = &VulnerabilityReport{}
+	}
+	data, err := json.Marshal(s.Report)
+	if err != nil {
+		return err
+	}
+	s.ReportDB = string(data)
+	return nil
+}
+
+// Unmarshal converts the report stored in database to the struct
+func (s *ScanReport) Unmarshal() error {
+	s.Report = &VulnerabilityReport{}
+	if len(s.ReportDB) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(s.ReportDB), s.Report)
+}
+
+// VulnerabilityReport is the scanner-neutral format of the scan result of an image.
+type VulnerabilityReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// the total number of the packages in the image, including the ones without vulnerabilities
+	Components      int                  `json:"components"`
+	Vulnerabilities []*VulnerabilityItem `json:"vulnerabilities"`
+}
+
+// Overview returns the number of packages of each severity level and the overall severity,
+// the severity of a package is the highest severity of its vulnerabilities.
+func (r *VulnerabilityReport) Overview() (*ComponentsOverview, Severity) {
+	pkgs := map[string]Severity{}
+	for _, v := range r.Vulnerabilities {
+		key := v.Pkg + ":" + v.Version
+		if v.Severity > pkgs[key] {
+			pkgs[key] = v.Severity
+		}
+	}
+	counts := map[Severity]int{}
+	overall := SevNone
+	for _, sev := range pkgs {
+		counts[sev]++
+		if sev > overall {
+			overall = sev
+		}
+	}
+	total := r.Components
+	if total < len(pkgs) {
+		total = len(pkgs)
+	}
+	if total > len(pkgs) {
+		counts[SevNone] += total - len(pkgs)
+	}
+	summary := []*ComponentsOverviewEntry{}
+	for sev, count := range counts {
+		summary = append(summary, &ComponentsOverviewEntry{
+			Sev:   int(sev),
+			Count: count,
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Sev > summary[j].Sev
+	})
+	return &ComponentsOverview{
+		Total:   total,
+		Summary: summary,
+	}, overall
+}
+
+// Filter returns a copy of the report without the vulnerabilities in the allowlist
+func (r *VulnerabilityReport) Filter(allowlist CVESet) *VulnerabilityReport {
+	report := &VulnerabilityReport{
+		GeneratedAt:     r.GeneratedAt,
+		Components:      r.Components,
+		Vulnerabilities: []*VulnerabilityItem{},
+	}
+	for _, v := range r.Vulnerabilities {
+		if !allowlist.Contains(v.ID) {
+			report.Vulnerabilities = append(report.Vulnerabilities, v)
+		}
+	}
+	return report
+}
+
+// VulnIDs returns the sorted IDs of the vulnerabilities whose severity is equal or higher than sev
+func (r *VulnerabilityReport) VulnIDs(sev Severity) []string {
+	ids := []string{}
+	found := map[string]bool{}
+	for _, v := range r.Vulnerabilities {
+		if v.Severity >= sev && !found[v.ID] {
+			found[v.ID] = true
+			ids = append(ids, v.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ParseSeverity parses the severity reported by scanners to Harbor's Severity type,
+// the value will be set to unknown if the string is not recognized.
+func ParseSeverity(severity string) Severity {
+	switch strings.ToLower(severity) {
+	case SeverityNone:
+		return SevNone
+	case SeverityLow:
+		return SevLow
+	case SeverityMedium:
+		return SevMedium
+	case SeverityHigh, SeverityCritical:
+		return SevHigh
+	default:
+		return SevUnknown
+	}
+}
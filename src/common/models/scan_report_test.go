@@ -0,0 +1,81 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnerabilityReport(t *testing.T) {
+	report := &VulnerabilityReport{
+		Components: 3,
+		Vulnerabilities: []*VulnerabilityItem{
+			{ID: "CVE-2019-0001", Pkg: "openssl", Version: "1.0", Severity: SevHigh},
+			{ID: "CVE-2019-0002", Pkg: "openssl", Version: "1.0", Severity: SevLow},
+			{ID: "CVE-2019-0003", Pkg: "bash", Version: "4.4", Severity: SevMedium},
+			{ID: "CVE-2019-0001", Pkg: "bash", Version: "4.4", Severity: SevHigh},
+		},
+	}
+
+	overview, sev := report.Overview()
+	assert.Equal(t, SevHigh, sev)
+	assert.Equal(t, 3, overview.Total)
+	assert.Equal(t, []*ComponentsOverviewEntry{
+		{Sev: int(SevHigh), Count: 2},
+		{Sev: int(SevNone), Count: 1},
+	}, overview.Summary)
+
+	assert.Equal(t, []string{"CVE-2019-0001", "CVE-2019-0003"}, report.VulnIDs(SevMedium))
+	assert.Equal(t, []string{"CVE-2019-0001", "CVE-2019-0002", "CVE-2019-0003"}, report.VulnIDs(SevLow))
+
+	filtered := report.Filter(CVESet{"CVE-2019-0001": struct{}{}})
+	assert.Equal(t, []string{"CVE-2019-0003"}, filtered.VulnIDs(SevMedium))
+	overview, sev = filtered.Overview()
+	assert.Equal(t, SevMedium, sev)
+	assert.Equal(t, 3, overview.Total)
+	// the original report is not changed
+	assert.Equal(t, 4, len(report.Vulnerabilities))
+
+	overview, sev = (&VulnerabilityReport{}).Overview()
+	assert.Equal(t, SevNone, sev)
+	assert.Equal(t, 0, overview.Total)
+}
+
+func TestScanReportMarshal(t *testing.T) {
+	report := &ScanReport{
+		Report: &VulnerabilityReport{
+			Components: 1,
+			Vulnerabilities: []*VulnerabilityItem{
+				{ID: "CVE-2019-0001", Pkg: "openssl", Severity: SevHigh},
+			},
+		},
+	}
+	require.Nil(t, report.Marshal())
+
+	r := &ScanReport{ReportDB: report.ReportDB}
+	require.Nil(t, r.Unmarshal())
+	assert.Equal(t, 1, r.Report.Components)
+	require.Equal(t, 1, len(r.Report.Vulnerabilities))
+	assert.Equal(t, SevHigh, r.Report.Vulnerabilities[0].Severity)
+}
+
+func TestParseSeverity(t *testing.T) {
+	assert.Equal(t, SevNone, ParseSeverity("Negligible"))
+	assert.Equal(t, SevHigh, ParseSeverity("Critical"))
+	assert.Equal(t, SevUnknown, ParseSeverity("foo"))
+}
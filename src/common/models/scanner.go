@@ -0,0 +1,60 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+// ScannerRegistrationTable is the table name for the registered scanners
+const ScannerRegistrationTable = "scanner_registration"
+
+// ScannerRegistration is a scanner endpoint registered to Harbor, the Adapter
+// specifies the implementation used to talk with the endpoint
+type ScannerRegistration struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Description  string    `orm:"column(description)" json:"description"`
+	URL          string    `orm:"column(url)" json:"url"`
+	Adapter      string    `orm:"column(adapter)" json:"adapter"`
+	IsDefault    bool      `orm:"column(is_default)" json:"is_default"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (s *ScannerRegistration) TableName() string {
+	return ScannerRegistrationTable
+}
+
+// Valid ...
+func (s *ScannerRegistration) Valid(v *validation.Validation) {
+	if len(s.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+	}
+	if len(s.Name) > 255 {
+		v.SetError("name", "max length is 255")
+	}
+	if len(s.Adapter) == 0 {
+		v.SetError("adapter", "cannot be empty")
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		v.SetError("url", "must be an absolute http or https URL")
+	}
+}
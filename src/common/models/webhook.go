@@ -0,0 +1,170 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/validation"
+)
+
+const (
+	// WebhookPolicyTable is the table name for webhook policies
+	WebhookPolicyTable = "webhook_policy"
+	// WebhookExecutionTable is the table name for the deliveries of webhook
+	WebhookExecutionTable = "webhook_execution"
+
+	// WebhookEventPushImage is sent when an image is pushed
+	WebhookEventPushImage = "pushImage"
+	// WebhookEventPullImage is sent when an image is pulled
+	WebhookEventPullImage = "pullImage"
+	// WebhookEventDeleteImage is sent when an image is deleted
+	WebhookEventDeleteImage = "deleteImage"
+	// WebhookEventScanningCompleted is sent when the scan job of an image is finished
+	WebhookEventScanningCompleted = "scanningCompleted"
+	// WebhookEventScanningFailed is sent when the scan job of an image is failed
+	WebhookEventScanningFailed = "scanningFailed"
+	// WebhookEventReplicationCompleted is sent when the replication job of an image is finished
+	WebhookEventReplicationCompleted = "replicationCompleted"
+	// WebhookEventReplicationFailed is sent when the replication job of an image is failed
+	WebhookEventReplicationFailed = "replicationFailed"
+)
+
+// WebhookEventTypes contains all supported event types of webhook
+var WebhookEventTypes = []string{
+	WebhookEventPushImage,
+	WebhookEventPullImage,
+	WebhookEventDeleteImage,
+	WebhookEventScanningCompleted,
+	WebhookEventScanningFailed,
+	WebhookEventReplicationCompleted,
+	WebhookEventReplicationFailed,
+}
+
+// WebhookPolicy sends the selected events of the project to the target URL
+type WebhookPolicy struct {
+	ID        int64  `orm:"pk;auto;column(id)" json:"id"`
+	Name      string `orm:"column(name)" json:"name"`
+	ProjectID int64  `orm:"column(project_id)" json:"project_id"`
+	TargetURL string `orm:"column(target_url)" json:"target_url"`
+	// used to sign the payload, it is stored encrypted and never returned
+	Secret         string    `orm:"column(secret)" json:"secret,omitempty"`
+	SkipCertVerify bool      `orm:"column(skip_cert_verify)" json:"skip_cert_verify"`
+	EventTypesDB   string    `orm:"column(event_types)" json:"-"`
+	EventTypes     []string  `orm:"-" json:"event_types"`
+	Enabled        bool      `orm:"column(enabled)" json:"enabled"`
+	Creator        string    `orm:"column(creator)" json:"creator"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime     time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (w *WebhookPolicy) TableName() string {
+	return WebhookPolicyTable
+}
+
+// Valid ...
+func (w *WebhookPolicy) Valid(v *validation.Validation) {
+	if len(w.Name) == 0 {
+		v.SetError("name", "cannot be empty")
+	}
+	if len(w.Name) > 256 {
+		v.SetError("name", "max length is 256")
+	}
+	u, err := url.Parse(w.TargetURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		v.SetError("target_url", "must be a valid http or https URL")
+	}
+	if len(w.EventTypes) == 0 {
+		v.SetError("event_types", "cannot be empty")
+	}
+	for _, t := range w.EventTypes {
+		if !isWebhookEventType(t) {
+			v.SetError("event_types", fmt.Sprintf("unsupported event type: %s", t))
+		}
+	}
+}
+
+// Subscribes returns whether the policy is enabled and subscribes the event type
+func (w *WebhookPolicy) Subscribes(eventType string) bool {
+	if !w.Enabled {
+		return false
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Marshal converts the event types to the string stored in database
+func (w *WebhookPolicy) Marshal() {
+	w.EventTypesDB = strings.Join(w.EventTypes, ",")
+}
+
+// Unmarshal converts the event types stored in database to the slice
+func (w *WebhookPolicy) Unmarshal() {
+	w.EventTypes = []string{}
+	for _, t := range strings.Split(w.EventTypesDB, ",") {
+		if t = strings.TrimSpace(t); len(t) > 0 {
+			w.EventTypes = append(w.EventTypes, t)
+		}
+	}
+}
+
+func isWebhookEventType(t string) bool {
+	for _, eventType := range WebhookEventTypes {
+		if eventType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPolicyQuery : query parameters for webhook policies
+type WebhookPolicyQuery struct {
+	Name      string
+	ProjectID int64
+	Pagination
+}
+
+// WebhookExecution records one delivery of the event to the target of policy
+type WebhookExecution struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	PolicyID     int64     `orm:"column(policy_id)" json:"policy_id"`
+	EventType    string    `orm:"column(event_type)" json:"event_type"`
+	Payload      string    `orm:"column(payload)" json:"payload"`
+	UUID         string    `orm:"column(job_uuid)" json:"-"`
+	Status       string    `orm:"column(status)" json:"status"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName ...
+func (w *WebhookExecution) TableName() string {
+	return WebhookExecutionTable
+}
+
+// WebhookExecutionQuery : query parameters for the deliveries of webhook
+type WebhookExecutionQuery struct {
+	PolicyID  int64
+	EventType string
+	Status    string
+	Pagination
+}
@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// Evaluate applies the rules to the candidates and returns the ones that should
+// be deleted, a candidate is retained if any rule keeps it. As deleting a tag removes
+// the manifest, the tags which share the digest with a retained one are retained too.
+// The immutable candidates are always retained. No candidate is deleted if there is no rule.
+func Evaluate(rules []*Rule, candidates []*Candidate, now time.Time) []*Candidate {
+	if len(rules) == 0 {
+		return []*Candidate{}
+	}
+
+	retained := map[*Candidate]bool{}
+	for _, candidate := range candidates {
+		if candidate.Immutable {
+			retained[candidate] = true
+		}
+	}
+	for _, rule := range rules {
+		for _, candidate := range keep(rule, candidates, now) {
+			retained[candidate] = true
+		}
+	}
+
+	// repository + digest of the retained candidates
+	retainedDigests := map[string]bool{}
+	for candidate := range retained {
+		retainedDigests[candidate.Repository+"@"+candidate.Digest] = true
+	}
+
+	deleted := []*Candidate{}
+	for _, candidate := range candidates {
+		if retained[candidate] || retainedDigests[candidate.Repository+"@"+candidate.Digest] {
+			continue
+		}
+		deleted = append(deleted, candidate)
+	}
+	return deleted
+}
+
+// keep returns the candidates retained by the rule
+func keep(rule *Rule, candidates []*Candidate, now time.Time) []*Candidate {
+	result := []*Candidate{}
+	switch rule.Kind {
+	case RuleKindLatestPushed:
+		repositories := map[string][]*Candidate{}
+		for _, candidate := range candidates {
+			// the push time is unknown if the tag has no push record, e.g. it's pushed
+			// before the access logs are recorded, keep it rather than treating it as
+			// the oldest one
+			if candidate.PushTime.IsZero() {
+				result = append(result, candidate)
+				continue
+			}
+			repositories[candidate.Repository] = append(repositories[candidate.Repository], candidate)
+		}
+		for _, cs := range repositories {
+			sort.SliceStable(cs, func(i, j int) bool {
+				return cs[i].PushTime.After(cs[j].PushTime)
+			})
+			if len(cs) > rule.Count {
+				cs = cs[:rule.Count]
+			}
+			result = append(result, cs...)
+		}
+	case RuleKindRecentlyPulled:
+		after := now.Add(-time.Duration(rule.Days) * 24 * time.Hour)
+		for _, candidate := range candidates {
+			if candidate.PullTime.After(after) {
+				result = append(result, candidate)
+			}
+		}
+	case RuleKindTagPattern:
+		for _, candidate := range candidates {
+			if match, _ := utils.Match(rule.Pattern, candidate.Tag); match {
+				result = append(result, candidate)
+			}
+		}
+	case RuleKindLabel:
+		for _, candidate := range candidates {
+			for _, label := range candidate.Labels {
+				if label == rule.LabelID {
+					result = append(result, candidate)
+					break
+				}
+			}
+		}
+	}
+	return result
+}
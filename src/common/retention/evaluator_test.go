@@ -0,0 +1,128 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Now()
+
+func newCandidate(repository, tag, digest string, pushedDaysAgo, pulledDaysAgo int, labels ...int64) *Candidate {
+	candidate := &Candidate{
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+		PushTime:   now.Add(-time.Duration(pushedDaysAgo) * 24 * time.Hour),
+		Labels:     labels,
+	}
+	if pulledDaysAgo >= 0 {
+		candidate.PullTime = now.Add(-time.Duration(pulledDaysAgo) * 24 * time.Hour)
+	}
+	return candidate
+}
+
+func tagsOf(candidates []*Candidate) []string {
+	tags := []string{}
+	for _, candidate := range candidates {
+		tags = append(tags, candidate.Repository+":"+candidate.Tag)
+	}
+	return tags
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		rule     *Rule
+		hasError bool
+	}{
+		{&Rule{Kind: "unknown"}, true},
+		{&Rule{Kind: RuleKindLatestPushed}, true},
+		{&Rule{Kind: RuleKindLatestPushed, Count: 10}, false},
+		{&Rule{Kind: RuleKindRecentlyPulled, Days: -1}, true},
+		{&Rule{Kind: RuleKindRecentlyPulled, Days: 7}, false},
+		{&Rule{Kind: RuleKindTagPattern, Pattern: "[release"}, true},
+		{&Rule{Kind: RuleKindTagPattern, Pattern: "release-*"}, false},
+		{&Rule{Kind: RuleKindLabel}, true},
+		{&Rule{Kind: RuleKindLabel, LabelID: 1}, false},
+	}
+	for _, c := range cases {
+		err := c.rule.Validate()
+		assert.Equal(t, c.hasError, err != nil, "rule: %+v", c.rule)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	candidates := []*Candidate{
+		newCandidate("library/hello", "1", "sha256:1", 4, -1),
+		newCandidate("library/hello", "2", "sha256:2", 3, 1),
+		newCandidate("library/hello", "3", "sha256:3", 2, -1),
+		newCandidate("library/hello", "latest", "sha256:3", 1, -1),
+		newCandidate("library/hello", "release-1", "sha256:4", 10, -1),
+		newCandidate("library/world", "1", "sha256:5", 10, 30, 1),
+		newCandidate("library/world", "2", "sha256:6", 1, -1),
+	}
+
+	// no rule
+	deleted := Evaluate(nil, candidates, now)
+	assert.Equal(t, 0, len(deleted))
+
+	// latest pushed
+	deleted = Evaluate([]*Rule{
+		{Kind: RuleKindLatestPushed, Count: 1},
+	}, candidates, now)
+	// "library/hello:3" shares the digest with "library/hello:latest"
+	assert.Equal(t, []string{"library/hello:1", "library/hello:2",
+		"library/hello:release-1", "library/world:1"}, tagsOf(deleted))
+
+	// recently pulled
+	deleted = Evaluate([]*Rule{
+		{Kind: RuleKindRecentlyPulled, Days: 7},
+	}, candidates, now)
+	require.Equal(t, 6, len(deleted))
+	assert.NotContains(t, tagsOf(deleted), "library/hello:2")
+
+	// tag pattern, label and latest pushed
+	deleted = Evaluate([]*Rule{
+		{Kind: RuleKindTagPattern, Pattern: "release-*"},
+		{Kind: RuleKindLabel, LabelID: 1},
+		{Kind: RuleKindLatestPushed, Count: 2},
+	}, candidates, now)
+	assert.Equal(t, []string{"library/hello:1", "library/hello:2"}, tagsOf(deleted))
+
+	// immutable
+	candidates[0].Immutable = true
+	deleted = Evaluate([]*Rule{
+		{Kind: RuleKindLatestPushed, Count: 1},
+	}, candidates, now)
+	assert.Equal(t, []string{"library/hello:2",
+		"library/hello:release-1", "library/world:1"}, tagsOf(deleted))
+}
+
+func TestEvaluateUnknownPushTime(t *testing.T) {
+	candidates := []*Candidate{
+		newCandidate("library/hello", "1", "sha256:1", 2, -1),
+		newCandidate("library/hello", "2", "sha256:2", 1, -1),
+		{Repository: "library/hello", Tag: "3", Digest: "sha256:3"},
+	}
+	// the tag without push record is kept and doesn't take the place of the latest ones
+	deleted := Evaluate([]*Rule{
+		{Kind: RuleKindLatestPushed, Count: 1},
+	}, candidates, now)
+	assert.Equal(t, []string{"library/hello:1"}, tagsOf(deleted))
+}
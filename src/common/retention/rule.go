@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+const (
+	// RuleKindLatestPushed keeps the latest N pushed tags of each repository
+	RuleKindLatestPushed = "latest_pushed"
+	// RuleKindRecentlyPulled keeps the tags pulled in the last X days
+	RuleKindRecentlyPulled = "recently_pulled"
+	// RuleKindTagPattern keeps the tags whose names match the pattern
+	RuleKindTagPattern = "tag_pattern"
+	// RuleKindLabel keeps the tags attached with the label
+	RuleKindLabel = "label"
+)
+
+// Rule decides which tags should be kept, the tags which
+// aren't kept by any rule of the policy will be deleted
+type Rule struct {
+	Kind string `json:"kind"`
+	// used by the rule latest_pushed
+	Count int `json:"count,omitempty"`
+	// used by the rule recently_pulled
+	Days int `json:"days,omitempty"`
+	// used by the rule tag_pattern, e.g. "release-*"
+	Pattern string `json:"pattern,omitempty"`
+	// used by the rule label
+	LabelID int64 `json:"label_id,omitempty"`
+}
+
+// Validate checks whether the parameters of the rule are valid
+func (r *Rule) Validate() error {
+	switch r.Kind {
+	case RuleKindLatestPushed:
+		if r.Count <= 0 {
+			return fmt.Errorf("invalid count of rule %s: %d", r.Kind, r.Count)
+		}
+	case RuleKindRecentlyPulled:
+		if r.Days <= 0 {
+			return fmt.Errorf("invalid days of rule %s: %d", r.Kind, r.Days)
+		}
+	case RuleKindTagPattern:
+		if len(r.Pattern) == 0 {
+			return fmt.Errorf("empty pattern of rule %s", r.Kind)
+		}
+		if _, err := utils.Match(r.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern of rule %s: %v", r.Kind, err)
+		}
+	case RuleKindLabel:
+		if r.LabelID <= 0 {
+			return fmt.Errorf("invalid label ID of rule %s: %d", r.Kind, r.LabelID)
+		}
+	default:
+		return fmt.Errorf("unsupported rule kind: %s", r.Kind)
+	}
+	return nil
+}
+
+// Candidate is a tag which may be deleted by the retention policy
+type Candidate struct {
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag"`
+	Digest     string    `json:"digest"`
+	PushTime   time.Time `json:"push_time"`
+	PullTime   time.Time `json:"pull_time"`
+	Labels     []int64   `json:"labels"`
+	// the tag protected by the immutable tag rules
+	Immutable bool `json:"immutable"`
+}
+
+// Report records the result of one run of the retention policy
+type Report struct {
+	DryRun    bool         `json:"dry_run"`
+	StartTime time.Time    `json:"start_time"`
+	EndTime   time.Time    `json:"end_time"`
+	Total     int          `json:"total"`
+	Retained  int          `json:"retained"`
+	Deleted   []*Candidate `json:"deleted"`
+	// the candidates which are failed to be deleted
+	Failed []*Candidate `json:"failed"`
+}
@@ -0,0 +1,168 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// AdapterGeneric is the type of the adapter which talks with the scanners
+// implementing the scanner adapter API over HTTP:
+//
+//	GET  /api/v1/metadata              returns the Metadata
+//	POST /api/v1/scan                  accepts the ScanRequest and returns {"id": "<scan ID>"} with 202
+//	GET  /api/v1/scan/{id}/report      returns the report with 200, or 302 if the report isn't ready
+const AdapterGeneric = "generic"
+
+func init() {
+	Register(AdapterGeneric, newGenericAdapter)
+}
+
+type genericAdapter struct {
+	endpoint string
+	logger   Logger
+	client   *http.Client
+}
+
+func newGenericAdapter(url string, logger Logger) (Adapter, error) {
+	return &genericAdapter{
+		endpoint: strings.TrimSuffix(url, "/"),
+		logger:   logger,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			// the scanner responds 302 when the report isn't ready
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+type scanResponse struct {
+	ID string `json:"id"`
+}
+
+// the report returned by the scanner, the severity is a string, e.g. "High"
+type genericReport struct {
+	GeneratedAt     time.Time               `json:"generated_at"`
+	Components      int                     `json:"components"`
+	Vulnerabilities []*genericVulnerability `json:"vulnerabilities"`
+}
+
+type genericVulnerability struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	FixVersion  string   `json:"fix_version"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Links       []string `json:"links"`
+}
+
+func (g *genericAdapter) Metadata() (*Metadata, error) {
+	req, err := http.NewRequest(http.MethodGet, g.endpoint+"/api/v1/metadata", nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{}
+	if _, err = g.send(req, http.StatusOK, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (g *genericAdapter) Scan(scanReq *ScanRequest) (string, error) {
+	data, err := json.Marshal(scanReq)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, g.endpoint+"/api/v1/scan", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Content-Type"), "application/json")
+	resp := &scanResponse{}
+	if _, err = g.send(req, http.StatusAccepted, resp); err != nil {
+		return "", err
+	}
+	if len(resp.ID) == 0 {
+		return "", fmt.Errorf("no scan ID returned by the scanner %s", g.endpoint)
+	}
+	g.logger.Debugf("Submitted the scan request of %s@%s to %s, scan ID: %s",
+		scanReq.Artifact.Repository, scanReq.Artifact.Digest, g.endpoint, resp.ID)
+	return resp.ID, nil
+}
+
+func (g *genericAdapter) GetReport(scanID string) (*models.VulnerabilityReport, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/scan/%s/report", g.endpoint, scanID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Accept"), MimeTypeVulnerabilityReport)
+	report := &genericReport{}
+	status, err := g.send(req, http.StatusOK, report)
+	if status == http.StatusFound {
+		return nil, ErrReportNotReady
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := &models.VulnerabilityReport{
+		GeneratedAt:     report.GeneratedAt,
+		Components:      report.Components,
+		Vulnerabilities: []*models.VulnerabilityItem{},
+	}
+	for _, v := range report.Vulnerabilities {
+		item := &models.VulnerabilityItem{
+			ID:          v.ID,
+			Severity:    models.ParseSeverity(v.Severity),
+			Pkg:         v.Package,
+			Version:     v.Version,
+			Description: v.Description,
+			Fixed:       v.FixVersion,
+		}
+		if len(v.Links) > 0 {
+			item.Link = v.Links[0]
+		}
+		res.Vulnerabilities = append(res.Vulnerabilities, item)
+	}
+	return res, nil
+}
+
+// send sends the request and decodes the response body into v if the status code
+// is the expected one, the status code is returned if the request is sent
+func (g *genericAdapter) send(req *http.Request, expectedStatus int, v interface{}) (int, error) {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode != expectedStatus {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d, text: %s", resp.StatusCode, string(data))
+	}
+	return resp.StatusCode, json.Unmarshal(data, v)
+}
@@ -0,0 +1,159 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	// MimeTypeDockerManifest is the mime type of the artifacts which can be scanned
+	MimeTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	// MimeTypeVulnerabilityReport is the mime type of the report in the format of models.VulnerabilityReport
+	MimeTypeVulnerabilityReport = "application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+)
+
+// ErrReportNotReady is returned by Adapter.GetReport when the scanning is still in progress
+var ErrReportNotReady = errors.New("the scan report is not ready")
+
+// Metadata describes the scanner and what it is capable of
+type Metadata struct {
+	Name         string            `json:"name"`
+	Vendor       string            `json:"vendor"`
+	Version      string            `json:"version"`
+	Capabilities []*Capability     `json:"capabilities"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// Capability is a pair of the mime types of the artifacts the scanner consumes
+// and the reports it produces
+type Capability struct {
+	ConsumesMimeTypes []string `json:"consumes_mime_types"`
+	ProducesMimeTypes []string `json:"produces_mime_types"`
+}
+
+// Supports returns whether the scanner can scan the artifacts of the mime type
+// and produce the report of the mime type
+func (m *Metadata) Supports(consumes, produces string) bool {
+	for _, c := range m.Capabilities {
+		if contains(c.ConsumesMimeTypes, consumes) && contains(c.ProducesMimeTypes, produces) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanRequest is the request sent to the scanner to scan an artifact
+type ScanRequest struct {
+	Registry *Registry `json:"registry"`
+	Artifact *Artifact `json:"artifact"`
+}
+
+// Registry is the registry where the scanner pulls the artifact from
+type Registry struct {
+	URL string `json:"url"`
+	// the value of the "Authorization" header used to pull the artifact, e.g. "Bearer <token>"
+	Authorization string `json:"authorization"`
+}
+
+// Artifact is the artifact to be scanned
+type Artifact struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+	MimeType   string `json:"mime_type"`
+}
+
+// Adapter is the interface implemented by the scanners
+type Adapter interface {
+	// Metadata returns the metadata of the scanner
+	Metadata() (*Metadata, error)
+	// Scan submits the scan request and returns the ID of the scanning
+	Scan(req *ScanRequest) (string, error)
+	// GetReport returns the report of the scanning specified by ID,
+	// ErrReportNotReady is returned if the scanning isn't finished
+	GetReport(scanID string) (*models.VulnerabilityReport, error)
+}
+
+// Logger is used by the adapters to write the logs, it's implemented by both
+// the logger of Harbor and the logger of the job running the adapter
+type Logger interface {
+	Debugf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+	Warningf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+}
+
+// Factory creates the adapter to talk with the scanner at the URL
+type Factory func(url string, logger Logger) (Adapter, error)
+
+var (
+	factories = map[string]Factory{}
+	lock      sync.RWMutex
+)
+
+// Register registers the factory of the adapter type
+func Register(adapter string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, exist := factories[adapter]; exist {
+		log.Infof("scanner adapter: %s has been registered, skip", adapter)
+		return
+	}
+	factories[adapter] = factory
+	log.Debugf("Registered scanner adapter: %s", adapter)
+}
+
+// Adapters returns the sorted types of the registered adapters
+func Adapters() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	adapters := []string{}
+	for adapter := range factories {
+		adapters = append(adapters, adapter)
+	}
+	sort.Strings(adapters)
+	return adapters
+}
+
+// New creates the adapter of the type to talk with the scanner at the URL,
+// the logger is used to write the logs, e.g. to the job log, and can be nil
+func New(adapter, url string, logger Logger) (Adapter, error) {
+	lock.RLock()
+	factory, exist := factories[adapter]
+	lock.RUnlock()
+	if !exist {
+		return nil, fmt.Errorf("scanner adapter %s is not supported", adapter)
+	}
+	if logger == nil {
+		logger = log.DefaultLogger()
+	}
+	return factory(url, logger)
+}
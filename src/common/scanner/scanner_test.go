@@ -0,0 +1,135 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogger records the logs written by the adapter
+type fakeLogger struct {
+	messages []string
+}
+
+func (f *fakeLogger) log(format string, v ...interface{}) {
+	f.messages = append(f.messages, fmt.Sprintf(format, v...))
+}
+
+func (f *fakeLogger) Debugf(format string, v ...interface{})   { f.log(format, v...) }
+func (f *fakeLogger) Infof(format string, v ...interface{})    { f.log(format, v...) }
+func (f *fakeLogger) Warningf(format string, v ...interface{}) { f.log(format, v...) }
+func (f *fakeLogger) Errorf(format string, v ...interface{})   { f.log(format, v...) }
+
+func newFakeScanner(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/metadata", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Metadata{
+			Name:    "fake",
+			Vendor:  "Harbor",
+			Version: "1.0",
+			Capabilities: []*Capability{
+				{
+					ConsumesMimeTypes: []string{MimeTypeDockerManifest},
+					ProducesMimeTypes: []string{MimeTypeVulnerabilityReport},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/api/v1/scan", func(w http.ResponseWriter, r *http.Request) {
+		req := &ScanRequest{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, "Bearer token", req.Registry.Authorization)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"` + req.Artifact.Digest + `"}`))
+	})
+	mux.HandleFunc("/api/v1/scan/ready/report", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, MimeTypeVulnerabilityReport, r.Header.Get("Accept"))
+		w.Write([]byte(`{"components":2,"vulnerabilities":[{"id":"CVE-2019-0001","package":"openssl",
+			"version":"1.0","fix_version":"1.1","severity":"High","links":["https://cve.example.com/CVE-2019-0001"]}]}`))
+	})
+	mux.HandleFunc("/api/v1/scan/running/report", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", r.URL.Path)
+		w.WriteHeader(http.StatusFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRegister(t *testing.T) {
+	assert.Contains(t, Adapters(), AdapterGeneric)
+	// duplicate registration is skipped
+	Register(AdapterGeneric, nil)
+	_, err := New(AdapterGeneric, "http://scanner", nil)
+	assert.Nil(t, err)
+	_, err = New("non-exist", "http://scanner", nil)
+	assert.NotNil(t, err)
+}
+
+func TestGenericAdapter(t *testing.T) {
+	server := newFakeScanner(t)
+	defer server.Close()
+	logger := &fakeLogger{}
+	adapter, err := New(AdapterGeneric, server.URL+"/", logger)
+	require.Nil(t, err)
+
+	metadata, err := adapter.Metadata()
+	require.Nil(t, err)
+	assert.Equal(t, "fake", metadata.Name)
+	assert.True(t, metadata.Supports(MimeTypeDockerManifest, MimeTypeVulnerabilityReport))
+	assert.False(t, metadata.Supports("application/tar", MimeTypeVulnerabilityReport))
+
+	id, err := adapter.Scan(&ScanRequest{
+		Registry: &Registry{
+			URL:           "http://registry:5000",
+			Authorization: "Bearer token",
+		},
+		Artifact: &Artifact{
+			Repository: "library/hello-world",
+			Tag:        "latest",
+			Digest:     "ready",
+			MimeType:   MimeTypeDockerManifest,
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "ready", id)
+	// the logs are written to the logger passed in, e.g. the job logger
+	require.Equal(t, 1, len(logger.messages))
+	assert.Contains(t, logger.messages[0], "scan ID: ready")
+
+	report, err := adapter.GetReport(id)
+	require.Nil(t, err)
+	assert.Equal(t, 2, report.Components)
+	require.Equal(t, 1, len(report.Vulnerabilities))
+	assert.Equal(t, &models.VulnerabilityItem{
+		ID:       "CVE-2019-0001",
+		Severity: models.SevHigh,
+		Pkg:      "openssl",
+		Version:  "1.0",
+		Fixed:    "1.1",
+		Link:     "https://cve.example.com/CVE-2019-0001",
+	}, report.Vulnerabilities[0])
+
+	_, err = adapter.GetReport("running")
+	assert.Equal(t, ErrReportNotReady, err)
+	_, err = adapter.GetReport("non-exist")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/promgr"
+)
+
+// SecurityContext implements security.Context interface based on robot account,
+// the permissions are limited to the actions granted in the project of the robot
+type SecurityContext struct {
+	robot *models.Robot
+	pm    promgr.ProjectManager
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(robot *models.Robot, pm promgr.ProjectManager) *SecurityContext {
+	return &SecurityContext{
+		robot: robot,
+		pm:    pm,
+	}
+}
+
+// IsAuthenticated returns true if the robot account is neither disabled nor expired
+func (s *SecurityContext) IsAuthenticated() bool {
+	return s.robot != nil && s.robot.IsValid()
+}
+
+// GetUsername returns the username of the robot account
+// It returns null if the robot account has not been authenticated
+func (s *SecurityContext) GetUsername() string {
+	if !s.IsAuthenticated() {
+		return ""
+	}
+	project, err := s.pm.Get(s.robot.ProjectID)
+	if err != nil {
+		log.Errorf("failed to get project %d: %v", s.robot.ProjectID, err)
+		return ""
+	}
+	if project == nil {
+		return ""
+	}
+	return s.robot.Username(project.Name)
+}
+
+// IsSysAdmin always returns false
+func (s *SecurityContext) IsSysAdmin() bool {
+	return false
+}
+
+// IsSolutionUser always returns false
+func (s *SecurityContext) IsSolutionUser() bool {
+	return false
+}
+
+// HasReadPerm returns true if the project is public or the robot account
+// belongs to the project and is permitted to pull
+func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+	public, err := s.pm.IsPublic(projectIDOrName)
+	if err != nil {
+		log.Errorf("failed to check the public of project %v: %v",
+			projectIDOrName, err)
+		return false
+	}
+	if public {
+		return true
+	}
+	return s.can(projectIDOrName, models.RobotActionPull)
+}
+
+// HasWritePerm returns true if the robot account belongs to the
+// project and is permitted to push
+func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
+	return s.can(projectIDOrName, models.RobotActionPush)
+}
+
+// HasAllPerm always returns false, robot account can never manage the project
+func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
+	return false
+}
+
+// GetMyProjects returns the project which the robot account belongs to
+func (s *SecurityContext) GetMyProjects() ([]*models.Project, error) {
+	if !s.IsAuthenticated() {
+		return []*models.Project{}, nil
+	}
+	project, err := s.pm.Get(s.robot.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return []*models.Project{}, nil
+	}
+	return []*models.Project{project}, nil
+}
+
+// GetProjectRoles returns developer role if the robot account can push to
+// the project, guest role if it can only pull, otherwise returns nil
+func (s *SecurityContext) GetProjectRoles(projectIDOrName interface{}) []int {
+	roles := []int{}
+	if s.can(projectIDOrName, models.RobotActionPush) {
+		roles = append(roles, common.RoleDeveloper)
+	} else if s.can(projectIDOrName, models.RobotActionPull) {
+		roles = append(roles, common.RoleGuest)
+	}
+	return roles
+}
+
+// can returns whether the robot account is permitted to perform the action in the project
+func (s *SecurityContext) can(projectIDOrName interface{}, action string) bool {
+	if !s.IsAuthenticated() || !s.robot.Can(action) {
+		return false
+	}
+	project, err := s.pm.Get(projectIDOrName)
+	if err != nil {
+		log.Errorf("failed to get project %v: %v", projectIDOrName, err)
+		return false
+	}
+	if project == nil {
+		return false
+	}
+	return project.ProjectID == s.robot.ProjectID
+}
@@ -0,0 +1,137 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/promgr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	private = &models.Project{
+		ProjectID: 1,
+		Name:      "private_project",
+	}
+	another = &models.Project{
+		ProjectID: 2,
+		Name:      "another_project",
+	}
+	public = &models.Project{
+		ProjectID: 3,
+		Name:      "public_project",
+		Metadata: map[string]string{
+			models.ProMetaPublic: "true",
+		},
+	}
+)
+
+type fakePM struct {
+	promgr.ProjectManager
+}
+
+func (f *fakePM) Get(projectIDOrName interface{}) (*models.Project, error) {
+	for _, project := range []*models.Project{private, another, public} {
+		if projectIDOrName == project.ProjectID || projectIDOrName == project.Name {
+			return project, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakePM) IsPublic(projectIDOrName interface{}) (bool, error) {
+	project, _ := f.Get(projectIDOrName)
+	return project != nil && project.IsPublic(), nil
+}
+
+func newRobot(access string) *models.Robot {
+	return &models.Robot{
+		Name:      "ci",
+		ProjectID: private.ProjectID,
+		Access:    access,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestIsAuthenticated(t *testing.T) {
+	ctx := NewSecurityContext(nil, &fakePM{})
+	assert.False(t, ctx.IsAuthenticated())
+
+	robot := newRobot("pull")
+	ctx = NewSecurityContext(robot, &fakePM{})
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "robot$private_project+ci", ctx.GetUsername())
+
+	robot.Disabled = true
+	assert.False(t, ctx.IsAuthenticated())
+	assert.Equal(t, "", ctx.GetUsername())
+
+	robot.Disabled = false
+	robot.ExpiresAt = time.Now().Add(-1 * time.Hour)
+	assert.False(t, ctx.IsAuthenticated())
+}
+
+func TestIsSysAdmin(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull,push"), &fakePM{})
+	assert.False(t, ctx.IsSysAdmin())
+	assert.False(t, ctx.IsSolutionUser())
+}
+
+func TestHasReadPerm(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull"), &fakePM{})
+	assert.True(t, ctx.HasReadPerm(private.ProjectID))
+	assert.True(t, ctx.HasReadPerm(private.Name))
+	assert.False(t, ctx.HasReadPerm(another.Name))
+	assert.True(t, ctx.HasReadPerm(public.Name))
+
+	ctx = NewSecurityContext(newRobot("push"), &fakePM{})
+	assert.False(t, ctx.HasReadPerm(private.Name))
+}
+
+func TestHasWritePerm(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull"), &fakePM{})
+	assert.False(t, ctx.HasWritePerm(private.Name))
+
+	ctx = NewSecurityContext(newRobot("pull,push"), &fakePM{})
+	assert.True(t, ctx.HasWritePerm(private.Name))
+	assert.False(t, ctx.HasWritePerm(another.Name))
+	assert.False(t, ctx.HasWritePerm(public.Name))
+}
+
+func TestHasAllPerm(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull,push"), &fakePM{})
+	assert.False(t, ctx.HasAllPerm(private.Name))
+}
+
+func TestGetMyProjects(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull"), &fakePM{})
+	projects, err := ctx.GetMyProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	assert.Equal(t, private.Name, projects[0].Name)
+}
+
+func TestGetProjectRoles(t *testing.T) {
+	ctx := NewSecurityContext(newRobot("pull"), &fakePM{})
+	assert.Equal(t, []int{common.RoleGuest}, ctx.GetProjectRoles(private.Name))
+
+	ctx = NewSecurityContext(newRobot("pull,push"), &fakePM{})
+	assert.Equal(t, []int{common.RoleDeveloper}, ctx.GetProjectRoles(private.Name))
+	assert.Equal(t, 0, len(ctx.GetProjectRoles(another.Name)))
+}
@@ -0,0 +1,157 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clair
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
+)
+
+const (
+	// AdapterClair is the type of the scanner adapter for Clair
+	AdapterClair = "clair"
+	// ScannerName is the name of the Clair deployed with Harbor, it's used when
+	// the project doesn't choose a scanner and no default scanner is registered
+	ScannerName = "Clair"
+)
+
+func init() {
+	scanner.Register(AdapterClair, newAdapter)
+}
+
+// adapter scans the image by submitting all layers to Clair, the ID of the
+// scanning is the name of the top layer
+type adapter struct {
+	client     *Client
+	httpClient *http.Client
+}
+
+func newAdapter(url string, logger scanner.Logger) (scanner.Adapter, error) {
+	return &adapter{
+		client:     NewClient(url, logger),
+		httpClient: &http.Client{},
+	}, nil
+}
+
+func (a *adapter) Metadata() (*scanner.Metadata, error) {
+	return &scanner.Metadata{
+		Name:    ScannerName,
+		Vendor:  "CoreOS",
+		Version: "2.x",
+		Capabilities: []*scanner.Capability{
+			{
+				ConsumesMimeTypes: []string{scanner.MimeTypeDockerManifest},
+				ProducesMimeTypes: []string{scanner.MimeTypeVulnerabilityReport},
+			},
+		},
+	}, nil
+}
+
+func (a *adapter) Scan(req *scanner.ScanRequest) (string, error) {
+	payload, err := a.pullManifest(req)
+	if err != nil {
+		return "", err
+	}
+	layers, err := prepareLayers(payload, req.Registry, req.Artifact.Repository)
+	if err != nil {
+		return "", err
+	}
+	if len(layers) == 0 {
+		return "", fmt.Errorf("no layer found in the manifest of %s@%s", req.Artifact.Repository, req.Artifact.Digest)
+	}
+	for _, l := range layers {
+		a.client.logger.Infof("Scanning Layer: %s, path: %s", l.Name, l.Path)
+		if err := a.client.ScanLayer(l); err != nil {
+			a.client.logger.Errorf("Failed to scan layer: %s, error: %v", l.Name, err)
+			return "", err
+		}
+	}
+	return layers[len(layers)-1].Name, nil
+}
+
+// GetReport returns the vulnerabilities of the top layer, the layers are scanned
+// synchronously so the report is always ready
+func (a *adapter) GetReport(scanID string) (*models.VulnerabilityReport, error) {
+	res, err := a.client.GetResult(scanID)
+	if err != nil {
+		return nil, err
+	}
+	return ToReport(res), nil
+}
+
+func (a *adapter) pullManifest(scanReq *scanner.ScanRequest) ([]byte, error) {
+	reference := scanReq.Artifact.Digest
+	if len(reference) == 0 {
+		reference = scanReq.Artifact.Tag
+	}
+	url := fmt.Sprintf("%s/v2/%s/manifests/%s", strings.TrimSuffix(scanReq.Registry.URL, "/"),
+		scanReq.Artifact.Repository, reference)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Accept"), schema2.MediaTypeManifest)
+	req.Header.Set(http.CanonicalHeaderKey("Authorization"), scanReq.Registry.Authorization)
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to pull the manifest of %s:%s, status code: %d, text: %s",
+			scanReq.Artifact.Repository, reference, resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+func prepareLayers(payload []byte, registry *scanner.Registry, repo string) ([]models.ClairLayer, error) {
+	layers := []models.ClairLayer{}
+	manifest, _, err := distribution.UnmarshalManifest(schema2.MediaTypeManifest, payload)
+	if err != nil {
+		return layers, err
+	}
+	header := map[string]string{"Connection": "close", "Authorization": registry.Authorization}
+	// form the chain by using the digests of all parent layers in the image, such that if another image is built on top of this image the layer name can be re-used.
+	shaChain := ""
+	for _, d := range manifest.References() {
+		if d.MediaType == schema2.MediaTypeConfig {
+			continue
+		}
+		shaChain += string(d.Digest) + "-"
+		l := models.ClairLayer{
+			Name:    fmt.Sprintf("%x", sha256.Sum256([]byte(shaChain))),
+			Headers: header,
+			Format:  "Docker",
+			Path:    fmt.Sprintf("%s/v2/%s/blobs/%s", strings.TrimSuffix(registry.URL, "/"), repo, d.Digest),
+		}
+		if len(layers) > 0 {
+			l.ParentName = layers[len(layers)-1].Name
+		}
+		layers = append(layers, l)
+	}
+	return layers, nil
+}
@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clair
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/scanner"
+	"github.com/goharbor/harbor/src/common/utils/clair/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	configDigest = "sha256:4ab4c602aa5eed5528a6620ff18a1dc4faef0e1ab3a5eddeddb410714478c67f"
+	layer1Digest = "sha256:1b930d010525941c1d56ec53b97bd057a67ae1865eebf042686d2a2d18271ced"
+	layer2Digest = "sha256:0a2e0b9b1a6f4b53d2c4e4d2c0e6b1f2f1d7b0b3c2d8c9a0e7f6b5a4c3d2e1f0"
+)
+
+var manifest = fmt.Sprintf(`{
+	"schemaVersion": 2,
+	"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+	"config": {"mediaType": "application/vnd.docker.container.image.v1+json", "size": 1510, "digest": "%s"},
+	"layers": [
+		{"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "size": 977, "digest": "%s"},
+		{"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "size": 512, "digest": "%s"}
+	]
+}`, configDigest, layer1Digest, layer2Digest)
+
+func TestAdapter(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/library/hello-world/manifests/sha256:digest" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(manifest))
+	}))
+	defer registry.Close()
+	clairServer := test.NewMockServer()
+	defer clairServer.Close()
+
+	a, err := scanner.New(AdapterClair, clairServer.URL, nil)
+	require.Nil(t, err)
+	metadata, err := a.Metadata()
+	require.Nil(t, err)
+	assert.Equal(t, ScannerName, metadata.Name)
+	assert.True(t, metadata.Supports(scanner.MimeTypeDockerManifest, scanner.MimeTypeVulnerabilityReport))
+
+	req := &scanner.ScanRequest{
+		Registry: &scanner.Registry{
+			URL:           registry.URL,
+			Authorization: "Bearer token",
+		},
+		Artifact: &scanner.Artifact{
+			Repository: "library/hello-world",
+			Tag:        "latest",
+			Digest:     "sha256:digest",
+			MimeType:   scanner.MimeTypeDockerManifest,
+		},
+	}
+	id, err := a.Scan(req)
+	require.Nil(t, err)
+	// the ID is the name of the top layer
+	chain := layer1Digest + "-" + layer2Digest + "-"
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(chain))), id)
+
+	req.Registry.Authorization = "Bearer invalid"
+	_, err = a.Scan(req)
+	assert.NotNil(t, err)
+}
+
+func TestPrepareLayers(t *testing.T) {
+	layers, err := prepareLayers([]byte(manifest), &scanner.Registry{
+		URL:           "http://registry:5000/",
+		Authorization: "Bearer token",
+	}, "library/hello-world")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(layers))
+	assert.Equal(t, "http://registry:5000/v2/library/hello-world/blobs/"+layer1Digest, layers[0].Path)
+	assert.Equal(t, "", layers[0].ParentName)
+	assert.Equal(t, layers[0].Name, layers[1].ParentName)
+	assert.Equal(t, "Bearer token", layers[1].Headers["Authorization"])
+}
@@ -24,6 +24,7 @@ import (
 	//	"path"
 
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
 	"github.com/goharbor/harbor/src/common/utils/log"
 )
 
@@ -31,12 +32,12 @@ import (
 type Client struct {
 	endpoint string
 	// need to customize the logger to write output to job log.
-	logger *log.Logger
+	logger scanner.Logger
 	client *http.Client
 }
 
 // NewClient creates a new instance of client, set the logger as the job's logger if it's used in a job handler.
-func NewClient(endpoint string, logger *log.Logger) *Client {
+func NewClient(endpoint string, logger scanner.Logger) *Client {
 	if logger == nil {
 		// 日志搜集器
 		logger = log.DefaultLogger()
@@ -19,26 +19,14 @@ import (
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
-	"strings"
+	"time"
 )
 
 // var client = NewClient()
 
 // ParseClairSev parse the severity of clair to Harbor's Severity type if the string is not recognized the value will be set to unknown.
 func ParseClairSev(clairSev string) models.Severity {
-	sev := strings.ToLower(clairSev)
-	switch sev {
-	case models.SeverityNone:
-		return models.SevNone
-	case models.SeverityLow:
-		return models.SevLow
-	case models.SeverityMedium:
-		return models.SevMedium
-	case models.SeverityHigh, models.SeverityCritical:
-		return models.SevHigh
-	default:
-		return models.SevUnknown
-	}
+	return models.ParseSeverity(clairSev)
 }
 
 // UpdateScanOverview qeuries the vulnerability based on the layerName and update the record in img_scan_overview table based on digest.
@@ -59,6 +47,18 @@ func UpdateScanOverview(digest, layerName string, clairEndpoint string, l ...*lo
 		return err
 	}
 	compOverview, sev := transformVuln(res)
+	// refresh the report of Clair if the image is scanned after the reports are stored per scanner
+	report, err := dao.GetScanReport(digest, ScannerName)
+	if err != nil {
+		return err
+	}
+	if report != nil {
+		report.Sev = int(sev)
+		report.Report = ToReport(res)
+		if err = dao.SetScanReport(report); err != nil {
+			return err
+		}
+	}
 	// 更新img_scan_overview中的components和serverity记录
 	return dao.UpdateImgScanOverview(digest, layerName, sev, compOverview)
 }
@@ -115,3 +115,29 @@ func transformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOver
 func TransformVuln(clairVuln *models.ClairLayerEnvelope) (*models.ComponentsOverview, models.Severity) {
 	return transformVuln(clairVuln)
 }
+
+// ToReport converts the result of Clair to the scanner-neutral vulnerability report
+func ToReport(clairVuln *models.ClairLayerEnvelope) *models.VulnerabilityReport {
+	report := &models.VulnerabilityReport{
+		GeneratedAt:     time.Now(),
+		Vulnerabilities: []*models.VulnerabilityItem{},
+	}
+	if clairVuln == nil || clairVuln.Layer == nil {
+		return report
+	}
+	report.Components = len(clairVuln.Layer.Features)
+	for _, f := range clairVuln.Layer.Features {
+		for _, v := range f.Vulnerabilities {
+			report.Vulnerabilities = append(report.Vulnerabilities, &models.VulnerabilityItem{
+				ID:          v.Name,
+				Pkg:         f.Name,
+				Version:     f.Version,
+				Severity:    ParseClairSev(v.Severity),
+				Fixed:       v.FixedBy,
+				Link:        v.Link,
+				Description: v.Description,
+			})
+		}
+	}
+	return report
+}
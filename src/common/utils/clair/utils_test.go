@@ -77,3 +77,31 @@ func loadVuln(input []byte, data *models.ClairLayerEnvelope) {
 		panic(err)
 	}
 }
+
+func TestToReport(t *testing.T) {
+	assert := assert.New(t)
+	clairVuln := &models.ClairLayerEnvelope{}
+	loadVuln([]byte(`{"Layer":{"Name":"layer","Features":[
+		{"Name":"openssl","Version":"1.0","Vulnerabilities":[
+			{"Name":"CVE-2019-0001","Severity":"High","FixedBy":"1.1"},
+			{"Name":"CVE-2019-0002","Severity":"Low"}]},
+		{"Name":"bash","Version":"4.4","Vulnerabilities":[
+			{"Name":"CVE-2019-0003","Severity":"Medium"}]},
+		{"Name":"zlib","Version":"1.2"}]}}`), clairVuln)
+
+	report := ToReport(clairVuln)
+	assert.Equal(3, report.Components)
+	assert.Equal(3, len(report.Vulnerabilities))
+	assert.Equal("CVE-2019-0001", report.Vulnerabilities[0].ID)
+	assert.Equal("openssl", report.Vulnerabilities[0].Pkg)
+	assert.Equal("1.1", report.Vulnerabilities[0].Fixed)
+	assert.Equal(models.SevHigh, report.Vulnerabilities[0].Severity)
+
+	overview, sev := report.Overview()
+	expectedOverview, expectedSev := TransformVuln(clairVuln)
+	assert.Equal(expectedSev, sev)
+	assert.Equal(expectedOverview.Total, overview.Total)
+	assert.ElementsMatch(expectedOverview.Summary, overview.Summary)
+
+	assert.Equal(0, len(ToReport(nil).Vulnerabilities))
+}
@@ -0,0 +1,374 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// WellKnownURLSuffix is the path of the discovery document of the provider
+	WellKnownURLSuffix = "/.well-known/openid-configuration"
+	// ScopeOpenID is the scope required by the OpenID Connect requests
+	ScopeOpenID = "openid"
+)
+
+// ErrInvalidToken is returned when the ID token can not be verified
+var ErrInvalidToken = errors.New("invalid ID token")
+
+// Client provides funcs to run the authorization code flow of OpenID Connect.
+type Client interface {
+	// AuthCodeURL returns the URL of the provider to redirect the user to, the state
+	// will be sent back to the redirect URL
+	AuthCodeURL(state string) string
+	// ExchangeToken exchanges the authorization code for the token
+	ExchangeToken(code string) (*Token, error)
+	// RefreshToken gets a new token with the refresh token of the token
+	RefreshToken(token *Token) (*Token, error)
+	// VerifyToken verifies the signature, issuer, audience and expiry of the ID token
+	// and returns the claims in it
+	VerifyToken(rawIDToken string) (*Claims, error)
+}
+
+// ClientConfig values to initialize the OIDC Client
+type ClientConfig struct {
+	Endpoint      string
+	ClientID      string
+	ClientSecret  string
+	Scope         []string
+	RedirectURL   string
+	GroupsClaim   string
+	SkipTLSVerify bool
+}
+
+// Token wraps the OAuth2 token and the raw ID token returned by the provider
+type Token struct {
+	*oauth2.Token
+	IDToken string `json:"id_token"`
+}
+
+// Claims is the subset of the claims in the ID token used in Harbor
+type Claims struct {
+	Subject  string
+	Issuer   string
+	Username string
+	Name     string
+	Email    string
+	Groups   []string
+}
+
+// SubIss returns the string that identifies the user at the provider
+func (c *Claims) SubIss() string {
+	return c.Subject + c.Issuer
+}
+
+type providerMetadata struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type defaultClient struct {
+	sync.Mutex
+	httpClient  *http.Client
+	oauth2Cfg   *oauth2.Config
+	metadata    *providerMetadata
+	clientID    string
+	groupsClaim string
+	keys        map[string]*rsa.PublicKey
+}
+
+// NewDefaultClient creates an instance of defaultClient, the discovery document
+// of the provider is fetched to get the endpoints.
+func NewDefaultClient(cfg *ClientConfig) (Client, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	hc := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: cfg.SkipTLSVerify,
+			},
+		},
+	}
+	md := &providerMetadata{}
+	if err := getJSON(hc, endpoint+WellKnownURLSuffix, md); err != nil {
+		return nil, fmt.Errorf("failed to get the discovery document of %s: %v", endpoint, err)
+	}
+	if strings.TrimSuffix(md.Issuer, "/") != endpoint {
+		return nil, fmt.Errorf("the issuer %s in the discovery document does not match the endpoint %s", md.Issuer, endpoint)
+	}
+
+	scope := cfg.Scope
+	hasOpenID := false
+	for _, s := range scope {
+		if s == ScopeOpenID {
+			hasOpenID = true
+			break
+		}
+	}
+	if !hasOpenID {
+		scope = append([]string{ScopeOpenID}, scope...)
+	}
+
+	return &defaultClient{
+		httpClient: hc,
+		oauth2Cfg: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scope,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  md.AuthURL,
+				TokenURL: md.TokenURL,
+			},
+		},
+		metadata:    md,
+		clientID:    cfg.ClientID,
+		groupsClaim: cfg.GroupsClaim,
+		keys:        map[string]*rsa.PublicKey{},
+	}, nil
+}
+
+func (dc *defaultClient) AuthCodeURL(state string) string {
+	return dc.oauth2Cfg.AuthCodeURL(state, oauth2.AccessTypeOffline)
+}
+
+func (dc *defaultClient) ExchangeToken(code string) (*Token, error) {
+	t, err := dc.oauth2Cfg.Exchange(dc.prepareCtx(), code)
+	if err != nil {
+		return nil, err
+	}
+	return toToken(t)
+}
+
+func (dc *defaultClient) RefreshToken(token *Token) (*Token, error) {
+	if token == nil || token.Token == nil || len(token.RefreshToken) == 0 {
+		return nil, errors.New("no refresh token")
+	}
+	// set the expiry in the past to force the refreshing
+	expired := &oauth2.Token{
+		RefreshToken: token.RefreshToken,
+	}
+	t, err := dc.oauth2Cfg.TokenSource(dc.prepareCtx(), expired).Token()
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := toToken(t)
+	if err != nil {
+		return nil, err
+	}
+	// the provider may not return a new ID token when refreshing
+	if len(refreshed.IDToken) == 0 {
+		refreshed.IDToken = token.IDToken
+	}
+	return refreshed, nil
+}
+
+func (dc *defaultClient) VerifyToken(rawIDToken string) (*Claims, error) {
+	mc := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(rawIDToken, mc, dc.keyFunc); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidToken, err)
+	}
+	if !mc.VerifyIssuer(dc.metadata.Issuer, true) {
+		return nil, fmt.Errorf("%v: unexpected issuer", ErrInvalidToken)
+	}
+	if !verifyAudience(mc["aud"], dc.clientID) {
+		return nil, fmt.Errorf("%v: unexpected audience", ErrInvalidToken)
+	}
+	if _, ok := mc["exp"]; !ok {
+		return nil, fmt.Errorf("%v: no expiry", ErrInvalidToken)
+	}
+
+	claims := &Claims{
+		Subject:  stringClaim(mc, "sub"),
+		Issuer:   stringClaim(mc, "iss"),
+		Username: stringClaim(mc, "preferred_username"),
+		Name:     stringClaim(mc, "name"),
+		Email:    stringClaim(mc, "email"),
+	}
+	if len(claims.Subject) == 0 {
+		return nil, fmt.Errorf("%v: no subject", ErrInvalidToken)
+	}
+	if len(claims.Username) == 0 {
+		claims.Username = claims.Email
+	}
+	if len(claims.Username) == 0 {
+		claims.Username = claims.Subject
+	}
+	if len(dc.groupsClaim) > 0 {
+		if groups, ok := mc[dc.groupsClaim].([]interface{}); ok {
+			for _, g := range groups {
+				if s, ok := g.(string); ok && len(s) > 0 {
+					claims.Groups = append(claims.Groups, s)
+				}
+			}
+		}
+	}
+	return claims, nil
+}
+
+// keyFunc returns the public key of the provider to verify the ID token, the
+// key set is fetched again if the key ID is unknown as the keys may be rotated
+func (dc *defaultClient) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+
+	dc.Lock()
+	defer dc.Unlock()
+	if key := dc.findKey(kid); key != nil {
+		return key, nil
+	}
+	if err := dc.loadKeys(); err != nil {
+		return nil, err
+	}
+	if key := dc.findKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no key found for the ID token, kid: %s", kid)
+}
+
+func (dc *defaultClient) findKey(kid string) *rsa.PublicKey {
+	if len(kid) > 0 {
+		return dc.keys[kid]
+	}
+	// the token without kid can only be verified when the provider has only one key
+	if len(dc.keys) == 1 {
+		for _, key := range dc.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func (dc *defaultClient) loadKeys() error {
+	set := &jsonWebKeySet{}
+	if err := getJSON(dc.httpClient, dc.metadata.JWKSURL, set); err != nil {
+		return fmt.Errorf("failed to get the key set: %v", err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (len(k.Use) > 0 && k.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAKey(k)
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = key
+	}
+	dc.keys = keys
+	return nil
+}
+
+func (dc *defaultClient) prepareCtx() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, dc.httpClient)
+}
+
+func toToken(t *oauth2.Token) (*Token, error) {
+	token := &Token{
+		Token: t,
+	}
+	if idToken, ok := t.Extra("id_token").(string); ok {
+		token.IDToken = idToken
+	}
+	if len(token.IDToken) == 0 {
+		return nil, errors.New("no ID token returned by the provider")
+	}
+	return token, nil
+}
+
+func parseRSAKey(k jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus of key %s: %v", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent of key %s: %v", k.Kid, err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func verifyAudience(aud interface{}, clientID string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == clientID
+	case []interface{}:
+		for _, v := range a {
+			if s, ok := v.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stringClaim(mc jwt.MapClaims, name string) string {
+	s, _ := mc[name].(string)
+	return s
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, url, string(data))
+	}
+	return json.Unmarshal(data, v)
+}
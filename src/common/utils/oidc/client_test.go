@@ -0,0 +1,141 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var issuer *test.OIDCIssuer
+
+func TestMain(m *testing.M) {
+	var err error
+	issuer, err = test.NewOIDCIssuer("harbor")
+	if err != nil {
+		panic(err)
+	}
+	defer issuer.Close()
+	rc := m.Run()
+	if rc != 0 {
+		os.Exit(rc)
+	}
+}
+
+func getCfg() *ClientConfig {
+	return &ClientConfig{
+		Endpoint:     issuer.URL,
+		ClientID:     "harbor",
+		ClientSecret: "secret",
+		Scope:        []string{"profile", "email"},
+		RedirectURL:  "https://harbor.test/c/oidc/callback",
+		GroupsClaim:  "groups",
+	}
+}
+
+func TestNewDefaultClient(t *testing.T) {
+	_, err := NewDefaultClient(getCfg())
+	require.Nil(t, err)
+
+	cfg := getCfg()
+	cfg.Endpoint = issuer.URL + "/invalid"
+	_, err = NewDefaultClient(cfg)
+	assert.NotNil(t, err)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	client, err := NewDefaultClient(getCfg())
+	require.Nil(t, err)
+	u, err := url.Parse(client.AuthCodeURL("state"))
+	require.Nil(t, err)
+	assert.Equal(t, "/auth", u.Path)
+	assert.Equal(t, "state", u.Query().Get("state"))
+	assert.Equal(t, "harbor", u.Query().Get("client_id"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+	assert.Equal(t, "https://harbor.test/c/oidc/callback", u.Query().Get("redirect_uri"))
+}
+
+func TestExchangeAndRefreshToken(t *testing.T) {
+	client, err := NewDefaultClient(getCfg())
+	require.Nil(t, err)
+
+	_, err = client.ExchangeToken("invalid")
+	assert.NotNil(t, err)
+
+	code := issuer.Authorize(map[string]interface{}{
+		"sub":                "user1",
+		"preferred_username": "user1",
+		"email":              "user1@test.com",
+		"groups":             []string{"dev", "ops"},
+	})
+	token, err := client.ExchangeToken(code)
+	require.Nil(t, err)
+	claims, err := client.VerifyToken(token.IDToken)
+	require.Nil(t, err)
+	assert.Equal(t, "user1", claims.Username)
+	assert.Equal(t, "user1@test.com", claims.Email)
+	assert.Equal(t, []string{"dev", "ops"}, claims.Groups)
+	assert.Equal(t, "user1"+issuer.URL, claims.SubIss())
+
+	// the code can only be used once
+	_, err = client.ExchangeToken(code)
+	assert.NotNil(t, err)
+
+	refreshed, err := client.RefreshToken(token)
+	require.Nil(t, err)
+	assert.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+	claims, err = client.VerifyToken(refreshed.IDToken)
+	require.Nil(t, err)
+	assert.Equal(t, "user1", claims.Username)
+}
+
+func TestVerifyToken(t *testing.T) {
+	client, err := NewDefaultClient(getCfg())
+	require.Nil(t, err)
+
+	cases := []struct {
+		claims   map[string]interface{}
+		username string
+		valid    bool
+	}{
+		{map[string]interface{}{"sub": "user1"}, "user1", true},
+		{map[string]interface{}{"sub": "user1", "email": "user1@test.com"}, "user1@test.com", true},
+		{map[string]interface{}{"sub": "user1", "aud": []string{"other", "harbor"}}, "user1", true},
+		{map[string]interface{}{}, "", false},
+		{map[string]interface{}{"sub": "user1", "aud": "other"}, "", false},
+		{map[string]interface{}{"sub": "user1", "iss": "https://other.issuer"}, "", false},
+		{map[string]interface{}{"sub": "user1", "exp": time.Now().Add(-time.Minute).Unix()}, "", false},
+	}
+	for _, c := range cases {
+		raw, err := issuer.SignToken(c.claims)
+		require.Nil(t, err)
+		claims, err := client.VerifyToken(raw)
+		if !c.valid {
+			assert.NotNil(t, err)
+			continue
+		}
+		require.Nil(t, err)
+		assert.Equal(t, c.username, claims.Username)
+	}
+
+	_, err = client.VerifyToken("invalid")
+	assert.NotNil(t, err)
+}
@@ -46,3 +46,26 @@ func (b *basicAuthCredential) Modify(req *http.Request) error {
 	b.AddAuthorization(req)
 	return nil
 }
+
+// fallbackAuthorizer adds the authorization of the credential to the request only
+// when no authorization header has been added by the modifiers before it
+type fallbackAuthorizer struct {
+	credential Credential
+}
+
+// NewFallbackAuthorizer returns a modifier which can be chained after the token authorizer,
+// it makes the registry client work with the registries which protect their APIs with
+// basic authentication rather than token service
+func NewFallbackAuthorizer(credential Credential) modifier.Modifier {
+	return &fallbackAuthorizer{
+		credential: credential,
+	}
+}
+
+// Modify adds the authorization of the credential if needed
+func (f *fallbackAuthorizer) Modify(req *http.Request) error {
+	if len(req.Header.Get(http.CanonicalHeaderKey("Authorization"))) > 0 {
+		return nil
+	}
+	return f.credential.Modify(req)
+}
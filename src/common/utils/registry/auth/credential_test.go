@@ -41,3 +41,33 @@ func TestAddAuthorizationOfBasicAuthCredential(t *testing.T) {
 		t.Errorf("unexpected password: %s != pwd", pwd)
 	}
 }
+
+func TestModifyOfFallbackAuthorizer(t *testing.T) {
+	authorizer := NewFallbackAuthorizer(NewBasicAuthCredential("usr", "pwd"))
+
+	// no authorization header, the basic auth should be added
+	req, err := http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if err = authorizer.Modify(req); err != nil {
+		t.Fatalf("failed to modify request: %v", err)
+	}
+	usr, pwd, ok := req.BasicAuth()
+	if !ok || usr != "usr" || pwd != "pwd" {
+		t.Errorf("unexpected basic auth: %s, %s, %v", usr, pwd, ok)
+	}
+
+	// the authorization header added by token authorizer should be kept
+	req, err = http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	if err = authorizer.Modify(req); err != nil {
+		t.Fatalf("failed to modify request: %v", err)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("unexpected authorization header: %s", auth)
+	}
+}
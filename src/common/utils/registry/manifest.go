@@ -15,10 +15,47 @@
 package registry
 
 import (
+	"strings"
+
 	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
 )
 
 // UnMarshal converts []byte to be distribution.Manifest
 func UnMarshal(mediaType string, data []byte) (distribution.Manifest, distribution.Descriptor, error) {
 	return distribution.UnmarshalManifest(mediaType, data)
 }
+
+// PullManifestReferences pulls the manifest specified by the reference and returns the digest
+// of the manifest and the blobs it references. As the manifest of schema1 doesn't contain the
+// sizes of layers, the sizes are got by pulling the blobs
+func PullManifestReferences(repo *Repository, reference string) (string, []distribution.Descriptor, error) {
+	digest, mediaType, payload, err := repo.PullManifest(reference,
+		[]string{schema1.MediaTypeManifest, schema2.MediaTypeManifest})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.Contains(mediaType, "application/json") {
+		mediaType = schema1.MediaTypeManifest
+	}
+	manifest, _, err := UnMarshal(mediaType, payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	references := []distribution.Descriptor{}
+	for _, reference := range manifest.References() {
+		if reference.Size == 0 && mediaType == schema1.MediaTypeManifest {
+			size, data, err := repo.PullBlob(reference.Digest.String())
+			if err != nil {
+				return "", nil, err
+			}
+			data.Close()
+			reference.Size = size
+		}
+		references = append(references, reference)
+	}
+	return digest, references, nil
+}
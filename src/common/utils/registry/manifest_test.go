@@ -15,11 +15,30 @@
 package registry
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/utils/test"
 )
 
+var schema2Manifest = []byte(`{
+   "schemaVersion":2,
+   "mediaType":"application/vnd.docker.distribution.manifest.v2+json",
+   "config":{
+      "mediaType":"application/vnd.docker.container.image.v1+json",
+      "size":1473,
+      "digest":"sha256:c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc"
+   },
+   "layers":[
+      {
+         "mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip",
+         "size":974,
+         "digest":"sha256:c04b14da8d1441880ed3fe6106fb2cc6fa1c9661846ac0266b8a5ec8edf37b7c"
+      }
+   ]
+}`)
+
 func TestUnMarshal(t *testing.T) {
 	b := []byte(`{  
    "schemaVersion":2,
@@ -58,3 +77,43 @@ func TestUnMarshal(t *testing.T) {
 		t.Errorf("unexpected digest: %s != %s", refs[1].Digest.String(), digest)
 	}
 }
+
+func TestPullManifestReferences(t *testing.T) {
+	handler := test.Handler(&test.Response{
+		Headers: map[string]string{
+			"Docker-Content-Digest": digest,
+			"Content-Type":          schema2.MediaTypeManifest,
+		},
+		Body: schema2Manifest,
+	})
+
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: fmt.Sprintf("/v2/%s/manifests/%s", repository, tag),
+			Handler: handler,
+		})
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client for repository: %v", err)
+	}
+
+	d, refs, err := PullManifestReferences(client, tag)
+	if err != nil {
+		t.Fatalf("failed to pull manifest references: %v", err)
+	}
+
+	if d != digest {
+		t.Errorf("unexpected digest of manifest: %s != %s", d, digest)
+	}
+
+	if len(refs) != 2 {
+		t.Fatalf("unexpected length of reference: %d != %d", len(refs), 2)
+	}
+
+	if refs[0].Size != 1473 || refs[1].Size != 974 {
+		t.Errorf("unexpected sizes of references: %d, %d", refs[0].Size, refs[1].Size)
+	}
+}
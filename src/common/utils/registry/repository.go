@@ -299,32 +299,42 @@ func (r *Repository) DeleteTag(tag string) error {
 
 // BlobExist ...
 func (r *Repository) BlobExist(digest string) (bool, error) {
+	exist, _, err := r.StatBlob(digest)
+	return exist, err
+}
+
+// StatBlob checks the existence of the blob and returns its size reported by registry
+func (r *Repository) StatBlob(digest string) (bool, int64, error) {
 	req, err := http.NewRequest("HEAD", buildBlobURL(r.Endpoint.String(), r.Name, digest), nil)
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return false, parseError(err)
+		return false, 0, parseError(err)
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK {
-		return true, nil
+		size, err := strconv.ParseInt(resp.Header.Get(http.CanonicalHeaderKey("Content-Length")), 10, 64)
+		if err != nil {
+			return false, 0, err
+		}
+		return true, size, nil
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return false, nil
+		return false, 0, nil
 	}
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
 
-	return false, &registry_error.HTTPError{
+	return false, 0, &registry_error.HTTPError{
 		StatusCode: resp.StatusCode,
 		Detail:     string(b),
 	}
@@ -84,6 +84,14 @@ func TestBlobExist(t *testing.T) {
 		t.Errorf("blob should exist on registry, but it does not exist")
 	}
 
+	exist, size, err := client.StatBlob(digest)
+	if err != nil {
+		t.Fatalf("failed to stat blob: %v", err)
+	}
+	if !exist || size != int64(len(blob)) {
+		t.Errorf("unexpected result of stating blob: %t %d", exist, size)
+	}
+
 	exist, err = client.BlobExist("invalid_digest")
 	if err != nil {
 		t.Fatalf("failed to check the existence of blob: %v", err)
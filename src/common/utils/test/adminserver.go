@@ -20,6 +20,7 @@ import (
 	"net/http/httptest"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
 )
 
 var adminServerDefaultConfig = map[string]interface{}{
@@ -71,6 +72,13 @@ var adminServerDefaultConfig = map[string]interface{}{
 	common.UAAClientSecret:            "testsecret",
 	common.UAAEndpoint:                "10.192.168.5",
 	common.UAAVerifyCert:              false,
+	common.OIDCName:                   "test",
+	common.OIDCEndpoint:               "https://oidc.test",
+	common.OIDCClientID:               "testid",
+	common.OIDCClientSecret:           "testsecret",
+	common.OIDCScope:                  "openid,profile,email,offline_access",
+	common.OIDCVerifyCert:             false,
+	common.OIDCGroupsClaim:            "groups",
 	common.CoreURL:                    "http://myui:8888/",
 	common.JobServiceURL:              "http://myjob:8888/",
 	common.ReadOnly:                   false,
@@ -120,6 +128,70 @@ func NewAdminserver(config map[string]interface{}) (*httptest.Server, error) {
 			StatusCode: http.StatusOK,
 		}),
 	})
+
+	revision := &models.ConfigRevision{
+		ID:       1,
+		Operator: "admin",
+		Changes: []*models.ConfigChange{
+			{
+				Key: common.AUTHMode,
+				Old: common.DBAuth,
+				New: common.LDAPAuth,
+			},
+		},
+	}
+	// registered before the revision as the patterns are matched by prefix
+	b, err = json.Marshal(map[string]interface{}{
+		common.AUTHMode: common.DBAuth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m = append(m, &RequestHandlerMapping{
+		Method:  "GET",
+		Pattern: "/api/configurations/revisions/1/snapshot",
+		Handler: Handler(&Response{
+			StatusCode: http.StatusOK,
+			Body:       b,
+		}),
+	})
+
+	b, err = json.Marshal(revision)
+	if err != nil {
+		return nil, err
+	}
+	m = append(m, &RequestHandlerMapping{
+		Method:  "GET",
+		Pattern: "/api/configurations/revisions/1",
+		Handler: Handler(&Response{
+			StatusCode: http.StatusOK,
+			Body:       b,
+		}),
+	})
+
+	b, err = json.Marshal(&models.ConfigRevisionList{
+		Total:     1,
+		Revisions: []*models.ConfigRevision{revision},
+	})
+	if err != nil {
+		return nil, err
+	}
+	m = append(m, &RequestHandlerMapping{
+		Method:  "GET",
+		Pattern: "/api/configurations/revisions",
+		Handler: Handler(&Response{
+			StatusCode: http.StatusOK,
+			Body:       b,
+		}),
+	})
+
+	m = append(m, &RequestHandlerMapping{
+		Method:  "POST",
+		Pattern: "/api/configurations/revisions/1/rollback",
+		Handler: Handler(&Response{
+			StatusCode: http.StatusOK,
+		}),
+	})
 	return NewServer(m...), nil
 }
 
@@ -0,0 +1,190 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// the key ID of the signing key of the OIDC issuer
+const oidcKeyID = "test-key"
+
+// OIDCIssuer is a local OpenID Connect provider for testing, the claims of the
+// ID token returned for a code are registered by calling Authorize
+type OIDCIssuer struct {
+	*httptest.Server
+	ClientID string
+	key      *rsa.PrivateKey
+	lock     sync.Mutex
+	codes    map[string]map[string]interface{}
+	refresh  map[string]map[string]interface{}
+}
+
+// NewOIDCIssuer returns a started OIDC issuer which accepts the client ID
+func NewOIDCIssuer(clientID string) (*OIDCIssuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	issuer := &OIDCIssuer{
+		ClientID: clientID,
+		key:      key,
+		codes:    map[string]map[string]interface{}{},
+		refresh:  map[string]map[string]interface{}{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.serveDiscovery)
+	mux.HandleFunc("/keys", issuer.serveKeys)
+	mux.HandleFunc("/auth", issuer.serveAuth)
+	mux.HandleFunc("/token", issuer.serveToken)
+	issuer.Server = httptest.NewServer(mux)
+	return issuer, nil
+}
+
+// Authorize registers the claims of the user and returns the authorization
+// code which can be exchanged for the ID token
+func (o *OIDCIssuer) Authorize(claims map[string]interface{}) string {
+	code := utils.GenerateRandomString()
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.codes[code] = claims
+	return code
+}
+
+// SignToken returns the ID token signed by the issuer, the issuer, audience
+// and expiry are set if they are not specified in the claims
+func (o *OIDCIssuer) SignToken(claims map[string]interface{}) (string, error) {
+	mc := jwt.MapClaims{
+		"iss": o.URL,
+		"aud": o.ClientID,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		mc[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, mc)
+	token.Header["kid"] = oidcKeyID
+	return token.SignedString(o.key)
+}
+
+func (o *OIDCIssuer) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{
+		"issuer":                 o.URL,
+		"authorization_endpoint": o.URL + "/auth",
+		"token_endpoint":         o.URL + "/token",
+		"jwks_uri":               o.URL + "/keys",
+	})
+}
+
+func (o *OIDCIssuer) serveKeys(w http.ResponseWriter, r *http.Request) {
+	e := big.NewInt(int64(o.key.PublicKey.E)).Bytes()
+	writeJSON(w, map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kid": oidcKeyID,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(o.key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(e),
+			},
+		},
+	})
+}
+
+// serveAuth redirects the user back with a code for the subject "test"
+func (o *OIDCIssuer) serveAuth(w http.ResponseWriter, r *http.Request) {
+	redirect, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	q := redirect.Query()
+	q.Set("code", o.Authorize(map[string]interface{}{"sub": "test"}))
+	q.Set("state", r.URL.Query().Get("state"))
+	redirect.RawQuery = q.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (o *OIDCIssuer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	clientID, _, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+	}
+	if clientID != o.ClientID {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var store map[string]map[string]interface{}
+	var key string
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		store, key = o.codes, r.PostForm.Get("code")
+	case "refresh_token":
+		store, key = o.refresh, r.PostForm.Get("refresh_token")
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	o.lock.Lock()
+	claims, exist := store[key]
+	delete(store, key)
+	o.lock.Unlock()
+	if !exist {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := o.SignToken(claims)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	refreshToken := utils.GenerateRandomString()
+	o.lock.Lock()
+	o.refresh[refreshToken] = claims
+	o.lock.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"access_token":  utils.GenerateRandomString(),
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": refreshToken,
+		"id_token":      idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -218,3 +219,9 @@ func ParseOfftime(offtime int64) (hour, minite, second int) {
 func TrimLower(str string) string {
 	return strings.TrimSpace(strings.ToLower(str))
 }
+
+// Match reports whether the string matches the shell file name pattern, it is used by
+// the replication filters, the tag retention rules and the immutable tag rules
+func Match(pattern, str string) (bool, error) {
+	return filepath.Match(pattern, str)
+}
@@ -381,3 +381,24 @@ func TestTrimLower(t *testing.T) {
 		})
 	}
 }
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		str     string
+		matched bool
+	}{
+		{"", "", true},
+		{"*", "library", true},
+		{"library/*", "library/mysql", true},
+		{"library/*", "library/mysql/5.6", false},
+		{"library/mysq?", "library/mysql", true},
+		{"library/mysq?", "library/mysqld", false},
+	}
+
+	for _, c := range cases {
+		matched, err := Match(c.pattern, c.str)
+		require.Nil(t, err)
+		assert.Equal(t, c.matched, matched)
+	}
+}
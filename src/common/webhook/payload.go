@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// EventTypeHeader carries the event type of the payload
+	EventTypeHeader = "X-Harbor-Event"
+	// SignatureHeader carries the HMAC-SHA256 signature of the payload in
+	// the format "sha256=<hex>", it is only set when the policy has a secret
+	SignatureHeader = "X-Harbor-Signature"
+)
+
+// Payload is the JSON body sent to the target of webhook policy
+type Payload struct {
+	Type      string     `json:"type"`
+	OccurAt   int64      `json:"occur_at"`
+	Operator  string     `json:"operator"`
+	EventData *EventData `json:"event_data"`
+}
+
+// EventData contains the resources the event is about
+type EventData struct {
+	Resources  []*Resource `json:"resources"`
+	Repository *Repository `json:"repository"`
+	// the extra attributes of the event, e.g. the scan summary
+	CustomAttributes map[string]string `json:"custom_attributes,omitempty"`
+}
+
+// Resource is the image the event is about
+type Resource struct {
+	Digest      string `json:"digest,omitempty"`
+	Tag         string `json:"tag"`
+	ResourceURL string `json:"resource_url,omitempty"`
+}
+
+// Repository is the repository of the resources
+type Repository struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	RepoFullName string `json:"repo_full_name"`
+}
+
+// Sign returns the signature of the payload calculated with the secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns whether the signature of the payload is valid, it
+// can be used by the receivers of webhook
+func Verify(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}
@@ -0,0 +1,34 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"type":"pushImage"}`)
+	signature := Sign("secret", payload)
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Equal(t, 71, len(signature))
+	assert.Equal(t, signature, Sign("secret", payload))
+	assert.NotEqual(t, signature, Sign("another", payload))
+
+	assert.True(t, Verify("secret", payload, signature))
+	assert.False(t, Verify("secret", []byte(`{}`), signature))
+	assert.False(t, Verify("another", payload, signature))
+}
@@ -10,13 +10,21 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/core/label"
 
 	"github.com/goharbor/harbor/src/chartserver"
+	"github.com/goharbor/harbor/src/common/utils"
 	hlog "github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+	helm_chart "k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 const (
@@ -26,6 +34,8 @@ const (
 	rootUploadingEndpoint   = "/api/chartrepo/charts"
 	rootIndexEndpoint       = "/chartrepo/index.yaml"
 	chartRepoHealthEndpoint = "/api/chartrepo/health"
+	chartSearchEndpoint     = "/api/chartrepo/search"
+	chartTopEndpoint        = "/api/chartrepo/top"
 
 	accessLevelPublic = iota
 	accessLevelRead
@@ -33,6 +43,8 @@ const (
 	accessLevelAll
 	accessLevelSystem
 
+	filenameParam         = ":filename"
+	chartFileExt          = ".tgz"
 	formFieldNameForChart = "chart"
 	formFiledNameForProv  = "prov"
 	headerContentType     = "Content-Type"
@@ -74,7 +86,9 @@ func (cra *ChartRepositoryAPI) Prepare() {
 	}
 
 	if incomingURI != rootIndexEndpoint &&
-		incomingURI != chartRepoHealthEndpoint {
+		incomingURI != chartRepoHealthEndpoint &&
+		incomingURI != chartSearchEndpoint &&
+		incomingURI != chartTopEndpoint {
 		if !cra.requireNamespace(cra.namespace) {
 			return
 		}
@@ -142,6 +156,24 @@ func (cra *ChartRepositoryAPI) DownloadChart() {
 
 	// Directly proxy to the backend
 	chartController.ProxyTraffic(cra.Ctx.ResponseWriter, cra.Ctx.Request)
+
+	// Count the successful downloading of chart version asynchronously
+	if cra.Ctx.ResponseWriter.Status != http.StatusOK {
+		return
+	}
+	chartName, version, ok := parseChartFileName(cra.GetStringFromPath(filenameParam))
+	if !ok {
+		return
+	}
+	go func(namespace string) {
+		projectID, err := cra.getProjectID(namespace)
+		if err == nil {
+			err = dao.IncreaseChartDownloadCount(projectID, chartName, version)
+		}
+		if err != nil {
+			hlog.Errorf("Failed to increase the download count of chart %s: %v", chartFullName(namespace, chartName, version), err)
+		}
+	}(cra.namespace)
 }
 
 // ListCharts handles GET /api/:repo/charts
@@ -185,9 +217,137 @@ func (cra *ChartRepositoryAPI) ListChartVersions() {
 		chartVersion.Labels = labels
 	}
 
+	// Append download counts
+	projectID, err := cra.getProjectID(cra.namespace)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+	downloads, err := dao.GetChartDownloads(projectID, chartName)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+	for _, chartVersion := range versions {
+		chartVersion.DownloadCount = downloads[chartVersion.Version]
+	}
+
 	cra.WriteJSONData(versions)
 }
 
+// QueryCharts handles GET /api/chartrepo/search
+func (cra *ChartRepositoryAPI) QueryCharts() {
+	query := &chartserver.ChartQuery{
+		Keyword:    strings.TrimSpace(cra.GetString("q")),
+		AppVersion: strings.TrimSpace(cra.GetString("app_version")),
+		Maintainer: strings.TrimSpace(cra.GetString("maintainer")),
+		Sort:       strings.TrimSpace(cra.GetString("sort")),
+	}
+	if len(cra.GetString("deprecated")) > 0 {
+		deprecated, err := cra.GetBool("deprecated")
+		if err != nil {
+			cra.SendBadRequestError(fmt.Errorf("invalid deprecated flag: %v", err))
+			return
+		}
+		query.Deprecated = &deprecated
+	}
+	if err := query.Valid(); err != nil {
+		cra.SendBadRequestError(err)
+		return
+	}
+
+	labelID, err := cra.GetInt64("label_id", 0)
+	if err != nil || labelID < 0 {
+		cra.SendBadRequestError(fmt.Errorf("invalid label_id: %s", cra.GetString("label_id")))
+		return
+	}
+	if labelID > 0 {
+		rls, err := dao.ListResourceLabels(&models.ResourceLabelQuery{
+			LabelID:      labelID,
+			ResourceType: common.ResourceTypeChart,
+		})
+		if err != nil {
+			cra.SendInternalServerError(err)
+			return
+		}
+		query.Candidates = map[string]struct{}{}
+		for _, rl := range rls {
+			query.Candidates[rl.ResourceName] = struct{}{}
+		}
+	}
+
+	page, pageSize := cra.GetPaginationParams()
+
+	namespaces, err := cra.readableNamespaces()
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+
+	charts, err := chartController.QueryCharts(query, namespaces)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+
+	total := int64(len(charts))
+	start, end := (page-1)*pageSize, page*pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	cra.SetPaginationHeader(total, page, pageSize)
+	cra.WriteJSONData(charts[start:end])
+}
+
+// topChart is the chart with the total download count of all its versions
+type topChart struct {
+	Name          string `json:"name"`
+	Project       string `json:"project"`
+	DownloadCount int64  `json:"download_count"`
+}
+
+// GetTopCharts handles GET /api/chartrepo/top
+func (cra *ChartRepositoryAPI) GetTopCharts() {
+	count, err := cra.GetInt("count", 10)
+	if err != nil || count <= 0 {
+		cra.SendBadRequestError(fmt.Errorf("invalid count: %s", cra.GetString("count")))
+		return
+	}
+
+	projects, err := cra.readableProjects()
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+	projectNames := map[int64]string{}
+	projectIDs := []int64{}
+	for _, project := range projects {
+		projectNames[project.ProjectID] = project.Name
+		projectIDs = append(projectIDs, project.ProjectID)
+	}
+
+	charts, err := dao.GetTopCharts(projectIDs, count)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+
+	result := []*topChart{}
+	for _, chart := range charts {
+		result = append(result, &topChart{
+			Name:          fmt.Sprintf("%s/%s", projectNames[chart.ProjectID], chart.Chart),
+			Project:       projectNames[chart.ProjectID],
+			DownloadCount: chart.DownloadCount,
+		})
+	}
+
+	cra.WriteJSONData(result)
+}
+
 // GetChartVersion handles GET /api/:repo/charts/:name/:version
 func (cra *ChartRepositoryAPI) GetChartVersion() {
 	// Check access
@@ -213,6 +373,22 @@ func (cra *ChartRepositoryAPI) GetChartVersion() {
 	}
 	chartVersion.Labels = labels
 
+	// Append download count
+	projectID, err := cra.getProjectID(cra.namespace)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+	downloads, err := dao.GetChartDownloads(projectID, chartName)
+	if err != nil {
+		cra.SendInternalServerError(err)
+		return
+	}
+	chartVersion.DownloadCount = downloads[version]
+
+	// Resolve the images referenced in the values
+	cra.resolveChartImages(chartVersion.Images)
+
 	cra.WriteJSONData(chartVersion)
 }
 
@@ -237,6 +413,8 @@ func (cra *ChartRepositoryAPI) DeleteChartVersion() {
 		cra.SendInternalServerError(err)
 		return
 	}
+
+	cra.removeDownloadsOfChart(chartName, version)
 }
 
 // UploadChartVersion handles POST /api/:repo/charts
@@ -248,6 +426,11 @@ func (cra *ChartRepositoryAPI) UploadChartVersion() {
 		return
 	}
 
+	// Reject the re-uploading of existing chart version if it's not allowed
+	if !cra.requireOverwritable(formFieldNameForChart) {
+		return
+	}
+
 	// Rewrite file content if the content type is "multipart/form-data"
 	if isMultipartFormData(cra.Ctx.Request) {
 		formFiles := make([]formFile, 0)
@@ -276,6 +459,11 @@ func (cra *ChartRepositoryAPI) UploadChartProvFile() {
 		return
 	}
 
+	// Reject replacing the provenance file of existing chart version if it's not allowed
+	if !cra.requireOverwritable(formFiledNameForProv) {
+		return
+	}
+
 	// Rewrite file content if the content type is "multipart/form-data"
 	if isMultipartFormData(cra.Ctx.Request) {
 		formFiles := make([]formFile, 0)
@@ -322,6 +510,57 @@ func (cra *ChartRepositoryAPI) DeleteChart() {
 		cra.SendInternalServerError(err)
 		return
 	}
+
+	cra.removeDownloadsOfChart(chartName, "")
+}
+
+// Remove the download counts of the deleted chart, all the versions are
+// removed if the version is empty. Just log the error as the chart is gone.
+func (cra *ChartRepositoryAPI) removeDownloadsOfChart(chartName, version string) {
+	projectID, err := cra.getProjectID(cra.namespace)
+	if err == nil {
+		err = dao.DeleteChartDownloads(projectID, chartName, version)
+	}
+	if err != nil {
+		hlog.Errorf("Failed to remove the download counts of chart %s/%s: %v", cra.namespace, chartName, err)
+	}
+}
+
+// Get the ID of the project by the namespace
+func (cra *ChartRepositoryAPI) getProjectID(namespace string) (int64, error) {
+	project, err := cra.ProjectMgr.Get(namespace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project %s: %v", namespace, err)
+	}
+	if project == nil {
+		return 0, fmt.Errorf("project %s not found", namespace)
+	}
+
+	return project.ProjectID, nil
+}
+
+// Parse the chart name and version from the chart file name like "harbor-0.2.0.tgz",
+// both the chart name and the version may contain "-", so take the first
+// split point after which the rest is a valid semver
+func parseChartFileName(filename string) (string, string, bool) {
+	if !strings.HasSuffix(filename, chartFileExt) {
+		return "", "", false
+	}
+
+	base := strings.TrimSuffix(filename, chartFileExt)
+	for i := strings.Index(base, "-"); i > 0 && i < len(base)-1; {
+		if _, err := semver.NewVersion(base[i+1:]); err == nil {
+			return base[:i], base[i+1:], true
+		}
+
+		next := strings.Index(base[i+1:], "-")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	return "", "", false
 }
 
 func (cra *ChartRepositoryAPI) removeLabelsFromChart(chartName, version string) error {
@@ -421,6 +660,98 @@ func (cra *ChartRepositoryAPI) requireAccess(namespace string, accessLevel uint)
 	return true
 }
 
+// Check if the uploaded chart version or its provenance file, which is specified by the form field,
+// can be written under the chart overwrite setting of the project, the version which doesn't exist
+// yet can always be written.
+// If it can be written, return true
+// If it can not, the blocked attempt is recorded in the access log and return false
+func (cra *ChartRepositoryAPI) requireOverwritable(formField string) bool {
+	project, err := cra.ProjectMgr.Get(cra.namespace)
+	if err != nil {
+		cra.SendInternalServerError(fmt.Errorf("failed to get project %s: %v", cra.namespace, err))
+		return false
+	}
+	if project == nil {
+		cra.SendNotFoundError(fmt.Errorf("project %s not found", cra.namespace))
+		return false
+	}
+
+	overwrite := project.ChartOverwrite()
+	if overwrite == models.ChartOverwriteAllow ||
+		(overwrite == models.ChartOverwriteAdminOnly && cra.SecurityCtx.HasAllPerm(cra.namespace)) {
+		return true
+	}
+
+	content, err := cra.readUploadedFile(formField)
+	if err != nil {
+		cra.SendBadRequestError(err)
+		return false
+	}
+	var metadata *helm_chart.Metadata
+	if formField == formFiledNameForProv {
+		metadata, err = (&chartserver.ChartOperator{}).GetProvMetadata(content)
+	} else {
+		metadata, err = (&chartserver.ChartOperator{}).GetChartMetadata(content)
+	}
+	if err != nil {
+		cra.SendBadRequestError(fmt.Errorf("failed to parse the uploaded %s file: %v", formField, err))
+		return false
+	}
+
+	fullName := chartFullName(cra.namespace, metadata.GetName(), metadata.GetVersion())
+	if _, err = chartController.GetChartVersion(cra.namespace, metadata.GetName(), metadata.GetVersion()); err != nil {
+		// The chart version doesn't exist
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			hlog.Debugf("chart %s is not found: %v, it can be uploaded", fullName, err)
+			return true
+		}
+		cra.SendInternalServerError(fmt.Errorf("failed to get chart %s: %v", fullName, err))
+		return false
+	}
+
+	go func() {
+		if err := dao.AddAccessLog(models.AccessLog{
+			Username:  cra.SecurityCtx.GetUsername(),
+			ProjectID: project.ProjectID,
+			RepoName:  cra.namespace + "/" + metadata.GetName(),
+			RepoTag:   metadata.GetVersion(),
+			Operation: "overwrite_denied",
+			OpTime:    time.Now(),
+		}); err != nil {
+			hlog.Errorf("failed to add access log: %v", err)
+		}
+	}()
+
+	if overwrite == models.ChartOverwriteAdminOnly {
+		cra.SendForbiddenError(fmt.Errorf("chart %s already exists, only project admin can overwrite it", fullName))
+		return false
+	}
+	cra.SendConflictError(fmt.Errorf("chart %s already exists and can not be overwritten", fullName))
+	return false
+}
+
+// Read the content of the uploaded file from the multipart form field or the request body,
+// the request body is restored to be proxied to the backend server
+func (cra *ChartRepositoryAPI) readUploadedFile(formField string) ([]byte, error) {
+	if isMultipartFormData(cra.Ctx.Request) {
+		mFile, _, err := cra.GetFile(formField)
+		if err != nil {
+			return nil, fmt.Errorf("Get file content with multipart header from key '%s' failed with error: %s", formField, err.Error())
+		}
+		defer mFile.Close()
+
+		return ioutil.ReadAll(mFile)
+	}
+
+	content, err := ioutil.ReadAll(cra.Ctx.Request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the request body: %v", err)
+	}
+	cra.Ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(content))
+
+	return content, nil
+}
+
 // formFile is used to represent the uploaded files in the form
 type formFile struct {
 	// form field key contains the form file
@@ -515,3 +846,119 @@ func isMultipartFormData(req *http.Request) bool {
 func chartFullName(namespace, chartName, version string) string {
 	return fmt.Sprintf("%s/%s:%s", namespace, chartName, version)
 }
+
+// Resolve the images referenced in the chart values against the local repositories
+// and append the scan overview to the resolved ones. The images which are not
+// readable by the current user are left unresolved.
+func (cra *ChartRepositoryAPI) resolveChartImages(images []*chartserver.ChartImage) {
+	scanEnabled := coreutils.ScanEnabled()
+	for _, image := range images {
+		repository, ok := localRepositoryName(image.Repository)
+		if !ok {
+			continue
+		}
+
+		projectName, _ := utils.ParseRepository(repository)
+		if !cra.SecurityCtx.HasReadPerm(projectName) || !dao.RepositoryExists(repository) {
+			continue
+		}
+
+		client, err := coreutils.NewRepositoryClientForUI(cra.SecurityCtx.GetUsername(), repository)
+		if err != nil {
+			hlog.Errorf("Failed to initialize the client for %s: %v", repository, err)
+			continue
+		}
+		digest, exist, err := client.ManifestExist(image.Tag)
+		if err != nil {
+			hlog.Errorf("Failed to check the existence of %s:%s: %v", repository, image.Tag, err)
+			continue
+		}
+		if !exist {
+			continue
+		}
+
+		image.Resolved = true
+		image.Digest = digest
+		if scanEnabled {
+			allowlist, err := getCVEAllowlistOfRepository(repository)
+			if err != nil {
+				allowlist = models.CVESet{}
+				hlog.Errorf("Failed to get the CVE allowlist of %s: %v", repository, err)
+			}
+			image.ScanOverview = getScanOverview(digest, image.Tag, allowlist)
+		}
+	}
+}
+
+// Strip the registry host of the local Harbor from the image repository,
+// false is returned if the image is hosted by other registries
+func localRepositoryName(repository string) (string, bool) {
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	host := parts[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return repository, true
+	}
+
+	extURL, err := config.ExtURL()
+	if err != nil || !strings.EqualFold(host, extURL) {
+		return "", false
+	}
+	if !strings.Contains(parts[1], "/") {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// Get the names of the projects which can be read by the current user
+func (cra *ChartRepositoryAPI) readableNamespaces() ([]string, error) {
+	projects, err := cra.readableProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	for _, p := range projects {
+		namespaces = append(namespaces, p.Name)
+	}
+
+	return namespaces, nil
+}
+
+// Get the projects which can be read by the current user
+func (cra *ChartRepositoryAPI) readableProjects() ([]*models.Project, error) {
+	if cra.SecurityCtx.IsSysAdmin() {
+		result, err := cra.ProjectMgr.List(nil)
+		if err != nil {
+			return nil, err
+		}
+		return result.Projects, nil
+	}
+
+	projects, err := cra.ProjectMgr.GetPublic()
+	if err != nil {
+		return nil, err
+	}
+	if cra.SecurityCtx.IsAuthenticated() {
+		myProjects, err := cra.SecurityCtx.GetMyProjects()
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, myProjects...)
+	}
+
+	exist := map[int64]bool{}
+	result := []*models.Project{}
+	for _, p := range projects {
+		if !exist[p.ProjectID] {
+			exist[p.ProjectID] = true
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
@@ -7,8 +7,12 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/chartserver"
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/core/promgr/metamgr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	helm_repo "k8s.io/helm/pkg/repo"
 )
 
 var (
@@ -184,6 +188,131 @@ func TestGetChartVersion(t *testing.T) {
 	if chartV.Metadata.GetVersion() != "0.2.0" {
 		t.Fatalf("expect get chart version '0.2.0' but got %s", chartV.Metadata.GetVersion())
 	}
+
+	if len(chartV.Images) == 0 {
+		t.Fatal("expect at least 1 image referenced in the chart values but got 0")
+	}
+}
+
+// Test querying charts
+func TestQueryCharts(t *testing.T) {
+	charts := []*helm_repo.ChartVersion{}
+	err := handleAndParse(&testingRequest{
+		url:    "/api/chartrepo/search",
+		method: http.MethodGet,
+		queryStruct: struct {
+			Keyword    string `url:"q"`
+			Maintainer string `url:"maintainer"`
+		}{
+			Keyword:    "harbor",
+			Maintainer: "Jesse",
+		},
+		credential: sysAdmin,
+	}, &charts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, chart := range charts {
+		if chart.Name == "library/harbor" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("expect the chart 'library/harbor' in the result but got nothing")
+	}
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			url:    "/api/chartrepo/search",
+			method: http.MethodGet,
+			queryStruct: struct {
+				AppVersion string `url:"app_version"`
+			}{
+				AppVersion: "not-a-constraint",
+			},
+			credential: sysAdmin,
+		},
+		code: http.StatusBadRequest,
+	})
+}
+
+// Test getting top charts
+func TestGetTopCharts(t *testing.T) {
+	require.Nil(t, dao.IncreaseChartDownloadCount(1, "harbor", "0.2.0"))
+	defer dao.DeleteChartDownloads(1, "harbor", "")
+
+	charts := []*topChart{}
+	err := handleAndParse(&testingRequest{
+		url:        "/api/chartrepo/top",
+		method:     http.MethodGet,
+		credential: sysAdmin,
+	}, &charts)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(charts))
+	assert.Equal(t, "library/harbor", charts[0].Name)
+	assert.Equal(t, int64(1), charts[0].DownloadCount)
+
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			url:    "/api/chartrepo/top",
+			method: http.MethodGet,
+			queryStruct: struct {
+				Count int `url:"count"`
+			}{
+				Count: -1,
+			},
+			credential: sysAdmin,
+		},
+		code: http.StatusBadRequest,
+	})
+}
+
+// Test parsing the chart name and version from the chart file name
+func TestParseChartFileName(t *testing.T) {
+	cases := []struct {
+		filename string
+		name     string
+		version  string
+		ok       bool
+	}{
+		{"harbor-0.2.0.tgz", "harbor", "0.2.0", true},
+		{"hello-helm-0.1.0.tgz", "hello-helm", "0.1.0", true},
+		{"hello-helm-1.0.0-rc1.tgz", "hello-helm", "1.0.0-rc1", true},
+		{"harbor-0.2.0.tgz.prov", "", "", false},
+		{"harbor.tgz", "", "", false},
+		{"harbor-latest.tgz", "", "", false},
+	}
+
+	for _, c := range cases {
+		name, version, ok := parseChartFileName(c.filename)
+		assert.Equal(t, c.name, name, c.filename)
+		assert.Equal(t, c.version, version, c.filename)
+		assert.Equal(t, c.ok, ok, c.filename)
+	}
+}
+
+// Test stripping the registry host from the image repository
+func TestLocalRepositoryName(t *testing.T) {
+	cases := []struct {
+		repository string
+		name       string
+		local      bool
+	}{
+		{"nginx", "", false},
+		{"library/redis", "library/redis", true},
+		{"docker.io/library/redis", "", false},
+		{"localhost:5000/library/redis", "", false},
+	}
+
+	for _, c := range cases {
+		name, local := localRepositoryName(c.repository)
+		if name != c.name || local != c.local {
+			t.Errorf("expect (%s, %v) for %s but got (%s, %v)", c.name, c.local, c.repository, name, local)
+		}
+	}
 }
 
 // Test delete chart version
@@ -21,6 +21,7 @@ import (
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
@@ -108,7 +109,7 @@ func (c *ConfigAPI) Put() {
 		c.CustomAbort(http.StatusBadRequest, err.Error())
 	}
 
-	if err := config.Upload(cfg); err != nil {
+	if err := config.Upload(cfg, c.SecurityCtx.GetUsername()); err != nil {
 		log.Errorf("failed to upload configurations: %v", err)
 		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
@@ -126,12 +127,115 @@ func (c *ConfigAPI) Put() {
 
 // Reset system configurations
 func (c *ConfigAPI) Reset() {
-	if err := config.Reset(); err != nil {
+	if err := config.Reset(c.SecurityCtx.GetUsername()); err != nil {
 		log.Errorf("failed to reset configurations: %v", err)
 		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
 }
 
+// ListRevisions lists the revisions of system configurations, the revisions can be
+// filtered by the operator and the changed key, e.g. key=auth_mode shows who changed
+// the authentication mode and when
+func (c *ConfigAPI) ListRevisions() {
+	page, size := c.GetPaginationParams()
+	list, err := config.ListRevisions(&models.ConfigRevisionQuery{
+		Operator: c.GetString("operator"),
+		Key:      c.GetString("key"),
+		Pagination: models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	})
+	if err != nil {
+		log.Errorf("failed to list the revisions of configurations: %v", err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	c.SetPaginationHeader(list.Total, page, size)
+	c.Data["json"] = list.Revisions
+	c.ServeJSON()
+}
+
+// GetRevision returns the revision of system configurations specified by ID
+func (c *ConfigAPI) GetRevision() {
+	id, err := c.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		c.SendBadRequestError(fmt.Errorf("invalid revision ID: %s", c.GetStringFromPath(":id")))
+		return
+	}
+
+	revision, err := config.GetRevision(id)
+	if err != nil {
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			c.SendNotFoundError(fmt.Errorf("revision %d not found", id))
+			return
+		}
+		log.Errorf("failed to get the revision %d of configurations: %v", id, err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	c.Data["json"] = revision
+	c.ServeJSON()
+}
+
+// Rollback restores system configurations to the revision specified by ID
+func (c *ConfigAPI) Rollback() {
+	id, err := c.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		c.SendBadRequestError(fmt.Errorf("invalid revision ID: %s", c.GetStringFromPath(":id")))
+		return
+	}
+
+	snapshot, err := config.GetRevisionSnapshot(id)
+	if err != nil {
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			c.SendNotFoundError(fmt.Errorf("revision %d not found", id))
+			return
+		}
+		log.Errorf("failed to get the snapshot of revision %d of configurations: %v", id, err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	// only the keys which can be updated by users are restored, and they are validated
+	// in the same way as updating them
+	cfg := map[string]interface{}{}
+	keys := []string{}
+	for _, k := range common.HarborValidKeys {
+		if v, ok := snapshot[k]; ok {
+			cfg[k] = v
+			keys = append(keys, k)
+		}
+	}
+
+	isSysErr, err := validateCfg(cfg)
+	if err != nil {
+		if isSysErr {
+			log.Errorf("failed to validate configurations: %v", err)
+			c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+
+		c.CustomAbort(http.StatusBadRequest, err.Error())
+	}
+
+	if err := config.Rollback(id, c.SecurityCtx.GetUsername(), keys); err != nil {
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			c.SendNotFoundError(fmt.Errorf("revision %d not found", id))
+			return
+		}
+		log.Errorf("failed to rollback configurations to revision %d: %v", id, err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	if err := config.Load(); err != nil {
+		log.Errorf("failed to load configurations: %v", err)
+		c.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	if err := watchConfigChanges(cfg); err != nil {
+		log.Errorf("Failed to watch configuration change with error: %s\n", err)
+	}
+}
+
 func validateCfg(c map[string]interface{}) (bool, error) {
 	strMap := map[string]string{}
 	for k := range common.HarborStringKeysMap {
@@ -170,8 +274,8 @@ func validateCfg(c map[string]interface{}) (bool, error) {
 	}
 
 	if value, ok := strMap[common.AUTHMode]; ok {
-		if value != common.DBAuth && value != common.LDAPAuth && value != common.UAAAuth {
-			return false, fmt.Errorf("invalid %s, shoud be one of %s, %s, %s", common.AUTHMode, common.DBAuth, common.LDAPAuth, common.UAAAuth)
+		if value != common.DBAuth && value != common.LDAPAuth && value != common.UAAAuth && value != common.OIDCAuth {
+			return false, fmt.Errorf("invalid %s, shoud be one of %s, %s, %s, %s", common.AUTHMode, common.DBAuth, common.LDAPAuth, common.UAAAuth, common.OIDCAuth)
 		}
 		flag, err := authModeCanBeModified()
 		if err != nil {
@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// CVEAllowlistAPI handles requests to /api/system/cve_allowlist and /api/projects/{}/cve_allowlist,
+// the system allowlist is stored with project ID 0
+type CVEAllowlistAPI struct {
+	BaseController
+	projectID int64
+}
+
+// Prepare validates the user and the project, the system allowlist can be read by
+// all users and updated by the system admin, the project allowlist can be read by
+// the members of the project and updated by the project admin
+func (c *CVEAllowlistAPI) Prepare() {
+	c.BaseController.Prepare()
+
+	if !c.SecurityCtx.IsAuthenticated() {
+		c.HandleUnauthorized()
+		return
+	}
+
+	if len(c.GetStringFromPath(":pid")) == 0 {
+		if c.Ctx.Request.Method != http.MethodGet && !c.SecurityCtx.IsSysAdmin() {
+			c.HandleForbidden(c.SecurityCtx.GetUsername())
+			return
+		}
+		return
+	}
+
+	pid, err := c.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		c.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", c.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := c.ProjectMgr.Get(pid)
+	if err != nil {
+		c.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		c.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	if c.Ctx.Request.Method == http.MethodGet {
+		if !c.SecurityCtx.HasReadPerm(pid) {
+			c.HandleForbidden(c.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !c.SecurityCtx.HasAllPerm(pid) {
+		c.HandleForbidden(c.SecurityCtx.GetUsername())
+		return
+	}
+	c.projectID = pid
+}
+
+// Get returns the CVE allowlist, an empty allowlist is returned if it is not set
+func (c *CVEAllowlistAPI) Get() {
+	allowlist, err := dao.GetCVEAllowlist(c.projectID)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get the CVE allowlist of project %d: %v", c.projectID, err))
+		return
+	}
+	if allowlist == nil {
+		allowlist = &models.CVEAllowlist{
+			ProjectID: c.projectID,
+			Items:     []models.CVEAllowlistItem{},
+		}
+	}
+	c.Data["json"] = allowlist
+	c.ServeJSON()
+}
+
+// Put replaces the CVE allowlist
+func (c *CVEAllowlistAPI) Put() {
+	allowlist := &models.CVEAllowlist{}
+	c.DecodeJSONReqAndValidate(allowlist)
+	allowlist.ProjectID = c.projectID
+	if err := dao.SetCVEAllowlist(allowlist); err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to set the CVE allowlist of project %d: %v", c.projectID, err))
+		return
+	}
+}
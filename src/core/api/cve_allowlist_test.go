@@ -0,0 +1,130 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCVEAllowlistAPI(t *testing.T) {
+	allowlist := &models.CVEAllowlist{
+		Items: []models.CVEAllowlistItem{
+			{CVEID: "CVE-2019-1234"},
+		},
+	}
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/system/cve_allowlist",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 200 get system allowlist by non system admin
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/system/cve_allowlist",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 403 update system allowlist by non system admin
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/system/cve_allowlist",
+				bodyJSON:   allowlist,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400 duplicate CVE ID
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    "/api/system/cve_allowlist",
+				bodyJSON: &models.CVEAllowlist{
+					Items: []models.CVEAllowlistItem{
+						{CVEID: "CVE-2019-1234"},
+						{CVEID: "CVE-2019-1234"},
+					},
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200 update system allowlist
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/system/cve_allowlist",
+				bodyJSON:   allowlist,
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404 non-exist project
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/cve_allowlist",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 403 update project allowlist by developer
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/projects/1/cve_allowlist",
+				bodyJSON:   allowlist,
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 200 update project allowlist
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/projects/1/cve_allowlist",
+				bodyJSON:   allowlist,
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer dao.DeleteCVEAllowlist(0)
+	defer dao.DeleteCVEAllowlist(1)
+
+	l := &models.CVEAllowlist{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        "/api/projects/1/cve_allowlist",
+		credential: projDeveloper,
+	}, l)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), l.ProjectID)
+	require.Equal(t, 1, len(l.Items))
+	assert.Equal(t, "CVE-2019-1234", l.Items[0].CVEID)
+}
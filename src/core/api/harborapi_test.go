@@ -33,6 +33,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/filter"
+	"github.com/goharbor/harbor/src/core/systeminfo"
 	"github.com/goharbor/harbor/tests/apitests/apilib"
 	//	"strconv"
 	//	"strings"
@@ -86,6 +87,7 @@ func init() {
 		log.Fatalf("failed to get database configurations: %v", err)
 	}
 	dao.InitDatabase(database)
+	systeminfo.Init()
 	_, file, _, _ := runtime.Caller(0)
 	dir := filepath.Dir(file)
 	dir = filepath.Join(dir, "..")
@@ -103,8 +105,23 @@ func init() {
 	beego.Router("/api/users", &UserAPI{}, "get:List;post:Post;delete:Delete;put:Put")
 	beego.Router("/api/users/:id([0-9]+)/password", &UserAPI{}, "put:ChangePassword")
 	beego.Router("/api/users/:id/sysadmin", &UserAPI{}, "put:ToggleUserAdminRole")
+	beego.Router("/api/users/:id/cli_secret", &UserAPI{}, "get:GetCLISecret;put:SetCLISecret")
 	beego.Router("/api/projects/:id([0-9]+)/logs", &ProjectAPI{}, "get:Logs")
 	beego.Router("/api/projects/:id([0-9]+)/_deletable", &ProjectAPI{}, "get:Deletable")
+	beego.Router("/api/projects/:id([0-9]+)/summary", &ProjectAPI{}, "get:Summary")
+	beego.Router("/api/projects/:pid([0-9]+)/robots", &RobotAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)", &RobotAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/immutabletagrules", &ImmutableTagRuleAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/immutabletagrules/:id([0-9]+)", &ImmutableTagRuleAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/cve_allowlist", &CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies", &WebhookPolicyAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies/:id([0-9]+)", &WebhookPolicyAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies/:id([0-9]+)/executions", &WebhookPolicyAPI{}, "get:ListExecutions")
+	beego.Router("/api/projects/:id([0-9]+)/retention", &RetentionAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:id([0-9]+)/retention/candidates", &RetentionAPI{}, "get:Candidates")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions", &RetentionAPI{}, "post:Execute;get:ListExecutions")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions/:eid([0-9]+)", &RetentionAPI{}, "get:GetExecution")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions/:eid([0-9]+)/log", &RetentionAPI{}, "get:GetExecutionLog")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/?:name", &MetadataAPI{}, "get:Get")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/", &MetadataAPI{}, "post:Post")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/:name", &MetadataAPI{}, "put:Put;delete:Delete")
@@ -141,6 +158,9 @@ func init() {
 	beego.Router("/api/ldap/users/import", &LdapAPI{}, "post:ImportUser")
 	beego.Router("/api/configurations", &ConfigAPI{})
 	beego.Router("/api/configurations/reset", &ConfigAPI{}, "post:Reset")
+	beego.Router("/api/configurations/revisions", &ConfigAPI{}, "get:ListRevisions")
+	beego.Router("/api/configurations/revisions/:id([0-9]+)", &ConfigAPI{}, "get:GetRevision")
+	beego.Router("/api/configurations/revisions/:id([0-9]+)/rollback", &ConfigAPI{}, "post:Rollback")
 	beego.Router("/api/configs", &ConfigAPI{}, "get:GetInternalConfig")
 	beego.Router("/api/email/ping", &EmailAPI{}, "post:Ping")
 	beego.Router("/api/replications", &ReplicationAPI{})
@@ -151,10 +171,18 @@ func init() {
 	beego.Router("/api/system/gc/:id", &GCAPI{}, "get:GetGC")
 	beego.Router("/api/system/gc/:id([0-9]+)/log", &GCAPI{}, "get:GetLog")
 	beego.Router("/api/system/gc/schedule", &GCAPI{}, "get:Get;put:Put;post:Post")
+	beego.Router("/api/internal/gc/orphans/purge", &InternalGCAPI{}, "post:PurgeOrphans")
+	beego.Router("/api/internal/gc/usages/refresh", &InternalGCAPI{}, "post:RefreshUsages")
+	beego.Router("/api/system/cve_allowlist", &CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/scanners", &ScannerAPI{}, "post:Post;get:List")
+	beego.Router("/api/scanners/:id([0-9]+)", &ScannerAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/scanners/:id([0-9]+)/metadata", &ScannerAPI{}, "get:Metadata")
 
 	// Charts are controlled under projects
 	chartRepositoryAPIType := &ChartRepositoryAPI{}
 	beego.Router("/api/chartrepo/health", chartRepositoryAPIType, "get:GetHealthStatus")
+	beego.Router("/api/chartrepo/search", chartRepositoryAPIType, "get:QueryCharts")
+	beego.Router("/api/chartrepo/top", chartRepositoryAPIType, "get:GetTopCharts")
 	beego.Router("/api/chartrepo/:repo/charts", chartRepositoryAPIType, "get:ListCharts")
 	beego.Router("/api/chartrepo/:repo/charts/:name", chartRepositoryAPIType, "get:ListChartVersions")
 	beego.Router("/api/chartrepo/:repo/charts/:name", chartRepositoryAPIType, "delete:DeleteChart")
@@ -0,0 +1,173 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// ImmutableTagRuleAPI handles requests to /api/projects/{}/immutabletagrules/{}
+type ImmutableTagRuleAPI struct {
+	BaseController
+	project *models.Project
+	rule    *models.ImmutableTagRule
+}
+
+// Prepare validates the project and the rule, the members of the project can
+// list the rules and only the project admin can manage them
+func (i *ImmutableTagRuleAPI) Prepare() {
+	i.BaseController.Prepare()
+
+	if !i.SecurityCtx.IsAuthenticated() {
+		i.HandleUnauthorized()
+		return
+	}
+
+	pid, err := i.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		i.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", i.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := i.ProjectMgr.Get(pid)
+	if err != nil {
+		i.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		i.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	i.project = project
+
+	if i.Ctx.Request.Method == http.MethodGet {
+		if !i.SecurityCtx.HasReadPerm(pid) {
+			i.HandleForbidden(i.SecurityCtx.GetUsername())
+			return
+		}
+	} else if !i.SecurityCtx.HasAllPerm(pid) {
+		i.HandleForbidden(i.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(i.GetStringFromPath(":id")) != 0 {
+		id, err := i.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			i.HandleBadRequest(fmt.Sprintf("invalid rule ID: %s", i.GetStringFromPath(":id")))
+			return
+		}
+		rule, err := dao.GetImmutableTagRule(id)
+		if err != nil {
+			i.HandleInternalServerError(fmt.Sprintf("failed to get immutable tag rule %d: %v", id, err))
+			return
+		}
+		if rule == nil || rule.ProjectID != project.ProjectID {
+			i.HandleNotFound(fmt.Sprintf("immutable tag rule %d not found", id))
+			return
+		}
+		i.rule = rule
+	}
+}
+
+// Post creates an immutable tag rule
+func (i *ImmutableTagRuleAPI) Post() {
+	rule := &models.ImmutableTagRule{}
+	i.DecodeJSONReqAndValidate(rule)
+	rule.ID = 0
+	rule.ProjectID = i.project.ProjectID
+
+	duplicated, err := i.isDuplicated(rule)
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to list immutable tag rules: %v", err))
+		return
+	}
+	if duplicated {
+		i.HandleConflict()
+		return
+	}
+
+	id, err := dao.AddImmutableTagRule(rule)
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to create immutable tag rule: %v", err))
+		return
+	}
+	i.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List the immutable tag rules of the project
+func (i *ImmutableTagRuleAPI) List() {
+	rules, err := dao.ListImmutableTagRules(i.project.ProjectID)
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to list immutable tag rules: %v", err))
+		return
+	}
+	i.Data["json"] = rules
+	i.ServeJSON()
+}
+
+// Get the immutable tag rule specified by ID
+func (i *ImmutableTagRuleAPI) Get() {
+	i.Data["json"] = i.rule
+	i.ServeJSON()
+}
+
+// Put updates the patterns of the immutable tag rule
+func (i *ImmutableTagRuleAPI) Put() {
+	req := &models.ImmutableTagRule{}
+	i.DecodeJSONReqAndValidate(req)
+
+	i.rule.RepoPattern = req.RepoPattern
+	i.rule.TagPattern = req.TagPattern
+	duplicated, err := i.isDuplicated(i.rule)
+	if err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to list immutable tag rules: %v", err))
+		return
+	}
+	if duplicated {
+		i.HandleConflict()
+		return
+	}
+
+	if err = dao.UpdateImmutableTagRule(i.rule); err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to update immutable tag rule %d: %v", i.rule.ID, err))
+		return
+	}
+}
+
+// Delete the immutable tag rule
+func (i *ImmutableTagRuleAPI) Delete() {
+	if err := dao.DeleteImmutableTagRule(i.rule.ID); err != nil {
+		i.HandleInternalServerError(fmt.Sprintf("failed to delete immutable tag rule %d: %v", i.rule.ID, err))
+		return
+	}
+}
+
+// isDuplicated returns whether the project has another rule with the same patterns
+func (i *ImmutableTagRuleAPI) isDuplicated(rule *models.ImmutableTagRule) (bool, error) {
+	rules, err := dao.ListImmutableTagRules(i.project.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range rules {
+		if r.ID != rule.ID && r.RepoPattern == rule.RepoPattern && r.TagPattern == rule.TagPattern {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,114 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var immutableTagRuleAPIBasePath = "/api/projects/1/immutabletagrules"
+
+func TestImmutableTagRuleAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    immutableTagRuleAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    immutableTagRuleAPIBasePath,
+				bodyJSON: &models.ImmutableTagRule{
+					RepoPattern: "*",
+					TagPattern:  "v*",
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400 invalid pattern
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    immutableTagRuleAPIBasePath,
+				bodyJSON: &models.ImmutableTagRule{
+					RepoPattern: "[",
+					TagPattern:  "v*",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404 non-exist project
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/immutabletagrules",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    immutableTagRuleAPIBasePath,
+				bodyJSON: &models.ImmutableTagRule{
+					RepoPattern: "*",
+					TagPattern:  "v*",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    immutableTagRuleAPIBasePath,
+				bodyJSON: &models.ImmutableTagRule{
+					RepoPattern: "*",
+					TagPattern:  "v*",
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer dao.DeleteImmutableTagRulesOfProject(1)
+
+	// list by developer
+	rules := []*models.ImmutableTagRule{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        immutableTagRuleAPIBasePath,
+		credential: projDeveloper,
+	}, &rules)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(rules))
+	assert.Equal(t, "v*", rules[0].TagPattern)
+}
@@ -15,12 +15,14 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/quota"
 )
 
 // InternalAPI handles request of harbor admin...
@@ -68,3 +70,50 @@ func (ia *InternalAPI) RenameAdmin() {
 	log.Debugf("The super user has been renamed to: %s", newName)
 	ia.DestroySession()
 }
+
+// InternalGCAPI handles the internal requests of the garbage collection job
+type InternalGCAPI struct {
+	BaseController
+}
+
+// Prepare validates the URL and parms, the request can be sent by the job service
+func (ia *InternalGCAPI) Prepare() {
+	ia.BaseController.Prepare()
+	if !ia.SecurityCtx.IsAuthenticated() {
+		ia.HandleUnauthorized()
+		return
+	}
+	if !ia.SecurityCtx.IsSysAdmin() && !ia.SecurityCtx.IsSolutionUser() {
+		ia.HandleForbidden(ia.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// PurgeOrphans purges the artifacts which exist only in registry and have no repository record
+func (ia *InternalGCAPI) PurgeOrphans() {
+	dryRun, err := ia.GetBool("dry_run", false)
+	if err != nil {
+		ia.HandleBadRequest(fmt.Sprintf("invalid dry_run: %v", err))
+		return
+	}
+	result, err := PurgeOrphanArtifacts(dryRun)
+	if err == ErrPurgeNotReadOnly {
+		ia.HandleStatusPreconditionFailed(err.Error())
+		return
+	}
+	if err != nil {
+		ia.HandleInternalServerError(fmt.Sprintf("failed to purge the orphan artifacts: %v", err))
+		return
+	}
+	ia.WriteJSONData(result)
+}
+
+// RefreshUsages recalculates the usages of all projects in background after the
+// garbage collection, as the storage released by the collection is unknown
+func (ia *InternalGCAPI) RefreshUsages() {
+	if err := quota.RefreshAll(); err != nil {
+		ia.HandleInternalServerError(fmt.Sprintf("failed to refresh the usages of projects: %v", err))
+		return
+	}
+	ia.Ctx.ResponseWriter.WriteHeader(http.StatusAccepted)
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/promgr/metamgr"
@@ -139,6 +140,11 @@ func (m *MetadataAPI) Post() {
 		return
 	}
 
+	if containsQuotaMetadata(ms) && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(m.SecurityCtx.GetUsername())
+		return
+	}
+
 	keys := reflect.ValueOf(ms).MapKeys()
 	mts, err := m.metaMgr.Get(m.project.ProjectID, keys[0].String())
 	if err != nil {
@@ -178,6 +184,11 @@ func (m *MetadataAPI) Put() {
 		return
 	}
 
+	if containsQuotaMetadata(ms) && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(m.SecurityCtx.GetUsername())
+		return
+	}
+
 	if err := m.metaMgr.Update(m.project.ProjectID, map[string]string{
 		m.name: ms[m.name],
 	}); err != nil {
@@ -188,6 +199,11 @@ func (m *MetadataAPI) Put() {
 
 // Delete ...
 func (m *MetadataAPI) Delete() {
+	if containsQuotaMetadata(map[string]string{m.name: ""}) && !m.SecurityCtx.IsSysAdmin() {
+		m.HandleForbidden(m.SecurityCtx.GetUsername())
+		return
+	}
+
 	if err := m.metaMgr.Delete(m.project.ProjectID, m.name); err != nil {
 		m.HandleInternalServerError(fmt.Sprintf("failed to delete metadata %s of project %d: %v", m.name, m.project.ProjectID, err))
 		return
@@ -227,5 +243,59 @@ func validateProjectMetadata(metas map[string]string) (map[string]string, error)
 		}
 	}
 
+	quotaMetas := []string{
+		models.ProMetaStorageQuota,
+		models.ProMetaRepositoryQuota,
+		models.ProMetaTagQuota}
+
+	for _, quotaMeta := range quotaMetas {
+		value, exist := metas[quotaMeta]
+		if exist {
+			quota, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s to integer: %v", value, err)
+			}
+			if quota < models.QuotaUnlimited {
+				return nil, fmt.Errorf("invalid quota %d, must be equal or greater than %d", quota, models.QuotaUnlimited)
+			}
+			metas[quotaMeta] = strconv.FormatInt(quota, 10)
+		}
+	}
+
+	value, exist = metas[models.ProMetaChartOverwrite]
+	if exist {
+		switch strings.ToLower(value) {
+		case models.ChartOverwriteAllow, models.ChartOverwriteAdminOnly, models.ChartOverwriteDeny:
+			metas[models.ProMetaChartOverwrite] = strings.ToLower(value)
+		default:
+			return nil, fmt.Errorf("invalid chart overwrite %s", value)
+		}
+	}
+
+	// the empty scanner means using the default one
+	if name := metas[models.ProMetaScanner]; len(name) > 0 {
+		registration, err := dao.GetScannerRegistrationByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scanner %s: %v", name, err)
+		}
+		if registration == nil {
+			return nil, fmt.Errorf("scanner %s is not registered", name)
+		}
+	}
+
 	return metas, nil
 }
+
+// containsQuotaMetadata returns whether the metas contain any quota, which
+// can only be managed by system admin
+func containsQuotaMetadata(metas map[string]string) bool {
+	for _, key := range []string{
+		models.ProMetaStorageQuota,
+		models.ProMetaRepositoryQuota,
+		models.ProMetaTagQuota} {
+		if _, exist := metas[key]; exist {
+			return true
+		}
+	}
+	return false
+}
@@ -60,6 +60,51 @@ func TestValidateProjectMetadata(t *testing.T) {
 	ms, err = validateProjectMetadata(metas)
 	require.Nil(t, err)
 	assert.Equal(t, "high", ms[models.ProMetaSeverity])
+
+	// valid key, invalid value(quota)
+	metas = map[string]string{
+		models.ProMetaStorageQuota: "-2",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.NotNil(t, err)
+
+	// valid key, valid value(quota)
+	metas = map[string]string{
+		models.ProMetaTagQuota: "010",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.Nil(t, err)
+	assert.Equal(t, "10", ms[models.ProMetaTagQuota])
+
+	// valid key, invalid value(scanner)
+	metas = map[string]string{
+		models.ProMetaScanner: "non-exist",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.NotNil(t, err)
+
+	// valid key, valid value(scanner)
+	metas = map[string]string{
+		models.ProMetaScanner: "",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.Nil(t, err)
+	assert.Equal(t, "", ms[models.ProMetaScanner])
+
+	// valid key, invalid value(chart overwrite)
+	metas = map[string]string{
+		models.ProMetaChartOverwrite: "invalid_value",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.NotNil(t, err)
+
+	// valid key, valid value(chart overwrite)
+	metas = map[string]string{
+		models.ProMetaChartOverwrite: "Admin_Only",
+	}
+	ms, err = validateProjectMetadata(metas)
+	require.Nil(t, err)
+	assert.Equal(t, models.ChartOverwriteAdminOnly, ms[models.ProMetaChartOverwrite])
 }
 
 func TestMetaAPI(t *testing.T) {
@@ -22,6 +22,7 @@ import (
 	"github.com/astaxie/beego/validation"
 	"github.com/goharbor/harbor/src/common/job"
 	"github.com/goharbor/harbor/src/common/job/models"
+	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
@@ -38,12 +39,21 @@ const (
 	ScheduleNone = "None"
 )
 
-// GCReq holds request information for admin job
+// GCReq holds request information for admin job,
+// Unlink runs the pre-pass unlinking the blobs not referenced by any manifest from the
+// repositories before the garbage collection of registry, DryRun and DeleteUntagged, which
+// deletes the manifests not referenced by any tag as the bundled registry can't delete them,
+// are only supported with the pre-pass, and PurgeOrphans purges the artifacts which exist
+// only in registry and have no repository record
 type GCReq struct {
-	Schedule   *ScheduleParam         `json:"schedule"`
-	Status     string                 `json:"status"`
-	ID         int64                  `json:"id"`
-	Parameters map[string]interface{} `json:"parameters"`
+	Schedule       *ScheduleParam         `json:"schedule"`
+	Unlink         bool                   `json:"unlink"`
+	DryRun         bool                   `json:"dry_run"`
+	DeleteUntagged bool                   `json:"delete_untagged"`
+	PurgeOrphans   bool                   `json:"purge_orphans"`
+	Status         string                 `json:"status"`
+	ID             int64                  `json:"id"`
+	Parameters     map[string]interface{} `json:"parameters"`
 }
 
 // ScheduleParam defines the parameter of schedule trigger
@@ -58,15 +68,16 @@ type ScheduleParam struct {
 
 // GCRep holds the response of query gc
 type GCRep struct {
-	ID           int64          `json:"id"`
-	Name         string         `json:"job_name"`
-	Kind         string         `json:"job_kind"`
-	Schedule     *ScheduleParam `json:"schedule"`
-	Status       string         `json:"job_status"`
-	UUID         string         `json:"-"`
-	Deleted      bool           `json:"deleted"`
-	CreationTime time.Time      `json:"creation_time"`
-	UpdateTime   time.Time      `json:"update_time"`
+	ID           int64                   `json:"id"`
+	Name         string                  `json:"job_name"`
+	Kind         string                  `json:"job_kind"`
+	Schedule     *ScheduleParam          `json:"schedule"`
+	Status       string                  `json:"job_status"`
+	UUID         string                  `json:"-"`
+	Deleted      bool                    `json:"deleted"`
+	Report       *common_models.GCReport `json:"report,omitempty"`
+	CreationTime time.Time               `json:"creation_time"`
+	UpdateTime   time.Time               `json:"update_time"`
 }
 
 // Valid validates the gc request
@@ -80,6 +91,17 @@ func (gr *GCReq) Valid(v *validation.Validation) {
 	default:
 		v.SetError("kind", fmt.Sprintf("Invalid schedule kind: %s", gr.Schedule.Type))
 	}
+
+	if !gr.Unlink {
+		// the garbage collection of registry has no dry run
+		if gr.DryRun {
+			v.SetError("dry_run", "Dry run is only supported with the unlink pre-pass")
+		}
+		// the bundled registry doesn't support deleting the untagged manifests
+		if gr.DeleteUntagged {
+			v.SetError("delete_untagged", "Deleting untagged manifests is only supported with the unlink pre-pass")
+		}
+	}
 }
 
 // ToJob converts request to a job reconiged by job service.
@@ -90,17 +112,11 @@ func (gr *GCReq) ToJob() (*models.JobData, error) {
 		IsUnique: true,
 	}
 
-	switch gr.Schedule.Type {
-	case ScheduleDaily:
-		h, m, s := utils.ParseOfftime(gr.Schedule.Offtime)
-		metadata.Cron = fmt.Sprintf("%d %d %d * * *", s, m, h)
-	case ScheduleWeekly:
-		h, m, s := utils.ParseOfftime(gr.Schedule.Offtime)
-		metadata.Cron = fmt.Sprintf("%d %d %d * * %d", s, m, h, gr.Schedule.Weekday%7)
-	case ScheduleManual, ScheduleNone:
-	default:
-		return nil, fmt.Errorf("unsupported schedule trigger type: %s", gr.Schedule.Type)
+	cron, err := gr.Schedule.Cron()
+	if err != nil {
+		return nil, err
 	}
+	metadata.Cron = cron
 
 	jobData := &models.JobData{
 		Name:       job.ImageGC,
@@ -112,6 +128,23 @@ func (gr *GCReq) ToJob() (*models.JobData, error) {
 	return jobData, nil
 }
 
+// Cron returns the cron string recognized by job service, it is
+// empty if the schedule type is manual or none
+func (sp *ScheduleParam) Cron() (string, error) {
+	switch sp.Type {
+	case ScheduleDaily:
+		h, m, s := utils.ParseOfftime(sp.Offtime)
+		return fmt.Sprintf("%d %d %d * * *", s, m, h), nil
+	case ScheduleWeekly:
+		h, m, s := utils.ParseOfftime(sp.Offtime)
+		return fmt.Sprintf("%d %d %d * * %d", s, m, h, sp.Weekday%7), nil
+	case ScheduleManual, ScheduleNone:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported schedule trigger type: %s", sp.Type)
+	}
+}
+
 // IsPeriodic ...
 func (gr *GCReq) IsPeriodic() bool {
 	return gr.JobKind() == job.JobKindPeriodic
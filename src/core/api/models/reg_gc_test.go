@@ -17,6 +17,7 @@ import (
 	"log"
 	"testing"
 
+	"github.com/astaxie/beego/validation"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/goharbor/harbor/src/common"
@@ -128,3 +129,37 @@ func TestCronString(t *testing.T) {
 	cronStr := adminjob.CronString()
 	assert.Equal(t, cronStr, "{\"type\":\"Daily\",\"Weekday\":0,\"Offtime\":102}")
 }
+
+func TestValidUnlink(t *testing.T) {
+	cases := []struct {
+		unlink         bool
+		dryRun         bool
+		deleteUntagged bool
+		purgeOrphans   bool
+		valid          bool
+	}{
+		{false, false, false, false, true},
+		{false, false, false, true, true},
+		{true, false, false, false, true},
+		{true, true, true, false, true},
+		{true, true, false, true, true},
+		{false, true, false, false, false},
+		{false, false, true, false, false},
+	}
+
+	for _, c := range cases {
+		v := &validation.Validation{}
+		gr := &GCReq{
+			Schedule: &ScheduleParam{
+				Type: "Manual",
+			},
+			Unlink:         c.unlink,
+			DryRun:         c.dryRun,
+			DeleteUntagged: c.deleteUntagged,
+			PurgeOrphans:   c.purgeOrphans,
+		}
+		gr.Valid(v)
+		assert.Equal(t, c.valid, !v.HasErrors(), "unlink: %t, dry run: %t, delete untagged: %t, purge orphans: %t",
+			c.unlink, c.dryRun, c.deleteUntagged, c.purgeOrphans)
+	}
+}
@@ -15,10 +15,12 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/astaxie/beego/validation"
 	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication"
 	rep_models "github.com/goharbor/harbor/src/replication/models"
 )
 
@@ -29,6 +31,7 @@ type ReplicationPolicy struct {
 	Description               string                     `json:"description"`
 	Filters                   []rep_models.Filter        `json:"filters"`
 	ReplicateDeletion         bool                       `json:"replicate_deletion"`
+	Direction                 string                     `json:"direction"`
 	Trigger                   *rep_models.Trigger        `json:"trigger"`
 	Projects                  []*common_models.Project   `json:"projects"`
 	Targets                   []*common_models.RepTarget `json:"targets"`
@@ -65,4 +68,28 @@ func (r *ReplicationPolicy) Valid(v *validation.Validation) {
 	} else {
 		r.Trigger.Valid(v)
 	}
+
+	if len(r.Direction) == 0 {
+		r.Direction = replication.DirectionPush
+	}
+	switch r.Direction {
+	case replication.DirectionPush:
+	case replication.DirectionPull:
+		// the immediate trigger and deletion replication depend on the
+		// events of local registry, they make no sense for pulling
+		if r.Trigger != nil && r.Trigger.Kind == replication.TriggerKindImmediate {
+			v.SetError("trigger", "immediate trigger is not supported by pull-based replication")
+		}
+		if r.ReplicateDeletion {
+			v.SetError("replicate_deletion", "deletion is not supported by pull-based replication")
+		}
+		for _, filter := range r.Filters {
+			if filter.Kind == replication.FilterItemKindLabel {
+				v.SetError("filters", "label filter is not supported by pull-based replication")
+				break
+			}
+		}
+	default:
+		v.SetError("direction", fmt.Sprintf("invalid direction: %s", r.Direction))
+	}
 }
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/common/retention"
+)
+
+// RetentionPolicyReq holds the rules and the schedule of tag retention policy
+type RetentionPolicyReq struct {
+	Rules    []*retention.Rule `json:"rules"`
+	Schedule *ScheduleParam    `json:"schedule"`
+}
+
+// Valid validates the tag retention policy
+func (r *RetentionPolicyReq) Valid(v *validation.Validation) {
+	for _, rule := range r.Rules {
+		if rule == nil {
+			v.SetError("rules", "rule cannot be null")
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			v.SetError("rules", err.Error())
+		}
+	}
+
+	if r.Schedule == nil {
+		r.Schedule = &ScheduleParam{
+			Type: ScheduleNone,
+		}
+	}
+	switch r.Schedule.Type {
+	case ScheduleDaily, ScheduleWeekly:
+		if r.Schedule.Offtime < 0 || r.Schedule.Offtime > 3600*24 {
+			v.SetError("offtime", fmt.Sprintf("Invalid schedule trigger parameter offtime: %d", r.Schedule.Offtime))
+		}
+	case ScheduleManual, ScheduleNone:
+	default:
+		v.SetError("kind", fmt.Sprintf("Invalid schedule kind: %s", r.Schedule.Type))
+	}
+}
+
+// RetentionPolicyRep holds the response of tag retention policy
+type RetentionPolicyRep struct {
+	ID        int64             `json:"id"`
+	ProjectID int64             `json:"project_id"`
+	Rules     []*retention.Rule `json:"rules"`
+	Schedule  *ScheduleParam    `json:"schedule"`
+}
+
+// RetentionExecutionReq triggers a run of the tag retention policy
+type RetentionExecutionReq struct {
+	DryRun bool `json:"dry_run"`
+}
@@ -26,6 +26,7 @@ import (
 	errutil "github.com/goharbor/harbor/src/common/utils/error"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/quota"
 
 	"strconv"
 	"time"
@@ -109,6 +110,11 @@ func (p *ProjectAPI) Post() {
 		return
 	}
 
+	if containsQuotaMetadata(pro.Metadata) && !p.SecurityCtx.IsSysAdmin() {
+		p.RenderError(http.StatusForbidden, "Only system admin can set the quotas of project")
+		return
+	}
+
 	exist, err := p.ProjectMgr.Exists(pro.Name)
 	if err != nil {
 		p.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %s",
@@ -148,6 +154,14 @@ func (p *ProjectAPI) Post() {
 		return
 	}
 
+	// the new project holds nothing, so its usage is known without calculation
+	if err = dao.SetProjectUsage(&models.ProjectUsage{
+		ProjectID:  projectID,
+		UpdateTime: time.Now(),
+	}); err != nil {
+		log.Errorf("failed to initialize the usage of project %s: %v", pro.Name, err)
+	}
+
 	go func() {
 		if err = dao.AddAccessLog(
 			models.AccessLog{
@@ -232,6 +246,30 @@ func (p *ProjectAPI) Delete() {
 		return
 	}
 
+	if err = dao.DeleteProjectUsage(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the usage of project %d: %v", p.project.ProjectID, err)
+	}
+
+	if err = dao.DeleteRobotsOfProject(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the robot accounts of project %d: %v", p.project.ProjectID, err)
+	}
+
+	if err = deleteRetentionPolicyOfProject(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the retention policy of project %d: %v", p.project.ProjectID, err)
+	}
+
+	if err = dao.DeleteImmutableTagRulesOfProject(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the immutable tag rules of project %d: %v", p.project.ProjectID, err)
+	}
+
+	if err = dao.DeleteWebhookPoliciesOfProject(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the webhook policies of project %d: %v", p.project.ProjectID, err)
+	}
+
+	if err = dao.DeleteCVEAllowlist(p.project.ProjectID); err != nil {
+		log.Errorf("failed to delete the CVE allowlist of project %d: %v", p.project.ProjectID, err)
+	}
+
 	go func() {
 		if err := dao.AddAccessLog(models.AccessLog{
 			Username:  p.SecurityCtx.GetUsername(),
@@ -446,6 +484,18 @@ func (p *ProjectAPI) Put() {
 	var req *models.ProjectRequest
 	p.DecodeJSONReq(&req)
 
+	metas, err := validateProjectMetadata(req.Metadata)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	req.Metadata = metas
+
+	if containsQuotaMetadata(req.Metadata) && !p.SecurityCtx.IsSysAdmin() {
+		p.RenderError(http.StatusForbidden, "Only system admin can set the quotas of project")
+		return
+	}
+
 	if err := p.ProjectMgr.Update(p.project.ProjectID,
 		&models.Project{
 			Metadata: req.Metadata,
@@ -456,6 +506,51 @@ func (p *ProjectAPI) Put() {
 	}
 }
 
+// Summary returns the quota and usage of the project
+func (p *ProjectAPI) Summary() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.HasReadPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	refresh, err := p.GetBool("refresh", false)
+	if err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid refresh: %v", err))
+		return
+	}
+	if refresh && !p.SecurityCtx.HasAllPerm(p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	var usage *models.ProjectUsage
+	if refresh {
+		usage, err = quota.Refresh(p.project)
+	} else {
+		usage, err = quota.GetUsage(p.project)
+	}
+	if err == quota.ErrUsageNotReady {
+		p.RenderError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		p.HandleInternalServerError(fmt.Sprintf(
+			"failed to get the usage of project %d: %v", p.project.ProjectID, err))
+		return
+	}
+
+	p.Data["json"] = &models.ProjectSummary{
+		Quota: p.project.Quota(),
+		Usage: usage,
+	}
+	p.ServeJSON()
+}
+
 // Logs ...
 func (p *ProjectAPI) Logs() {
 	if !p.SecurityCtx.IsAuthenticated() {
@@ -242,6 +242,19 @@ func (gc *GCAPI) submitJob(gr *models.GCReq) {
 	gr.ID = id
 	gr.Parameters = map[string]interface{}{
 		"redis_url_reg": os.Getenv("_REDIS_URL_REG"),
+		"admin_job_id":  id,
+	}
+	if gr.Unlink {
+		gr.Parameters["unlink"] = true
+	}
+	if gr.DryRun {
+		gr.Parameters["dry_run"] = true
+	}
+	if gr.DeleteUntagged {
+		gr.Parameters["delete_untagged"] = true
+	}
+	if gr.PurgeOrphans {
+		gr.Parameters["purge_orphans"] = true
 	}
 	job, err := gr.ToJob()
 	if err != nil {
@@ -286,5 +299,12 @@ func convertToGCRep(job *common_models.AdminJob) (models.GCRep, error) {
 		}
 		gcrep.Schedule = schedule
 	}
+	if len(job.Report) > 0 {
+		report := &common_models.GCReport{}
+		if err := json.Unmarshal([]byte(job.Report), report); err != nil {
+			return models.GCRep{}, err
+		}
+		gcrep.Report = report
+	}
 	return gcrep, nil
 }
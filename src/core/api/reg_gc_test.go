@@ -1,6 +1,7 @@
 package api
 
 import (
+	"net/http"
 	"testing"
 
 	common_models "github.com/goharbor/harbor/src/common/models"
@@ -71,6 +72,31 @@ func TestConvertToGCRep(t *testing.T) {
 				Deleted: false,
 			},
 		},
+		{
+			input: &common_models.AdminJob{
+				ID:     2,
+				Name:   "IMAGE_GC",
+				Kind:   "Generic",
+				Status: "finished",
+				Report: "{\"dry_run\":true,\"blobs_deleted\":2,\"bytes_freed\":1024,\"blobs_unlinked\":3,\"manifests_deleted\":1,\"artifacts_purged\":3,\"repositories_purged\":[\"library/orphan\"],\"duration\":1.5}",
+			},
+			expected: api_modes.GCRep{
+				ID:     2,
+				Name:   "IMAGE_GC",
+				Kind:   "Generic",
+				Status: "finished",
+				Report: &common_models.GCReport{
+					DryRun:             true,
+					BlobsDeleted:       2,
+					BytesFreed:         1024,
+					BlobsUnlinked:      3,
+					ManifestsDeleted:   1,
+					ArtifactsPurged:    3,
+					RepositoriesPurged: []string{"library/orphan"},
+					Duration:           1.5,
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -78,3 +104,44 @@ func TestConvertToGCRep(t *testing.T) {
 		assert.EqualValues(t, c.expected, actual)
 	}
 }
+
+func TestPurgeOrphans(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/internal/gc/orphans/purge",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/internal/gc/orphans/purge",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/internal/gc/orphans/purge?dry_run=invalid",
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 412, not in read only mode
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/internal/gc/orphans/purge",
+				credential: sysAdmin,
+			},
+			code: http.StatusPreconditionFailed,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
@@ -359,6 +359,7 @@ func convertFromRepPolicy(projectMgr promgr.ProjectManager, policy rep_models.Re
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		Direction:         policy.Direction,
 		Trigger:           policy.Trigger,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
@@ -421,6 +422,7 @@ func convertToRepPolicy(policy *api_models.ReplicationPolicy) rep_models.Replica
 		Description:       policy.Description,
 		Filters:           policy.Filters,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		Direction:         policy.Direction,
 		Trigger:           policy.Trigger,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
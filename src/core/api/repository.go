@@ -31,14 +31,16 @@ import (
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
-	"github.com/goharbor/harbor/src/common/utils/clair"
 	registry_error "github.com/goharbor/harbor/src/common/utils/error"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/notary"
 	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/immutable"
 	"github.com/goharbor/harbor/src/core/notifier"
+	"github.com/goharbor/harbor/src/core/quota"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/goharbor/harbor/src/core/webhook"
 	"github.com/goharbor/harbor/src/replication/event/notification"
 	"github.com/goharbor/harbor/src/replication/event/topic"
 )
@@ -306,6 +308,24 @@ func (ra *RepositoryAPI) Delete() {
 		}
 	}
 
+	if err = immutable.CheckDeletion(project.ProjectID, rc, repoName, tags); err != nil {
+		if e, ok := err.(*immutable.ViolationError); ok {
+			ra.HandleStatusPreconditionFailed(e.Error())
+			return
+		}
+		ra.HandleInternalServerError(fmt.Sprintf("failed to check the immutability of tags of %s: %v", repoName, err))
+		return
+	}
+
+	// the tags sharing the manifest with the deleted ones are removed as well,
+	// so the tags are counted before and after the deletion
+	tagsBefore, err := countTags(rc)
+	if err != nil {
+		log.Errorf("failed to count the tags of repository %s: %v", repoName, err)
+		ra.CustomAbort(http.StatusInternalServerError, "internal error")
+	}
+
+	deletedTags := map[string]string{}
 	for _, t := range tags {
 		image := fmt.Sprintf("%s:%s", repoName, t)
 		if err = dao.DeleteLabelsOfResource(common.ResourceTypeImage, image); err != nil {
@@ -324,6 +344,7 @@ func (ra *RepositoryAPI) Delete() {
 			ra.CustomAbort(http.StatusInternalServerError, "internal error")
 		}
 		log.Infof("delete tag: %s:%s", repoName, t)
+		deletedTags[t] = ""
 
 		go func(tag string) {
 			image := repoName + ":" + tag
@@ -351,6 +372,30 @@ func (ra *RepositoryAPI) Delete() {
 		}(t)
 	}
 
+	// the deleted tags are no longer counted in the usage of the project, while the
+	// storage is held until the blobs are removed by the garbage collection
+	if tagsAfter, err := countTags(rc); err != nil {
+		log.Errorf("failed to count the tags of repository %s: %v", repoName, err)
+	} else if tagsBefore > tagsAfter {
+		var repositories int64
+		if tagsAfter == 0 {
+			repositories = 1
+		}
+		if err = quota.Release(project, 0, repositories, tagsBefore-tagsAfter); err != nil {
+			log.Errorf("failed to release the quota of project %s: %v", projectName, err)
+		}
+	}
+
+	if len(deletedTags) > 0 {
+		webhook.Notify(webhook.Event{
+			Type:       models.WebhookEventDeleteImage,
+			ProjectID:  project.ProjectID,
+			Operator:   ra.SecurityCtx.GetUsername(),
+			Repository: repoName,
+			Tags:       deletedTags,
+		})
+	}
+
 	exist, err := repositoryExist(repoName, rc)
 	if err != nil {
 		log.Errorf("failed to check the existence of repository %s: %v", repoName, err)
@@ -454,7 +499,7 @@ func (ra *RepositoryAPI) Retag() {
 	}
 
 	// Check whether source image exists
-	exist, _, err := ra.checkExistence(fmt.Sprintf("%s/%s", srcImage.Project, srcImage.Repo), srcImage.Tag)
+	exist, srcDigest, err := ra.checkExistence(fmt.Sprintf("%s/%s", srcImage.Project, srcImage.Repo), srcImage.Tag)
 	if err != nil {
 		ra.HandleInternalServerError(fmt.Sprintf("check existence of %s error: %v", request.SrcImage, err))
 		return
@@ -502,12 +547,62 @@ func (ra *RepositoryAPI) Retag() {
 		return
 	}
 
+	targetProject, err := ra.ProjectMgr.Get(project)
+	if err != nil {
+		ra.ParseAndHandleError(fmt.Sprintf("failed to get the project %s", project), err)
+		return
+	}
+
+	// Check whether the target tag is immutable when overriding it
+	if request.Override {
+		client, err := coreutils.NewRepositoryClientForUI(ra.SecurityCtx.GetUsername(), repoName)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v", repoName, err))
+			return
+		}
+		if err = immutable.CheckOverwrite(targetProject.ProjectID, client, repoName, request.Tag, srcDigest); err != nil {
+			if e, ok := err.(*immutable.ViolationError); ok {
+				ra.HandleStatusPreconditionFailed(e.Error())
+				return
+			}
+			ra.HandleInternalServerError(fmt.Sprintf("failed to check the immutability of %s:%s: %v", repoName, request.Tag, err))
+			return
+		}
+	}
+
+	// Reserve the quota of the target project, the blobs are counted
+	// only when they are copied from another project
+	repositories, tags, err := quota.ManifestRequest(repoName, request.Tag)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get the quota request of %s:%s: %v", repoName, request.Tag, err))
+		return
+	}
+	var storage int64
+	if srcImage.Project != project {
+		storage, err = quota.ImageStorageRequest(targetProject, fmt.Sprintf("%s/%s", srcImage.Project, srcImage.Repo), srcImage.Tag)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get the storage request of %s: %v", request.SrcImage, err))
+			return
+		}
+	}
+	if err = quota.Reserve(targetProject, storage, repositories, tags); err != nil {
+		if e, ok := err.(*quota.ExceededError); ok {
+			ra.SendForbiddenError(e)
+			return
+		}
+		ra.HandleInternalServerError(fmt.Sprintf("failed to reserve the quota of project %s: %v", project, err))
+		return
+	}
+
 	// Retag the image
 	if err = coreutils.Retag(srcImage, &models.Image{
 		Project: project,
 		Repo:    repo,
 		Tag:     request.Tag,
 	}); err != nil {
+		if e := quota.Release(targetProject, storage, repositories, tags); e != nil {
+			log.Errorf("failed to release the quota of project %s: %v", project, e)
+		}
 		ra.HandleInternalServerError(fmt.Sprintf("%v", err))
 	}
 }
@@ -597,10 +692,20 @@ func assembleTagsInParallel(client *registry.Repository, repository string,
 		}
 	}
 
+	scanEnabled := coreutils.ScanEnabled()
+	allowlist := models.CVESet{}
+	if scanEnabled {
+		allowlist, err = getCVEAllowlistOfRepository(repository)
+		if err != nil {
+			allowlist = models.CVESet{}
+			log.Errorf("failed to get the CVE allowlist of %s: %v", repository, err)
+		}
+	}
+
 	c := make(chan *tagResp)
 	for _, tag := range tags {
-		go assembleTag(c, client, repository, tag, config.WithClair(),
-			config.WithNotary(), signatures)
+		go assembleTag(c, client, repository, tag, scanEnabled,
+			config.WithNotary(), signatures, allowlist)
 	}
 	result := []*tagResp{}
 	var item *tagResp
@@ -615,8 +720,8 @@ func assembleTagsInParallel(client *registry.Repository, repository string,
 }
 
 func assembleTag(c chan *tagResp, client *registry.Repository,
-	repository, tag string, clairEnabled, notaryEnabled bool,
-	signatures map[string][]notary.Target) {
+	repository, tag string, scanEnabled, notaryEnabled bool,
+	signatures map[string][]notary.Target, allowlist models.CVESet) {
 	item := &tagResp{}
 	// labels
 	image := fmt.Sprintf("%s:%s", repository, tag)
@@ -637,8 +742,8 @@ func assembleTag(c chan *tagResp, client *registry.Repository,
 	}
 
 	// scan overview
-	if clairEnabled {
-		item.ScanOverview = getScanOverview(item.Digest, item.Name)
+	if scanEnabled {
+		item.ScanOverview = getScanOverview(item.Digest, item.Name, allowlist)
 	}
 
 	// signature, compare both digest and tag
@@ -929,8 +1034,8 @@ func (ra *RepositoryAPI) GetSignatures() {
 // ScanImage handles request POST /api/repository/$repository/tags/$tag/scan to trigger image scan manually.
 // 启动镜像扫描
 func (ra *RepositoryAPI) ScanImage() {
-	if !config.WithClair() {
-		log.Warningf("Harbor is not deployed with Clair, scan is disabled.")
+	if !coreutils.ScanEnabled() {
+		log.Warningf("Harbor is not deployed with Clair and no scanner is registered, scan is disabled.")
 		ra.RenderError(http.StatusServiceUnavailable, "")
 		return
 	}
@@ -971,8 +1076,8 @@ func (ra *RepositoryAPI) ScanImage() {
 
 // VulnerabilityDetails fetch vulnerability info from clair, transform to Harbor's format and return to client.
 func (ra *RepositoryAPI) VulnerabilityDetails() {
-	if !config.WithClair() {
-		log.Warningf("Harbor is not deployed with Clair, it's not impossible to get vulnerability details.")
+	if !coreutils.ScanEnabled() {
+		log.Warningf("Harbor is not deployed with Clair and no scanner is registered, it's not impossible to get vulnerability details.")
 		ra.RenderError(http.StatusServiceUnavailable, "")
 		return
 	}
@@ -1002,15 +1107,15 @@ func (ra *RepositoryAPI) VulnerabilityDetails() {
 		ra.HandleInternalServerError(fmt.Sprintf("failed to get the scan overview, error: %v", err))
 		return
 	}
-	if overview != nil && len(overview.DetailsKey) > 0 {
-		clairClient := clair.NewClient(config.ClairEndpoint(), nil)
+	// the overview whose severity is 0 isn't scanned successfully
+	if overview != nil && overview.Sev > 0 {
 		log.Debugf("The key for getting details: %s", overview.DetailsKey)
-		details, err := clairClient.GetResult(overview.DetailsKey)
+		report, err := coreutils.GetVulnReport(overview)
 		if err != nil {
-			ra.HandleInternalServerError(fmt.Sprintf("Failed to get scan details from Clair, error: %v", err))
+			ra.HandleInternalServerError(fmt.Sprintf("Failed to get scan details, error: %v", err))
 			return
 		}
-		res = transformVulnerabilities(details)
+		res = report.Vulnerabilities
 	}
 	ra.Data["json"] = res
 	ra.ServeJSON()
@@ -1018,8 +1123,8 @@ func (ra *RepositoryAPI) VulnerabilityDetails() {
 
 // ScanAll handles the api to scan all images on Harbor.
 func (ra *RepositoryAPI) ScanAll() {
-	if !config.WithClair() {
-		log.Warningf("Harbor is not deployed with Clair, it's not possible to scan images.")
+	if !coreutils.ScanEnabled() {
+		log.Warningf("Harbor is not deployed with Clair and no scanner is registered, it's not possible to scan images.")
 		ra.RenderError(http.StatusServiceUnavailable, "")
 		return
 	}
@@ -1088,7 +1193,8 @@ func (ra *RepositoryAPI) checkExistence(repository, tag string) (bool, string, e
 }
 
 // will return nil when it failed to get data.  The parm "tag" is for logging only.
-func getScanOverview(digest string, tag string) *models.ImgScanOverview {
+// The vulnerabilities in the allowlist are not counted in the overview.
+func getScanOverview(digest string, tag string, allowlist models.CVESet) *models.ImgScanOverview {
 	if len(digest) == 0 {
 		log.Debug("digest is nil")
 		return nil
@@ -1115,5 +1221,21 @@ func getScanOverview(digest string, tag string) *models.ImgScanOverview {
 		data.CompOverview = nil
 		data.DetailsKey = ""
 	}
+	if err := coreutils.ApplyCVEAllowlist(data, allowlist); err != nil {
+		log.Errorf("Failed to apply the CVE allowlist to the scan result for tag:%s, digest: %s, error: %v", tag, digest, err)
+	}
 	return data
 }
+
+// getCVEAllowlistOfRepository returns the CVE allowlist of the project which the repository belongs to
+func getCVEAllowlistOfRepository(repository string) (models.CVESet, error) {
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %s not found", projectName)
+	}
+	return coreutils.GetCVEAllowlist(project.ProjectID)
+}
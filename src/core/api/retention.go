@@ -0,0 +1,499 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/retention"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/api/models"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/immutable"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+// RetentionAPI handles requests to /api/projects/{}/retention, only
+// the project admin can manage the tag retention policy
+type RetentionAPI struct {
+	BaseController
+	project *common_models.Project
+}
+
+// Prepare ...
+func (r *RetentionAPI) Prepare() {
+	r.BaseController.Prepare()
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	id, err := r.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", r.GetStringFromPath(":id")))
+		return
+	}
+	project, err := r.ProjectMgr.Get(id)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %d", id), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(fmt.Sprintf("project %d not found", id))
+		return
+	}
+	r.project = project
+
+	if !r.SecurityCtx.HasAllPerm(id) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Get returns the tag retention policy of the project
+func (r *RetentionAPI) Get() {
+	policy := r.getPolicy()
+	if policy == nil {
+		return
+	}
+	rep, err := convertToRetentionPolicyRep(policy)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to convert retention policy: %v", err))
+		return
+	}
+	r.Data["json"] = rep
+	r.ServeJSON()
+}
+
+// Put creates or updates the tag retention policy of the project, the
+// periodic job is resubmitted with the new rules
+func (r *RetentionAPI) Put() {
+	req := &models.RetentionPolicyReq{}
+	r.DecodeJSONReqAndValidate(req)
+
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to marshal rules: %v", err))
+		return
+	}
+	schedule, err := json.Marshal(req.Schedule)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to marshal schedule: %v", err))
+		return
+	}
+	// validate the schedule before changing the policy and its scheduled job
+	cron, err := req.Schedule.Cron()
+	if err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	policy := r.getPolicy()
+	if policy == nil {
+		return
+	}
+	if policy.ID == 0 {
+		id, err := dao.AddRetentionPolicy(policy)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to create retention policy: %v", err))
+			return
+		}
+		policy.ID = id
+	}
+
+	// stop the scheduled job
+	if len(policy.UUID) > 0 {
+		if err = stopRetentionJob(policy.UUID); err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to stop the retention job %s: %v", policy.UUID, err))
+			return
+		}
+		policy.UUID = ""
+	}
+
+	policy.Rules = string(rules)
+	policy.Schedule = string(schedule)
+	if len(cron) > 0 {
+		uuid, err := submitRetentionJob(&job_models.JobMetadata{
+			JobKind:  common_job.JobKindPeriodic,
+			Cron:     cron,
+			IsUnique: true,
+		}, policy.ProjectID, policy.Rules, false,
+			fmt.Sprintf("%s/service/notifications/jobs/retention/policy/%d", config.InternalCoreURL(), policy.ID))
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to submit the retention job: %v", err))
+			return
+		}
+		policy.UUID = uuid
+	}
+
+	if err = dao.UpdateRetentionPolicy(policy); err != nil {
+		// the job submitted above isn't recorded, stop it to avoid running the rules not persisted
+		if len(policy.UUID) > 0 {
+			if e := stopRetentionJob(policy.UUID); e != nil {
+				log.Errorf("failed to stop the retention job %s: %v", policy.UUID, e)
+			}
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to update retention policy %d: %v", policy.ID, err))
+		return
+	}
+}
+
+// Candidates returns the tags of the project with the push and pull time, it
+// is used by the retention job to evaluate the rules
+func (r *RetentionAPI) Candidates() {
+	candidates, err := getRetentionCandidates(r.project.ProjectID)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the candidates of project %d: %v", r.project.ProjectID, err))
+		return
+	}
+	r.Data["json"] = candidates
+	r.ServeJSON()
+}
+
+// Execute runs the tag retention policy manually, the tags won't be deleted in dry run mode
+func (r *RetentionAPI) Execute() {
+	req := &models.RetentionExecutionReq{}
+	// the body is optional, the policy runs in normal mode by default
+	if len(r.Ctx.Input.CopyBody(1<<32)) > 0 {
+		r.DecodeJSONReq(req)
+	}
+
+	policy := r.getPolicy()
+	if policy == nil {
+		return
+	}
+	if policy.ID == 0 {
+		r.HandleNotFound(fmt.Sprintf("retention policy of project %d not found", r.project.ProjectID))
+		return
+	}
+
+	execution := &common_models.RetentionExecution{
+		PolicyID: policy.ID,
+		DryRun:   req.DryRun,
+		Status:   common_models.JobPending,
+	}
+	id, err := dao.AddRetentionExecution(execution)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to create retention execution: %v", err))
+		return
+	}
+	execution.ID = id
+
+	uuid, err := submitRetentionJob(&job_models.JobMetadata{
+		JobKind: common_job.JobKindGeneric,
+	}, policy.ProjectID, policy.Rules, req.DryRun,
+		fmt.Sprintf("%s/service/notifications/jobs/retention/execution/%d", config.InternalCoreURL(), id))
+	if err != nil {
+		execution.Status = common_models.JobError
+		if e := dao.UpdateRetentionExecution(execution, "Status"); e != nil {
+			log.Errorf("failed to update the status of retention execution %d: %v", id, e)
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to submit the retention job: %v", err))
+		return
+	}
+	execution.UUID = uuid
+	if err = dao.UpdateRetentionExecution(execution, "UUID"); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to update retention execution %d: %v", id, err))
+		return
+	}
+
+	r.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// ListExecutions lists the executions of the tag retention policy
+func (r *RetentionAPI) ListExecutions() {
+	policy := r.getPolicy()
+	if policy == nil {
+		return
+	}
+	if policy.ID == 0 {
+		r.Data["json"] = []*retentionExecutionRep{}
+		r.ServeJSON()
+		return
+	}
+
+	page, size := r.GetPaginationParams()
+	query := &common_models.RetentionExecutionQuery{
+		PolicyID: policy.ID,
+		Pagination: common_models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	}
+	total, err := dao.GetTotalOfRetentionExecutions(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the total of retention executions: %v", err))
+		return
+	}
+	executions, err := dao.ListRetentionExecutions(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list retention executions: %v", err))
+		return
+	}
+
+	reps := []*retentionExecutionRep{}
+	for _, execution := range executions {
+		rep, err := convertToRetentionExecutionRep(execution, false)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to convert retention execution: %v", err))
+			return
+		}
+		reps = append(reps, rep)
+	}
+	r.SetPaginationHeader(total, page, size)
+	r.Data["json"] = reps
+	r.ServeJSON()
+}
+
+// GetExecution returns the execution with the report
+func (r *RetentionAPI) GetExecution() {
+	execution := r.getExecution()
+	if execution == nil {
+		return
+	}
+	rep, err := convertToRetentionExecutionRep(execution, true)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to convert retention execution: %v", err))
+		return
+	}
+	r.Data["json"] = rep
+	r.ServeJSON()
+}
+
+// GetExecutionLog returns the log of the execution
+func (r *RetentionAPI) GetExecutionLog() {
+	execution := r.getExecution()
+	if execution == nil {
+		return
+	}
+	if len(execution.UUID) == 0 {
+		r.HandleNotFound(fmt.Sprintf("log of retention execution %d not found", execution.ID))
+		return
+	}
+	logBytes, err := utils_core.GetJobServiceClient().GetJobLog(execution.UUID)
+	if err != nil {
+		if httpErr, ok := err.(*common_http.Error); ok {
+			r.RenderError(httpErr.Code, "")
+			log.Errorf("failed to get log of retention execution %d: %d %s",
+				execution.ID, httpErr.Code, httpErr.Message)
+			return
+		}
+		r.HandleInternalServerError(fmt.Sprintf("failed to get log of retention execution %d: %v", execution.ID, err))
+		return
+	}
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Length"), strconv.Itoa(len(logBytes)))
+	r.Ctx.ResponseWriter.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain")
+	if _, err = r.Ctx.ResponseWriter.Write(logBytes); err != nil {
+		log.Errorf("failed to write log of retention execution %d: %v", execution.ID, err)
+	}
+}
+
+// getPolicy returns the tag retention policy of the project, an empty
+// one will be returned if the project doesn't have
+func (r *RetentionAPI) getPolicy() *common_models.RetentionPolicy {
+	policy, err := dao.GetRetentionPolicyByProject(r.project.ProjectID)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the retention policy of project %d: %v", r.project.ProjectID, err))
+		return nil
+	}
+	if policy == nil {
+		policy = &common_models.RetentionPolicy{
+			ProjectID: r.project.ProjectID,
+			Rules:     "[]",
+		}
+	}
+	return policy
+}
+
+func (r *RetentionAPI) getExecution() *common_models.RetentionExecution {
+	id, err := r.GetInt64FromPath(":eid")
+	if err != nil || id <= 0 {
+		r.HandleBadRequest(fmt.Sprintf("invalid execution ID: %s", r.GetStringFromPath(":eid")))
+		return nil
+	}
+	execution, err := dao.GetRetentionExecution(id)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get retention execution %d: %v", id, err))
+		return nil
+	}
+	policy := r.getPolicy()
+	if policy == nil {
+		return nil
+	}
+	if execution == nil || execution.PolicyID != policy.ID {
+		r.HandleNotFound(fmt.Sprintf("retention execution %d not found", id))
+		return nil
+	}
+	return execution
+}
+
+// deleteRetentionPolicyOfProject stops the scheduled retention job and
+// deletes the policy of the project
+func deleteRetentionPolicyOfProject(projectID int64) error {
+	policy, err := dao.GetRetentionPolicyByProject(projectID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	if len(policy.UUID) > 0 {
+		if err = stopRetentionJob(policy.UUID); err != nil {
+			return err
+		}
+	}
+	return dao.DeleteRetentionPolicy(policy.ID)
+}
+
+// stopRetentionJob stops the scheduled retention job, the job not found is ignored
+func stopRetentionJob(uuid string) error {
+	err := utils_core.GetJobServiceClient().PostAction(uuid, common_job.JobActionStop)
+	if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func submitRetentionJob(metadata *job_models.JobMetadata, projectID int64,
+	rules string, dryRun bool, hook string) (string, error) {
+	log.Debugf("submiting retention job of project %d to jobservice", projectID)
+	return utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.TagRetention,
+		Parameters: map[string]interface{}{
+			"project_id": projectID,
+			"rules":      rules,
+			"dry_run":    dryRun,
+		},
+		Metadata:   metadata,
+		StatusHook: hook,
+	})
+}
+
+// getRetentionCandidates returns all tags of the project, the push and
+// pull time are the latest ones recorded in access log
+func getRetentionCandidates(projectID int64) ([]*retention.Candidate, error) {
+	repositories, err := dao.GetRepositories(&common_models.RepositoryQuery{
+		ProjectIDs: []int64{projectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	pushTimes, err := dao.GetLatestOperationTimes(projectID, "push")
+	if err != nil {
+		return nil, err
+	}
+	pullTimes, err := dao.GetLatestOperationTimes(projectID, "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := dao.ListImmutableTagRules(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*retention.Candidate{}
+	for _, repository := range repositories {
+		client, err := utils_core.NewRepositoryClientForUI("harbor-core", repository.Name)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := client.ListTag()
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			digest, exist, err := client.ManifestExist(tag)
+			if err != nil {
+				return nil, err
+			}
+			if !exist {
+				continue
+			}
+			image := repository.Name + ":" + tag
+			candidate := &retention.Candidate{
+				Repository: repository.Name,
+				Tag:        tag,
+				Digest:     digest,
+				PushTime:   pushTimes[image],
+				PullTime:   pullTimes[image],
+				Labels:     []int64{},
+			}
+			if candidate.Immutable, err = immutable.Match(rules, repository.Name, tag); err != nil {
+				return nil, err
+			}
+			labels, err := dao.GetLabelsOfResource(common.ResourceTypeImage, image)
+			if err != nil {
+				return nil, err
+			}
+			for _, label := range labels {
+				candidate.Labels = append(candidate.Labels, label.ID)
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+func convertToRetentionPolicyRep(policy *common_models.RetentionPolicy) (*models.RetentionPolicyRep, error) {
+	rep := &models.RetentionPolicyRep{
+		ID:        policy.ID,
+		ProjectID: policy.ProjectID,
+		Rules:     []*retention.Rule{},
+		Schedule: &models.ScheduleParam{
+			Type: models.ScheduleNone,
+		},
+	}
+	if len(policy.Rules) > 0 {
+		if err := json.Unmarshal([]byte(policy.Rules), &rep.Rules); err != nil {
+			return nil, err
+		}
+	}
+	if len(policy.Schedule) > 0 {
+		if err := json.Unmarshal([]byte(policy.Schedule), rep.Schedule); err != nil {
+			return nil, err
+		}
+	}
+	return rep, nil
+}
+
+// retentionExecutionRep is the execution returned by the API
+type retentionExecutionRep struct {
+	*common_models.RetentionExecution
+	Report *retention.Report `json:"report,omitempty"`
+}
+
+func convertToRetentionExecutionRep(execution *common_models.RetentionExecution,
+	withReport bool) (*retentionExecutionRep, error) {
+	rep := &retentionExecutionRep{
+		RetentionExecution: execution,
+	}
+	if withReport && len(execution.Report) > 0 {
+		rep.Report = &retention.Report{}
+		if err := json.Unmarshal([]byte(execution.Report), rep.Report); err != nil {
+			return nil, err
+		}
+	}
+	return rep, nil
+}
@@ -0,0 +1,131 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/retention"
+	"github.com/goharbor/harbor/src/core/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var retentionAPIBasePath = "/api/projects/1/retention"
+
+func TestRetentionAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    retentionAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        retentionAPIBasePath,
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404 non-exist project
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/retention",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 400 invalid rule
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    retentionAPIBasePath,
+				bodyJSON: &models.RetentionPolicyReq{
+					Rules: []*retention.Rule{
+						{
+							Kind: retention.RuleKindLatestPushed,
+						},
+					},
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404 non-exist policy
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    retentionAPIBasePath + "/executions",
+				bodyJSON: &models.RetentionExecutionReq{
+					DryRun: true,
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method: http.MethodPut,
+				url:    retentionAPIBasePath,
+				bodyJSON: &models.RetentionPolicyReq{
+					Rules: []*retention.Rule{
+						{
+							Kind:  retention.RuleKindLatestPushed,
+							Count: 3,
+						},
+					},
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404 non-exist execution
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        retentionAPIBasePath + "/executions/10000",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	policy, err := dao.GetRetentionPolicyByProject(1)
+	require.Nil(t, err)
+	require.NotNil(t, policy)
+	defer dao.DeleteRetentionPolicy(policy.ID)
+
+	rep := &models.RetentionPolicyRep{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        retentionAPIBasePath,
+		credential: projAdmin,
+	}, rep)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(rep.Rules))
+	assert.Equal(t, retention.RuleKindLatestPushed, rep.Rules[0].Kind)
+	assert.Equal(t, 3, rep.Rules[0].Count)
+	assert.Equal(t, models.ScheduleNone, rep.Schedule.Type)
+}
@@ -0,0 +1,214 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// RobotAPI handles requests to /api/projects/{}/robots/{}
+type RobotAPI struct {
+	BaseController
+	project *models.Project
+	robot   *models.Robot
+}
+
+// Prepare validates the project and the robot account, only the
+// project admin can manage the robot accounts
+func (r *RobotAPI) Prepare() {
+	r.BaseController.Prepare()
+
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	pid, err := r.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		text := "invalid project ID: "
+		if err != nil {
+			text += err.Error()
+		} else {
+			text += fmt.Sprintf("%d", pid)
+		}
+		r.HandleBadRequest(text)
+		return
+	}
+	project, err := r.ProjectMgr.Get(pid)
+	if err != nil {
+		r.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	r.project = project
+
+	if !r.SecurityCtx.HasAllPerm(pid) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(r.GetStringFromPath(":id")) != 0 {
+		id, err := r.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			r.HandleBadRequest(fmt.Sprintf("invalid robot ID: %s", r.GetStringFromPath(":id")))
+			return
+		}
+		robot, err := dao.GetRobot(id)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get robot account %d: %v", id, err))
+			return
+		}
+		if robot == nil || robot.ProjectID != project.ProjectID {
+			r.HandleNotFound(fmt.Sprintf("robot account %d not found", id))
+			return
+		}
+		r.robot = robot
+	}
+}
+
+// Post creates a robot account, the token is returned in the response only once
+func (r *RobotAPI) Post() {
+	req := &models.RobotReq{}
+	r.DecodeJSONReqAndValidate(req)
+
+	robot, err := dao.GetRobotByName(r.project.ProjectID, req.Name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot account %s: %v", req.Name, err))
+		return
+	}
+	if robot != nil {
+		r.HandleConflict()
+		return
+	}
+
+	expiresAt := time.Now().Add(models.RobotDefaultDuration)
+	if req.ExpiresAt > 0 {
+		expiresAt = time.Unix(req.ExpiresAt, 0)
+	}
+	token := utils.GenerateRandomString()
+	salt := utils.GenerateRandomString()
+	robot = &models.Robot{
+		Name:        req.Name,
+		Description: req.Description,
+		ProjectID:   r.project.ProjectID,
+		Token:       utils.Encrypt(token, salt),
+		Salt:        salt,
+		Access:      strings.Join(req.Access, ","),
+		ExpiresAt:   expiresAt,
+	}
+	id, err := dao.AddRobot(robot)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to create robot account: %v", err))
+		return
+	}
+
+	r.Data["json"] = &models.RobotRep{
+		ID:    id,
+		Name:  robot.Username(r.project.Name),
+		Token: token,
+	}
+	r.Ctx.ResponseWriter.WriteHeader(http.StatusCreated)
+	r.ServeJSON()
+}
+
+// List the robot accounts of the project
+func (r *RobotAPI) List() {
+	page, size := r.GetPaginationParams()
+	query := &models.RobotQuery{
+		Name:      r.GetString("name"),
+		ProjectID: r.project.ProjectID,
+		Pagination: models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	}
+
+	total, err := dao.GetTotalOfRobots(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get the total of robot accounts: %v", err))
+		return
+	}
+	robots, err := dao.ListRobots(query)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list robot accounts: %v", err))
+		return
+	}
+
+	r.SetPaginationHeader(total, page, size)
+	r.Data["json"] = convertRobots(robots, r.project.Name)
+	r.ServeJSON()
+}
+
+// Get the robot account specified by ID
+func (r *RobotAPI) Get() {
+	r.Data["json"] = convertRobot(r.robot, r.project.Name)
+	r.ServeJSON()
+}
+
+// Put disables or enables the robot account
+func (r *RobotAPI) Put() {
+	req := struct {
+		Disabled bool `json:"disabled"`
+	}{}
+	r.DecodeJSONReq(&req)
+
+	if err := dao.DisableRobot(r.robot.ID, req.Disabled); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to update robot account %d: %v", r.robot.ID, err))
+		return
+	}
+}
+
+// Delete revokes the robot account
+func (r *RobotAPI) Delete() {
+	if err := dao.DeleteRobot(r.robot.ID); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete robot account %d: %v", r.robot.ID, err))
+		return
+	}
+}
+
+// robotInfo is the robot account returned by the API
+type robotInfo struct {
+	*models.Robot
+	Username string   `json:"username"`
+	Access   []string `json:"access"`
+	Expired  bool     `json:"expired"`
+}
+
+func convertRobot(r *models.Robot, projectName string) *robotInfo {
+	return &robotInfo{
+		Robot:    r,
+		Username: r.Username(projectName),
+		Access:   r.Actions(),
+		Expired:  r.IsExpired(),
+	}
+}
+
+func convertRobots(robots []*models.Robot, projectName string) []*robotInfo {
+	result := []*robotInfo{}
+	for _, r := range robots {
+		result = append(result, convertRobot(r, projectName))
+	}
+	return result
+}
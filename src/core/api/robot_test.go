@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var robotAPIBasePath = "/api/projects/1/robots"
+
+func TestRobotAPI(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    robotAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    robotAPIBasePath,
+				bodyJSON: &models.RobotReq{
+					Name:   "robot_api_test",
+					Access: []string{models.RobotActionPull},
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400 invalid action
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    robotAPIBasePath,
+				bodyJSON: &models.RobotReq{
+					Name:   "robot_api_test",
+					Access: []string{"delete"},
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 404 non-exist project
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/projects/10000/robots",
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// create
+	rep := &models.RobotRep{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    robotAPIBasePath,
+		bodyJSON: &models.RobotReq{
+			Name:   "robot_api_test",
+			Access: []string{models.RobotActionPull, models.RobotActionPush},
+		},
+		credential: projAdmin,
+	}, rep)
+	require.Nil(t, err)
+	defer dao.DeleteRobot(rep.ID)
+	assert.Equal(t, "robot$library+robot_api_test", rep.Name)
+	assert.NotEmpty(t, rep.Token)
+
+	// conflict
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPost,
+			url:    robotAPIBasePath,
+			bodyJSON: &models.RobotReq{
+				Name:   "robot_api_test",
+				Access: []string{models.RobotActionPull},
+			},
+			credential: projAdmin,
+		},
+		code: http.StatusConflict,
+	})
+
+	// list
+	robots := []*models.Robot{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        robotAPIBasePath,
+		credential: projAdmin,
+	}, &robots)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(robots))
+	assert.Equal(t, rep.ID, robots[0].ID)
+
+	// disable
+	url := fmt.Sprintf("%s/%d", robotAPIBasePath, rep.ID)
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPut,
+			url:    url,
+			bodyJSON: map[string]bool{
+				"disabled": true,
+			},
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+	robot := &models.Robot{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        url,
+		credential: projAdmin,
+	}, robot)
+	require.Nil(t, err)
+	assert.True(t, robot.Disabled)
+
+	// revoke
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodDelete,
+			url:        url,
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method:     http.MethodGet,
+			url:        url,
+			credential: projAdmin,
+		},
+		code: http.StatusNotFound,
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
+)
+
+// ScannerAPI handles requests to /api/scanners/{}
+type ScannerAPI struct {
+	BaseController
+	registration *models.ScannerRegistration
+}
+
+// Prepare validates the user and the registration, only the system admin can manage the scanners
+func (s *ScannerAPI) Prepare() {
+	s.BaseController.Prepare()
+
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(s.GetStringFromPath(":id")) != 0 {
+		id, err := s.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			s.HandleBadRequest(fmt.Sprintf("invalid scanner ID: %s", s.GetStringFromPath(":id")))
+			return
+		}
+		registration, err := dao.GetScannerRegistration(id)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get scanner %d: %v", id, err))
+			return
+		}
+		if registration == nil {
+			s.HandleNotFound(fmt.Sprintf("scanner %d not found", id))
+			return
+		}
+		s.registration = registration
+	}
+}
+
+// Post registers a scanner
+func (s *ScannerAPI) Post() {
+	registration := &models.ScannerRegistration{}
+	s.DecodeJSONReqAndValidate(registration)
+	registration.ID = 0
+	if !s.validateAdapter(registration) {
+		return
+	}
+	if s.isDuplicated(registration) {
+		return
+	}
+
+	id, err := dao.AddScannerRegistration(registration)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to register scanner: %v", err))
+		return
+	}
+	s.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List the registered scanners
+func (s *ScannerAPI) List() {
+	registrations, err := dao.ListScannerRegistrations()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to list scanners: %v", err))
+		return
+	}
+	s.Data["json"] = registrations
+	s.ServeJSON()
+}
+
+// Get the scanner specified by ID
+func (s *ScannerAPI) Get() {
+	s.Data["json"] = s.registration
+	s.ServeJSON()
+}
+
+// Put updates the scanner
+func (s *ScannerAPI) Put() {
+	req := &models.ScannerRegistration{}
+	s.DecodeJSONReqAndValidate(req)
+	req.ID = s.registration.ID
+	if !s.validateAdapter(req) {
+		return
+	}
+	if s.isDuplicated(req) {
+		return
+	}
+
+	if err := dao.UpdateScannerRegistration(req); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to update scanner %d: %v", req.ID, err))
+		return
+	}
+}
+
+// Delete the scanner, the projects choosing it will use the default one
+func (s *ScannerAPI) Delete() {
+	if err := dao.DeleteScannerRegistration(s.registration.ID); err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to delete scanner %d: %v", s.registration.ID, err))
+		return
+	}
+}
+
+// Metadata returns the metadata of the scanner, which describes the capabilities of it
+func (s *ScannerAPI) Metadata() {
+	adapter, err := scanner.New(s.registration.Adapter, s.registration.URL, nil)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to create the adapter of scanner %d: %v", s.registration.ID, err))
+		return
+	}
+	metadata, err := adapter.Metadata()
+	if err != nil {
+		s.RenderError(http.StatusBadGateway, fmt.Sprintf("failed to get the metadata of scanner %d: %v", s.registration.ID, err))
+		return
+	}
+	s.Data["json"] = metadata
+	s.ServeJSON()
+}
+
+// validateAdapter returns false and handles the error if the adapter of the registration isn't supported
+func (s *ScannerAPI) validateAdapter(registration *models.ScannerRegistration) bool {
+	for _, adapter := range scanner.Adapters() {
+		if adapter == registration.Adapter {
+			return true
+		}
+	}
+	s.HandleBadRequest(fmt.Sprintf("unsupported adapter %s, supported: %v", registration.Adapter, scanner.Adapters()))
+	return false
+}
+
+// isDuplicated returns true and handles the error if another scanner with the same name exists
+func (s *ScannerAPI) isDuplicated(registration *models.ScannerRegistration) bool {
+	r, err := dao.GetScannerRegistrationByName(registration.Name)
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get scanner %s: %v", registration.Name, err))
+		return true
+	}
+	if r != nil && r.ID != registration.ID {
+		s.HandleConflict(fmt.Sprintf("scanner %s already exists", registration.Name))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scannerAPIBasePath = "/api/scanners"
+
+func TestScannerAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&scanner.Metadata{
+			Name:    "fake",
+			Vendor:  "Harbor",
+			Version: "1.0",
+		})
+	}))
+	defer server.Close()
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    scannerAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        scannerAPIBasePath,
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400 invalid URL
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    scannerAPIBasePath,
+				bodyJSON: &models.ScannerRegistration{
+					Name:    "scanner1",
+					URL:     "scanner1:8080",
+					Adapter: scanner.AdapterGeneric,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400 unsupported adapter
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    scannerAPIBasePath,
+				bodyJSON: &models.ScannerRegistration{
+					Name:    "scanner1",
+					URL:     server.URL,
+					Adapter: "non-exist",
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    scannerAPIBasePath,
+				bodyJSON: &models.ScannerRegistration{
+					Name:    "scanner1",
+					URL:     server.URL,
+					Adapter: scanner.AdapterGeneric,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    scannerAPIBasePath,
+				bodyJSON: &models.ScannerRegistration{
+					Name:    "scanner1",
+					URL:     server.URL,
+					Adapter: scanner.AdapterGeneric,
+				},
+				credential: sysAdmin,
+			},
+			code: http.StatusConflict,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        scannerAPIBasePath + "/10000",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	registration, err := dao.GetScannerRegistrationByName("scanner1")
+	require.Nil(t, err)
+	require.NotNil(t, registration)
+	defer dao.DeleteScannerRegistration(registration.ID)
+	path := fmt.Sprintf("%s/%d", scannerAPIBasePath, registration.ID)
+
+	// update
+	resp, err := handle(&testingRequest{
+		method: http.MethodPut,
+		url:    path,
+		bodyJSON: &models.ScannerRegistration{
+			Name:      "scanner1",
+			URL:       server.URL,
+			Adapter:   scanner.AdapterGeneric,
+			IsDefault: true,
+		},
+		credential: sysAdmin,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// get
+	r := &models.ScannerRegistration{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path,
+		credential: sysAdmin,
+	}, r))
+	assert.True(t, r.IsDefault)
+
+	// metadata
+	metadata := &scanner.Metadata{}
+	require.Nil(t, handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        path + "/metadata",
+		credential: sysAdmin,
+	}, metadata))
+	assert.Equal(t, "fake", metadata.Name)
+
+	// delete
+	resp, err = handle(&testingRequest{
+		method:     http.MethodDelete,
+		url:        path,
+		credential: sysAdmin,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
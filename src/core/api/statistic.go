@@ -36,6 +36,12 @@ const (
 	TPC = "total_project_count"
 	// TRC : total count of repositories
 	TRC = "total_repo_count"
+	// PubCDC : download count of the charts in public projects
+	PubCDC = "public_chart_download_count"
+	// PriCDC : download count of the charts in private projects
+	PriCDC = "private_chart_download_count"
+	// TCDC : total download count of charts
+	TCDC = "total_chart_download_count"
 )
 
 // StatisticAPI handles request to /api/statistics/
@@ -66,6 +72,7 @@ func (s *StatisticAPI) Get() {
 	statistic[PubPC] = (int64)(len(pubProjs))
 	if len(pubProjs) == 0 {
 		statistic[PubRC] = 0
+		statistic[PubCDC] = 0
 	} else {
 		ids := []int64{}
 		for _, p := range pubProjs {
@@ -79,6 +86,13 @@ func (s *StatisticAPI) Get() {
 			s.CustomAbort(http.StatusInternalServerError, "")
 		}
 		statistic[PubRC] = n
+
+		n, err = dao.GetTotalOfChartDownloads(ids...)
+		if err != nil {
+			log.Errorf("failed to get download count of public charts: %v", err)
+			s.CustomAbort(http.StatusInternalServerError, "")
+		}
+		statistic[PubCDC] = n
 	}
 
 	if s.SecurityCtx.IsSysAdmin() {
@@ -97,6 +111,14 @@ func (s *StatisticAPI) Get() {
 		}
 		statistic[TRC] = n
 		statistic[PriRC] = n - statistic[PubRC]
+
+		n, err = dao.GetTotalOfChartDownloads()
+		if err != nil {
+			log.Errorf("failed to get total download count of charts: %v", err)
+			s.CustomAbort(http.StatusInternalServerError, "")
+		}
+		statistic[TCDC] = n
+		statistic[PriCDC] = n - statistic[PubCDC]
 	} else {
 		value := false
 		result, err := s.ProjectMgr.List(&models.ProjectQueryParam{
@@ -114,6 +136,7 @@ func (s *StatisticAPI) Get() {
 		statistic[PriPC] = result.Total
 		if result.Total == 0 {
 			statistic[PriRC] = 0
+			statistic[PriCDC] = 0
 		} else {
 			ids := []int64{}
 			for _, p := range result.Projects {
@@ -130,6 +153,15 @@ func (s *StatisticAPI) Get() {
 				return
 			}
 			statistic[PriRC] = n
+
+			n, err = dao.GetTotalOfChartDownloads(ids...)
+			if err != nil {
+				s.HandleInternalServerError(fmt.Sprintf(
+					"failed to get download count of charts for user %s: %v",
+					s.username, err))
+				return
+			}
+			statistic[PriCDC] = n
 		}
 	}
 
@@ -29,7 +29,6 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
-	"github.com/goharbor/harbor/src/core/systeminfo"
 	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
 )
 
@@ -48,8 +47,10 @@ type SystemInfo struct {
 
 // Storage models for storage.
 type Storage struct {
-	Total uint64 `json:"total"`
-	Free  uint64 `json:"free"`
+	Driver string `json:"driver"`
+	Total  uint64 `json:"total"`
+	Free   uint64 `json:"free"`
+	Used   uint64 `json:"used"`
 }
 
 // namespaces stores all name spaces on Clair, it should be initialised only once.
@@ -102,6 +103,7 @@ type GeneralInfo struct {
 	RegistryStorageProviderName string                           `json:"registry_storage_provider_name"`
 	ReadOnly                    bool                             `json:"read_only"`
 	WithChartMuseum             bool                             `json:"with_chartmuseum"`
+	OIDCProviderName            string                           `json:"oidc_provider_name,omitempty"`
 }
 
 // validate for validating user if an admin.
@@ -121,16 +123,17 @@ func (sia *SystemInfoAPI) validate() {
 func (sia *SystemInfoAPI) GetVolumeInfo() {
 	sia.validate()
 
-	systeminfo.Init()
 	capacity, err := imagestorage.GlobalDriver.Cap()
 	if err != nil {
-		log.Errorf("failed to get capacity: %v", err)
+		log.Errorf("failed to get capacity of %s storage: %v", imagestorage.GlobalDriver.Name(), err)
 		sia.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
 	systemInfo := SystemInfo{
 		HarborStorage: Storage{
-			Total: capacity.Total,
-			Free:  capacity.Free,
+			Driver: imagestorage.GlobalDriver.Name(),
+			Total:  capacity.Total,
+			Free:   capacity.Free,
+			Used:   capacity.Used,
 		},
 	}
 
@@ -183,6 +186,9 @@ func (sia *SystemInfoAPI) GetGeneralInfo() {
 		ReadOnly:                    config.ReadOnly(),
 		WithChartMuseum:             config.WithChartMuseum(),
 	}
+	if info.AuthMode == common.OIDCAuth {
+		info.OIDCProviderName = utils.SafeCastString(cfg[common.OIDCName])
+	}
 	if info.WithClair {
 		info.ClairVulnStatus = getClairVulnStatus()
 	}
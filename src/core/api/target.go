@@ -26,6 +26,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/common/utils/registry/auth"
 	"github.com/goharbor/harbor/src/core/config"
+	rep_registry "github.com/goharbor/harbor/src/replication/registry"
 )
 
 // TargetAPI handles request to /api/targets/ping /api/targets/{}
@@ -168,6 +169,12 @@ func (t *TargetAPI) Post() {
 	target := &models.RepTarget{}
 	t.DecodeJSONReqAndValidate(target)
 
+	if len(target.RegistryType) > 0 && !rep_registry.IsSupported(target.RegistryType) {
+		t.HandleBadRequest(fmt.Sprintf("unsupported registry type %s, supported types: %v",
+			target.RegistryType, rep_registry.ListKinds()))
+		return
+	}
+
 	ta, err := dao.GetRepTargetByName(target.Name)
 	if err != nil {
 		log.Errorf("failed to get target %s: %v", target.Name, err)
@@ -231,11 +238,12 @@ func (t *TargetAPI) Put() {
 	}
 
 	req := struct {
-		Name     *string `json:"name"`
-		Endpoint *string `json:"endpoint"`
-		Username *string `json:"username"`
-		Password *string `json:"password"`
-		Insecure *bool   `json:"insecure"`
+		Name         *string `json:"name"`
+		Endpoint     *string `json:"endpoint"`
+		Username     *string `json:"username"`
+		Password     *string `json:"password"`
+		Insecure     *bool   `json:"insecure"`
+		RegistryType *string `json:"registry_type"`
 	}{}
 	t.DecodeJSONReq(&req)
 
@@ -257,6 +265,14 @@ func (t *TargetAPI) Put() {
 	if req.Insecure != nil {
 		target.Insecure = *req.Insecure
 	}
+	if req.RegistryType != nil {
+		if !rep_registry.IsSupported(*req.RegistryType) {
+			t.HandleBadRequest(fmt.Sprintf("unsupported registry type %s, supported types: %v",
+				*req.RegistryType, rep_registry.ListKinds()))
+			return
+		}
+		target.RegistryType = *req.RegistryType
+	}
 
 	t.Validate(target)
 
@@ -338,8 +354,11 @@ func newRegistryClient(endpoint string, insecure bool, username, password string
 	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
 		Transport: transport,
 	}, credential)
+	// the fallback authorizer takes effect only when the target doesn't use token service,
+	// e.g. a Docker Registry protected by basic authentication
 	return registry.NewRegistry(endpoint, &http.Client{
-		Transport: registry.NewTransport(transport, authorizer),
+		Transport: registry.NewTransport(transport, authorizer,
+			auth.NewFallbackAuthorizer(credential)),
 	})
 }
 
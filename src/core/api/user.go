@@ -26,6 +26,7 @@ import (
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/auth/oidc"
 	"github.com/goharbor/harbor/src/core/config"
 )
 
@@ -44,6 +45,10 @@ type passwordReq struct {
 	NewPassword string `json:"new_password"`
 }
 
+type cliSecretReq struct {
+	Secret string `json:"secret"`
+}
+
 // Prepare validates the URL and parms
 func (ua *UserAPI) Prepare() {
 	// 从请求中获取 security context 和 project manager
@@ -349,6 +354,60 @@ func (ua *UserAPI) ToggleUserAdminRole() {
 	}
 }
 
+// GetCLISecret handles GET api/users/{}/cli_secret, the CLI secret is used by the user
+// onboarded from the OIDC provider to run "docker login"
+func (ua *UserAPI) GetCLISecret() {
+	if !ua.cliSecretAccessible() {
+		return
+	}
+	secret, err := oidc.GetCLISecret(ua.userID)
+	if err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to get the CLI secret of user %d: %v", ua.userID, err))
+		return
+	}
+	ua.Data["json"] = &cliSecretReq{
+		Secret: secret,
+	}
+	ua.ServeJSON()
+}
+
+// SetCLISecret handles PUT api/users/{}/cli_secret, a random secret is generated
+// if the secret in the request is empty
+func (ua *UserAPI) SetCLISecret() {
+	if !ua.cliSecretAccessible() {
+		return
+	}
+	req := &cliSecretReq{}
+	if len(ua.Ctx.Input.CopyBody(1<<32)) > 0 {
+		ua.DecodeJSONReq(req)
+	}
+	if len(req.Secret) == 0 {
+		req.Secret = utils.GenerateRandomString()
+	} else if isIllegalLength(req.Secret, 8, 128) {
+		ua.HandleBadRequest("secret with illegal length")
+		return
+	}
+	if err := oidc.SetCLISecret(ua.userID, req.Secret); err != nil {
+		ua.HandleInternalServerError(fmt.Sprintf("failed to set the CLI secret of user %d: %v", ua.userID, err))
+		return
+	}
+	ua.Data["json"] = req
+	ua.ServeJSON()
+}
+
+// cliSecretAccessible checks the CLI secret is only accessed by the user himself in OIDC auth mode
+func (ua *UserAPI) cliSecretAccessible() bool {
+	if ua.AuthMode != common.OIDCAuth {
+		ua.HandleStatusPreconditionFailed(fmt.Sprintf("the CLI secret is only available in %s mode", common.OIDCAuth))
+		return false
+	}
+	if ua.userID != ua.currentUserID {
+		ua.HandleForbidden(ua.SecurityCtx.GetUsername())
+		return false
+	}
+	return true
+}
+
 // modifiable returns whether the modify is allowed based on current auth mode and context
 func (ua *UserAPI) modifiable() bool {
 	if ua.AuthMode == common.DBAuth {
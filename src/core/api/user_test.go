@@ -572,3 +572,38 @@ func TestModifiable(t *testing.T) {
 	}
 	assert.True(ua4.modifiable())
 }
+
+func TestUsersCLISecret(t *testing.T) {
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    "/api/users/current/cli_secret",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 412, the auth mode is not oidc_auth
+		{
+			request: &testingRequest{
+				method:     http.MethodGet,
+				url:        "/api/users/current/cli_secret",
+				credential: admin,
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		// 412, the auth mode is not oidc_auth
+		{
+			request: &testingRequest{
+				method:     http.MethodPut,
+				url:        "/api/users/current/cli_secret",
+				credential: admin,
+				bodyJSON: map[string]string{
+					"secret": "cli-secret",
+				},
+			},
+			code: http.StatusPreconditionFailed,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+}
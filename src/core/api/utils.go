@@ -15,6 +15,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -23,7 +24,6 @@ import (
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
-	"github.com/goharbor/harbor/src/common/utils/clair"
 	registry_error "github.com/goharbor/harbor/src/common/utils/error"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/registry"
@@ -35,6 +35,9 @@ import (
 	coreutils "github.com/goharbor/harbor/src/core/utils"
 )
 
+// ErrPurgeNotReadOnly is returned when purging the orphan artifacts while Harbor isn't in read only mode
+var ErrPurgeNotReadOnly = errors.New("the orphan artifacts can only be purged in read only mode")
+
 // SyncRegistry syncs the repositories of registry with database.
 func SyncRegistry(pm promgr.ProjectManager) error {
 
@@ -114,6 +117,95 @@ func SyncRegistry(pm promgr.ProjectManager) error {
 	return nil
 }
 
+// PurgeOrphanArtifacts deletes the artifacts which exist only in registry and have no
+// repository record in Harbor. The blobs referenced by the artifacts are recorded before
+// deleting the manifests, so that they can be unlinked by the pre-pass of the garbage collection.
+// Harbor must be in read only mode unless it's a dry run, otherwise the repository whose
+// push has just finished but whose notification isn't handled yet would be purged.
+func PurgeOrphanArtifacts(dryRun bool) (*models.OrphanPurgeResult, error) {
+	if !dryRun && !config.ReadOnly() {
+		return nil, ErrPurgeNotReadOnly
+	}
+	log.Infof("Start purging the artifacts which exist only in registry, dry run: %t", dryRun)
+
+	reposInRegistry, err := catalog()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	result := &models.OrphanPurgeResult{
+		DryRun:       dryRun,
+		Repositories: []string{},
+	}
+	for _, repository := range reposInRegistry {
+		if dao.RepositoryExists(repository) {
+			continue
+		}
+		count, err := purgeOrphanRepository(repository, dryRun)
+		if err != nil {
+			log.Errorf("failed to purge the artifacts of repository %s: %v", repository, err)
+		}
+		if count == 0 {
+			continue
+		}
+		result.Repositories = append(result.Repositories, repository)
+		result.ArtifactsPurged += count
+	}
+
+	log.Infof("Purge the artifacts which exist only in registry is done, %d artifacts of %d repositories purged.",
+		result.ArtifactsPurged, len(result.Repositories))
+	return result, nil
+}
+
+// purgeOrphanRepository deletes the tagged manifests of the repository and returns the count of them
+func purgeOrphanRepository(repository string, dryRun bool) (int, error) {
+	client, err := coreutils.NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return 0, err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		if regErr, ok := err.(*registry_error.HTTPError); ok && regErr.StatusCode == http.StatusNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	count := 0
+	purged := map[string]bool{}
+	for _, tag := range tags {
+		digest, exist, err := client.ManifestExist(tag)
+		if err != nil {
+			return count, err
+		}
+		if !exist || purged[digest] {
+			continue
+		}
+		purged[digest] = true
+
+		if dryRun {
+			log.Infof("[dry run] artifact %s:%s(%s) will be purged", repository, tag, digest)
+			count++
+			continue
+		}
+		// the record may be created by the image pushed during the purging
+		if dao.RepositoryExists(repository) {
+			log.Infof("repository %s is recorded during the purging, skip it", repository)
+			return count, nil
+		}
+		if err := coreutils.RecordArtifactBlobs(repository, tag); err != nil {
+			log.Warningf("failed to record the blobs of artifact %s:%s: %v", repository, tag, err)
+		}
+		if err := client.DeleteManifest(digest); err != nil {
+			return count, err
+		}
+		log.Infof("artifact %s:%s(%s) purged", repository, tag, digest)
+		count++
+	}
+	return count, nil
+}
+
 func catalog() ([]string, error) {
 	repositories := []string{}
 
@@ -293,36 +385,17 @@ func repositoryExist(name string, client *registry.Repository) (bool, error) {
 	return len(tags) != 0, nil
 }
 
-// transformVulnerabilities transforms the returned value of Clair API to a list of VulnerabilityItem
-func transformVulnerabilities(layerWithVuln *models.ClairLayerEnvelope) []*models.VulnerabilityItem {
-	res := []*models.VulnerabilityItem{}
-	l := layerWithVuln.Layer
-	if l == nil {
-		return res
-	}
-	features := l.Features
-	if features == nil {
-		return res
-	}
-	for _, f := range features {
-		vulnerabilities := f.Vulnerabilities
-		if vulnerabilities == nil {
-			continue
-		}
-		for _, v := range vulnerabilities {
-			vItem := &models.VulnerabilityItem{
-				ID:          v.Name,
-				Pkg:         f.Name,
-				Version:     f.Version,
-				Severity:    clair.ParseClairSev(v.Severity),
-				Fixed:       v.FixedBy,
-				Link:        v.Link,
-				Description: v.Description,
-			}
-			res = append(res, vItem)
+// countTags returns the count of the tags under the repository, 0 is returned
+// if the repository doesn't exist
+func countTags(client *registry.Repository) (int64, error) {
+	tags, err := client.ListTag()
+	if err != nil {
+		if regErr, ok := err.(*registry_error.HTTPError); ok && regErr.StatusCode == http.StatusNotFound {
+			return 0, nil
 		}
+		return 0, err
 	}
-	return res
+	return int64(len(tags)), nil
 }
 
 // Watch the configuration changes.
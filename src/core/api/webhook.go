@@ -0,0 +1,250 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// WebhookPolicyAPI handles requests to /api/projects/{}/webhook/policies/{}
+type WebhookPolicyAPI struct {
+	BaseController
+	project   *models.Project
+	policy    *models.WebhookPolicy
+	secretKey string
+}
+
+// Prepare validates the project and the policy, only the project
+// admin can manage the webhook policies
+func (w *WebhookPolicyAPI) Prepare() {
+	w.BaseController.Prepare()
+
+	if !w.SecurityCtx.IsAuthenticated() {
+		w.HandleUnauthorized()
+		return
+	}
+
+	pid, err := w.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		w.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", w.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := w.ProjectMgr.Get(pid)
+	if err != nil {
+		w.ParseAndHandleError(fmt.Sprintf("failed to get project %d", pid), err)
+		return
+	}
+	if project == nil {
+		w.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	w.project = project
+
+	if !w.SecurityCtx.HasAllPerm(pid) {
+		w.HandleForbidden(w.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(w.GetStringFromPath(":id")) != 0 {
+		id, err := w.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			w.HandleBadRequest(fmt.Sprintf("invalid webhook policy ID: %s", w.GetStringFromPath(":id")))
+			return
+		}
+		policy, err := dao.GetWebhookPolicy(id)
+		if err != nil {
+			w.HandleInternalServerError(fmt.Sprintf("failed to get webhook policy %d: %v", id, err))
+			return
+		}
+		if policy == nil || policy.ProjectID != project.ProjectID {
+			w.HandleNotFound(fmt.Sprintf("webhook policy %d not found", id))
+			return
+		}
+		w.policy = policy
+	}
+
+	w.secretKey, err = config.SecretKey()
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to get secret key: %v", err))
+		return
+	}
+}
+
+// Post creates a webhook policy
+func (w *WebhookPolicyAPI) Post() {
+	policy := &models.WebhookPolicy{}
+	w.DecodeJSONReqAndValidate(policy)
+	policy.ID = 0
+	policy.ProjectID = w.project.ProjectID
+	policy.Creator = w.SecurityCtx.GetUsername()
+
+	duplicated, err := w.isDuplicated(policy)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to list webhook policies: %v", err))
+		return
+	}
+	if duplicated {
+		w.HandleConflict(fmt.Sprintf("webhook policy %s already exists", policy.Name))
+		return
+	}
+
+	if len(policy.Secret) > 0 {
+		secret, err := utils.ReversibleEncrypt(policy.Secret, w.secretKey)
+		if err != nil {
+			w.HandleInternalServerError(fmt.Sprintf("failed to encrypt secret: %v", err))
+			return
+		}
+		policy.Secret = secret
+	}
+
+	id, err := dao.AddWebhookPolicy(policy)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to create webhook policy: %v", err))
+		return
+	}
+	w.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List the webhook policies of the project
+func (w *WebhookPolicyAPI) List() {
+	page, size := w.GetPaginationParams()
+	query := &models.WebhookPolicyQuery{
+		Name:      w.GetString("name"),
+		ProjectID: w.project.ProjectID,
+		Pagination: models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	}
+
+	total, err := dao.GetTotalOfWebhookPolicies(query)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to get the total of webhook policies: %v", err))
+		return
+	}
+	policies, err := dao.ListWebhookPolicies(query)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to list webhook policies: %v", err))
+		return
+	}
+	for _, policy := range policies {
+		policy.Secret = ""
+	}
+
+	w.SetPaginationHeader(total, page, size)
+	w.Data["json"] = policies
+	w.ServeJSON()
+}
+
+// Get the webhook policy specified by ID, the secret is never returned
+func (w *WebhookPolicyAPI) Get() {
+	w.policy.Secret = ""
+	w.Data["json"] = w.policy
+	w.ServeJSON()
+}
+
+// Put updates the webhook policy, the secret is kept if it isn't provided
+func (w *WebhookPolicyAPI) Put() {
+	req := &models.WebhookPolicy{}
+	w.DecodeJSONReqAndValidate(req)
+	req.ID = w.policy.ID
+	req.ProjectID = w.policy.ProjectID
+
+	duplicated, err := w.isDuplicated(req)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to list webhook policies: %v", err))
+		return
+	}
+	if duplicated {
+		w.HandleConflict(fmt.Sprintf("webhook policy %s already exists", req.Name))
+		return
+	}
+
+	if len(req.Secret) > 0 {
+		secret, err := utils.ReversibleEncrypt(req.Secret, w.secretKey)
+		if err != nil {
+			w.HandleInternalServerError(fmt.Sprintf("failed to encrypt secret: %v", err))
+			return
+		}
+		req.Secret = secret
+	} else {
+		req.Secret = w.policy.Secret
+	}
+
+	if err = dao.UpdateWebhookPolicy(req); err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to update webhook policy %d: %v", w.policy.ID, err))
+		return
+	}
+}
+
+// Delete the webhook policy and its delivery history
+func (w *WebhookPolicyAPI) Delete() {
+	if err := dao.DeleteWebhookPolicy(w.policy.ID); err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to delete webhook policy %d: %v", w.policy.ID, err))
+		return
+	}
+}
+
+// ListExecutions returns the delivery history of the webhook policy
+func (w *WebhookPolicyAPI) ListExecutions() {
+	page, size := w.GetPaginationParams()
+	query := &models.WebhookExecutionQuery{
+		PolicyID:  w.policy.ID,
+		EventType: w.GetString("event_type"),
+		Status:    w.GetString("status"),
+		Pagination: models.Pagination{
+			Page: page,
+			Size: size,
+		},
+	}
+
+	total, err := dao.GetTotalOfWebhookExecutions(query)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to get the total of webhook executions: %v", err))
+		return
+	}
+	executions, err := dao.ListWebhookExecutions(query)
+	if err != nil {
+		w.HandleInternalServerError(fmt.Sprintf("failed to list webhook executions: %v", err))
+		return
+	}
+
+	w.SetPaginationHeader(total, page, size)
+	w.Data["json"] = executions
+	w.ServeJSON()
+}
+
+// isDuplicated returns whether the project has another policy with the same name
+func (w *WebhookPolicyAPI) isDuplicated(policy *models.WebhookPolicy) (bool, error) {
+	policies, err := dao.ListWebhookPolicies(&models.WebhookPolicyQuery{
+		ProjectID: w.project.ProjectID,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, p := range policies {
+		if p.ID != policy.ID && p.Name == policy.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var webhookPolicyAPIBasePath = "/api/projects/1/webhook/policies"
+
+func TestWebhookPolicyAPI(t *testing.T) {
+	policy := &models.WebhookPolicy{
+		Name:       "webhook_api_test",
+		TargetURL:  "http://127.0.0.1/webhook",
+		Secret:     "secret",
+		EventTypes: []string{models.WebhookEventPushImage},
+		Enabled:    true,
+	}
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    webhookPolicyAPIBasePath,
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403 developer
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        webhookPolicyAPIBasePath,
+				bodyJSON:   policy,
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+		// 400 invalid event type
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    webhookPolicyAPIBasePath,
+				bodyJSON: &models.WebhookPolicy{
+					Name:       "webhook_api_test",
+					TargetURL:  "http://127.0.0.1/webhook",
+					EventTypes: []string{"unknown"},
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400 invalid target URL
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    webhookPolicyAPIBasePath,
+				bodyJSON: &models.WebhookPolicy{
+					Name:       "webhook_api_test",
+					TargetURL:  "127.0.0.1/webhook",
+					EventTypes: []string{models.WebhookEventPushImage},
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+		// 201
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        webhookPolicyAPIBasePath,
+				bodyJSON:   policy,
+				credential: projAdmin,
+			},
+			code: http.StatusCreated,
+		},
+		// 409
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        webhookPolicyAPIBasePath,
+				bodyJSON:   policy,
+				credential: projAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+	defer dao.DeleteWebhookPoliciesOfProject(1)
+
+	// list
+	policies := []*models.WebhookPolicy{}
+	err := handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        webhookPolicyAPIBasePath,
+		credential: projAdmin,
+	}, &policies)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(policies))
+	assert.Empty(t, policies[0].Secret)
+	assert.Equal(t, projAdmin.Name, policies[0].Creator)
+
+	// the secret is stored encrypted
+	p, err := dao.GetWebhookPolicy(policies[0].ID)
+	require.Nil(t, err)
+	require.NotNil(t, p)
+	assert.NotEqual(t, "secret", p.Secret)
+
+	// update without secret keeps the original one
+	url := fmt.Sprintf("%s/%d", webhookPolicyAPIBasePath, p.ID)
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPut,
+			url:    url,
+			bodyJSON: &models.WebhookPolicy{
+				Name:       "webhook_api_test",
+				TargetURL:  "http://127.0.0.1/webhook",
+				EventTypes: []string{models.WebhookEventPullImage},
+			},
+			credential: projAdmin,
+		},
+		code: http.StatusOK,
+	})
+	updated, err := dao.GetWebhookPolicy(p.ID)
+	require.Nil(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, p.Secret, updated.Secret)
+	assert.Equal(t, []string{models.WebhookEventPullImage}, updated.EventTypes)
+	assert.False(t, updated.Enabled)
+
+	// delivery history
+	executions := []*models.WebhookExecution{}
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodGet,
+		url:        url + "/executions",
+		credential: projAdmin,
+	}, &executions)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(executions))
+}
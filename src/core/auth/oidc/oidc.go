@@ -0,0 +1,158 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/dao/group"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/auth"
+)
+
+// Auth is the implementation of AuthenticateHelper for the users onboarded from the
+// OIDC provider, as the password of the user is not known by Harbor, the user
+// authenticates with the CLI secret
+type Auth struct {
+	auth.DefaultAuthenticateHelper
+}
+
+// Authenticate checks the CLI secret of the user and refreshes the token of the
+// user to make sure the user is still valid in the OIDC provider
+func (o *Auth) Authenticate(m models.AuthModel) (*models.User, error) {
+	user, err := dao.GetUser(models.User{Username: m.Principal})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, auth.NewErrAuth(fmt.Sprintf("user %s not found", m.Principal))
+	}
+	oidcUser, err := dao.GetOIDCUserByUserID(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if oidcUser == nil {
+		return nil, auth.NewErrAuth(fmt.Sprintf("user %s is not onboarded from the OIDC provider", m.Principal))
+	}
+	secret, err := decrypt(oidcUser.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(m.Password)) != 1 {
+		return nil, auth.NewErrAuth("invalid CLI secret")
+	}
+
+	claims, err := refreshClaims(oidcUser)
+	if err != nil {
+		log.Warningf("Failed to refresh the token of user %s: %v", m.Principal, err)
+		return nil, auth.NewErrAuth("the OIDC session of the user is expired, please log in to the UI again")
+	}
+	user.GroupList, err = onBoardGroups(claims.Groups)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// OnBoardUser will check if a user exists in user table, if not insert the user and
+// put the id in the pointer of user model, if it does exist, return the user's profile.
+func (o *Auth) OnBoardUser(user *models.User) error {
+	user.Username = strings.TrimSpace(user.Username)
+	if len(user.Username) == 0 {
+		return fmt.Errorf("The Username is empty")
+	}
+	if len(user.Password) == 0 {
+		user.Password = "1234567ab"
+	}
+	fillEmailRealName(user)
+	user.Comment = "From OIDC"
+	return dao.OnBoardUser(user)
+}
+
+func fillEmailRealName(user *models.User) {
+	if len(user.Realname) == 0 {
+		user.Realname = user.Username
+	}
+	if len(user.Email) == 0 {
+		user.Email = user.Username + "@oidc.placeholder"
+	}
+}
+
+// SearchUser searches the users onboarded from the OIDC provider, as the provider
+// does not support searching users, a user can only be found after logging in once
+func (o *Auth) SearchUser(username string) (*models.User, error) {
+	user, err := dao.GetUser(models.User{Username: username})
+	if err != nil || user == nil {
+		return nil, err
+	}
+	oidcUser, err := dao.GetOIDCUserByUserID(user.UserID)
+	if err != nil || oidcUser == nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SearchGroup returns the group whose key is the value in the groups claim of the ID token,
+// as the provider does not support searching groups, any key is accepted
+func (o *Auth) SearchGroup(groupKey string) (*models.UserGroup, error) {
+	groupKey = strings.TrimSpace(groupKey)
+	if len(groupKey) == 0 {
+		return nil, nil
+	}
+	return &models.UserGroup{
+		GroupName:   groupKey,
+		GroupType:   common.OIDCGroupType,
+		LdapGroupDN: groupKey,
+	}, nil
+}
+
+// OnBoardGroup creates the group in Harbor DB if it does not exist, if altGroupName is
+// not empty, take the altGroupName as groupName in harbor DB.
+func (o *Auth) OnBoardGroup(g *models.UserGroup, altGroupName string) error {
+	if len(g.LdapGroupDN) == 0 {
+		return auth.ErrorGroupNotExist
+	}
+	if len(altGroupName) > 0 {
+		g.GroupName = altGroupName
+	}
+	g.GroupType = common.OIDCGroupType
+	return group.OnBoardUserGroup(g, "LdapGroupDN", "GroupType")
+}
+
+// onBoardGroups onboards the groups in the ID token so that they can be added as project members
+func onBoardGroups(names []string) ([]*models.UserGroup, error) {
+	groups := []*models.UserGroup{}
+	for _, name := range names {
+		g := &models.UserGroup{
+			GroupName:   name,
+			GroupType:   common.OIDCGroupType,
+			LdapGroupDN: name,
+		}
+		if err := group.OnBoardUserGroup(g, "LdapGroupDN", "GroupType"); err != nil {
+			return nil, fmt.Errorf("failed to onboard group %s: %v", name, err)
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+func init() {
+	auth.Register(common.OIDCAuth, &Auth{})
+}
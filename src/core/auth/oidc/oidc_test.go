@@ -0,0 +1,159 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/auth"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var issuer *test.OIDCIssuer
+
+func TestMain(m *testing.M) {
+	test.InitDatabaseFromEnv()
+	server, err := test.NewAdminserver(nil)
+	if err != nil {
+		panic(err)
+	}
+	defer server.Close()
+
+	if err := os.Setenv("ADMINSERVER_URL", server.URL); err != nil {
+		log.Fatalf("failed to set env %s: %v", "ADMINSERVER_URL", err)
+	}
+	secretKeyPath := "/tmp/secretkey"
+	if _, err = test.GenerateKey(secretKeyPath); err != nil {
+		log.Fatalf("failed to generate secret key: %v", err)
+	}
+	defer os.Remove(secretKeyPath)
+	if err := os.Setenv("KEY_PATH", secretKeyPath); err != nil {
+		log.Fatalf("failed to set env %s: %v", "KEY_PATH", err)
+	}
+	if err = config.Init(); err != nil {
+		panic(err)
+	}
+
+	issuer, err = test.NewOIDCIssuer("harbor")
+	if err != nil {
+		panic(err)
+	}
+	defer issuer.Close()
+	settings = func() (*models.OIDCSettings, error) {
+		return &models.OIDCSettings{
+			Endpoint:    issuer.URL,
+			ClientID:    "harbor",
+			Scope:       []string{"openid"},
+			GroupsClaim: "groups",
+		}, nil
+	}
+
+	rc := m.Run()
+	os.Exit(rc)
+}
+
+func TestLoginAndAuthenticate(t *testing.T) {
+	code := issuer.Authorize(map[string]interface{}{
+		"sub":                "oidc-subject",
+		"preferred_username": "oidc_user",
+		"email":              "oidc_user@test.com",
+		"groups":             []string{"oidc_dev"},
+	})
+	user, err := Login(code)
+	require.Nil(t, err)
+	require.NotNil(t, user)
+	defer dao.PrepareTestData([]string{
+		"delete from oidc_user where subiss like 'oidc-subject%'",
+		"delete from user_group where group_type = 2",
+		"delete from harbor_user where username = 'oidc_user'",
+	}, nil)
+	assert.Equal(t, "oidc_user", user.Username)
+	assert.Equal(t, "oidc_user@test.com", user.Email)
+	require.Equal(t, 1, len(user.GroupList))
+	assert.Equal(t, "oidc_dev", user.GroupList[0].LdapGroupDN)
+	assert.Equal(t, common.OIDCGroupType, user.GroupList[0].GroupType)
+
+	// log in again returns the same user
+	code = issuer.Authorize(map[string]interface{}{
+		"sub":                "oidc-subject",
+		"preferred_username": "oidc_user",
+	})
+	u, err := Login(code)
+	require.Nil(t, err)
+	assert.Equal(t, user.UserID, u.UserID)
+	assert.Equal(t, 0, len(u.GroupList))
+
+	// another subject can not take over the username
+	code = issuer.Authorize(map[string]interface{}{
+		"sub":                "another-subject",
+		"preferred_username": "oidc_user",
+	})
+	_, err = Login(code)
+	assert.Equal(t, ErrUserConflict, err)
+
+	// authenticate with the CLI secret
+	helper := &Auth{}
+	_, err = helper.Authenticate(models.AuthModel{
+		Principal: "oidc_user",
+		Password:  "invalid",
+	})
+	_, ok := err.(auth.ErrAuth)
+	assert.True(t, ok)
+
+	require.Nil(t, SetCLISecret(user.UserID, "cli-secret"))
+	secret, err := GetCLISecret(user.UserID)
+	require.Nil(t, err)
+	assert.Equal(t, "cli-secret", secret)
+	u, err = helper.Authenticate(models.AuthModel{
+		Principal: "oidc_user",
+		Password:  "cli-secret",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, user.UserID, u.UserID)
+
+	// the user can be searched after logging in
+	u, err = helper.SearchUser("oidc_user")
+	require.Nil(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, user.UserID, u.UserID)
+}
+
+func TestSearchAndOnBoardGroup(t *testing.T) {
+	helper := &Auth{}
+	g, err := helper.SearchGroup(" ")
+	require.Nil(t, err)
+	assert.Nil(t, g)
+
+	g, err = helper.SearchGroup("oidc_ops")
+	require.Nil(t, err)
+	require.NotNil(t, g)
+	require.Nil(t, helper.OnBoardGroup(g, "ops"))
+	defer dao.PrepareTestData([]string{"delete from user_group where group_type = 2"}, nil)
+	assert.NotEqual(t, 0, g.ID)
+	assert.Equal(t, "ops", g.GroupName)
+
+	// onboarding the same group returns the existing one
+	g2 := &models.UserGroup{LdapGroupDN: "oidc_ops"}
+	require.Nil(t, helper.OnBoardGroup(g2, ""))
+	assert.Equal(t, g.ID, g2.ID)
+}
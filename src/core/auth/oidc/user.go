@@ -0,0 +1,258 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	oidc_utils "github.com/goharbor/harbor/src/common/utils/oidc"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// CallbackPath is the path the OIDC provider redirects the user back to
+const CallbackPath = "/c/oidc/callback"
+
+// ErrUserConflict is returned when the username of the OIDC user is taken by
+// a user not onboarded from the OIDC provider
+var ErrUserConflict = errors.New("the username is already used by another user")
+
+var (
+	// settings and newClient can be replaced in tests
+	settings  = config.OIDCSettings
+	newClient = oidc_utils.NewDefaultClient
+
+	lock      sync.Mutex
+	client    oidc_utils.Client
+	clientCfg *oidc_utils.ClientConfig
+)
+
+// GetClient returns the client to access the OIDC provider, the client is
+// created again when the settings are changed
+func GetClient() (oidc_utils.Client, error) {
+	s, err := settings()
+	if err != nil {
+		return nil, err
+	}
+	extEndpoint, err := config.ExtEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &oidc_utils.ClientConfig{
+		Endpoint:      s.Endpoint,
+		ClientID:      s.ClientID,
+		ClientSecret:  s.ClientSecret,
+		Scope:         s.Scope,
+		RedirectURL:   strings.TrimSuffix(extEndpoint, "/") + CallbackPath,
+		GroupsClaim:   s.GroupsClaim,
+		SkipTLSVerify: !s.VerifyCert,
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if client != nil && reflect.DeepEqual(cfg, clientCfg) {
+		return client, nil
+	}
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, clientCfg = c, cfg
+	return client, nil
+}
+
+// Login exchanges the authorization code for the token, onboards the user when the
+// user logs in for the first time and returns the user with the groups in the ID token
+func Login(code string) (*models.User, error) {
+	c, err := GetClient()
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.ExchangeToken(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the token: %v", err)
+	}
+	claims, err := c.VerifyToken(token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	encryptedToken, err := encryptToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := getOrOnBoardUser(claims, encryptedToken)
+	if err != nil {
+		return nil, err
+	}
+	user.GroupList, err = onBoardGroups(claims.Groups)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func getOrOnBoardUser(claims *oidc_utils.Claims, encryptedToken string) (*models.User, error) {
+	oidcUser, err := dao.GetOIDCUserBySubIss(claims.SubIss())
+	if err != nil {
+		return nil, err
+	}
+	if oidcUser != nil {
+		user, err := dao.GetUser(models.User{UserID: oidcUser.UserID})
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("user %d onboarded from the OIDC provider not found", oidcUser.UserID)
+		}
+		oidcUser.Token = encryptedToken
+		if err = dao.UpdateOIDCUser(oidcUser, "Token"); err != nil {
+			return nil, err
+		}
+		user.Email = claims.Email
+		user.Realname = claims.Name
+		fillEmailRealName(user)
+		if err = dao.ChangeUserProfile(*user, "Email", "Realname"); err != nil {
+			log.Warningf("Failed to update user profile, user: %s, error: %v", user.Username, err)
+		}
+		return user, nil
+	}
+
+	// the username of the local users or the users onboarded from other
+	// subjects must not be taken over
+	existing, err := dao.GetUser(models.User{Username: claims.Username})
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrUserConflict
+	}
+	user := &models.User{
+		Username: claims.Username,
+		Email:    claims.Email,
+		Realname: claims.Name,
+	}
+	if err = (&Auth{}).OnBoardUser(user); err != nil {
+		return nil, err
+	}
+	secret, err := encrypt(utils.GenerateRandomString())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dao.AddOIDCUser(&models.OIDCUser{
+		UserID: user.UserID,
+		SubIss: claims.SubIss(),
+		Secret: secret,
+		Token:  encryptedToken,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// refreshClaims returns the claims of the stored ID token, the token is refreshed
+// when it is expired and the refreshed one is stored
+func refreshClaims(oidcUser *models.OIDCUser) (*oidc_utils.Claims, error) {
+	c, err := GetClient()
+	if err != nil {
+		return nil, err
+	}
+	data, err := decrypt(oidcUser.Token)
+	if err != nil {
+		return nil, err
+	}
+	token := &oidc_utils.Token{}
+	if err = json.Unmarshal([]byte(data), token); err != nil {
+		return nil, err
+	}
+	if claims, err := c.VerifyToken(token.IDToken); err == nil {
+		return claims, nil
+	}
+
+	refreshed, err := c.RefreshToken(token)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := c.VerifyToken(refreshed.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if oidcUser.Token, err = encryptToken(refreshed); err != nil {
+		return nil, err
+	}
+	if err = dao.UpdateOIDCUser(oidcUser, "Token"); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GetCLISecret returns the CLI secret of the user onboarded from the OIDC provider
+func GetCLISecret(userID int) (string, error) {
+	oidcUser, err := dao.GetOIDCUserByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if oidcUser == nil {
+		return "", fmt.Errorf("user %d is not onboarded from the OIDC provider", userID)
+	}
+	return decrypt(oidcUser.Secret)
+}
+
+// SetCLISecret updates the CLI secret of the user onboarded from the OIDC provider
+func SetCLISecret(userID int, secret string) error {
+	oidcUser, err := dao.GetOIDCUserByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if oidcUser == nil {
+		return fmt.Errorf("user %d is not onboarded from the OIDC provider", userID)
+	}
+	if oidcUser.Secret, err = encrypt(secret); err != nil {
+		return err
+	}
+	return dao.UpdateOIDCUser(oidcUser, "Secret")
+}
+
+func encryptToken(token *oidc_utils.Token) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return encrypt(string(data))
+}
+
+func encrypt(str string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleEncrypt(str, key)
+}
+
+func decrypt(str string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleDecrypt(str, key)
+}
@@ -168,14 +168,34 @@ func Load() error {
 	return err
 }
 
-// Reset configurations
-func Reset() error {
-	return mg.Reset()
+// Reset configurations, the changes are recorded with the operator
+func Reset(operator string) error {
+	return mg.Reset(operator)
 }
 
-// Upload uploads all system configurations to admin server
-func Upload(cfg map[string]interface{}) error {
-	return mg.Upload(cfg)
+// Upload uploads all system configurations to admin server, the changes are recorded with the operator
+func Upload(cfg map[string]interface{}, operator string) error {
+	return mg.Upload(cfg, operator)
+}
+
+// ListRevisions lists the revisions of system configurations
+func ListRevisions(query *models.ConfigRevisionQuery) (*models.ConfigRevisionList, error) {
+	return mg.Revisions(query)
+}
+
+// GetRevision returns the revision of system configurations specified by ID
+func GetRevision(id int64) (*models.ConfigRevision, error) {
+	return mg.Revision(id)
+}
+
+// GetRevisionSnapshot returns system configurations recorded by the revision specified by ID
+func GetRevisionSnapshot(id int64) (map[string]interface{}, error) {
+	return mg.RevisionSnapshot(id)
+}
+
+// Rollback restores the keys of system configurations to the revision specified by ID
+func Rollback(id int64, operator string, keys []string) error {
+	return mg.Rollback(id, operator, keys)
 }
 
 // GetSystemCfg returns the system configurations
@@ -535,6 +555,29 @@ func UAASettings() (*models.UAASettings, error) {
 	return us, nil
 }
 
+// OIDCSettings returns the OIDCSettings to access the OIDC provider.
+func OIDCSettings() (*models.OIDCSettings, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+	scope := []string{}
+	for _, s := range strings.Split(utils.SafeCastString(cfg[common.OIDCScope]), ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			scope = append(scope, s)
+		}
+	}
+	return &models.OIDCSettings{
+		Name:         utils.SafeCastString(cfg[common.OIDCName]),
+		Endpoint:     utils.SafeCastString(cfg[common.OIDCEndpoint]),
+		ClientID:     utils.SafeCastString(cfg[common.OIDCClientID]),
+		ClientSecret: utils.SafeCastString(cfg[common.OIDCClientSecret]),
+		Scope:        scope,
+		VerifyCert:   utils.SafeCastBool(cfg[common.OIDCVerifyCert]),
+		GroupsClaim:  utils.SafeCastString(cfg[common.OIDCGroupsClaim]),
+	}, nil
+}
+
 // ReadOnly returns a bool to indicates if Harbor is in read only mode.
 func ReadOnly() bool {
 	cfg, err := mg.Get()
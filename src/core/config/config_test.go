@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -64,10 +65,31 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("failed to load configurations: %v", err)
 	}
 
-	if err := Upload(map[string]interface{}{}); err != nil {
+	if err := Upload(map[string]interface{}{}, "admin"); err != nil {
 		t.Fatalf("failed to upload configurations: %v", err)
 	}
 
+	revisions, err := ListRevisions(&models.ConfigRevisionQuery{
+		Key: common.AUTHMode,
+	})
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	assert.Equal(int64(1), revisions.Total)
+	revision, err := GetRevision(1)
+	if err != nil {
+		t.Fatalf("failed to get revision: %v", err)
+	}
+	assert.Equal("admin", revision.Operator)
+	snapshot, err := GetRevisionSnapshot(1)
+	if err != nil {
+		t.Fatalf("failed to get the snapshot of revision: %v", err)
+	}
+	assert.Equal(common.DBAuth, snapshot[common.AUTHMode])
+	if err := Rollback(1, "admin", []string{common.AUTHMode}); err != nil {
+		t.Fatalf("failed to rollback configurations: %v", err)
+	}
+
 	if _, err := GetSystemCfg(); err != nil {
 		t.Fatalf("failed to get system configurations: %v", err)
 	}
@@ -171,7 +193,7 @@ func TestConfig(t *testing.T) {
 	}
 
 	// reset configurations
-	if err = Reset(); err != nil {
+	if err = Reset("admin"); err != nil {
 		t.Errorf("failed to reset configurations: %v", err)
 		return
 	}
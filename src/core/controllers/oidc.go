@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/auth/oidc"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// the key of the state of the authorization code flow stored in session
+const oidcStateKey = "oidc_state"
+
+// OIDCController handles the authorization code flow to log in with the OIDC provider
+type OIDCController struct {
+	CommonController
+}
+
+// Prepare checks the auth mode is OIDC
+func (oc *OIDCController) Prepare() {
+	mode, err := config.AuthMode()
+	if err != nil {
+		log.Errorf("Failed to get the auth mode: %v", err)
+		oc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	if mode != common.OIDCAuth {
+		oc.CustomAbort(http.StatusPreconditionFailed, fmt.Sprintf("the auth mode is not %s", common.OIDCAuth))
+	}
+}
+
+// RedirectLogin redirects the user to the OIDC provider to log in
+func (oc *OIDCController) RedirectLogin() {
+	client, err := oidc.GetClient()
+	if err != nil {
+		log.Errorf("Failed to get the OIDC client: %v", err)
+		oc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	state := utils.GenerateRandomString()
+	oc.SetSession(oidcStateKey, state)
+	oc.Controller.Redirect(client.AuthCodeURL(state), http.StatusFound)
+}
+
+// Callback handles the redirection from the OIDC provider, the user is onboarded
+// and logged in with the authorization code
+func (oc *OIDCController) Callback() {
+	state, _ := oc.GetSession(oidcStateKey).(string)
+	oc.DelSession(oidcStateKey)
+	if len(state) == 0 || oc.GetString("state") != state {
+		oc.CustomAbort(http.StatusBadRequest, "invalid state")
+	}
+	if e := oc.GetString("error"); len(e) > 0 {
+		log.Errorf("Failed to log in with the OIDC provider: %s, %s", e, oc.GetString("error_description"))
+		oc.CustomAbort(http.StatusUnauthorized, "")
+	}
+
+	user, err := oidc.Login(oc.GetString("code"))
+	if err == oidc.ErrUserConflict {
+		oc.CustomAbort(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		log.Errorf("Error occurred in OIDC login: %v", err)
+		oc.CustomAbort(http.StatusUnauthorized, "")
+	}
+	oc.SetSession("user", *user)
+	oc.Controller.Redirect("/", http.StatusFound)
+}
@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/astaxie/beego"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCRedirectLogin(t *testing.T) {
+	beego.Router("/c/oidc/login", &OIDCController{}, "get:RedirectLogin")
+	issuer, err := test.NewOIDCIssuer("harbor")
+	require.Nil(t, err)
+	defer issuer.Close()
+
+	dbAuthAdminsvr, err := test.NewAdminserver(map[string]interface{}{
+		common.AUTHMode: common.DBAuth,
+	})
+	require.Nil(t, err)
+	defer dbAuthAdminsvr.Close()
+	require.Nil(t, config.InitByURL(dbAuthAdminsvr.URL))
+	r, _ := http.NewRequest(http.MethodGet, "/c/oidc/login", nil)
+	w := httptest.NewRecorder()
+	beego.BeeApp.Handlers.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	oidcAuthAdminsvr, err := test.NewAdminserver(map[string]interface{}{
+		common.AUTHMode:     common.OIDCAuth,
+		common.OIDCEndpoint: issuer.URL,
+		common.OIDCClientID: "harbor",
+	})
+	require.Nil(t, err)
+	defer oidcAuthAdminsvr.Close()
+	require.Nil(t, config.InitByURL(oidcAuthAdminsvr.URL))
+	r, _ = http.NewRequest(http.MethodGet, "/c/oidc/login", nil)
+	w = httptest.NewRecorder()
+	beego.BeeApp.Handlers.ServeHTTP(w, r)
+	require.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(location.String(), issuer.URL+"/auth"))
+	assert.Equal(t, "harbor", location.Query().Get("client_id"))
+	assert.NotEmpty(t, location.Query().Get("state"))
+	assert.True(t, strings.HasSuffix(location.Query().Get("redirect_uri"), "/c/oidc/callback"))
+}
@@ -16,19 +16,23 @@ package filter
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"regexp"
 
 	beegoctx "github.com/astaxie/beego/context"
 	"github.com/docker/distribution/reference"
+	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	secstore "github.com/goharbor/harbor/src/common/secret"
 	"github.com/goharbor/harbor/src/common/security"
 	admr "github.com/goharbor/harbor/src/common/security/admiral"
 	"github.com/goharbor/harbor/src/common/security/admiral/authcontext"
 	"github.com/goharbor/harbor/src/common/security/local"
+	robotCtx "github.com/goharbor/harbor/src/common/security/robot"
 	"github.com/goharbor/harbor/src/common/security/secret"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/auth"
 	"github.com/goharbor/harbor/src/core/config"
@@ -95,6 +99,7 @@ func Init() {
 	// standalone
 	reqCtxModifiers = []ReqCtxModifier{
 		&secretReqCtxModifier{config.SecretStore},
+		&robotReqCtxModifier{},
 		&basicAuthReqCtxModifier{},
 		&sessionReqCtxModifier{},
 		&unauthorizedReqCtxModifier{}}
@@ -150,6 +155,53 @@ func (s *secretReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
 	return true
 }
 
+type robotReqCtxModifier struct{}
+
+// Modify authenticates the robot account whose username has the format
+// "robot$<project>+<name>" with the token in basic auth
+func (r *robotReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
+	username, token, ok := ctx.Request.BasicAuth()
+	if !ok {
+		return false
+	}
+	projectName, name, ok := models.ParseRobotUsername(username)
+	if !ok {
+		return false
+	}
+	log.Debug("got robot account information via basic auth")
+
+	log.Debug("using local database project manager")
+	pm := config.GlobalProjectMgr
+	project, err := pm.Get(projectName)
+	if err != nil {
+		log.Errorf("failed to get project %s: %v", projectName, err)
+		return false
+	}
+	if project == nil {
+		log.Errorf("failed to authenticate robot account %s", username)
+		return false
+	}
+	robot, err := dao.GetRobotByName(project.ProjectID, name)
+	if err != nil {
+		log.Errorf("failed to get robot account %s: %v", username, err)
+		return false
+	}
+	if robot == nil || subtle.ConstantTimeCompare([]byte(robot.Token),
+		[]byte(utils.Encrypt(token, robot.Salt))) != 1 {
+		log.Errorf("failed to authenticate robot account %s", username)
+		return false
+	}
+	if !robot.IsValid() {
+		log.Errorf("robot account %s is disabled or expired", username)
+		return false
+	}
+
+	log.Debug("creating robot security context...")
+	securCtx := robotCtx.NewSecurityContext(robot, pm)
+	setSecurCtxAndPM(ctx.Request, securCtx, pm)
+	return true
+}
+
 type basicAuthReqCtxModifier struct{}
 
 func (b *basicAuthReqCtxModifier) Modify(ctx *beegoctx.Context) bool {
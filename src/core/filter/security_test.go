@@ -33,7 +33,9 @@ import (
 	commonsecret "github.com/goharbor/harbor/src/common/secret"
 	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/common/security/local"
+	"github.com/goharbor/harbor/src/common/security/robot"
 	"github.com/goharbor/harbor/src/common/security/secret"
+	"github.com/goharbor/harbor/src/common/utils"
 	_ "github.com/goharbor/harbor/src/core/auth/db"
 	_ "github.com/goharbor/harbor/src/core/auth/ldap"
 	"github.com/goharbor/harbor/src/core/config"
@@ -146,6 +148,50 @@ func TestBasicAuthReqCtxModifier(t *testing.T) {
 	assert.NotNil(t, projectManager(ctx))
 }
 
+func TestRobotReqCtxModifier(t *testing.T) {
+	salt := utils.GenerateRandomString()
+	id, err := dao.AddRobot(&models.Robot{
+		Name:      "filter_test_robot",
+		ProjectID: 1,
+		Token:     utils.Encrypt("secret", salt),
+		Salt:      salt,
+		Access:    models.RobotActionPull,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create robot account: %v", err)
+	}
+	defer dao.DeleteRobot(id)
+
+	req, err := http.NewRequest(http.MethodGet,
+		"http://127.0.0.1/service/token", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", req)
+	}
+
+	// wrong token
+	req.SetBasicAuth("robot$library+filter_test_robot", "invalid")
+	ctx, err := newContext(req)
+	if err != nil {
+		t.Fatalf("failed to crate context: %v", err)
+	}
+	modifier := &robotReqCtxModifier{}
+	assert.False(t, modifier.Modify(ctx))
+
+	req.SetBasicAuth("robot$library+filter_test_robot", "secret")
+	ctx, err = newContext(req)
+	if err != nil {
+		t.Fatalf("failed to crate context: %v", err)
+	}
+	assert.True(t, modifier.Modify(ctx))
+
+	sc := securityContext(ctx)
+	assert.IsType(t, &robot.SecurityContext{}, sc)
+	s := sc.(security.Context)
+	assert.Equal(t, "robot$library+filter_test_robot", s.GetUsername())
+	assert.NotNil(t, projectManager(ctx))
+}
+
 func TestSessionReqCtxModifier(t *testing.T) {
 	user := models.User{
 		Username:     "admin",
@@ -0,0 +1,164 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package immutable
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// the rules are loaded from database by default, replaced in testing
+var listRules = dao.ListImmutableTagRules
+
+// Client is the part of registry client used to inspect the tags
+// of a repository, it is implemented by registry.Repository
+type Client interface {
+	ListTag() ([]string, error)
+	ManifestExist(reference string) (digest string, exist bool, err error)
+}
+
+// ViolationError is returned when the request would overwrite or delete an immutable tag
+type ViolationError struct {
+	Repository string
+	Tag        string
+	// "overwritten" or "deleted"
+	Action string
+}
+
+// Error ...
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("the tag %s:%s is immutable and cannot be %s",
+		e.Repository, e.Tag, e.Action)
+}
+
+// Match returns whether the tag is protected by any of the rules, the
+// repository is the full name which contains the project name
+func Match(rules []*models.ImmutableTagRule, repository, tag string) (bool, error) {
+	_, repo := utils.ParseRepository(repository)
+	for _, rule := range rules {
+		matched, err := utils.Match(rule.RepoPattern, repo)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			continue
+		}
+		matched, err = utils.Match(rule.TagPattern, tag)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsImmutable returns whether the tag is protected by the rules of the project
+func IsImmutable(projectID int64, repository, tag string) (bool, error) {
+	rules, err := listRules(projectID)
+	if err != nil {
+		return false, err
+	}
+	return Match(rules, repository, tag)
+}
+
+// CheckOverwrite returns ViolationError if the tag is immutable and pointing
+// to the manifest other than the digest, pushing the same manifest again is
+// allowed. An empty digest means the new manifest is unknown
+func CheckOverwrite(projectID int64, client Client, repository, tag, digest string) error {
+	immutable, err := IsImmutable(projectID, repository, tag)
+	if err != nil || !immutable {
+		return err
+	}
+	current, exist, err := client.ManifestExist(tag)
+	if err != nil {
+		return err
+	}
+	if !exist || (len(digest) > 0 && current == digest) {
+		return nil
+	}
+	return &ViolationError{
+		Repository: repository,
+		Tag:        tag,
+		Action:     "overwritten",
+	}
+}
+
+// CheckDeletion returns ViolationError if any of the tags is immutable. As
+// deleting a tag deletes the manifest, the tags sharing the same digest with
+// an immutable one cannot be deleted either
+func CheckDeletion(projectID int64, client Client, repository string, tags []string) error {
+	rules, err := listRules(projectID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	allTags, err := client.ListTag()
+	if err != nil {
+		return err
+	}
+
+	// digest -> immutable tag
+	protected := map[string]string{}
+	for _, tag := range allTags {
+		immutable, err := Match(rules, repository, tag)
+		if err != nil {
+			return err
+		}
+		if !immutable {
+			continue
+		}
+		for _, t := range tags {
+			if t == tag {
+				return &ViolationError{
+					Repository: repository,
+					Tag:        tag,
+					Action:     "deleted",
+				}
+			}
+		}
+		digest, exist, err := client.ManifestExist(tag)
+		if err != nil {
+			return err
+		}
+		if exist {
+			protected[digest] = tag
+		}
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+
+	for _, t := range tags {
+		digest, exist, err := client.ManifestExist(t)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		if tag, ok := protected[digest]; ok {
+			return &ViolationError{
+				Repository: repository,
+				Tag:        tag,
+				Action:     "deleted",
+			}
+		}
+	}
+	return nil
+}
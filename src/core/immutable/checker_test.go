@@ -0,0 +1,141 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package immutable
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	// tag -> digest
+	tags map[string]string
+}
+
+func (f *fakeClient) ListTag() ([]string, error) {
+	tags := []string{}
+	for tag := range f.tags {
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (f *fakeClient) ManifestExist(reference string) (string, bool, error) {
+	digest, exist := f.tags[reference]
+	return digest, exist, nil
+}
+
+func setRules(rules ...*models.ImmutableTagRule) func() {
+	original := listRules
+	listRules = func(projectID int64) ([]*models.ImmutableTagRule, error) {
+		return rules, nil
+	}
+	return func() {
+		listRules = original
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rules := []*models.ImmutableTagRule{
+		{
+			RepoPattern: "hello",
+			TagPattern:  "v*",
+		},
+		{
+			RepoPattern: "release/*",
+			TagPattern:  "*",
+		},
+	}
+	cases := []struct {
+		repository string
+		tag        string
+		matched    bool
+	}{
+		{"library/hello", "v1.0", true},
+		{"library/hello", "latest", false},
+		{"library/world", "v1.0", false},
+		{"library/release/app", "latest", true},
+		{"library/release/app/sub", "latest", false},
+	}
+	for _, c := range cases {
+		matched, err := Match(rules, c.repository, c.tag)
+		require.Nil(t, err)
+		assert.Equal(t, c.matched, matched, "%s:%s", c.repository, c.tag)
+	}
+}
+
+func TestCheckOverwrite(t *testing.T) {
+	defer setRules(&models.ImmutableTagRule{
+		RepoPattern: "*",
+		TagPattern:  "v*",
+	})()
+	client := &fakeClient{
+		tags: map[string]string{
+			"v1":     "sha256:1",
+			"latest": "sha256:1",
+		},
+	}
+
+	// not immutable
+	assert.Nil(t, CheckOverwrite(1, client, "library/hello", "latest", "sha256:2"))
+	// new tag
+	assert.Nil(t, CheckOverwrite(1, client, "library/hello", "v2", "sha256:2"))
+	// same manifest
+	assert.Nil(t, CheckOverwrite(1, client, "library/hello", "v1", "sha256:1"))
+	// overwrite
+	err := CheckOverwrite(1, client, "library/hello", "v1", "sha256:2")
+	e, ok := err.(*ViolationError)
+	require.True(t, ok)
+	assert.Equal(t, "v1", e.Tag)
+	// unknown manifest
+	_, ok = CheckOverwrite(1, client, "library/hello", "v1", "").(*ViolationError)
+	assert.True(t, ok)
+}
+
+func TestCheckDeletion(t *testing.T) {
+	client := &fakeClient{
+		tags: map[string]string{
+			"v1":     "sha256:1",
+			"latest": "sha256:1",
+			"dev":    "sha256:2",
+		},
+	}
+
+	// no rules
+	restore := setRules()
+	assert.Nil(t, CheckDeletion(1, client, "library/hello", []string{"v1"}))
+	restore()
+
+	defer setRules(&models.ImmutableTagRule{
+		RepoPattern: "*",
+		TagPattern:  "v*",
+	})()
+	assert.Nil(t, CheckDeletion(1, client, "library/hello", []string{"dev"}))
+
+	// immutable tag
+	err := CheckDeletion(1, client, "library/hello", []string{"dev", "v1"})
+	e, ok := err.(*ViolationError)
+	require.True(t, ok)
+	assert.Equal(t, "v1", e.Tag)
+
+	// sharing the digest with immutable tag
+	err = CheckDeletion(1, client, "library/hello", []string{"latest"})
+	e, ok = err.(*ViolationError)
+	require.True(t, ok)
+	assert.Equal(t, "v1", e.Tag)
+}
@@ -30,12 +30,15 @@ import (
 	"github.com/goharbor/harbor/src/core/api"
 	_ "github.com/goharbor/harbor/src/core/auth/db"
 	_ "github.com/goharbor/harbor/src/core/auth/ldap"
+	_ "github.com/goharbor/harbor/src/core/auth/oidc"
 	_ "github.com/goharbor/harbor/src/core/auth/uaa"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/filter"
 	"github.com/goharbor/harbor/src/core/notifier"
 	"github.com/goharbor/harbor/src/core/proxy"
+	"github.com/goharbor/harbor/src/core/quota"
 	"github.com/goharbor/harbor/src/core/service/token"
+	"github.com/goharbor/harbor/src/core/systeminfo"
 	"github.com/goharbor/harbor/src/replication/core"
 	_ "github.com/goharbor/harbor/src/replication/event"
 )
@@ -105,6 +108,8 @@ func main() {
 		log.Error(err)
 	}
 
+	systeminfo.Init()
+
 	// Init chart API handler。hlemchart
 	if err := api.Init(); err != nil {
 		log.Fatalf("Failed to initialize API handlers with error: %s", err.Error())
@@ -153,6 +158,11 @@ func main() {
 		log.Infof("Because SYNC_REGISTRY set false , no need to sync registry \n")
 	}
 
+	// calculate the usages missing in background rather than in the quota checks of pushes
+	if err := quota.SeedAll(); err != nil {
+		log.Errorf("failed to seed the usages of projects: %v", err)
+	}
+
 	log.Info("Init proxy")
 	// 前端代理初始化，用来对传入到后端的请求先进行一步处理。主要是针对 registry 的请求进行反向代理。
 	proxy.Init()
@@ -94,6 +94,59 @@ func TestMatchPullManifest(t *testing.T) {
 	assert.Equal("sha256:ca4626b691f57d16ce1576231e4a2e2135554d32e13a85dcff380d51fdd13f6a", tag7)
 }
 
+func TestMatchPushManifest(t *testing.T) {
+	assert := assert.New(t)
+	req1, _ := http.NewRequest("GET", "http://127.0.0.1:5000/v2/library/ubuntu/manifests/14.04", nil)
+	res1, _, _ := MatchPushManifest(req1)
+	assert.False(res1, "%s %v is not a request to push manifest", req1.Method, req1.URL)
+
+	req2, _ := http.NewRequest("PUT", "http://192.168.0.3:80/v2/library/ubuntu/manifests/14.04", nil)
+	res2, repo2, tag2 := MatchPushManifest(req2)
+	assert.True(res2, "%s %v is a request to push manifest", req2.Method, req2.URL)
+	assert.Equal("library/ubuntu", repo2)
+	assert.Equal("14.04", tag2)
+
+	req3, _ := http.NewRequest("PUT", "https://192.168.0.5/v2/library/ubuntu/blobs/uploads/uuid", nil)
+	res3, _, _ := MatchPushManifest(req3)
+	assert.False(res3, "%s %v is not a request to push manifest", req3.Method, req3.URL)
+}
+
+func TestMatchBlobUpload(t *testing.T) {
+	assert := assert.New(t)
+	req1, _ := http.NewRequest("GET", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/uuid", nil)
+	res1, _ := MatchBlobUpload(req1)
+	assert.False(res1, "%s %v is not a request to upload blob", req1.Method, req1.URL)
+
+	req2, _ := http.NewRequest("PATCH", "http://127.0.0.1:5000/v2/path1/golang/blobs/uploads/uuid", nil)
+	res2, repo2 := MatchBlobUpload(req2)
+	assert.True(res2, "%s %v is a request to upload blob", req2.Method, req2.URL)
+	assert.Equal("path1/golang", repo2)
+
+	req3, _ := http.NewRequest("POST", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/", nil)
+	res3, repo3 := MatchBlobUpload(req3)
+	assert.True(res3, "%s %v is a request to upload blob", req3.Method, req3.URL)
+	assert.Equal("library/ubuntu", repo3)
+
+	req4, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/v2/library/ubuntu/manifests/latest", nil)
+	res4, _ := MatchBlobUpload(req4)
+	assert.False(res4, "%s %v is not a request to upload blob", req4.Method, req4.URL)
+}
+
+func TestCommittedBlob(t *testing.T) {
+	assert := assert.New(t)
+	req1, _ := http.NewRequest("PATCH", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/uuid", nil)
+	assert.Equal("", committedBlob(req1))
+
+	req2, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/uuid?digest=sha256:abc", nil)
+	assert.Equal("sha256:abc", committedBlob(req2))
+
+	req3, _ := http.NewRequest("POST", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/?mount=sha256:abc&from=library/busybox", nil)
+	assert.Equal("sha256:abc", committedBlob(req3))
+
+	req4, _ := http.NewRequest("PUT", "http://127.0.0.1:5000/v2/library/ubuntu/blobs/uploads/uuid?mount=sha256:abc", nil)
+	assert.Equal("", committedBlob(req4))
+}
+
 func TestMatchListRepos(t *testing.T) {
 	assert := assert.New(t)
 	req1, _ := http.NewRequest("POST", "http://127.0.0.1:5000/v2/_catalog", nil)
@@ -8,12 +8,18 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/clair"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/notary"
+	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/immutable"
 	"github.com/goharbor/harbor/src/core/promgr"
+	"github.com/goharbor/harbor/src/core/quota"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/opencontainers/go-digest"
 
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -26,6 +32,7 @@ type contextKey string
 const (
 	manifestURLPattern = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)manifests/([\w][\w.:-]{0,127})`
 	catalogURLPattern  = `/v2/_catalog`
+	blobUploadPattern  = `^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/uploads/`
 	imageInfoCtxKey    = contextKey("ImageInfo")
 	// TODO: temp solution, remove after vmware/harbor#2242 is resolved.
 	tokenUsername = "harbor-core"
@@ -65,12 +72,41 @@ func MatchListRepos(req *http.Request) bool {
 	return false
 }
 
+// MatchPushManifest checks if the request looks like a request to push manifest. If it is returns the image and tag/sha256 digest as 2nd and 3rd return values
+func MatchPushManifest(req *http.Request) (bool, string, string) {
+	if req.Method != http.MethodPut {
+		return false, "", ""
+	}
+	re := regexp.MustCompile(manifestURLPattern)
+	s := re.FindStringSubmatch(req.URL.Path)
+	if len(s) == 3 {
+		s[1] = strings.TrimSuffix(s[1], "/")
+		return true, s[1], s[2]
+	}
+	return false, "", ""
+}
+
+// MatchBlobUpload checks if the request looks like a request to upload blob data. If it is returns the repository as 2nd return value
+func MatchBlobUpload(req *http.Request) (bool, string) {
+	if req.Method != http.MethodPost && req.Method != http.MethodPatch && req.Method != http.MethodPut {
+		return false, ""
+	}
+	re := regexp.MustCompile(blobUploadPattern)
+	s := re.FindStringSubmatch(req.URL.Path)
+	if len(s) == 2 {
+		return true, strings.TrimSuffix(s[1], "/")
+	}
+	return false, ""
+}
+
 // policyChecker checks the policy of a project by project name, to determine if it's needed to check the image's status under this project.
 type policyChecker interface {
 	// contentTrustEnabled returns whether a project has enabled content trust.
 	contentTrustEnabled(name string) bool
 	// vulnerablePolicy  returns whether a project has enabled vulnerable, and the project's severity.
 	vulnerablePolicy(name string) (bool, models.Severity)
+	// cveAllowlist returns the CVE IDs ignored by the vulnerability check of the project.
+	cveAllowlist(name string) (models.CVESet, error)
 }
 
 type pmsPolicyChecker struct {
@@ -94,6 +130,17 @@ func (pc pmsPolicyChecker) vulnerablePolicy(name string) (bool, models.Severity)
 	return project.VulPrevented(), clair.ParseClairSev(project.Severity())
 }
 
+func (pc pmsPolicyChecker) cveAllowlist(name string) (models.CVESet, error) {
+	project, err := pc.pm.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %s not found", name)
+	}
+	return coreutils.GetCVEAllowlist(project.ProjectID)
+}
+
 // newPMSPolicyChecker returns an instance of an pmsPolicyChecker
 func newPMSPolicyChecker(pm promgr.ProjectManager) policyChecker {
 	return &pmsPolicyChecker{
@@ -263,7 +310,7 @@ type vulnerableHandler struct {
 
 func (vh vulnerableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	imgRaw := req.Context().Value(imageInfoCtxKey)
-	if imgRaw == nil || !config.WithClair() {
+	if imgRaw == nil || !coreutils.ScanEnabled() {
 		vh.next.ServeHTTP(rw, req)
 		return
 	}
@@ -291,13 +338,241 @@ func (vh vulnerableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 	}
 	imageSev := overview.Sev
 	if imageSev >= int(projectVulnerableSeverity) {
-		log.Debugf("the image severity: %q is higher then project setting: %q, failing the response.", models.Severity(imageSev), projectVulnerableSeverity)
-		http.Error(rw, marshalError("PROJECT_POLICY_VIOLATION", fmt.Sprintf("The severity of vulnerability of the image: %q is equal or higher than the threshold in project setting: %q.", models.Severity(imageSev), projectVulnerableSeverity)), http.StatusPreconditionFailed)
-		return
+		// only the vulnerabilities not in the CVE allowlist can block the pulling
+		allowlist, err := getPolicyChecker().cveAllowlist(img.projectName)
+		if err != nil {
+			log.Errorf("failed to get the CVE allowlist of project %s: %v", img.projectName, err)
+			http.Error(rw, marshalError("PROJECT_POLICY_VIOLATION", "Failed to get the CVE allowlist."), http.StatusPreconditionFailed)
+			return
+		}
+		report, err := coreutils.GetVulnReport(overview)
+		if err != nil {
+			log.Errorf("failed to get the vulnerabilities of image %s:%s: %v", img.repository, img.reference, err)
+			http.Error(rw, marshalError("PROJECT_POLICY_VIOLATION", fmt.Sprintf("The severity of vulnerability of the image: %q is equal or higher than the threshold in project setting: %q.", models.Severity(imageSev), projectVulnerableSeverity)), http.StatusPreconditionFailed)
+			return
+		}
+		cves := report.Filter(allowlist).VulnIDs(projectVulnerableSeverity)
+		if len(cves) > 0 {
+			log.Debugf("the image has vulnerabilities: %v higher than project setting: %q, failing the response.", cves, projectVulnerableSeverity)
+			http.Error(rw, marshalError("PROJECT_POLICY_VIOLATION", fmt.Sprintf("The image has vulnerabilities equal or higher than the threshold in project setting: %q, CVEs not in the allowlist: %s.", projectVulnerableSeverity, strings.Join(cves, ", "))), http.StatusPreconditionFailed)
+			return
+		}
+		log.Debugf("all the vulnerabilities of image %s:%s higher than project setting are in the CVE allowlist", img.repository, img.reference)
 	}
 	vh.next.ServeHTTP(rw, req)
 }
 
+type quotaHandler struct {
+	next http.Handler
+}
+
+func (qh quotaHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if flag, repository := MatchBlobUpload(req); flag {
+		project, err := getProjectOfRepository(repository)
+		if err != nil {
+			log.Errorf("failed to get the project of repository %s: %v", repository, err)
+			http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+			return
+		}
+		if project != nil {
+			// fail fast if the size is known before uploading, the chunked uploads
+			// are checked when they are committed
+			if req.ContentLength > 0 {
+				if err = quota.CheckStorage(project, req.ContentLength); err != nil {
+					writeQuotaError(rw, err)
+					return
+				}
+			}
+			if digest := committedBlob(req); len(digest) > 0 {
+				qh.commitBlob(rw, req, project, repository, digest)
+				return
+			}
+		}
+	}
+
+	if flag, repository, reference := MatchPushManifest(req); flag {
+		project, err := getProjectOfRepository(repository)
+		if err != nil {
+			log.Errorf("failed to get the project of repository %s: %v", repository, err)
+			http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+			return
+		}
+		if project != nil {
+			qh.pushManifest(rw, req, project, repository, reference)
+			return
+		}
+	}
+	qh.next.ServeHTTP(rw, req)
+}
+
+// committedBlob returns the digest of the blob if the request completes the upload,
+// i.e. the final PUT of a chunked upload, a monolithic upload or a cross repository mount
+func committedBlob(req *http.Request) string {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return ""
+	}
+	query := req.URL.Query()
+	if digest := query.Get("digest"); len(digest) > 0 {
+		return digest
+	}
+	if req.Method == http.MethodPost {
+		return query.Get("mount")
+	}
+	return ""
+}
+
+// commitBlob forwards the request completing the upload and reserves the size of the
+// blob reported by registry, the blob is removed from the repository if the reservation fails
+func (qh quotaHandler) commitBlob(rw http.ResponseWriter, req *http.Request,
+	project *models.Project, repository, digest string) {
+	client, err := coreutils.NewRepositoryClientForUI(tokenUsername, repository)
+	if err != nil {
+		writeQuotaError(rw, err)
+		return
+	}
+	exist, err := client.BlobExist(digest)
+	if err != nil {
+		writeQuotaError(rw, err)
+		return
+	}
+	// the blob has been counted when it was linked to the repository
+	if exist {
+		qh.next.ServeHTTP(rw, req)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	qh.next.ServeHTTP(recorder, req)
+	if recorder.Result().StatusCode != http.StatusCreated {
+		copyResp(recorder, rw)
+		return
+	}
+
+	size, err := blobSize(client, project, digest)
+	if err == nil && size > 0 {
+		err = quota.Reserve(project, size, 0, 0)
+	}
+	if err != nil {
+		if e := client.DeleteBlob(digest); e != nil {
+			log.Errorf("failed to delete the blob %s from repository %s: %v", digest, repository, e)
+		}
+		writeQuotaError(rw, err)
+		return
+	}
+	copyResp(recorder, rw)
+}
+
+// blobSize returns the size of the blob that the project needs to hold, the blobs
+// referenced by other repositories of the project are counted only once
+func blobSize(client *registry.Repository, project *models.Project, digest string) (int64, error) {
+	shared, err := dao.ArtifactBlobExistsInProject(project.Name, digest)
+	if err != nil {
+		return 0, err
+	}
+	if shared {
+		return 0, nil
+	}
+	exist, size, err := client.StatBlob(digest)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, fmt.Errorf("blob %s not found in repository %s", digest, client.Name)
+	}
+	return size, nil
+}
+
+// pushManifest reserves the repository and tag created by pushing the manifest
+// and releases them if the pushing fails
+func (qh quotaHandler) pushManifest(rw http.ResponseWriter, req *http.Request,
+	project *models.Project, repository, reference string) {
+	repositories, tags, err := quota.ManifestRequest(repository, reference)
+	if err != nil {
+		writeQuotaError(rw, err)
+		return
+	}
+	if repositories == 0 && tags == 0 {
+		qh.next.ServeHTTP(rw, req)
+		return
+	}
+	if err = quota.Reserve(project, 0, repositories, tags); err != nil {
+		writeQuotaError(rw, err)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	qh.next.ServeHTTP(recorder, req)
+	if status := recorder.Result().StatusCode; status < 200 || status >= 300 {
+		if err = quota.Release(project, 0, repositories, tags); err != nil {
+			log.Errorf("failed to release the quota of project %s: %v", project.Name, err)
+		}
+	}
+	copyResp(recorder, rw)
+}
+
+func getProjectOfRepository(repository string) (*models.Project, error) {
+	components := strings.SplitN(repository, "/", 2)
+	return config.GlobalProjectMgr.Get(components[0])
+}
+
+func writeQuotaError(rw http.ResponseWriter, err error) {
+	if e, ok := err.(*quota.ExceededError); ok {
+		log.Debugf("the request is denied: %v", e)
+		http.Error(rw, marshalError("DENIED", e.Error()), http.StatusForbidden)
+		return
+	}
+	log.Errorf("failed to check the quota: %v", err)
+	http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+}
+
+type immutableHandler struct {
+	next http.Handler
+}
+
+func (ih immutableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	flag, repository, reference := MatchPushManifest(req)
+	if !flag || isDigest(reference) {
+		ih.next.ServeHTTP(rw, req)
+		return
+	}
+	project, err := getProjectOfRepository(repository)
+	if err != nil {
+		log.Errorf("failed to get the project of repository %s: %v", repository, err)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+		return
+	}
+	if project == nil {
+		ih.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// read the manifest to calculate the digest, pushing the same manifest again is allowed
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Errorf("failed to read the manifest of %s:%s: %v", repository, reference, err)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+		return
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	client, err := coreutils.NewRepositoryClientForUI(tokenUsername, repository)
+	if err == nil {
+		err = immutable.CheckOverwrite(project.ProjectID, client, repository, reference, digest.FromBytes(body).String())
+	}
+	if err != nil {
+		if e, ok := err.(*immutable.ViolationError); ok {
+			log.Debugf("the request is denied: %v", e)
+			http.Error(rw, marshalError("DENIED", e.Error()), http.StatusPreconditionFailed)
+			return
+		}
+		log.Errorf("failed to check the immutability of %s:%s: %v", repository, reference, err)
+		http.Error(rw, marshalError("DENIED", fmt.Sprintf("Failed due to internal Error: %v", err)), http.StatusInternalServerError)
+		return
+	}
+	ih.next.ServeHTTP(rw, req)
+}
+
 func matchNotaryDigest(img imageInfo) (bool, error) {
 	if NotaryEndpoint == "" {
 		NotaryEndpoint = config.InternalNotaryEndpoint()
@@ -41,7 +41,7 @@ func Init(urls ...string) error {
 	// 对指定 URL 设置反向代理，重定向路由
 	Proxy = httputil.NewSingleHostReverseProxy(targetURL)
 	// 将多个 handler 层次调用在一起
-	handlers = handlerChain{head: readonlyHandler{next: urlHandler{next: listReposHandler{next: contentTrustHandler{next: vulnerableHandler{next: Proxy}}}}}}
+	handlers = handlerChain{head: readonlyHandler{next: urlHandler{next: quotaHandler{next: immutableHandler{next: listReposHandler{next: contentTrustHandler{next: vulnerableHandler{next: Proxy}}}}}}}}
 	return nil
 }
 
@@ -0,0 +1,104 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// ExceededError is returned when the request would make the project go over the quota
+type ExceededError struct {
+	Resource string
+	Quota    int64
+	Usage    int64
+	Request  int64
+}
+
+// Error ...
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded: quota %d, used %d, requested %d",
+		e.Resource, e.Quota, e.Usage, e.Request)
+}
+
+// check returns ExceededError if the usage plus the requested
+// amount would go over the quota
+func check(resource string, quota, usage, request int64) error {
+	if quota == models.QuotaUnlimited {
+		return nil
+	}
+	if usage+request > quota {
+		return &ExceededError{
+			Resource: resource,
+			Quota:    quota,
+			Usage:    usage,
+			Request:  request,
+		}
+	}
+	return nil
+}
+
+// getUsage returns the usage of the project for the quota checks, nil is returned
+// without error if the usage is still being calculated, as the checks fail open
+// rather than blocking the pushes until the calculation is done
+func getUsage(project *models.Project) (*models.ProjectUsage, error) {
+	usage, err := GetUsage(project)
+	if err == ErrUsageNotReady {
+		log.Warningf("the usage of project %s is not ready, skip checking the quota", project.Name)
+		return nil, nil
+	}
+	return usage, err
+}
+
+// CheckStorage checks whether the project can store another size bytes
+func CheckStorage(project *models.Project, size int64) error {
+	quota := project.Quota()
+	if quota.Storage == models.QuotaUnlimited {
+		return nil
+	}
+	usage, err := getUsage(project)
+	if err != nil || usage == nil {
+		return err
+	}
+	return check("storage", quota.Storage, usage.Storage, size)
+}
+
+// CheckRepository checks whether a new repository can be created under the project
+func CheckRepository(project *models.Project) error {
+	quota := project.Quota()
+	if quota.RepositoryCount == models.QuotaUnlimited {
+		return nil
+	}
+	usage, err := getUsage(project)
+	if err != nil || usage == nil {
+		return err
+	}
+	return check("repository", quota.RepositoryCount, usage.RepositoryCount, 1)
+}
+
+// CheckTag checks whether a new tag can be created under the project
+func CheckTag(project *models.Project) error {
+	quota := project.Quota()
+	if quota.TagCount == models.QuotaUnlimited {
+		return nil
+	}
+	usage, err := getUsage(project)
+	if err != nil || usage == nil {
+		return err
+	}
+	return check("tag", quota.TagCount, usage.TagCount, 1)
+}
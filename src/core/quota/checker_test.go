@@ -0,0 +1,40 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	assert.Nil(t, check("storage", models.QuotaUnlimited, 100, 100))
+	assert.Nil(t, check("storage", 200, 100, 100))
+
+	err := check("repository", 1, 1, 1)
+	e, ok := err.(*ExceededError)
+	assert.True(t, ok)
+	assert.Equal(t, "repository", e.Resource)
+	assert.Equal(t, int64(1), e.Quota)
+}
+
+func TestCheckUnlimited(t *testing.T) {
+	project := &models.Project{}
+	assert.Nil(t, CheckStorage(project, 1024))
+	assert.Nil(t, CheckRepository(project))
+	assert.Nil(t, CheckTag(project))
+}
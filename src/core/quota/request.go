@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+// ManifestRequest returns the count of the repositories and tags created
+// by pushing a manifest to the repository with the reference
+func ManifestRequest(repository, reference string) (int64, int64, error) {
+	var tags int64
+	if !isDigest(reference) {
+		tags = 1
+	}
+	if !dao.RepositoryExists(repository) {
+		return 1, tags, nil
+	}
+	if tags == 0 {
+		return 0, 0, nil
+	}
+
+	client, err := coreutils.NewRepositoryClientForUI(username, repository)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, exist, err := client.ManifestExist(reference)
+	if err != nil {
+		return 0, 0, err
+	}
+	if exist {
+		return 0, 0, nil
+	}
+	return 0, 1, nil
+}
+
+// ImageStorageRequest returns the size of the blobs referenced by the image which
+// are not held by the project yet, it's the storage the project needs when the
+// image is copied into it from another project
+func ImageStorageRequest(project *models.Project, repository, reference string) (int64, error) {
+	client, err := coreutils.NewRepositoryClientForUI(username, repository)
+	if err != nil {
+		return 0, err
+	}
+	blobs := map[string]int64{}
+	if err = collectBlobs(client, reference, map[string]struct{}{}, blobs); err != nil {
+		return 0, err
+	}
+	var storage int64
+	for digest, size := range blobs {
+		exist, err := dao.ArtifactBlobExistsInProject(project.Name, digest)
+		if err != nil {
+			return 0, err
+		}
+		if !exist {
+			storage += size
+		}
+	}
+	return storage, nil
+}
+
+func isDigest(reference string) bool {
+	return strings.HasPrefix(reference, "sha256:") && len(reference) == 71
+}
@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// the times to retry the reservation when it fails but no quota is exceeded
+// as the usage is decreased by others at the same time
+const reserveRetries = 3
+
+// Reserve checks whether the project can hold another storage bytes, repositories
+// and tags and adds them to the usage if it can. The check and the update are done
+// in one conditional update of the database so that the concurrent pushes, even
+// handled by different core instances, can't go over the quota together. The
+// resources are added to the usage even the quota is unlimited to keep the usage
+// up to date. Nothing is reserved if the usage of the project is still being calculated
+func Reserve(project *models.Project, storage, repositories, tags int64) error {
+	usage, err := getUsage(project)
+	if err != nil || usage == nil {
+		return err
+	}
+	quota := project.Quota()
+	for i := 0; i < reserveRetries; i++ {
+		reserved, err := dao.ReserveProjectUsage(project.ProjectID, storage, repositories, tags, quota)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return nil
+		}
+
+		// find out the exceeded resource
+		usage, err = dao.GetProjectUsage(project.ProjectID)
+		if err != nil {
+			return err
+		}
+		if usage == nil {
+			return fmt.Errorf("usage of project %s not found", project.Name)
+		}
+		if storage > 0 {
+			if err = check("storage", quota.Storage, usage.Storage, storage); err != nil {
+				return err
+			}
+		}
+		if repositories > 0 {
+			if err = check("repository", quota.RepositoryCount, usage.RepositoryCount, repositories); err != nil {
+				return err
+			}
+		}
+		if tags > 0 {
+			if err = check("tag", quota.TagCount, usage.TagCount, tags); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("failed to reserve the quota of project %s", project.Name)
+}
+
+// Release subtracts the storage bytes, repositories and tags from the usage of the
+// project, nothing is changed if the usage of the project has not been calculated
+func Release(project *models.Project, storage, repositories, tags int64) error {
+	return dao.IncreaseProjectUsage(project.ProjectID, -storage, -repositories, -tags)
+}
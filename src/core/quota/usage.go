@@ -0,0 +1,209 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	coreutils "github.com/goharbor/harbor/src/core/utils"
+)
+
+const username = "harbor-core"
+
+// ErrUsageNotReady is returned when the usage of the project has not been
+// calculated yet, the calculation is triggered in background then
+var ErrUsageNotReady = errors.New("the usage of the project is being calculated")
+
+// the IDs of the projects whose usages are being calculated in background
+var calculating sync.Map
+
+// Calculate walks through the manifests of all tags under the project in registry storage
+// and calculates the usage, the blobs shared by different images are counted only once.
+// The storage only includes the layers and configs referenced by the manifests, which
+// is the same as the one accumulated by Reserve when the blobs are uploaded
+func Calculate(project *models.Project) (*models.ProjectUsage, error) {
+	repositories, err := dao.GetRepositories(&models.RepositoryQuery{
+		ProjectIDs: []int64{project.ProjectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.ProjectUsage{
+		ProjectID: project.ProjectID,
+	}
+	manifests := map[string]struct{}{}
+	blobs := map[string]int64{}
+	for _, repository := range repositories {
+		client, err := coreutils.NewRepositoryClientForUI(username, repository.Name)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := client.ListTag()
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		usage.RepositoryCount++
+		usage.TagCount += int64(len(tags))
+
+		for _, tag := range tags {
+			if err = collectBlobs(client, tag, manifests, blobs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, size := range blobs {
+		usage.Storage += size
+	}
+	usage.UpdateTime = time.Now()
+	return usage, nil
+}
+
+// collect the sizes of the blobs referenced by the manifest
+func collectBlobs(client *registry.Repository, tag string, manifests map[string]struct{}, blobs map[string]int64) error {
+	digest, mediaType, payload, err := client.PullManifest(tag,
+		[]string{schema1.MediaTypeManifest, schema2.MediaTypeManifest})
+	if err != nil {
+		return err
+	}
+	if _, exist := manifests[digest]; exist {
+		return nil
+	}
+	manifests[digest] = struct{}{}
+
+	if strings.Contains(mediaType, "application/json") {
+		mediaType = schema1.MediaTypeManifest
+	}
+	manifest, _, err := registry.UnMarshal(mediaType, payload)
+	if err != nil {
+		return err
+	}
+
+	for _, reference := range manifest.References() {
+		d := reference.Digest.String()
+		if _, exist := blobs[d]; exist {
+			continue
+		}
+		size := reference.Size
+		// the manifest of schema1 doesn't contain the sizes of layers
+		if size == 0 && mediaType == schema1.MediaTypeManifest {
+			s, data, err := client.PullBlob(d)
+			if err != nil {
+				return err
+			}
+			data.Close()
+			size = s
+		}
+		blobs[d] = size
+	}
+	return nil
+}
+
+// Refresh recalculates the usage of the project and persists it
+func Refresh(project *models.Project) (*models.ProjectUsage, error) {
+	usage, err := Calculate(project)
+	if err != nil {
+		return nil, err
+	}
+	if err = dao.SetProjectUsage(usage); err != nil {
+		return nil, err
+	}
+	log.Debugf("usage of project %s refreshed: %+v", project.Name, usage)
+	return usage, nil
+}
+
+// GetUsage returns the cached usage of the project. The usage is never calculated
+// inline as it walks through all the manifests of the project, the calculation is
+// triggered in background and ErrUsageNotReady is returned if it doesn't exist
+func GetUsage(project *models.Project) (*models.ProjectUsage, error) {
+	usage, err := dao.GetProjectUsage(project.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if usage != nil {
+		return usage, nil
+	}
+	go refreshMissing(project)
+	return nil, ErrUsageNotReady
+}
+
+// refreshMissing calculates the usage of the project if it still doesn't exist,
+// it does nothing if the calculation of the project is already in progress
+func refreshMissing(project *models.Project) {
+	if _, loaded := calculating.LoadOrStore(project.ProjectID, struct{}{}); loaded {
+		return
+	}
+	defer calculating.Delete(project.ProjectID)
+
+	usage, err := dao.GetProjectUsage(project.ProjectID)
+	if err != nil {
+		log.Errorf("failed to get the usage of project %s: %v", project.Name, err)
+		return
+	}
+	if usage != nil {
+		return
+	}
+	if _, err = Refresh(project); err != nil {
+		log.Errorf("failed to calculate the usage of project %s: %v", project.Name, err)
+	}
+}
+
+// SeedAll calculates in background the usages of the projects which have not
+// been calculated, e.g. the projects created before the quota was introduced.
+// It's called at startup so that the quota checks find the usages ready
+func SeedAll() error {
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for _, project := range projects {
+			refreshMissing(project)
+		}
+		log.Infof("usages of %d projects seeded", len(projects))
+	}()
+	return nil
+}
+
+// RefreshAll recalculates the usages of all projects in background, the
+// stale usages are still served until they are replaced by the new ones
+func RefreshAll() error {
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for _, project := range projects {
+			if _, err := Refresh(project); err != nil {
+				log.Errorf("failed to refresh the usage of project %s: %v", project.Name, err)
+			}
+		}
+		log.Infof("usages of %d projects refreshed", len(projects))
+	}()
+	return nil
+}
@@ -16,6 +16,7 @@ package main
 
 import (
 	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/auth/oidc"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/controllers"
 	"github.com/goharbor/harbor/src/core/service/notifications/admin"
@@ -42,6 +43,8 @@ func initRouters() {
 		beego.Router("/c/userExists", &controllers.CommonController{}, "post:UserExists")
 		// 用来发送邮件的 api
 		beego.Router("/c/sendEmail", &controllers.CommonController{}, "get:SendResetEmail")
+		beego.Router("/c/oidc/login", &controllers.OIDCController{}, "get:RedirectLogin")
+		beego.Router(oidc.CallbackPath, &controllers.OIDCController{}, "get:Callback")
 
 		// API:
 		// 给具体某个项目增加成员，对于项目的控制很有用。
@@ -55,6 +58,7 @@ func initRouters() {
 		beego.Router("/api/users", &api.UserAPI{}, "get:List;post:Post")
 		beego.Router("/api/users/:id([0-9]+)/password", &api.UserAPI{}, "put:ChangePassword")
 		beego.Router("/api/users/:id/sysadmin", &api.UserAPI{}, "put:ToggleUserAdminRole")
+		beego.Router("/api/users/:id/cli_secret", &api.UserAPI{}, "get:GetCLISecret;put:SetCLISecret")
 		beego.Router("/api/usergroups/?:ugid([0-9]+)", &api.UserGroupAPI{})
 		beego.Router("/api/ldap/ping", &api.LdapAPI{}, "post:Ping")
 		beego.Router("/api/ldap/users/search", &api.LdapAPI{}, "get:Search")
@@ -71,6 +75,20 @@ func initRouters() {
 	// 用来获取具体项目的日志信息
 	beego.Router("/api/projects/:id([0-9]+)/logs", &api.ProjectAPI{}, "get:Logs")
 	beego.Router("/api/projects/:id([0-9]+)/_deletable", &api.ProjectAPI{}, "get:Deletable")
+	beego.Router("/api/projects/:id([0-9]+)/summary", &api.ProjectAPI{}, "get:Summary")
+	beego.Router("/api/projects/:pid([0-9]+)/robots", &api.RobotAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/:id([0-9]+)", &api.RobotAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/immutabletagrules", &api.ImmutableTagRuleAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/immutabletagrules/:id([0-9]+)", &api.ImmutableTagRuleAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/cve_allowlist", &api.CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies", &api.WebhookPolicyAPI{}, "post:Post;get:List")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies/:id([0-9]+)", &api.WebhookPolicyAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/projects/:pid([0-9]+)/webhook/policies/:id([0-9]+)/executions", &api.WebhookPolicyAPI{}, "get:ListExecutions")
+	beego.Router("/api/projects/:id([0-9]+)/retention", &api.RetentionAPI{}, "get:Get;put:Put")
+	beego.Router("/api/projects/:id([0-9]+)/retention/candidates", &api.RetentionAPI{}, "get:Candidates")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions", &api.RetentionAPI{}, "post:Execute;get:ListExecutions")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions/:eid([0-9]+)", &api.RetentionAPI{}, "get:GetExecution")
+	beego.Router("/api/projects/:id([0-9]+)/retention/executions/:eid([0-9]+)/log", &api.RetentionAPI{}, "get:GetExecutionLog")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/?:name", &api.MetadataAPI{}, "get:Get")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/", &api.MetadataAPI{}, "post:Post")
 	beego.Router("/api/projects/:id([0-9]+)/metadatas/:name", &api.MetadataAPI{}, "put:Put;delete:Delete")
@@ -103,6 +121,10 @@ func initRouters() {
 	beego.Router("/api/system/gc/:id", &api.GCAPI{}, "get:GetGC")
 	beego.Router("/api/system/gc/:id([0-9]+)/log", &api.GCAPI{}, "get:GetLog")
 	beego.Router("/api/system/gc/schedule", &api.GCAPI{}, "get:Get;put:Put;post:Post")
+	beego.Router("/api/system/cve_allowlist", &api.CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/scanners", &api.ScannerAPI{}, "post:Post;get:List")
+	beego.Router("/api/scanners/:id([0-9]+)", &api.ScannerAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/scanners/:id([0-9]+)/metadata", &api.ScannerAPI{}, "get:Metadata")
 
 	beego.Router("/api/policies/replication/:id([0-9]+)", &api.RepPolicyAPI{})
 	beego.Router("/api/policies/replication", &api.RepPolicyAPI{}, "get:List")
@@ -117,6 +139,9 @@ func initRouters() {
 	beego.Router("/api/configs", &api.ConfigAPI{}, "get:GetInternalConfig")
 	beego.Router("/api/configurations", &api.ConfigAPI{})
 	beego.Router("/api/configurations/reset", &api.ConfigAPI{}, "post:Reset")
+	beego.Router("/api/configurations/revisions", &api.ConfigAPI{}, "get:ListRevisions")
+	beego.Router("/api/configurations/revisions/:id([0-9]+)", &api.ConfigAPI{}, "get:GetRevision")
+	beego.Router("/api/configurations/revisions/:id([0-9]+)/rollback", &api.ConfigAPI{}, "post:Rollback")
 	beego.Router("/api/statistics", &api.StatisticAPI{})
 	beego.Router("/api/replications", &api.ReplicationAPI{})
 	beego.Router("/api/labels", &api.LabelAPI{}, "post:Post;get:List")
@@ -132,6 +157,8 @@ func initRouters() {
 	// 重新命名
 	beego.Router("/api/internal/renameadmin", &api.InternalAPI{}, "post:RenameAdmin")
 	beego.Router("/api/internal/configurations", &api.ConfigAPI{}, "get:GetInternalConfig")
+	beego.Router("/api/internal/gc/orphans/purge", &api.InternalGCAPI{}, "post:PurgeOrphans")
+	beego.Router("/api/internal/gc/usages/refresh", &api.InternalGCAPI{}, "post:RefreshUsages")
 
 	// external service that hosted on harbor process:
 	// /service/notifications 用于镜像上传时的通知服务
@@ -141,6 +168,9 @@ func initRouters() {
 	// jobservice 中的 hook_url 进行访问，用来更新数据库中 job 的状态信息
 	beego.Router("/service/notifications/jobs/scan/:id([0-9]+)", &jobs.Handler{}, "post:HandleScan")
 	beego.Router("/service/notifications/jobs/replication/:id([0-9]+)", &jobs.Handler{}, "post:HandleReplication")
+	beego.Router("/service/notifications/jobs/retention/execution/:id([0-9]+)", &jobs.Handler{}, "post:HandleRetentionExecution")
+	beego.Router("/service/notifications/jobs/retention/policy/:id([0-9]+)", &jobs.Handler{}, "post:HandleRetentionPolicy")
+	beego.Router("/service/notifications/jobs/webhook/:id([0-9]+)", &jobs.Handler{}, "post:HandleWebhook")
 	// 用来更新数据库中 job service 的工作状态 ，主要涉及到 admin job 表
 	beego.Router("/service/notifications/jobs/adminjob/:id([0-9]+)", &admin.Handler{}, "post:HandleAdminJob")
 	// 获取 token 信息
@@ -153,6 +183,8 @@ func initRouters() {
 		// Charts are controlled under projects
 		chartRepositoryAPIType := &api.ChartRepositoryAPI{}
 		beego.Router("/api/chartrepo/health", chartRepositoryAPIType, "get:GetHealthStatus")
+		beego.Router("/api/chartrepo/search", chartRepositoryAPIType, "get:QueryCharts")
+		beego.Router("/api/chartrepo/top", chartRepositoryAPIType, "get:GetTopCharts")
 		beego.Router("/api/chartrepo/:repo/charts", chartRepositoryAPIType, "get:ListCharts")
 		beego.Router("/api/chartrepo/:repo/charts/:name", chartRepositoryAPIType, "get:ListChartVersions")
 		beego.Router("/api/chartrepo/:repo/charts/:name", chartRepositoryAPIType, "delete:DeleteChart")
@@ -93,6 +93,10 @@ func (h *Handler) Handle() {
 				return
 			}
 			for _, e := range l {
+				// the images scanned by the registered scanners have no details key in Clair
+				if len(e.DetailsKey) == 0 {
+					continue
+				}
 				// 更新img_scan_overview中的记录。根据镜像中的 layer 来查询漏洞，e.DetailsKey 为layername
 				if err := clair.UpdateScanOverview(e.Digest, e.DetailsKey, config.ClairEndpoint()); err != nil {
 					log.Errorf("Failed to refresh scan overview for image: %s", e.Digest)
@@ -21,8 +21,11 @@ import (
 	"github.com/goharbor/harbor/src/common/job"
 	jobmodels "github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/api"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/webhook"
 )
 
 var statusMap = map[string]string{
@@ -38,8 +41,10 @@ var statusMap = map[string]string{
 // Handler handles reqeust on /service/notifications/jobs/*, which listens to the webhook of jobservice.
 type Handler struct {
 	api.BaseController
-	id     int64
-	status string
+	id      int64
+	status  string
+	jobID   string
+	checkIn string
 }
 
 // Prepare ...
@@ -70,6 +75,8 @@ func (h *Handler) Prepare() {
 	}
 	// 上述操作的主要目的是获取状态值，将其从 pending 状态更新为 running
 	h.status = status
+	h.jobID = data.JobID
+	h.checkIn = data.CheckIn
 }
 
 // HandleScan handles the webhook of scan job
@@ -82,6 +89,9 @@ func (h *Handler) HandleScan() {
 		h.HandleInternalServerError(err.Error())
 		return
 	}
+	if h.status == models.JobFinished || h.status == models.JobError {
+		go notifyScan(h.id, h.status)
+	}
 }
 
 // HandleReplication handles the webhook of replication job
@@ -93,4 +103,168 @@ func (h *Handler) HandleReplication() {
 		h.HandleInternalServerError(err.Error())
 		return
 	}
+	if h.status == models.JobFinished || h.status == models.JobError {
+		go notifyReplication(h.id, h.status)
+	}
+}
+
+// HandleRetentionExecution handles the webhook of the manually triggered retention job
+func (h *Handler) HandleRetentionExecution() {
+	log.Debugf("received retention job status update event: execution-%d, status-%s", h.id, h.status)
+	execution, err := dao.GetRetentionExecution(h.id)
+	if err != nil {
+		log.Errorf("Failed to get retention execution %d: %v", h.id, err)
+		h.HandleInternalServerError(err.Error())
+		return
+	}
+	if execution == nil {
+		log.Warningf("retention execution %d not found, drop the status update event", h.id)
+		return
+	}
+	h.updateRetentionExecution(execution)
+}
+
+// HandleRetentionPolicy handles the webhook of the scheduled retention job, an
+// execution is recorded for every run of the periodic job
+func (h *Handler) HandleRetentionPolicy() {
+	log.Debugf("received retention job status update event: policy-%d, job-%s, status-%s", h.id, h.jobID, h.status)
+	policy, err := dao.GetRetentionPolicy(h.id)
+	if err != nil {
+		log.Errorf("Failed to get retention policy %d: %v", h.id, err)
+		h.HandleInternalServerError(err.Error())
+		return
+	}
+	// the status of the periodic job itself is ignored
+	if policy == nil || len(h.jobID) == 0 || h.jobID == policy.UUID {
+		return
+	}
+	execution, err := dao.GetRetentionExecutionByUUID(h.jobID)
+	if err != nil {
+		log.Errorf("Failed to get retention execution of job %s: %v", h.jobID, err)
+		h.HandleInternalServerError(err.Error())
+		return
+	}
+	if execution == nil {
+		execution = &models.RetentionExecution{
+			PolicyID: policy.ID,
+			UUID:     h.jobID,
+			Status:   h.status,
+		}
+		id, err := dao.AddRetentionExecution(execution)
+		if err != nil {
+			log.Errorf("Failed to create retention execution of job %s: %v", h.jobID, err)
+			h.HandleInternalServerError(err.Error())
+			return
+		}
+		execution.ID = id
+	}
+	h.updateRetentionExecution(execution)
+}
+
+func (h *Handler) updateRetentionExecution(execution *models.RetentionExecution) {
+	execution.Status = h.status
+	cols := []string{"Status"}
+	// the report is sent by the job via check in
+	if len(h.checkIn) > 0 {
+		execution.Report = h.checkIn
+		cols = append(cols, "Report")
+	}
+	if err := dao.UpdateRetentionExecution(execution, cols...); err != nil {
+		log.Errorf("Failed to update retention execution %d, status: %s: %v", execution.ID, h.status, err)
+		h.HandleInternalServerError(err.Error())
+		return
+	}
+}
+
+// HandleWebhook handles the webhook of webhook delivery job
+func (h *Handler) HandleWebhook() {
+	log.Debugf("received webhook job status update event: execution-%d, status-%s", h.id, h.status)
+	execution := &models.WebhookExecution{
+		ID:     h.id,
+		Status: h.status,
+	}
+	if err := dao.UpdateWebhookExecution(execution, "Status"); err != nil {
+		log.Errorf("Failed to update webhook execution %d, status: %s: %v", h.id, h.status, err)
+		h.HandleInternalServerError(err.Error())
+		return
+	}
+}
+
+// notifyScan sends the scanning event to the webhook policies of the project
+func notifyScan(id int64, status string) {
+	job, err := dao.GetScanJob(id)
+	if err != nil || job == nil {
+		log.Errorf("Failed to get scan job %d for webhook: %v", id, err)
+		return
+	}
+	projectID, err := getProjectID(job.Repository)
+	if err != nil {
+		log.Errorf("Failed to get the project of repository %s for webhook: %v", job.Repository, err)
+		return
+	}
+	if projectID == 0 {
+		return
+	}
+
+	event := webhook.Event{
+		Type:       models.WebhookEventScanningFailed,
+		ProjectID:  projectID,
+		Repository: job.Repository,
+		Tags: map[string]string{
+			job.Tag: job.Digest,
+		},
+	}
+	if status == models.JobFinished {
+		event.Type = models.WebhookEventScanningCompleted
+		overview, err := dao.GetImgScanOverview(job.Digest)
+		if err != nil {
+			log.Errorf("Failed to get the scan overview of %s for webhook: %v", job.Digest, err)
+		} else if overview != nil {
+			event.CustomAttributes = map[string]string{
+				"severity": models.Severity(overview.Sev).String(),
+			}
+		}
+	}
+	webhook.Notify(event)
+}
+
+// notifyReplication sends the replication event to the webhook policies of the project
+func notifyReplication(id int64, status string) {
+	job, err := dao.GetRepJob(id)
+	if err != nil || job == nil {
+		log.Errorf("Failed to get replication job %d for webhook: %v", id, err)
+		return
+	}
+	policy, err := dao.GetRepPolicy(job.PolicyID)
+	if err != nil || policy == nil {
+		log.Errorf("Failed to get replication policy %d for webhook: %v", job.PolicyID, err)
+		return
+	}
+
+	event := webhook.Event{
+		Type:       models.WebhookEventReplicationFailed,
+		ProjectID:  policy.ProjectID,
+		Repository: job.Repository,
+		Tags:       map[string]string{},
+		CustomAttributes: map[string]string{
+			"policy":    policy.Name,
+			"operation": job.Operation,
+		},
+	}
+	if status == models.JobFinished {
+		event.Type = models.WebhookEventReplicationCompleted
+	}
+	for _, tag := range job.TagList {
+		event.Tags[tag] = ""
+	}
+	webhook.Notify(event)
+}
+
+func getProjectID(repository string) (int64, error) {
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil || project == nil {
+		return 0, err
+	}
+	return project.ProjectID, nil
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/notifier"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/goharbor/harbor/src/core/webhook"
 	rep_notification "github.com/goharbor/harbor/src/replication/event/notification"
 	"github.com/goharbor/harbor/src/replication/event/topic"
 )
@@ -121,6 +122,22 @@ func (n *NotificationHandler) Post() {
 				return
 			}
 
+			go func() {
+				if err := coreutils.RecordArtifactBlobs(repository, tag); err != nil {
+					log.Errorf("failed to record the blobs of image %s:%s: %v", repository, tag, err)
+				}
+			}()
+
+			webhook.Notify(webhook.Event{
+				Type:       models.WebhookEventPushImage,
+				ProjectID:  pro.ProjectID,
+				Operator:   user,
+				Repository: repository,
+				Tags: map[string]string{
+					tag: event.Target.Digest,
+				},
+			})
+
 			// 当完成完成镜像manifest 的检查之后，发布消息
 			go func() {
 				image := repository + ":" + tag
@@ -148,6 +165,15 @@ func (n *NotificationHandler) Post() {
 			}
 		}
 		if action == "pull" {
+			webhook.Notify(webhook.Event{
+				Type:       models.WebhookEventPullImage,
+				ProjectID:  pro.ProjectID,
+				Operator:   user,
+				Repository: repository,
+				Tags: map[string]string{
+					tag: event.Target.Digest,
+				},
+			})
 			go func() {
 				log.Debugf("Increase the repository %s pull count.", repository)
 				if err := dao.IncreasePullCount(repository); err != nil {
@@ -202,8 +228,8 @@ func checkEvent(event *models.Event) bool {
 }
 
 func autoScanEnabled(project *models.Project) bool {
-	if !config.WithClair() {
-		log.Debugf("Auto Scan disabled because Harbor is not deployed with Clair")
+	if !coreutils.ScanEnabled() {
+		log.Debugf("Auto Scan disabled because Harbor is not deployed with Clair and no scanner is registered")
 		return false
 	}
 
@@ -188,12 +188,16 @@ func (rep repositoryFilter) filter(ctx security.Context, pm promgr.ProjectManage
 	}
 
 	// 检查这个用户对这个项目具有什么样的权限。因为在之前的处理中，ctx 中包含了用户和项目管理器的信息。
+	// 读写权限分别检查，robot 账号可以只被授予 push 或 pull 权限
 	if ctx.HasAllPerm(project) {
 		permission = "RWM"
-	} else if ctx.HasWritePerm(project) {
-		permission = "RW"
-	} else if ctx.HasReadPerm(project) {
-		permission = "R"
+	} else {
+		if ctx.HasWritePerm(project) {
+			permission += "W"
+		}
+		if ctx.HasReadPerm(project) {
+			permission += "R"
+		}
 	}
 
 	// 将 pull/push 权限 授权给用户,根据上述的 RWM 给用户分配 pull/push 权限。
@@ -0,0 +1,113 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagestorage
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// cachedDriver caches the capacity returned by the driver which measures the storage
+// slowly, e.g. listing all the objects in the bucket of the object storage. The capacity
+// is measured in background and refreshed once it's older than the lifespan, the cached
+// one is returned meanwhile. The requests never wait for the measuring, the capacity
+// returned by the fallback driver is served before the first measuring is done and is
+// cached instead when the measuring fails, e.g. the storage can't be reached
+type cachedDriver struct {
+	Driver
+	fallback Driver
+	lifespan time.Duration
+	// only one measuring runs at the same time
+	refreshing int32
+	lock       sync.RWMutex
+	capacity   *Capacity
+	updatedAt  time.Time
+}
+
+// NewCachedDriver returns a driver caching the capacity returned by the driver for the
+// lifespan, the fallback driver is used when the capacity isn't measured successfully
+func NewCachedDriver(driver, fallback Driver, lifespan time.Duration) Driver {
+	return &cachedDriver{
+		Driver:   driver,
+		fallback: fallback,
+		lifespan: lifespan,
+	}
+}
+
+// Refresh starts measuring the capacity in background if the driver caches it,
+// it does nothing for the other drivers
+func Refresh(driver Driver) {
+	if d, ok := driver.(*cachedDriver); ok {
+		d.refresh()
+	}
+}
+
+// Cap returns the cached capacity of the image storage
+func (d *cachedDriver) Cap() (*Capacity, error) {
+	capacity, fresh := d.cached()
+	if !fresh {
+		d.refresh()
+	}
+	if capacity == nil {
+		return d.fallback.Cap()
+	}
+	return capacity, nil
+}
+
+// cached returns a copy of the cached capacity and whether it's within the lifespan
+func (d *cachedDriver) cached() (*Capacity, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.capacity == nil {
+		return nil, false
+	}
+	capacity := *d.capacity
+	return &capacity, time.Since(d.updatedAt) <= d.lifespan
+}
+
+// refresh measures the capacity in background unless it's being measured
+func (d *cachedDriver) refresh() {
+	if !atomic.CompareAndSwapInt32(&d.refreshing, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&d.refreshing, 0)
+		// measured by others just before
+		if _, fresh := d.cached(); fresh {
+			return
+		}
+		capacity, err := d.measure()
+		if err != nil {
+			log.Errorf("failed to refresh the capacity of %s storage: %v", d.Name(), err)
+			return
+		}
+		d.lock.Lock()
+		d.capacity = capacity
+		d.updatedAt = time.Now()
+		d.lock.Unlock()
+	}()
+}
+
+func (d *cachedDriver) measure() (*Capacity, error) {
+	capacity, err := d.Driver.Cap()
+	if err == nil {
+		return capacity, nil
+	}
+	log.Warningf("failed to measure the %s storage, the capacity returned by the fallback driver is used instead: %v", d.Name(), err)
+	return d.fallback.Cap()
+}
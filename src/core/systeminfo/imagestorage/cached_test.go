@@ -0,0 +1,107 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagestorage
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDriver struct {
+	name  string
+	used  uint64
+	err   error
+	calls int32
+}
+
+func (f *fakeDriver) Name() string {
+	return f.name
+}
+
+func (f *fakeDriver) Cap() (*Capacity, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Capacity{Used: atomic.LoadUint64(&f.used)}, nil
+}
+
+// waitForUsed calls Cap until the used size is the expected one or it times out
+func waitForUsed(driver Driver, used uint64) *Capacity {
+	var capacity *Capacity
+	for i := 0; i < 50; i++ {
+		if capacity, _ = driver.Cap(); capacity.Used == used {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return capacity
+}
+
+func TestCachedDriver(t *testing.T) {
+	fake := &fakeDriver{name: "fake", used: 100}
+	fallback := &fakeDriver{name: "fallback", used: 10}
+	driver := NewCachedDriver(fake, fallback, time.Hour)
+	assert.Equal(t, "fake", driver.Name())
+
+	// the fallback one is returned before the first measuring is done
+	Refresh(driver)
+	capacity := waitForUsed(driver, 100)
+	assert.Equal(t, uint64(100), capacity.Used)
+
+	// the cached one is returned within the lifespan
+	atomic.StoreUint64(&fake.used, 200)
+	capacity, err := driver.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, uint64(100), capacity.Used)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fake.calls))
+
+	// the expired one is returned while it's refreshed in background
+	driver.(*cachedDriver).lock.Lock()
+	driver.(*cachedDriver).updatedAt = time.Now().Add(-2 * time.Hour)
+	driver.(*cachedDriver).lock.Unlock()
+	capacity, err = driver.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, uint64(100), capacity.Used)
+	capacity = waitForUsed(driver, 200)
+	assert.Equal(t, uint64(200), capacity.Used)
+}
+
+func TestCachedDriverFallback(t *testing.T) {
+	fake := &fakeDriver{name: "fake", err: errors.New("forbidden")}
+	fallback := &fakeDriver{name: "fallback", used: 10}
+	driver := NewCachedDriver(fake, fallback, time.Hour)
+
+	// the capacity returned by the fallback driver is cached when the measuring fails
+	capacity, err := driver.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, uint64(10), capacity.Used)
+	for i := 0; i < 50; i++ {
+		if c, _ := driver.(*cachedDriver).cached(); c != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cached, fresh := driver.(*cachedDriver).cached()
+	require.NotNil(t, cached)
+	assert.True(t, fresh)
+	assert.Equal(t, uint64(10), cached.Used)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fake.calls))
+}
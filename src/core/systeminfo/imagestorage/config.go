@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagestorage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BlobsPath is the path under the root directory of registry storage where the blobs are stored
+const BlobsPath = "docker/registry/v2/blobs"
+
+// ParseProviderConfig parses the storage provider config of registry, which is
+// comma separated "key: value" pairs, e.g. "key1: value1, key2: value2"
+func ParseProviderConfig(cfg string) map[string]string {
+	params := map[string]string{}
+	for _, kv := range strings.Split(cfg, ",") {
+		i := strings.Index(kv, ":")
+		if i <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(kv[:i])
+		if len(key) == 0 {
+			continue
+		}
+		params[key] = strings.TrimSpace(kv[i+1:])
+	}
+	return params
+}
+
+// BlobsPrefix returns the prefix of the blob objects under the root directory
+func BlobsPrefix(rootDirectory string) string {
+	root := strings.Trim(rootDirectory, "/")
+	if len(root) == 0 {
+		return BlobsPath + "/"
+	}
+	return root + "/" + BlobsPath + "/"
+}
+
+// IsBlobData returns whether the object is the data of a blob, the other
+// objects under the blobs path are the directories of the blobs
+func IsBlobData(key string) bool {
+	return strings.HasSuffix(key, "/data")
+}
+
+// GetBool returns the bool value of the parameter or the default value if it's not set
+func GetBool(params map[string]string, key string, def bool) (bool, error) {
+	v, ok := params[key]
+	if !ok || len(v) == 0 {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %s", key, v)
+	}
+	return b, nil
+}
@@ -0,0 +1,57 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagestorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProviderConfig(t *testing.T) {
+	params := ParseProviderConfig("region: us-west-1, bucket: harbor,regionendpoint: http://minio:9000, invalid, : empty")
+	assert.Equal(t, map[string]string{
+		"region":         "us-west-1",
+		"bucket":         "harbor",
+		"regionendpoint": "http://minio:9000",
+	}, params)
+
+	assert.Equal(t, map[string]string{}, ParseProviderConfig(""))
+}
+
+func TestBlobsPrefix(t *testing.T) {
+	assert.Equal(t, "docker/registry/v2/blobs/", BlobsPrefix(""))
+	assert.Equal(t, "docker/registry/v2/blobs/", BlobsPrefix("/"))
+	assert.Equal(t, "harbor/docker/registry/v2/blobs/", BlobsPrefix("/harbor/"))
+}
+
+func TestIsBlobData(t *testing.T) {
+	assert.True(t, IsBlobData("docker/registry/v2/blobs/sha256/aa/aabb/data"))
+	assert.False(t, IsBlobData("docker/registry/v2/blobs/sha256/aa/aabb"))
+}
+
+func TestGetBool(t *testing.T) {
+	params := map[string]string{"secure": "false", "invalid": "no-bool"}
+	b, err := GetBool(params, "secure", true)
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	b, err = GetBool(params, "not_set", true)
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	_, err = GetBool(params, "invalid", true)
+	assert.NotNil(t, err)
+}
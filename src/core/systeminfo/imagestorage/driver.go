@@ -23,6 +23,9 @@ type Capacity struct {
 	Total uint64 `json:"total"`
 	// available size(byte)
 	Free uint64 `json:"free"`
+	// used size(byte), it's the sum of the sizes of the blobs stored
+	// by the drivers which have no concept of capacity
+	Used uint64 `json:"used"`
 }
 
 // Driver defines methods that an image storage driver must implement
@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	storage "github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
+)
+
+type driver struct {
+	name string
+	// returns the sum of the sizes of the blobs tracked by Harbor
+	usage func() (int64, error)
+}
+
+// NewDriver returns an instance of the fallback driver for the object storage of
+// registry, e.g. s3 and swift, which is used when the storage can't be accessed
+// per the config. It reports the sum of the sizes of the blobs tracked by Harbor
+// as the used size, so the blobs pushed before they were tracked are not included
+func NewDriver(name string) storage.Driver {
+	return &driver{
+		name:  name,
+		usage: dao.GetTotalOfArtifactBlobSize,
+	}
+}
+
+// Name returns the name of the storage provider of registry
+func (d *driver) Name() string {
+	return d.name
+}
+
+// Cap returns the used size of the object storage
+func (d *driver) Cap() (*storage.Capacity, error) {
+	used, err := d.usage()
+	if err != nil {
+		return nil, err
+	}
+	return &storage.Capacity{
+		Used: uint64(used),
+	}, nil
+}
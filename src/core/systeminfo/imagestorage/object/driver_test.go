@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"errors"
+	"testing"
+
+	storage "github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "s3", NewDriver("s3").Name())
+	assert.Equal(t, "swift", NewDriver("swift").Name())
+}
+
+func TestCap(t *testing.T) {
+	d := &driver{
+		name: "s3",
+		usage: func() (int64, error) {
+			return 1024, nil
+		},
+	}
+	c, err := d.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, storage.Capacity{Used: 1024}, *c)
+
+	d.usage = func() (int64, error) {
+		return 0, errors.New("error")
+	}
+	_, err = d.Cap()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,243 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	storage "github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
+)
+
+const (
+	driverName = "s3"
+
+	defaultRegion = "us-east-1"
+	// the hash of the empty payload, all the requests sent by the driver have no body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	timeFormat       = "20060102T150405Z"
+	dateFormat       = "20060102"
+)
+
+type driver struct {
+	client        *http.Client
+	endpoint      *url.URL
+	region        string
+	bucket        string
+	accessKey     string
+	secretKey     string
+	rootDirectory string
+}
+
+// listResult is the response of the ListObjectsV2 API
+type listResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// NewDriver returns an instance of s3 driver per the s3 storage config of registry, the
+// "regionendpoint" is used to access the S3 compatible services
+func NewDriver(params map[string]string) (storage.Driver, error) {
+	bucket := params["bucket"]
+	if len(bucket) == 0 {
+		return nil, errors.New("no bucket is provided for s3 storage")
+	}
+	region := params["region"]
+	if len(region) == 0 {
+		region = defaultRegion
+	}
+	secure, err := storage.GetBool(params, "secure", true)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := params["regionendpoint"]
+	if len(endpoint) == 0 {
+		scheme := "https"
+		if !secure {
+			scheme = "http"
+		}
+		endpoint = fmt.Sprintf("%s://s3.%s.amazonaws.com", scheme, region)
+	} else if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid region endpoint %s: %v", endpoint, err)
+	}
+
+	return &driver{
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+		endpoint:      u,
+		region:        region,
+		bucket:        bucket,
+		accessKey:     params["accesskey"],
+		secretKey:     params["secretkey"],
+		rootDirectory: params["rootdirectory"],
+	}, nil
+}
+
+// Name returns a human-readable name of the s3 driver
+func (d *driver) Name() string {
+	return driverName
+}
+
+// Cap returns the capacity of the s3 storage. S3 has no concept of capacity,
+// so only the used size, which is the sum of the sizes of the blobs, is returned
+func (d *driver) Cap() (*storage.Capacity, error) {
+	prefix := storage.BlobsPrefix(d.rootDirectory)
+	var used uint64
+	token := ""
+	for {
+		result, err := d.list(prefix, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range result.Contents {
+			if storage.IsBlobData(object.Key) {
+				used += uint64(object.Size)
+			}
+		}
+		if !result.IsTruncated || len(result.NextContinuationToken) == 0 {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	return &storage.Capacity{Used: used}, nil
+}
+
+// list lists one page of the objects under the prefix in the bucket
+func (d *driver) list(prefix, token string) (*listResult, error) {
+	query := map[string]string{
+		"list-type": "2",
+		"prefix":    prefix,
+	}
+	if len(token) > 0 {
+		query["continuation-token"] = token
+	}
+	path := d.endpoint.Path + "/" + d.bucket
+	rawQuery := canonicalQuery(query)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s%s?%s", d.endpoint.Scheme, d.endpoint.Host, path, rawQuery), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.accessKey) > 0 {
+		d.sign(req, path, rawQuery, time.Now().UTC())
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list the objects in bucket %s: %d %s", d.bucket, resp.StatusCode, string(data))
+	}
+
+	result := &listResult{}
+	if err := xml.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sign signs the request with AWS signature version 4
+func (d *driver) sign(req *http.Request, path, rawQuery string, t time.Time) {
+	amzDate := t.Format(timeFormat)
+	date := t.Format(dateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		rawQuery,
+		fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, emptyPayloadHash, amzDate),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, d.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+d.secretKey), date)
+	key = hmacSHA256(key, d.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		d.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query in the canonical form of AWS signature version 4,
+// the same string is used as the query of the request
+func canonicalQuery(query map[string]string) string {
+	keys := []string{}
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		pairs = append(pairs, escape(key)+"="+escape(query[key]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escape encodes the string as required by AWS, only the unreserved characters are kept
+func escape(s string) string {
+	return strings.Replace(strings.Replace(url.QueryEscape(s), "+", "%20", -1), "%7E", "~", -1)
+}
+
+func hashHex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,350 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swift
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	storage "github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
+)
+
+const (
+	driverName = "swift"
+
+	// the page size of listing the objects
+	listLimit = 1000
+)
+
+type driver struct {
+	client      *http.Client
+	authURL     string
+	authVersion int
+	username    string
+	password    string
+	tenant      string
+	domain      string
+	region      string
+	container   string
+	prefix      string
+}
+
+// object is the item of the container listing
+type object struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+// NewDriver returns an instance of swift driver per the swift storage config of registry,
+// the authentication version 1 and 3 are supported
+func NewDriver(params map[string]string) (storage.Driver, error) {
+	for _, key := range []string{"authurl", "username", "password", "container"} {
+		if len(params[key]) == 0 {
+			return nil, fmt.Errorf("no %s is provided for swift storage", key)
+		}
+	}
+
+	authURL := strings.TrimRight(params["authurl"], "/")
+	authVersion := 0
+	if v := params["authversion"]; len(v) > 0 {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authversion: %s", v)
+		}
+		authVersion = version
+	}
+	// detect the version from the URL like the swift driver of registry
+	if authVersion == 0 {
+		authVersion = 1
+		if strings.Contains(authURL, "v3") {
+			authVersion = 3
+		}
+	}
+	if authVersion != 1 && authVersion != 3 {
+		return nil, fmt.Errorf("unsupported authversion: %d", authVersion)
+	}
+
+	// the "prefix" is used by registry while the "rootdirectory" is used by chart repository
+	prefix := params["prefix"]
+	if len(prefix) == 0 {
+		prefix = params["rootdirectory"]
+	}
+	tenant := params["tenant"]
+	if len(tenant) == 0 {
+		tenant = params["tenantid"]
+	}
+	domain := params["domain"]
+	if len(domain) == 0 {
+		domain = params["domainid"]
+	}
+
+	return &driver{
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+		authURL:     authURL,
+		authVersion: authVersion,
+		username:    params["username"],
+		password:    params["password"],
+		tenant:      tenant,
+		domain:      domain,
+		region:      params["region"],
+		container:   params["container"],
+		prefix:      prefix,
+	}, nil
+}
+
+// Name returns a human-readable name of the swift driver
+func (d *driver) Name() string {
+	return driverName
+}
+
+// Cap returns the capacity of the swift storage. The capacity is reported only when the quota
+// of the container is set, the used size is the sum of the sizes of the blobs
+func (d *driver) Cap() (*storage.Capacity, error) {
+	storageURL, token, err := d.authenticate()
+	if err != nil {
+		return nil, err
+	}
+	containerURL := storageURL + "/" + url.PathEscape(d.container)
+
+	req, err := http.NewRequest(http.MethodHead, containerURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to get the metadata of container %s: %d", d.container, resp.StatusCode)
+	}
+
+	capacity := &storage.Capacity{}
+	if quota, err := strconv.ParseUint(resp.Header.Get("X-Container-Meta-Quota-Bytes"), 10, 64); err == nil && quota > 0 {
+		bytesUsed, _ := strconv.ParseUint(resp.Header.Get("X-Container-Bytes-Used"), 10, 64)
+		capacity.Total = quota
+		if quota > bytesUsed {
+			capacity.Free = quota - bytesUsed
+		}
+	}
+
+	prefix := storage.BlobsPrefix(d.prefix)
+	marker := ""
+	for {
+		objects, err := d.list(containerURL, token, prefix, marker)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objects {
+			if storage.IsBlobData(o.Name) {
+				capacity.Used += uint64(o.Bytes)
+			}
+		}
+		if len(objects) < listLimit {
+			break
+		}
+		marker = objects[len(objects)-1].Name
+	}
+
+	return capacity, nil
+}
+
+// list lists one page of the objects under the prefix in the container
+func (d *driver) list(containerURL, token, prefix, marker string) ([]*object, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("prefix", prefix)
+	query.Set("limit", strconv.Itoa(listLimit))
+	if len(marker) > 0 {
+		query.Set("marker", marker)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, containerURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// no content is returned for the empty container
+	if resp.StatusCode == http.StatusNoContent {
+		return []*object{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list the objects in container %s: %d %s", d.container, resp.StatusCode, string(data))
+	}
+
+	objects := []*object{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// authenticate returns the storage URL and the token
+func (d *driver) authenticate() (string, string, error) {
+	if d.authVersion == 3 {
+		return d.authenticateV3()
+	}
+	return d.authenticateV1()
+}
+
+func (d *driver) authenticateV1() (string, string, error) {
+	req, err := http.NewRequest(http.MethodGet, d.authURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("X-Auth-User", d.username)
+	req.Header.Set("X-Auth-Key", d.password)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", "", fmt.Errorf("failed to authenticate against %s: %d", d.authURL, resp.StatusCode)
+	}
+
+	storageURL := resp.Header.Get("X-Storage-Url")
+	token := resp.Header.Get("X-Auth-Token")
+	if len(storageURL) == 0 || len(token) == 0 {
+		return "", "", errors.New("no storage URL or token returned by the authentication")
+	}
+	return strings.TrimRight(storageURL, "/"), token, nil
+}
+
+// the request and response of the keystone password authentication, only the needed fields are included
+type v3AuthRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User v3Named `json:"user"`
+			} `json:"password"`
+		} `json:"identity"`
+		Scope *v3Scope `json:"scope,omitempty"`
+	} `json:"auth"`
+}
+
+type v3Named struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password,omitempty"`
+	Domain   *v3Named `json:"domain,omitempty"`
+}
+
+type v3Scope struct {
+	Project v3Named `json:"project"`
+}
+
+type v3AuthResponse struct {
+	Token struct {
+		Catalog []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+func (d *driver) authenticateV3() (string, string, error) {
+	authReq := &v3AuthRequest{}
+	authReq.Auth.Identity.Methods = []string{"password"}
+	authReq.Auth.Identity.Password.User = v3Named{
+		Name:     d.username,
+		Password: d.password,
+	}
+	if len(d.domain) > 0 {
+		authReq.Auth.Identity.Password.User.Domain = &v3Named{Name: d.domain}
+	}
+	if len(d.tenant) > 0 {
+		authReq.Auth.Scope = &v3Scope{
+			Project: v3Named{Name: d.tenant},
+		}
+		if len(d.domain) > 0 {
+			authReq.Auth.Scope.Project.Domain = &v3Named{Name: d.domain}
+		}
+	}
+	data, err := json.Marshal(authReq)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL := d.authURL
+	if !strings.HasSuffix(authURL, "/auth/tokens") {
+		authURL += "/auth/tokens"
+	}
+	req, err := http.NewRequest(http.MethodPost, authURL, bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", "", fmt.Errorf("failed to authenticate against %s: %d %s", authURL, resp.StatusCode, string(data))
+	}
+	token := resp.Header.Get("X-Subject-Token")
+	if len(token) == 0 {
+		return "", "", errors.New("no token returned by the authentication")
+	}
+
+	authResp := &v3AuthResponse{}
+	if err := json.Unmarshal(data, authResp); err != nil {
+		return "", "", err
+	}
+	for _, service := range authResp.Token.Catalog {
+		if service.Type != "object-store" {
+			continue
+		}
+		for _, endpoint := range service.Endpoints {
+			if endpoint.Interface != "public" {
+				continue
+			}
+			if len(d.region) > 0 && endpoint.Region != d.region {
+				continue
+			}
+			return strings.TrimRight(endpoint.URL, "/"), token, nil
+		}
+	}
+	return "", "", fmt.Errorf("no object store endpoint found in the service catalog for region %s", d.region)
+}
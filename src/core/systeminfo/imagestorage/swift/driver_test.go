@@ -0,0 +1,157 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swift
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSwiftServer returns a swift stand-in which supports the authentication version 1 and 3
+func newSwiftServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/v1.0":
+			if r.Header.Get("X-Auth-User") != "user" || r.Header.Get("X-Auth-Key") != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-Storage-Url", server.URL+"/v1/AUTH_test")
+			w.Header().Set("X-Auth-Token", "token")
+		case "/v3/auth/tokens":
+			req := &v3AuthRequest{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil ||
+				req.Auth.Identity.Password.User.Password != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-Subject-Token", "token")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token":{"catalog":[
+				{"type":"identity","endpoints":[{"interface":"public","region":"RegionOne","url":"` + server.URL + `/v3"}]},
+				{"type":"object-store","endpoints":[
+					{"interface":"internal","region":"RegionOne","url":"` + server.URL + `/internal"},
+					{"interface":"public","region":"RegionOne","url":"` + server.URL + `/v1/AUTH_test"}]}]}}`))
+		case "/v1/AUTH_test/harbor":
+			if r.Header.Get("X-Auth-Token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Method == http.MethodHead {
+				w.Header().Set("X-Container-Bytes-Used", "700")
+				w.Header().Set("X-Container-Meta-Quota-Bytes", "1000")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if r.URL.Query().Get("prefix") != "registry/docker/registry/v2/blobs/" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Write([]byte(`[
+				{"name":"registry/docker/registry/v2/blobs/sha256/aa/aa01/data","bytes":100},
+				{"name":"registry/docker/registry/v2/blobs/sha256/aa/aa02/data","bytes":200},
+				{"name":"registry/docker/registry/v2/blobs/sha256/aa/aa02","bytes":0}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestNewDriver(t *testing.T) {
+	_, err := NewDriver(map[string]string{"authurl": "http://swift/auth/v1.0"})
+	assert.NotNil(t, err)
+
+	params := map[string]string{
+		"authurl":   "http://keystone/v3",
+		"username":  "user",
+		"password":  "password",
+		"container": "harbor",
+	}
+	d, err := NewDriver(params)
+	require.Nil(t, err)
+	assert.Equal(t, driverName, d.Name())
+	assert.Equal(t, 3, d.(*driver).authVersion)
+
+	params["authversion"] = "2"
+	_, err = NewDriver(params)
+	assert.NotNil(t, err)
+}
+
+func TestCapV1(t *testing.T) {
+	server := newSwiftServer(t)
+	defer server.Close()
+
+	d, err := NewDriver(map[string]string{
+		"authurl":   server.URL + "/auth/v1.0",
+		"username":  "user",
+		"password":  "password",
+		"container": "harbor",
+		"prefix":    "/registry",
+	})
+	require.Nil(t, err)
+	c, err := d.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, uint64(1000), c.Total)
+	assert.Equal(t, uint64(300), c.Free)
+	assert.Equal(t, uint64(300), c.Used)
+
+	d, err = NewDriver(map[string]string{
+		"authurl":   server.URL + "/auth/v1.0",
+		"username":  "user",
+		"password":  "invalid",
+		"container": "harbor",
+	})
+	require.Nil(t, err)
+	_, err = d.Cap()
+	assert.NotNil(t, err)
+}
+
+func TestCapV3(t *testing.T) {
+	server := newSwiftServer(t)
+	defer server.Close()
+
+	d, err := NewDriver(map[string]string{
+		"authurl":       server.URL + "/v3",
+		"username":      "user",
+		"password":      "password",
+		"tenant":        "harbor",
+		"domain":        "default",
+		"region":        "RegionOne",
+		"container":     "harbor",
+		"rootdirectory": "registry",
+	})
+	require.Nil(t, err)
+	c, err := d.Cap()
+	require.Nil(t, err)
+	assert.Equal(t, uint64(300), c.Used)
+
+	d, err = NewDriver(map[string]string{
+		"authurl":   server.URL + "/v3",
+		"username":  "user",
+		"password":  "password",
+		"region":    "RegionTwo",
+		"container": "harbor",
+	})
+	require.Nil(t, err)
+	_, err = d.Cap()
+	assert.NotNil(t, err)
+}
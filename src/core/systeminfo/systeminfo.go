@@ -16,16 +16,53 @@ package systeminfo
 
 import (
 	"os"
+	"time"
 
+	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage"
 	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage/filesystem"
+	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage/object"
+	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage/s3"
+	"github.com/goharbor/harbor/src/core/systeminfo/imagestorage/swift"
 )
 
-// Init image storage driver
+// the lifespan of the usage of the object storage measured by listing the objects
+const objectStorageUsageLifespan = 10 * time.Minute
+
+// Init image storage driver per the storage provider of registry, the
+// filesystem driver is used for the providers not supported. The s3 and
+// swift drivers measure the bucket/container per the storage config of
+// registry in background and the result is cached. The sum of the blobs
+// tracked by Harbor is reported instead before the first measuring is done,
+// when the storage can't be measured or the driver can't be created
 func Init() {
-	path := os.Getenv("IMAGE_STORE_PATH")
-	if len(path) == 0 {
-		path = "/data"
+	imagestorage.GlobalDriver = newDriver()
+	// start measuring at startup so that the requests never wait for listing the whole bucket
+	imagestorage.Refresh(imagestorage.GlobalDriver)
+}
+
+func newDriver() imagestorage.Driver {
+	switch name := os.Getenv("REGISTRY_STORAGE_PROVIDER_NAME"); name {
+	case "s3", "swift":
+		driver, err := newObjectDriver(name, os.Getenv("REGISTRY_STORAGE_PROVIDER_CONFIG"))
+		if err != nil {
+			log.Warningf("failed to initialize the %s storage driver, the usage of the blobs tracked by Harbor is reported instead: %v", name, err)
+			return object.NewDriver(name)
+		}
+		return imagestorage.NewCachedDriver(driver, object.NewDriver(name), objectStorageUsageLifespan)
+	default:
+		path := os.Getenv("IMAGE_STORE_PATH")
+		if len(path) == 0 {
+			path = "/data"
+		}
+		return filesystem.NewDriver(path)
+	}
+}
+
+func newObjectDriver(name, config string) (imagestorage.Driver, error) {
+	params := imagestorage.ParseProviderConfig(config)
+	if name == "s3" {
+		return s3.NewDriver(params)
 	}
-	imagestorage.GlobalDriver = filesystem.NewDriver(path)
+	return swift.NewDriver(params)
 }
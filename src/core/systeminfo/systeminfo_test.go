@@ -0,0 +1,51 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systeminfo
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDriver(t *testing.T) {
+	defer os.Unsetenv("REGISTRY_STORAGE_PROVIDER_NAME")
+	defer os.Unsetenv("REGISTRY_STORAGE_PROVIDER_CONFIG")
+
+	cases := []struct {
+		provider string
+		config   string
+		driver   string
+	}{
+		{"", "", "*filesystem.driver"},
+		{"filesystem", "rootdirectory: /storage", "*filesystem.driver"},
+		{"gcs", "bucket: harbor", "*filesystem.driver"},
+		{"s3", "region: us-west-1, bucket: harbor", "*imagestorage.cachedDriver"},
+		{"s3", "region: us-west-1", "*object.driver"},
+		{"swift", "authurl: http://swift/auth/v1.0, username: user, password: password, container: harbor", "*imagestorage.cachedDriver"},
+		{"swift", "authurl: http://swift/auth/v1.0", "*object.driver"},
+	}
+	for _, c := range cases {
+		os.Setenv("REGISTRY_STORAGE_PROVIDER_NAME", c.provider)
+		os.Setenv("REGISTRY_STORAGE_PROVIDER_CONFIG", c.config)
+		driver := newDriver()
+		assert.Equal(t, c.driver, fmt.Sprintf("%T", driver))
+		if len(c.provider) > 0 && c.provider != "gcs" {
+			assert.Equal(t, c.provider, driver.Name())
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/cache"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+const (
+	// the system CVE allowlist is stored with project ID 0
+	systemCVEAllowlistProjectID int64 = 0
+	// the lifespan of the reports queried from Clair in the cache, the report
+	// of the same layer may change when the vulnerability database is updated
+	clairReportCacheLifespan = 5 * time.Minute
+)
+
+var (
+	clairReportCache     cache.Cache
+	clairReportCacheOnce sync.Once
+)
+
+// getClairReportCache returns the cache of the reports queried from Clair, which avoids
+// querying Clair for each vulnerable tag when listing the tags of a repository
+func getClairReportCache() cache.Cache {
+	clairReportCacheOnce.Do(func() {
+		c, err := cache.NewCache("memory", `{"interval":60}`)
+		if err != nil {
+			log.Errorf("failed to create the cache of Clair reports, the reports are not cached: %v", err)
+			return
+		}
+		clairReportCache = c
+	})
+	return clairReportCache
+}
+
+// GetCVEAllowlist returns the CVE IDs ignored by the vulnerability check of the project,
+// which are the union of the unexpired system allowlist and the unexpired project allowlist
+func GetCVEAllowlist(projectID int64) (models.CVESet, error) {
+	set := models.CVESet{}
+	for _, id := range []int64{systemCVEAllowlistProjectID, projectID} {
+		allowlist, err := dao.GetCVEAllowlist(id)
+		if err != nil {
+			return nil, err
+		}
+		if allowlist != nil {
+			set.Merge(allowlist.CVESet())
+		}
+	}
+	return set, nil
+}
+
+// GetVulnReport returns the vulnerability report of the image recorded in the scan overview,
+// the report of the Clair deployed with Harbor is queried from Clair by the details key and
+// cached for a while, and the reports of the registered scanners are read from database
+func GetVulnReport(overview *models.ImgScanOverview) (*models.VulnerabilityReport, error) {
+	if len(overview.DetailsKey) > 0 {
+		return getClairReport(overview.DetailsKey)
+	}
+	report, err := dao.GetLatestScanReport(overview.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("no scan report found for %s", overview.Digest)
+	}
+	return report.Report, nil
+}
+
+// getClairReport returns the report of the layer queried from Clair, the cached one is returned if existing
+func getClairReport(detailsKey string) (*models.VulnerabilityReport, error) {
+	c := getClairReportCache()
+	if c != nil {
+		if report, ok := c.Get(detailsKey).(*models.VulnerabilityReport); ok {
+			return report, nil
+		}
+	}
+	details, err := clair.NewClient(config.ClairEndpoint(), nil).GetResult(detailsKey)
+	if err != nil {
+		return nil, err
+	}
+	report := clair.ToReport(details)
+	if c != nil {
+		if err := c.Put(detailsKey, report, clairReportCacheLifespan); err != nil {
+			log.Warningf("failed to cache the Clair report of %s: %v", detailsKey, err)
+		}
+	}
+	return report, nil
+}
+
+// ApplyCVEAllowlist recalculates the severity and components of the scan overview
+// without the vulnerabilities in the allowlist
+func ApplyCVEAllowlist(overview *models.ImgScanOverview, allowlist models.CVESet) error {
+	if overview == nil || len(allowlist) == 0 || overview.Sev <= int(models.SevNone) {
+		return nil
+	}
+	report, err := GetVulnReport(overview)
+	if err != nil {
+		return err
+	}
+	compOverview, sev := report.Filter(allowlist).Overview()
+	overview.CompOverview = compOverview
+	overview.Sev = int(sev)
+	return nil
+}
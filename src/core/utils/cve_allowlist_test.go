@@ -12,32 +12,29 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package source
+package utils
 
 import (
 	"testing"
 
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestMatch(t *testing.T) {
-	cases := []struct {
-		pattern string
-		str     string
-		matched bool
-	}{
-		{"", "", true},
-		{"*", "library", true},
-		{"library/*", "library/mysql", true},
-		{"library/*", "library/mysql/5.6", false},
-		{"library/mysq?", "library/mysql", true},
-		{"library/mysq?", "library/mysqld", false},
+func TestGetClairReportCached(t *testing.T) {
+	report := &models.VulnerabilityReport{
+		Vulnerabilities: []*models.VulnerabilityItem{
+			{ID: "CVE-2019-0001"},
+		},
 	}
+	c := getClairReportCache()
+	require.NotNil(t, c)
+	require.Nil(t, c.Put("cached-layer", report, clairReportCacheLifespan))
+	defer c.Delete("cached-layer")
 
-	for _, c := range cases {
-		matched, err := match(c.pattern, c.str)
-		require.Nil(t, err)
-		assert.Equal(t, c.matched, matched)
-	}
+	// Clair isn't queried for the cached report
+	r, err := getClairReport("cached-layer")
+	require.Nil(t, err)
+	assert.Equal(t, report, r)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/goharbor/harbor/src/common/job"
 	jobmodels "github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
 
@@ -106,10 +107,22 @@ func TriggerImageScan(repository string, tag string) error {
 		log.Errorf("Failed to get Manifest for %s:%s", repository, tag)
 		return err
 	}
-	return triggerImageScan(repository, tag, digest, GetJobServiceClient())
+	projectName, _ := utils.ParseRepository(repository)
+	project, err := config.GlobalProjectMgr.Get(projectName)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("unable to perform scan: project %s not found", projectName)
+	}
+	registration, err := GetScannerOfProject(project)
+	if err != nil {
+		return err
+	}
+	return triggerImageScan(repository, tag, digest, registration, GetJobServiceClient())
 }
 
-func triggerImageScan(repository, tag, digest string, client job.Client) error {
+func triggerImageScan(repository, tag, digest string, registration *models.ScannerRegistration, client job.Client) error {
 	id, err := dao.AddScanJob(models.ScanJob{
 		Repository: repository,
 		Digest:     digest,
@@ -123,7 +136,7 @@ func triggerImageScan(repository, tag, digest string, client job.Client) error {
 	if err != nil {
 		return err
 	}
-	data, err := buildScanJobData(id, repository, tag, digest)
+	data, err := buildScanJobData(id, repository, tag, digest, registration)
 	if err != nil {
 		return err
 	}
@@ -138,13 +151,20 @@ func triggerImageScan(repository, tag, digest string, client job.Client) error {
 	return nil
 }
 
-func buildScanJobData(jobID int64, repository, tag, digest string) (*jobmodels.JobData, error) {
+// buildScanJobData builds the data of the scan job, the Clair deployed with Harbor
+// is used if the scanner registration is nil
+func buildScanJobData(jobID int64, repository, tag, digest string, registration *models.ScannerRegistration) (*jobmodels.JobData, error) {
 	parms := job.ScanJobParms{
 		JobID:      jobID,
 		Repository: repository,
 		Digest:     digest,
 		Tag:        tag,
 	}
+	if registration != nil {
+		parms.Scanner = registration.Name
+		parms.ScannerAdapter = registration.Adapter
+		parms.ScannerURL = registration.URL
+	}
 	parmsMap := make(map[string]interface{})
 	b, err := json.Marshal(parms)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/goharbor/harbor/src/common/job"
 	jobmodels "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,10 +42,22 @@ func TestBuildScanJobData(t *testing.T) {
 		},
 	}
 	for _, d := range testData {
-		r, err := buildScanJobData(d.input.JobID, d.input.Repository, d.input.Tag, d.input.Digest)
+		r, err := buildScanJobData(d.input.JobID, d.input.Repository, d.input.Tag, d.input.Digest, nil)
 		assert.Nil(err)
 		assert.Equal(d.expect.Name, r.Name)
 		//		assert.Equal(d.expect.Parameters, r.Parameters)
 		assert.Equal(d.expect.StatusHook, r.StatusHook)
+		_, exist := r.Parameters["scanner_adapter"]
+		assert.False(exist)
 	}
+
+	r, err := buildScanJobData(123, "library/ubuntu", "latest", "sha256:abcde", &models.ScannerRegistration{
+		Name:    "scanner1",
+		URL:     "http://scanner1:8080",
+		Adapter: "generic",
+	})
+	assert.Nil(err)
+	assert.Equal("scanner1", r.Parameters["scanner"])
+	assert.Equal("generic", r.Parameters["scanner_adapter"])
+	assert.Equal("http://scanner1:8080", r.Parameters["scanner_url"])
 }
@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+)
+
+// ScanEnabled returns whether the images can be scanned, which requires Harbor
+// is deployed with Clair or at least one scanner is registered
+func ScanEnabled() bool {
+	if config.WithClair() {
+		return true
+	}
+	n, err := dao.CountScannerRegistrations()
+	if err != nil {
+		log.Errorf("failed to count the scanner registrations: %v", err)
+		return false
+	}
+	return n > 0
+}
+
+// GetScannerOfProject returns the scanner registration used to scan the images of the project,
+// which is the one chosen by the project or the default one. nil is returned if neither
+// exists and the Clair deployed with Harbor should be used.
+func GetScannerOfProject(project *models.Project) (*models.ScannerRegistration, error) {
+	if name := project.Scanner(); len(name) > 0 {
+		registration, err := dao.GetScannerRegistrationByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if registration != nil {
+			return registration, nil
+		}
+		log.Warningf("the scanner %s chosen by project %s is not registered, use the default one", name, project.Name)
+	}
+	return dao.GetDefaultScannerRegistration()
+}
@@ -19,6 +19,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/common/utils/registry/auth"
@@ -75,3 +77,27 @@ func WaitForManifestReady(repository string, tag string, maxRetry int) bool {
 	}
 	return false
 }
+
+// RecordArtifactBlobs records the blobs referenced by the manifest of the image into DB,
+// the unlink pre-pass of the garbage collection uses the records to find out the unreferenced blobs
+func RecordArtifactBlobs(repository, tag string) error {
+	repoClient, err := NewRepositoryClientForUI("harbor-core", repository)
+	if err != nil {
+		return err
+	}
+	digest, references, err := registry.PullManifestReferences(repoClient, tag)
+	if err != nil {
+		return err
+	}
+
+	blobs := []*models.ArtifactBlob{}
+	for _, reference := range references {
+		blobs = append(blobs, &models.ArtifactBlob{
+			Repository: repository,
+			DigestAF:   digest,
+			DigestBlob: reference.Digest.String(),
+			Size:       reference.Size,
+		})
+	}
+	return dao.AddArtifactBlobs(blobs)
+}
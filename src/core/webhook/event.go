@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier"
+)
+
+// EventTopic is the topic of the events sent by webhook
+const EventTopic = "OnWebhookEvent"
+
+// Event is published to the topic by the modules which produce the events,
+// e.g. the notification handlers of registry and jobservice
+type Event struct {
+	Type       string
+	ProjectID  int64
+	Operator   string
+	OccurAt    time.Time
+	Repository string
+	// tag -> digest, the digest is optional
+	Tags             map[string]string
+	CustomAttributes map[string]string
+}
+
+// Notify publishes the event, the deliveries are handled asynchronously
+func Notify(event Event) {
+	if event.OccurAt.IsZero() {
+		event.OccurAt = time.Now()
+	}
+	if err := notifier.Publish(EventTopic, event); err != nil {
+		log.Errorf("failed to publish the %s event of project %d: %v", event.Type, event.ProjectID, err)
+		return
+	}
+	log.Debugf("the %s event of project %d published", event.Type, event.ProjectID)
+}
@@ -0,0 +1,180 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	common_job "github.com/goharbor/harbor/src/common/job"
+	job_models "github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/webhook"
+	"github.com/goharbor/harbor/src/core/config"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
+)
+
+// EventHandler implements the notification handler interface, it
+// submits a delivery job for each policy subscribing the event
+type EventHandler struct{}
+
+// Handle implements the same method of notification handler interface
+func (e *EventHandler) Handle(value interface{}) error {
+	if value == nil {
+		return errors.New("EventHandler can not handle nil value")
+	}
+	event, ok := value.(Event)
+	if !ok {
+		return fmt.Errorf("Mismatch value type of EventHandler, expect webhook.Event but got %T", value)
+	}
+
+	policies, err := dao.ListWebhookPolicies(&models.WebhookPolicyQuery{
+		ProjectID: event.ProjectID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, policy := range policies {
+		if !policy.Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(buildPayload(&event, extURL())); err != nil {
+				return err
+			}
+		}
+		if err = deliver(policy, event.Type, payload); err != nil {
+			log.Errorf("failed to deliver the %s event to webhook policy %d: %v", event.Type, policy.ID, err)
+		}
+	}
+	return nil
+}
+
+// IsStateful implements the same method of notification handler interface
+func (e *EventHandler) IsStateful() bool {
+	// Statless
+	return false
+}
+
+// extURL returns the external URL without the scheme, e.g. "reg.mydomain.com"
+func extURL() string {
+	endpoint, err := config.ExtEndpoint()
+	if err != nil {
+		log.Errorf("failed to get the external endpoint: %v", err)
+		return ""
+	}
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		endpoint = endpoint[i+3:]
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+func buildPayload(event *Event, extURL string) *webhook.Payload {
+	namespace, name := utils.ParseRepository(event.Repository)
+
+	tags := []string{}
+	for tag := range event.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	resources := []*webhook.Resource{}
+	for _, tag := range tags {
+		resource := &webhook.Resource{
+			Digest: event.Tags[tag],
+			Tag:    tag,
+		}
+		if len(extURL) > 0 {
+			resource.ResourceURL = fmt.Sprintf("%s/%s:%s", extURL, event.Repository, tag)
+		}
+		resources = append(resources, resource)
+	}
+
+	return &webhook.Payload{
+		Type:     event.Type,
+		OccurAt:  event.OccurAt.Unix(),
+		Operator: event.Operator,
+		EventData: &webhook.EventData{
+			Resources: resources,
+			Repository: &webhook.Repository{
+				Name:         name,
+				Namespace:    namespace,
+				RepoFullName: event.Repository,
+			},
+			CustomAttributes: event.CustomAttributes,
+		},
+	}
+}
+
+// deliver records the delivery and submits the job to send the payload
+func deliver(policy *models.WebhookPolicy, eventType string, payload []byte) error {
+	execution := &models.WebhookExecution{
+		PolicyID:  policy.ID,
+		EventType: eventType,
+		Payload:   string(payload),
+		Status:    models.JobPending,
+	}
+	id, err := dao.AddWebhookExecution(execution)
+	if err != nil {
+		return err
+	}
+	execution.ID = id
+
+	uuid, err := submit(policy, eventType, payload,
+		fmt.Sprintf("%s/service/notifications/jobs/webhook/%d", config.InternalCoreURL(), id))
+	if err != nil {
+		execution.Status = models.JobError
+		if e := dao.UpdateWebhookExecution(execution, "Status"); e != nil {
+			log.Errorf("failed to update the status of webhook execution %d: %v", id, e)
+		}
+		return err
+	}
+	execution.UUID = uuid
+	return dao.UpdateWebhookExecution(execution, "UUID")
+}
+
+func submit(policy *models.WebhookPolicy, eventType string, payload []byte, hook string) (string, error) {
+	secret := ""
+	if len(policy.Secret) > 0 {
+		key, err := config.SecretKey()
+		if err != nil {
+			return "", err
+		}
+		if secret, err = utils.ReversibleDecrypt(policy.Secret, key); err != nil {
+			return "", err
+		}
+	}
+	return utils_core.GetJobServiceClient().SubmitJob(&job_models.JobData{
+		Name: common_job.WebhookDelivery,
+		Parameters: map[string]interface{}{
+			"address":          policy.TargetURL,
+			"payload":          string(payload),
+			"event_type":       eventType,
+			"secret":           secret,
+			"skip_cert_verify": policy.SkipCertVerify,
+		},
+		Metadata: &job_models.JobMetadata{
+			JobKind: common_job.JobKindGeneric,
+		},
+		StatusHook: hook,
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPayload(t *testing.T) {
+	now := time.Now()
+	payload := buildPayload(&Event{
+		Type:       models.WebhookEventPushImage,
+		ProjectID:  1,
+		Operator:   "admin",
+		OccurAt:    now,
+		Repository: "library/hello",
+		Tags: map[string]string{
+			"v2": "",
+			"v1": "sha256:1",
+		},
+	}, "reg.mydomain.com")
+
+	assert.Equal(t, models.WebhookEventPushImage, payload.Type)
+	assert.Equal(t, now.Unix(), payload.OccurAt)
+	assert.Equal(t, "admin", payload.Operator)
+	require.NotNil(t, payload.EventData)
+	assert.Equal(t, "hello", payload.EventData.Repository.Name)
+	assert.Equal(t, "library", payload.EventData.Repository.Namespace)
+	assert.Equal(t, "library/hello", payload.EventData.Repository.RepoFullName)
+	require.Equal(t, 2, len(payload.EventData.Resources))
+	assert.Equal(t, "v1", payload.EventData.Resources[0].Tag)
+	assert.Equal(t, "sha256:1", payload.EventData.Resources[0].Digest)
+	assert.Equal(t, "reg.mydomain.com/library/hello:v1", payload.EventData.Resources[0].ResourceURL)
+
+	// without external URL
+	payload = buildPayload(&Event{
+		Repository: "library/hello",
+		Tags: map[string]string{
+			"v1": "",
+		},
+	}, "")
+	require.Equal(t, 1, len(payload.EventData.Resources))
+	assert.Empty(t, payload.EventData.Resources[0].ResourceURL)
+}
+
+func TestHandleInvalidValue(t *testing.T) {
+	handler := &EventHandler{}
+	assert.NotNil(t, handler.Handle(nil))
+	assert.NotNil(t, handler.Handle("event"))
+	assert.False(t, handler.IsStateful())
+}
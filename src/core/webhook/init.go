@@ -12,12 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package source
+package webhook
 
 import (
-	"path/filepath"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier"
 )
 
-func match(pattern, str string) (bool, error) {
-	return filepath.Match(pattern, str)
+// Subscribe the topic of webhook events
+func init() {
+	if err := notifier.Subscribe(EventTopic, &EventHandler{}); err != nil {
+		log.Errorf("failed to subscribe topic %s: %v", EventTopic, err)
+		return
+	}
+	log.Debugf("topic %s is subscribed", EventTopic)
 }
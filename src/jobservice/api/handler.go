@@ -20,15 +20,18 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/goharbor/harbor/src/jobservice/core"
 	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/models"
 	"github.com/goharbor/harbor/src/jobservice/opm"
+	"github.com/goharbor/harbor/src/jobservice/utils"
 )
 
 // Handler defines approaches to handle the http requests.
@@ -41,6 +44,9 @@ type Handler interface {
 	// 用来处理 查询 job 状态请求
 	HandleGetJobReq(w http.ResponseWriter, req *http.Request)
 
+	// HandleListJobsReq is used to handle the job listing request.
+	HandleListJobsReq(w http.ResponseWriter, req *http.Request)
+
 	// HandleJobActionReq is used to handle the job action requests (stop/retry).
 	// 用来处理 对 job 的控制请求
 	HandleJobActionReq(w http.ResponseWriter, req *http.Request)
@@ -52,6 +58,12 @@ type Handler interface {
 	// HandleJobLogReq is used to handle the request of getting job logs
 	// 获取 job 日志
 	HandleJobLogReq(w http.ResponseWriter, req *http.Request)
+
+	// HandleLaunchWorkflowReq is used to handle the workflow submission request.
+	HandleLaunchWorkflowReq(w http.ResponseWriter, req *http.Request)
+
+	// HandleGetWorkflowReq is used to handle the workflow stats query request.
+	HandleGetWorkflowReq(w http.ResponseWriter, req *http.Request)
 }
 
 // DefaultHandler is the default request handler which implements the Handler interface.
@@ -136,6 +148,27 @@ func (dh *DefaultHandler) HandleGetJobReq(w http.ResponseWriter, req *http.Reque
 	dh.handleJSONData(w, req, http.StatusOK, jobStats)
 }
 
+// HandleListJobsReq is implementation of method defined in interface 'Handler'
+func (dh *DefaultHandler) HandleListJobsReq(w http.ResponseWriter, req *http.Request) {
+	if !dh.preCheck(w, req) {
+		return
+	}
+
+	query, err := parseJobQuery(req)
+	if err != nil {
+		dh.handleError(w, req, http.StatusBadRequest, errs.ListJobsError(err))
+		return
+	}
+
+	jobs, err := dh.controller.ListJobs(query)
+	if err != nil {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.ListJobsError(err))
+		return
+	}
+
+	dh.handleJSONData(w, req, http.StatusOK, jobs)
+}
+
 // HandleJobActionReq is implementation of method defined in interface 'Handler'
 // 对 job 进行控制 stop/cancel
 func (dh *DefaultHandler) HandleJobActionReq(w http.ResponseWriter, req *http.Request) {
@@ -253,6 +286,57 @@ func (dh *DefaultHandler) HandleJobLogReq(w http.ResponseWriter, req *http.Reque
 	w.Write(logData)
 }
 
+// HandleLaunchWorkflowReq is implementation of method defined in interface 'Handler'
+func (dh *DefaultHandler) HandleLaunchWorkflowReq(w http.ResponseWriter, req *http.Request) {
+	if !dh.preCheck(w, req) {
+		return
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.ReadRequestBodyError(err))
+		return
+	}
+
+	workflowReq := models.WorkflowRequest{}
+	if err = json.Unmarshal(data, &workflowReq); err != nil {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.HandleJSONDataError(err))
+		return
+	}
+
+	workflowStats, err := dh.controller.LaunchWorkflow(workflowReq)
+	if err != nil {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.LaunchWorkflowError(err))
+		return
+	}
+
+	dh.handleJSONData(w, req, http.StatusAccepted, workflowStats)
+}
+
+// HandleGetWorkflowReq is implementation of method defined in interface 'Handler'
+func (dh *DefaultHandler) HandleGetWorkflowReq(w http.ResponseWriter, req *http.Request) {
+	if !dh.preCheck(w, req) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	workflowID := vars["workflow_id"]
+
+	workflowStats, err := dh.controller.GetWorkflow(workflowID)
+	if err != nil {
+		code := http.StatusInternalServerError
+		backErr := errs.GetWorkflowStatsError(err)
+		if errs.IsObjectNotFoundError(err) {
+			code = http.StatusNotFound
+			backErr = err
+		}
+		dh.handleError(w, req, code, backErr)
+		return
+	}
+
+	dh.handleJSONData(w, req, http.StatusOK, workflowStats)
+}
+
 func (dh *DefaultHandler) handleJSONData(w http.ResponseWriter, req *http.Request, code int, object interface{}) {
 	data, err := json.Marshal(object)
 	if err != nil {
@@ -285,6 +369,67 @@ func (dh *DefaultHandler) preCheck(w http.ResponseWriter, req *http.Request) boo
 	return true
 }
 
+// parseJobQuery builds the job query from the query parameters of the request
+func parseJobQuery(req *http.Request) (models.JobQuery, error) {
+	values := req.URL.Query()
+	query := models.JobQuery{
+		JobName:       values.Get("job_name"),
+		Status:        values.Get("status"),
+		UpstreamJobID: values.Get("upstream_job_id"),
+	}
+
+	switch query.Status {
+	case "",
+		job.JobStatusPending,
+		job.JobStatusScheduled,
+		job.JobStatusRunning,
+		job.JobStatusStopped,
+		job.JobStatusCancelled,
+		job.JobStatusError,
+		job.JobStatusSuccess:
+	default:
+		return query, fmt.Errorf("unknown job status '%s'", query.Status)
+	}
+
+	var err error
+	if query.From, err = parseInt64Param(values.Get("from"), "from"); err != nil {
+		return query, err
+	}
+	if query.To, err = parseInt64Param(values.Get("to"), "to"); err != nil {
+		return query, err
+	}
+	if query.From > 0 && query.To > 0 && query.From > query.To {
+		return query, fmt.Errorf("'from' %d is later than 'to' %d", query.From, query.To)
+	}
+
+	pageNumber, err := parseInt64Param(values.Get("page_number"), "page_number")
+	if err != nil {
+		return query, err
+	}
+	query.PageNumber = uint(pageNumber)
+	pageSize, err := parseInt64Param(values.Get("page_size"), "page_size")
+	if err != nil {
+		return query, err
+	}
+	query.PageSize = uint(pageSize)
+
+	return query, nil
+}
+
+// parseInt64Param parses the none negative integer parameter, empty means 0
+func parseInt64Param(value string, name string) (int64, error) {
+	if utils.IsEmptyStr(value) {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid value '%s' of parameter '%s'", value, name)
+	}
+
+	return v, nil
+}
+
 func (dh *DefaultHandler) log(req *http.Request, code int, text string) {
 	logger.Debugf("Serve http request '%s %s': %d %s", req.Method, req.URL.String(), code, text)
 }
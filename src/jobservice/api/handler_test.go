@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
 	"github.com/goharbor/harbor/src/jobservice/models"
 )
 
@@ -277,6 +278,113 @@ func TestGetJobLog(t *testing.T) {
 	ctx.WG.Wait()
 }
 
+func TestLaunchWorkflow(t *testing.T) {
+	exportUISecret(fakeSecret)
+
+	server, port, ctx := createServer()
+	server.Start()
+	<-time.After(200 * time.Millisecond)
+
+	resData, err := postReq(fmt.Sprintf("http://localhost:%d/api/v1/workflows", port), []byte("{}"))
+	if e := expectFormatedError(resData, err); e != nil {
+		t.Error(e)
+	}
+
+	workflowReq := models.WorkflowRequest{
+		Workflow: &models.WorkflowData{
+			Name: "fake_workflow",
+			Nodes: []*models.WorkflowNode{
+				{ID: "fake_node", Job: &models.JobData{Name: "fake_job_ok"}},
+			},
+		},
+	}
+	data, err := json.Marshal(&workflowReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := postReq(fmt.Sprintf("http://localhost:%d/api/v1/workflows", port), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := models.WorkflowStats{}
+	if err := json.Unmarshal(res, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.Stats.WorkflowID != "fake_workflow_ok" || len(obj.Stats.Nodes) != 1 {
+		t.Fatalf("expect workflow 'fake_workflow_ok' with 1 node but got %s\n", res)
+	}
+
+	server.Stop()
+	ctx.WG.Wait()
+}
+
+func TestGetWorkflow(t *testing.T) {
+	exportUISecret(fakeSecret)
+
+	server, port, ctx := createServer()
+	server.Start()
+	<-time.After(200 * time.Millisecond)
+
+	res, err := getReq(fmt.Sprintf("http://localhost:%d/api/v1/workflows/fake_workflow", port))
+	if e := expectFormatedError(res, err); e != nil {
+		t.Fatal(e)
+	}
+	if strings.Index(err.Error(), "404") == -1 {
+		t.Fatalf("expect '404' but got %s", err)
+	}
+
+	res, err = getReq(fmt.Sprintf("http://localhost:%d/api/v1/workflows/fake_workflow_ok", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := models.WorkflowStats{}
+	if err := json.Unmarshal(res, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.Stats.Status != "Running" {
+		t.Fatalf("expect workflow status 'Running' but got '%s'\n", obj.Stats.Status)
+	}
+
+	server.Stop()
+	ctx.WG.Wait()
+}
+
+func TestListJobs(t *testing.T) {
+	exportUISecret(fakeSecret)
+
+	server, port, ctx := createServer()
+	server.Start()
+	<-time.After(200 * time.Millisecond)
+
+	res, err := getReq(fmt.Sprintf("http://localhost:%d/api/v1/jobs?status=Unknown", port))
+	if e := expectFormatedError(res, err); e != nil {
+		t.Fatal(e)
+	}
+	if strings.Index(err.Error(), "400") == -1 {
+		t.Fatalf("expect '400' but got %s", err)
+	}
+
+	res, err = getReq(fmt.Sprintf("http://localhost:%d/api/v1/jobs?from=200&to=100", port))
+	if e := expectFormatedError(res, err); e != nil {
+		t.Fatal(e)
+	}
+
+	res, err = getReq(fmt.Sprintf("http://localhost:%d/api/v1/jobs?job_name=testing&status=Error&from=100&page_number=2&page_size=5", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := models.JobStatsList{}
+	if err := json.Unmarshal(res, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.Total != 1 || len(obj.Jobs) != 1 || obj.Jobs[0].JobName != "testing" {
+		t.Fatalf("expect 1 job of 'testing' but got %+v\n", obj)
+	}
+
+	server.Stop()
+	ctx.WG.Wait()
+}
+
 func expectFormatedError(data []byte, err error) error {
 	if err == nil {
 		return errors.New("expect error but got nil")
@@ -412,6 +520,22 @@ func (fc *fakeController) GetJob(jobID string) (models.JobStats, error) {
 	return createJobStats("testing", "Generic", ""), nil
 }
 
+func (fc *fakeController) ListJobs(query models.JobQuery) (models.JobStatsList, error) {
+	if query.JobName != "testing" ||
+		query.Status != "Error" ||
+		query.From != 100 ||
+		query.To != 0 ||
+		query.PageNumber != 2 ||
+		query.PageSize != 5 {
+		return models.JobStatsList{}, errors.New("failed")
+	}
+
+	return models.JobStatsList{
+		Total: 1,
+		Jobs:  []*models.JobStatData{createJobStats("testing", "Generic", "").Stats},
+	}, nil
+}
+
 func (fc *fakeController) StopJob(jobID string) error {
 	if jobID == "fake_job_ok" {
 		return nil
@@ -454,6 +578,45 @@ func (fc *fakeController) GetJobLogData(jobID string) ([]byte, error) {
 	return nil, errors.New("failed")
 }
 
+func (fc *fakeController) LaunchWorkflow(req models.WorkflowRequest) (models.WorkflowStats, error) {
+	if req.Workflow == nil || len(req.Workflow.Nodes) == 0 {
+		return models.WorkflowStats{}, errors.New("failed")
+	}
+
+	return createWorkflowStats(), nil
+}
+
+func (fc *fakeController) GetWorkflow(workflowID string) (models.WorkflowStats, error) {
+	if workflowID != "fake_workflow_ok" {
+		return models.WorkflowStats{}, errs.NoObjectFoundError(workflowID)
+	}
+
+	return createWorkflowStats(), nil
+}
+
+func createWorkflowStats() models.WorkflowStats {
+	now := time.Now().Unix()
+
+	return models.WorkflowStats{
+		Stats: &models.WorkflowStatData{
+			WorkflowID:  "fake_workflow_ok",
+			Name:        "fake_workflow",
+			Status:      "Running",
+			EnqueueTime: now,
+			UpdateTime:  now,
+			Nodes: []*models.WorkflowNodeStatData{
+				{
+					NodeID:    "fake_node",
+					JobName:   "fake_job_ok",
+					JobID:     "fake_ID_ok",
+					Status:    "Pending",
+					Condition: "success",
+				},
+			},
+		},
+	}
+}
+
 func createJobStats(name, kind, cron string) models.JobStats {
 	now := time.Now()
 
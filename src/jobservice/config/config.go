@@ -36,6 +36,7 @@ const (
 	jobServiceWorkers            = "JOB_SERVICE_POOL_WORKERS"
 	jobServiceRedisURL           = "JOB_SERVICE_POOL_REDIS_URL"
 	jobServiceRedisNamespace     = "JOB_SERVICE_POOL_REDIS_NAMESPACE"
+	jobServiceMemPersistenceFile = "JOB_SERVICE_POOL_MEMORY_PERSISTENCE_FILE"
 	jobServiceCoreServerEndpoint = "CORE_URL"
 	jobServiceAuthSecret         = "JOBSERVICE_SECRET"
 
@@ -46,12 +47,17 @@ const (
 
 	// JobServicePoolBackendRedis represents redis backend
 	JobServicePoolBackendRedis = "redis"
+	// JobServicePoolBackendMemory represents in-memory backend
+	JobServicePoolBackendMemory = "memory"
 
 	// secret of UI
 	uiAuthSecret = "CORE_SECRET"
 
 	// redis protocol schema
 	redisSchema = "redis://"
+
+	// max priority of job supported by the worker pool
+	maxJobPriority = 10000
 )
 
 // DefaultConfig is the default configuration reference
@@ -93,12 +99,30 @@ type RedisPoolConfig struct {
 	Namespace string `yaml:"namespace"`
 }
 
+// MemPoolConfig keeps in-memory pool info.
+type MemPoolConfig struct {
+	// File to persist the queued jobs and stats, empty means no persistence
+	PersistenceFile string `yaml:"persistence_file"`
+}
+
 // PoolConfig keeps worker pool configurations.
 type PoolConfig struct {
 	// Worker concurrency
 	WorkerCount  uint             `yaml:"workers"`
 	Backend      string           `yaml:"backend"`
 	RedisPoolCfg *RedisPoolConfig `yaml:"redis_pool,omitempty"`
+	MemPoolCfg   *MemPoolConfig   `yaml:"memory_pool,omitempty"`
+	// Priority and concurrency settings of jobs
+	JobConfigs []*JobConfig `yaml:"jobs,omitempty"`
+}
+
+// JobConfig keeps the priority and concurrency settings of the job with the name.
+type JobConfig struct {
+	Name string `yaml:"name"`
+	// Priority from 1 to 10000, the job with higher priority is more likely to be picked up
+	Priority uint `yaml:"priority"`
+	// Max number of the running jobs, 0 means no limit
+	MaxConcurrency uint `yaml:"max_concurrency"`
 }
 
 // CustomizedSettings keeps the customized settings of logger
@@ -177,6 +201,21 @@ func GetAdminServerEndpoint() string {
 	return DefaultConfig.AdminServer
 }
 
+// GetJobConfig returns the settings of the job with the specified name if it's configured
+func GetJobConfig(name string) *JobConfig {
+	if DefaultConfig.PoolConfig == nil {
+		return nil
+	}
+
+	for _, jobCfg := range DefaultConfig.PoolConfig.JobConfigs {
+		if jobCfg != nil && jobCfg.Name == name {
+			return jobCfg
+		}
+	}
+
+	return nil
+}
+
 // Load env variables
 func (c *Configuration) loadEnvs() {
 	prot := utils.ReadEnv(jobServiceProtocol)
@@ -252,6 +291,16 @@ func (c *Configuration) loadEnvs() {
 		}
 	}
 
+	if c.PoolConfig != nil && c.PoolConfig.Backend == JobServicePoolBackendMemory {
+		persistenceFile := utils.ReadEnv(jobServiceMemPersistenceFile)
+		if !utils.IsEmptyStr(persistenceFile) {
+			if c.PoolConfig.MemPoolCfg == nil {
+				c.PoolConfig.MemPoolCfg = &MemPoolConfig{}
+			}
+			c.PoolConfig.MemPoolCfg.PersistenceFile = persistenceFile
+		}
+	}
+
 	// admin server
 	if coreServer := utils.ReadEnv(jobServiceCoreServerEndpoint); !utils.IsEmptyStr(coreServer) {
 		c.AdminServer = coreServer
@@ -290,7 +339,8 @@ func (c *Configuration) validate() error {
 		return errors.New("no worker pool is configured")
 	}
 
-	if c.PoolConfig.Backend != JobServicePoolBackendRedis {
+	if c.PoolConfig.Backend != JobServicePoolBackendRedis &&
+		c.PoolConfig.Backend != JobServicePoolBackendMemory {
 		return fmt.Errorf("worker pool backend %s does not support", c.PoolConfig.Backend)
 	}
 
@@ -316,6 +366,21 @@ func (c *Configuration) validate() error {
 		}
 	}
 
+	jobNames := make(map[string]bool)
+	for _, jobCfg := range c.PoolConfig.JobConfigs {
+		if jobCfg == nil || utils.IsEmptyStr(jobCfg.Name) {
+			return errors.New("name of job must be specified in the job settings")
+		}
+		if jobNames[jobCfg.Name] {
+			return fmt.Errorf("job %s is configured more than once", jobCfg.Name)
+		}
+		jobNames[jobCfg.Name] = true
+
+		if jobCfg.Priority > maxJobPriority {
+			return fmt.Errorf("priority of job %s should be less or equal %d, but current is %d", jobCfg.Name, maxJobPriority, jobCfg.Priority)
+		}
+	}
+
 	// Job service loggers
 	if len(c.LoggerConfigs) == 0 {
 		return errors.New("missing logger config of job service")
@@ -110,6 +110,69 @@ func TestDefaultConfig(t *testing.T) {
 	if theLogger.Sweeper.Settings["work_dir"] != "/tmp/job_logs" {
 		t.Errorf("expect work dir of sweeper of FILE logger to be '/tmp/job_logs' but got %s", theLogger.Sweeper.Settings["work_dir"])
 	}
+
+	if len(DefaultConfig.PoolConfig.JobConfigs) != 2 {
+		t.Fatalf("expect 2 job settings but got %d", len(DefaultConfig.PoolConfig.JobConfigs))
+	}
+	jobCfg := GetJobConfig("IMAGE_SCAN")
+	if jobCfg == nil {
+		t.Fatal("expect settings of job IMAGE_SCAN but got nil")
+	}
+	if jobCfg.Priority != 1 || jobCfg.MaxConcurrency != 2 {
+		t.Errorf("expect priority 1 and max concurrency 2 of job IMAGE_SCAN but got %d and %d", jobCfg.Priority, jobCfg.MaxConcurrency)
+	}
+	if GetJobConfig("IMAGE_GC") != nil {
+		t.Error("expect no settings of job IMAGE_GC")
+	}
+}
+
+func TestInvalidJobConfigs(t *testing.T) {
+	cfg := &Configuration{}
+	if err := cfg.Load("../config_test.yml", false); err != nil {
+		t.Fatalf("Load config from yaml file, expect nil error but got error '%s'\n", err)
+	}
+
+	cfg.PoolConfig.JobConfigs[0].Priority = 10001
+	if err := cfg.validate(); err == nil {
+		t.Error("expect error of invalid priority but got nil")
+	}
+
+	cfg.PoolConfig.JobConfigs[0].Priority = 1
+	cfg.PoolConfig.JobConfigs[1].Name = cfg.PoolConfig.JobConfigs[0].Name
+	if err := cfg.validate(); err == nil {
+		t.Error("expect error of duplicated job settings but got nil")
+	}
+
+	cfg.PoolConfig.JobConfigs[1].Name = ""
+	if err := cfg.validate(); err == nil {
+		t.Error("expect error of empty job name but got nil")
+	}
+}
+
+func TestConfigLoadingMemoryBackend(t *testing.T) {
+	os.Setenv("JOB_SERVICE_POOL_BACKEND", "memory")
+	os.Setenv("JOB_SERVICE_POOL_MEMORY_PERSISTENCE_FILE", "/tmp/ut_job_service_pool.json")
+	defer func() {
+		os.Unsetenv("JOB_SERVICE_POOL_BACKEND")
+		os.Unsetenv("JOB_SERVICE_POOL_MEMORY_PERSISTENCE_FILE")
+	}()
+
+	cfg := &Configuration{}
+	if err := cfg.Load("../config_test.yml", true); err != nil {
+		t.Fatalf("Load config from yaml file, expect nil error but got error '%s'\n", err)
+	}
+
+	if cfg.PoolConfig.Backend != JobServicePoolBackendMemory {
+		t.Errorf("expect backend '%s' but got '%s'\n", JobServicePoolBackendMemory, cfg.PoolConfig.Backend)
+	}
+	if cfg.PoolConfig.MemPoolCfg == nil || cfg.PoolConfig.MemPoolCfg.PersistenceFile != "/tmp/ut_job_service_pool.json" {
+		t.Errorf("expect persistence file '/tmp/ut_job_service_pool.json' but got %v\n", cfg.PoolConfig.MemPoolCfg)
+	}
+
+	cfg.PoolConfig.Backend = "kafka"
+	if err := cfg.validate(); err == nil {
+		t.Error("expect error of unsupported backend but got nil")
+	}
 }
 
 func setENV() {
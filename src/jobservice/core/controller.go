@@ -30,6 +30,9 @@ import (
 const (
 	hookActivated   = "activated"
 	hookDeactivated = "error"
+
+	defaultJobsPageSize = 20
+	maxJobsPageSize     = 500
 )
 
 // Controller implement the core interface and provides related job handle methods.
@@ -50,6 +53,11 @@ func NewController(backendPool pool.Interface) *Controller {
 
 // LaunchJob is implementation of same method in core interface.
 func (c *Controller) LaunchJob(req models.JobRequest) (models.JobStats, error) {
+	return c.launchJob(req, nil)
+}
+
+// launchJob launches the job with the extra parameters which are not validated by the job.
+func (c *Controller) launchJob(req models.JobRequest, extraParams models.Parameters) (models.JobStats, error) {
 	if err := validJobReq(req); err != nil {
 		return models.JobStats{}, err
 	}
@@ -66,6 +74,17 @@ func (c *Controller) LaunchJob(req models.JobRequest) (models.JobStats, error) {
 		return models.JobStats{}, err
 	}
 
+	params := req.Job.Parameters
+	if len(extraParams) > 0 {
+		params = make(models.Parameters, len(req.Job.Parameters)+len(extraParams))
+		for k, v := range req.Job.Parameters {
+			params[k] = v
+		}
+		for k, v := range extraParams {
+			params[k] = v
+		}
+	}
+
 	// Enqueue job regarding of the kind
 	var (
 		res models.JobStats
@@ -76,17 +95,17 @@ func (c *Controller) LaunchJob(req models.JobRequest) (models.JobStats, error) {
 	case job.JobKindScheduled:
 		res, err = c.backendPool.Schedule(
 			req.Job.Name,
-			req.Job.Parameters,
+			params,
 			req.Job.Metadata.ScheduleDelay,
 			req.Job.Metadata.IsUnique)
 	case job.JobKindPeriodic:
 		res, err = c.backendPool.PeriodicallyEnqueue(
 			req.Job.Name,
-			req.Job.Parameters,
+			params,
 			req.Job.Metadata.Cron)
 	default:
 		// 默认工作类型为 Generic，镜像扫描任务就是属于此种类型
-		res, err = c.backendPool.Enqueue(req.Job.Name, req.Job.Parameters, req.Job.Metadata.IsUnique)
+		res, err = c.backendPool.Enqueue(req.Job.Name, params, req.Job.Metadata.IsUnique)
 	}
 
 	// Register status hook?
@@ -114,6 +133,21 @@ func (c *Controller)  GetJob(jobID string) (models.JobStats, error) {
 	return c.backendPool.GetJobStats(jobID)
 }
 
+// ListJobs is implementation of same method in core interface.
+func (c *Controller) ListJobs(query models.JobQuery) (models.JobStatsList, error) {
+	if query.PageNumber == 0 {
+		query.PageNumber = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = defaultJobsPageSize
+	}
+	if query.PageSize > maxJobsPageSize {
+		query.PageSize = maxJobsPageSize
+	}
+
+	return c.backendPool.ListJobs(query)
+}
+
 // StopJob is implementation of same method in core interface.
 func (c *Controller) StopJob(jobID string) error {
 	if utils.IsEmptyStr(jobID) {
@@ -177,6 +211,12 @@ func validJobReq(req models.JobRequest) error {
 		return errors.New("metadata of job is missing")
 	}
 
+	for _, key := range []string{job.ParamKeyWorkflowID, job.ParamKeyWorkflowNode} {
+		if _, ok := req.Job.Parameters[key]; ok {
+			return fmt.Errorf("parameter '%s' is reserved", key)
+		}
+	}
+
 	if req.Job.Metadata.JobKind != job.JobKindGeneric &&
 		req.Job.Metadata.JobKind != job.JobKindPeriodic &&
 		req.Job.Metadata.JobKind != job.JobKindScheduled {
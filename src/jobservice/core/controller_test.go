@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/models"
 )
 
@@ -118,6 +120,29 @@ func TestGetJobStats(t *testing.T) {
 	}
 }
 
+func TestListJobs(t *testing.T) {
+	pool := &fakePool{}
+	c := NewController(pool)
+
+	res, err := c.ListJobs(models.JobQuery{PageSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 {
+		t.Fatalf("expect 1 job but got %d", res.Total)
+	}
+	if pool.listed.PageNumber != 1 || pool.listed.PageSize != maxJobsPageSize {
+		t.Fatalf("expect page 1 with size %d but got %d with size %d", maxJobsPageSize, pool.listed.PageNumber, pool.listed.PageSize)
+	}
+
+	if _, err := c.ListJobs(models.JobQuery{PageNumber: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if pool.listed.PageNumber != 2 || pool.listed.PageSize != defaultJobsPageSize {
+		t.Fatalf("expect page 2 with size %d but got %d with size %d", defaultJobsPageSize, pool.listed.PageNumber, pool.listed.PageSize)
+	}
+}
+
 func TestJobActions(t *testing.T) {
 	pool := &fakePool{}
 	c := NewController(pool)
@@ -196,6 +221,156 @@ func TestInvalidCheck(t *testing.T) {
 	}
 }
 
+func TestLaunchWorkflow(t *testing.T) {
+	pool := &fakePool{}
+	c := NewController(pool)
+
+	req := createWorkflowReq()
+	res, err := c.LaunchWorkflow(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workflowID := res.Stats.WorkflowID
+	if res.Stats.Status != job.JobStatusRunning {
+		t.Fatalf("expect workflow status '%s' but got '%s'\n", job.JobStatusRunning, res.Stats.Status)
+	}
+	if len(pool.enqueued) != 1 {
+		t.Fatalf("expect only the root node launched but got %d jobs\n", len(pool.enqueued))
+	}
+	if pool.enqueued[0][job.ParamKeyWorkflowID] != workflowID || pool.enqueued[0][job.ParamKeyWorkflowNode] != "replicate" {
+		t.Fatalf("expect workflow binding in job parameters but got %v\n", pool.enqueued[0])
+	}
+	assertNodeStatus(t, res, "replicate", "running")
+	assertNodeStatus(t, res, "scan", job.JobStatusWaiting)
+
+	if err := c.OnWorkflowNodeDone(workflowID, "replicate", job.JobStatusSuccess); err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.enqueued) != 2 {
+		t.Fatalf("expect 2 launched jobs but got %d\n", len(pool.enqueued))
+	}
+	res, err = c.GetWorkflow(workflowID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNodeStatus(t, res, "scan", "running")
+	assertNodeStatus(t, res, "rollback", job.JobStatusSkipped)
+	assertNodeStatus(t, res, "notify", job.JobStatusWaiting)
+
+	if err := c.OnWorkflowNodeDone(workflowID, "scan", job.JobStatusError); err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.enqueued) != 3 {
+		t.Fatalf("expect 3 launched jobs but got %d\n", len(pool.enqueued))
+	}
+
+	if err := c.OnWorkflowNodeDone(workflowID, "notify", job.JobStatusSuccess); err != nil {
+		t.Fatal(err)
+	}
+	res, err = c.GetWorkflow(workflowID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stats.Status != job.JobStatusError {
+		t.Fatalf("expect workflow status '%s' but got '%s'\n", job.JobStatusError, res.Stats.Status)
+	}
+}
+
+func TestGetWorkflowNotFound(t *testing.T) {
+	pool := &fakePool{}
+	c := NewController(pool)
+
+	if _, err := c.GetWorkflow("not_existing"); !errs.IsObjectNotFoundError(err) {
+		t.Fatalf("expect object not found error but got %v", err)
+	}
+}
+
+func TestInvalidWorkflow(t *testing.T) {
+	pool := &fakePool{}
+	c := NewController(pool)
+
+	if _, err := c.LaunchWorkflow(models.WorkflowRequest{}); err == nil {
+		t.Fatal("error expected but got nil")
+	}
+
+	req := createWorkflowReq()
+	req.Workflow.Nodes[0].Upstreams = []string{"notify"}
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("cycle error expected but got nil")
+	}
+
+	req = createWorkflowReq()
+	req.Workflow.Nodes[1].Upstreams = []string{"unknown"}
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("unknown upstream error expected but got nil")
+	}
+
+	req = createWorkflowReq()
+	req.Workflow.Nodes[1].ID = "replicate"
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("duplicated node error expected but got nil")
+	}
+
+	req = createWorkflowReq()
+	req.Workflow.Nodes[1].Condition = "sometimes"
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("condition error expected but got nil")
+	}
+
+	req = createWorkflowReq()
+	req.Workflow.Nodes[1].Job.Metadata.JobKind = job.JobKindPeriodic
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("job kind error expected but got nil")
+	}
+
+	req = createWorkflowReq()
+	req.Workflow.Nodes[1].Job.Parameters[job.ParamKeyWorkflowID] = "fake"
+	if _, err := c.LaunchWorkflow(req); err == nil {
+		t.Fatal("reserved parameter error expected but got nil")
+	}
+
+	if len(pool.enqueued) != 0 {
+		t.Fatalf("expect no launched jobs but got %d\n", len(pool.enqueued))
+	}
+}
+
+func createWorkflowReq() models.WorkflowRequest {
+	node := func(id string, condition string, upstreams ...string) *models.WorkflowNode {
+		return &models.WorkflowNode{
+			ID:        id,
+			Job:       createJobReq(job.JobKindGeneric, false, false).Job,
+			Upstreams: upstreams,
+			Condition: condition,
+		}
+	}
+
+	return models.WorkflowRequest{
+		Workflow: &models.WorkflowData{
+			Name: "replicate_then_scan",
+			Nodes: []*models.WorkflowNode{
+				node("replicate", ""),
+				node("scan", job.WorkflowConditionSuccess, "replicate"),
+				node("rollback", job.WorkflowConditionFailure, "replicate"),
+				node("notify", job.WorkflowConditionAlways, "scan", "rollback"),
+			},
+		},
+	}
+}
+
+func assertNodeStatus(t *testing.T, stats models.WorkflowStats, nodeID string, status string) {
+	for _, node := range stats.Stats.Nodes {
+		if node.NodeID == nodeID {
+			if node.Status != status {
+				t.Fatalf("expect status '%s' of node '%s' but got '%s'\n", status, nodeID, node.Status)
+			}
+			return
+		}
+	}
+
+	t.Fatalf("node '%s' not found\n", nodeID)
+}
+
 func createJobReq(kind string, isUnique bool, withHook bool) models.JobRequest {
 	params := make(map[string]interface{})
 	params["name"] = "testing"
@@ -219,7 +394,12 @@ func createJobReq(kind string, isUnique bool, withHook bool) models.JobRequest {
 	return req
 }
 
-type fakePool struct{}
+type fakePool struct {
+	enqueued []models.Parameters
+	listed   models.JobQuery
+	workflow *models.WorkflowStats
+	claimed  map[string]bool
+}
 
 func (f *fakePool) Start() error {
 	return nil
@@ -234,6 +414,7 @@ func (f *fakePool) RegisterJobs(jobs map[string]interface{}) error {
 }
 
 func (f *fakePool) Enqueue(jobName string, params models.Parameters, isUnique bool) (models.JobStats, error) {
+	f.enqueued = append(f.enqueued, params)
 	return models.JobStats{
 		Stats: &models.JobStatData{
 			JobID: "fake_ID",
@@ -284,6 +465,20 @@ func (f *fakePool) GetJobStats(jobID string) (models.JobStats, error) {
 	}, nil
 }
 
+func (f *fakePool) ListJobs(query models.JobQuery) (models.JobStatsList, error) {
+	f.listed = query
+
+	return models.JobStatsList{
+		Total: 1,
+		Jobs: []*models.JobStatData{
+			{
+				JobID:  "fake_ID",
+				Status: "running",
+			},
+		},
+	}, nil
+}
+
 func (f *fakePool) StopJob(jobID string) error {
 	return nil
 }
@@ -323,3 +518,66 @@ func (j *fakeJob) Validate(params map[string]interface{}) error {
 func (j *fakeJob) Run(ctx env.JobContext, params map[string]interface{}) error {
 	return nil
 }
+
+func (f *fakePool) SaveWorkflow(stats models.WorkflowStats) error {
+	saved := *stats.Stats
+	for _, node := range saved.Definition.Nodes {
+		saved.Nodes = append(saved.Nodes, &models.WorkflowNodeStatData{
+			NodeID:    node.ID,
+			JobName:   node.Job.Name,
+			Upstreams: node.Upstreams,
+			Condition: node.Condition,
+		})
+	}
+	f.workflow = &models.WorkflowStats{Stats: &saved}
+	f.claimed = make(map[string]bool)
+
+	return nil
+}
+
+func (f *fakePool) GetWorkflowStats(workflowID string) (models.WorkflowStats, error) {
+	if f.workflow == nil || f.workflow.Stats.WorkflowID != workflowID {
+		return models.WorkflowStats{}, errs.NoObjectFoundError(workflowID)
+	}
+
+	stats := *f.workflow.Stats
+	stats.Nodes = nil
+	for _, node := range f.workflow.Stats.Nodes {
+		copied := *node
+		stats.Nodes = append(stats.Nodes, &copied)
+	}
+
+	return models.WorkflowStats{Stats: &stats}, nil
+}
+
+func (f *fakePool) ClaimWorkflowNode(workflowID, nodeID string) (bool, error) {
+	if f.claimed[nodeID] {
+		return false, nil
+	}
+	f.claimed[nodeID] = true
+
+	return true, nil
+}
+
+func (f *fakePool) SetWorkflowNodeJob(workflowID, nodeID, jobID string) error {
+	return f.updateWorkflowNode(nodeID, func(node *models.WorkflowNodeStatData) {
+		node.JobID = jobID
+	})
+}
+
+func (f *fakePool) SetWorkflowNodeStatus(workflowID, nodeID, status string) error {
+	return f.updateWorkflowNode(nodeID, func(node *models.WorkflowNodeStatData) {
+		node.Status = status
+	})
+}
+
+func (f *fakePool) updateWorkflowNode(nodeID string, update func(node *models.WorkflowNodeStatData)) error {
+	for _, node := range f.workflow.Stats.Nodes {
+		if node.NodeID == nodeID {
+			update(node)
+			return nil
+		}
+	}
+
+	return errs.NoObjectFoundError(nodeID)
+}
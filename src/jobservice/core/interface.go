@@ -41,6 +41,15 @@ type Interface interface {
 	//  error   : Error returned if failed to get the specified job.
 	GetJob(jobID string) (models.JobStats, error)
 
+	// ListJobs is used to handle the job listing request.
+	//
+	// query	JobQuery: Conditions and page of the listing, the default page is used if not set.
+	//
+	// Returns:
+	//	JobStatsList: Total count and the page of the matched jobs.
+	//  error       : Error returned if failed to list the jobs.
+	ListJobs(query models.JobQuery) (models.JobStatsList, error)
+
 	// StopJob is used to handle the job stopping request.
 	//
 	// jobID	string: ID of job.
@@ -70,4 +79,22 @@ type Interface interface {
 
 	// GetJobLogData is used to return the log text data for the specified job if exists
 	 GetJobLogData(jobID string) ([]byte, error)
+
+	// LaunchWorkflow is used to handle the workflow submission request.
+	//
+	// req	WorkflowRequest : Workflow request contains the nodes and their dependencies.
+	//
+	// Returns:
+	//	WorkflowStats: Workflow status info with ID returned if workflow is successfully launched.
+	//  error        : Error returned if failed to launch the specified workflow.
+	LaunchWorkflow(req models.WorkflowRequest) (models.WorkflowStats, error)
+
+	// GetWorkflow is used to handle the workflow stats query request.
+	//
+	// workflowID	string: ID of workflow.
+	//
+	// Returns:
+	//	WorkflowStats: Workflow status info with the status of each node if workflow exists.
+	//  error        : Error returned if failed to get the specified workflow.
+	GetWorkflow(workflowID string) (models.WorkflowStats, error)
 }
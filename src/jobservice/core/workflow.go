@@ -0,0 +1,311 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+)
+
+// LaunchWorkflow is implementation of same method in core interface.
+// Only the root nodes are launched here, the downstream nodes are launched
+// when their upstream nodes are done.
+func (c *Controller) LaunchWorkflow(req models.WorkflowRequest) (models.WorkflowStats, error) {
+	if err := c.validWorkflowReq(req); err != nil {
+		return models.WorkflowStats{}, err
+	}
+
+	for _, node := range req.Workflow.Nodes {
+		if utils.IsEmptyStr(node.Condition) {
+			node.Condition = job.WorkflowConditionSuccess
+		}
+	}
+
+	now := time.Now().Unix()
+	stats := models.WorkflowStats{
+		Stats: &models.WorkflowStatData{
+			WorkflowID:  utils.MakeIdentifier(),
+			Name:        req.Workflow.Name,
+			EnqueueTime: now,
+			UpdateTime:  now,
+			Definition:  req.Workflow,
+		},
+	}
+	if err := c.backendPool.SaveWorkflow(stats); err != nil {
+		return models.WorkflowStats{}, err
+	}
+
+	for _, node := range req.Workflow.Nodes {
+		if len(node.Upstreams) == 0 {
+			c.launchWorkflowNode(stats.Stats.WorkflowID, node)
+		}
+	}
+
+	return c.GetWorkflow(stats.Stats.WorkflowID)
+}
+
+// GetWorkflow is implementation of same method in core interface.
+func (c *Controller) GetWorkflow(workflowID string) (models.WorkflowStats, error) {
+	if utils.IsEmptyStr(workflowID) {
+		return models.WorkflowStats{}, errors.New("empty workflow ID")
+	}
+
+	stats, err := c.backendPool.GetWorkflowStats(workflowID)
+	if err != nil {
+		return models.WorkflowStats{}, err
+	}
+
+	// Only the final status is kept with the node,
+	// the status of the running node comes from its job.
+	for _, node := range stats.Stats.Nodes {
+		if !utils.IsEmptyStr(node.Status) {
+			continue
+		}
+
+		if utils.IsEmptyStr(node.JobID) {
+			node.Status = job.JobStatusWaiting
+			continue
+		}
+
+		node.Status = job.JobStatusPending
+		if jobStats, err := c.backendPool.GetJobStats(node.JobID); err == nil {
+			node.Status = jobStats.Stats.Status
+		}
+	}
+
+	stats.Stats.Status = workflowStatus(stats.Stats.Nodes)
+
+	return stats, nil
+}
+
+// OnWorkflowNodeDone records the final status of the workflow node and then
+// launches or skips the downstream nodes whose upstream nodes are all done.
+func (c *Controller) OnWorkflowNodeDone(workflowID, nodeID, status string) error {
+	if utils.IsEmptyStr(workflowID) || utils.IsEmptyStr(nodeID) {
+		return errors.New("empty workflow ID or node ID")
+	}
+
+	if err := c.backendPool.SetWorkflowNodeStatus(workflowID, nodeID, status); err != nil {
+		return err
+	}
+
+	stats, err := c.backendPool.GetWorkflowStats(workflowID)
+	if err != nil {
+		return err
+	}
+
+	finalStatus := make(map[string]string)
+	launched := make(map[string]bool)
+	for _, node := range stats.Stats.Nodes {
+		if !utils.IsEmptyStr(node.Status) {
+			finalStatus[node.NodeID] = node.Status
+		}
+		launched[node.NodeID] = !utils.IsEmptyStr(node.JobID)
+	}
+
+	// Skipping a node may unblock its downstream nodes, keep going until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, node := range stats.Stats.Definition.Nodes {
+			if _, ok := finalStatus[node.ID]; ok || launched[node.ID] || len(node.Upstreams) == 0 {
+				continue
+			}
+
+			upstreams := make([]string, 0, len(node.Upstreams))
+			for _, upstream := range node.Upstreams {
+				if s, ok := finalStatus[upstream]; ok {
+					upstreams = append(upstreams, s)
+				}
+			}
+			if len(upstreams) < len(node.Upstreams) {
+				continue // still waiting
+			}
+
+			if conditionMet(node.Condition, upstreams) {
+				launched[node.ID] = true
+				c.launchWorkflowNode(workflowID, node)
+				continue
+			}
+
+			if err := c.backendPool.SetWorkflowNodeStatus(workflowID, node.ID, job.JobStatusSkipped); err != nil {
+				return err
+			}
+			finalStatus[node.ID] = job.JobStatusSkipped
+			changed = true
+		}
+	}
+
+	return nil
+}
+
+// launchWorkflowNode launches the job of the node if it's not claimed yet.
+func (c *Controller) launchWorkflowNode(workflowID string, node *models.WorkflowNode) {
+	claimed, err := c.backendPool.ClaimWorkflowNode(workflowID, node.ID)
+	if err != nil {
+		logger.Errorf("Claim node '%s' of workflow '%s' error: %s", node.ID, workflowID, err)
+		return
+	}
+	if !claimed {
+		return // launched by others
+	}
+
+	res, err := c.launchJob(models.JobRequest{Job: node.Job}, models.Parameters{
+		job.ParamKeyWorkflowID:   workflowID,
+		job.ParamKeyWorkflowNode: node.ID,
+	})
+	if err != nil {
+		logger.Errorf("Launch node '%s' of workflow '%s' error: %s", node.ID, workflowID, err)
+		// Fail the node to let the downstream nodes move on
+		if err := c.OnWorkflowNodeDone(workflowID, node.ID, job.JobStatusError); err != nil {
+			logger.Errorf("Advance workflow '%s' error: %s", workflowID, err)
+		}
+		return
+	}
+
+	if err := c.backendPool.SetWorkflowNodeJob(workflowID, node.ID, res.Stats.JobID); err != nil {
+		logger.Errorf("Bind job '%s' to node '%s' of workflow '%s' error: %s", res.Stats.JobID, node.ID, workflowID, err)
+	}
+}
+
+func (c *Controller) validWorkflowReq(req models.WorkflowRequest) error {
+	if req.Workflow == nil || len(req.Workflow.Nodes) == 0 {
+		return errors.New("workflow without nodes is not allowed")
+	}
+
+	nodes := make(map[string]*models.WorkflowNode, len(req.Workflow.Nodes))
+	for _, node := range req.Workflow.Nodes {
+		if node == nil || utils.IsEmptyStr(node.ID) {
+			return errors.New("ID of workflow node must be specified")
+		}
+		if _, ok := nodes[node.ID]; ok {
+			return fmt.Errorf("duplicated workflow node '%s'", node.ID)
+		}
+		nodes[node.ID] = node
+
+		if err := validJobReq(models.JobRequest{Job: node.Job}); err != nil {
+			return fmt.Errorf("workflow node '%s': %s", node.ID, err)
+		}
+		if node.Job.Metadata.JobKind != job.JobKindGeneric {
+			return fmt.Errorf("workflow node '%s': only job kind '%s' is supported", node.ID, job.JobKindGeneric)
+		}
+
+		jobType, isKnownJob := c.backendPool.IsKnownJob(node.Job.Name)
+		if !isKnownJob {
+			return fmt.Errorf("workflow node '%s': job with name '%s' is unknown", node.ID, node.Job.Name)
+		}
+		if err := c.backendPool.ValidateJobParameters(jobType, node.Job.Parameters); err != nil {
+			return fmt.Errorf("workflow node '%s': %s", node.ID, err)
+		}
+
+		switch node.Condition {
+		case "", job.WorkflowConditionSuccess, job.WorkflowConditionFailure, job.WorkflowConditionAlways:
+		default:
+			return fmt.Errorf(
+				"workflow node '%s': condition '%s' is not supported, only support '%s','%s','%s'",
+				node.ID,
+				node.Condition,
+				job.WorkflowConditionSuccess,
+				job.WorkflowConditionFailure,
+				job.WorkflowConditionAlways)
+		}
+	}
+
+	// Check the upstream nodes and make sure there is no cycle
+	inDegrees := make(map[string]int, len(nodes))
+	downstreams := make(map[string][]string, len(nodes))
+	for _, node := range req.Workflow.Nodes {
+		for _, upstream := range node.Upstreams {
+			if _, ok := nodes[upstream]; !ok {
+				return fmt.Errorf("workflow node '%s': upstream node '%s' does not exist", node.ID, upstream)
+			}
+			downstreams[upstream] = append(downstreams[upstream], node.ID)
+		}
+		inDegrees[node.ID] = len(node.Upstreams)
+	}
+
+	queue := []string{}
+	for id, degree := range inDegrees {
+		if degree == 0 {
+			queue = append(queue, id)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, downstream := range downstreams[id] {
+			inDegrees[downstream]--
+			if inDegrees[downstream] == 0 {
+				queue = append(queue, downstream)
+			}
+		}
+	}
+	if visited < len(nodes) {
+		return errors.New("cycle exists in the workflow")
+	}
+
+	return nil
+}
+
+// conditionMet checks the condition of node with the final status of its upstream nodes.
+func conditionMet(condition string, upstreams []string) bool {
+	switch condition {
+	case job.WorkflowConditionAlways:
+		return true
+	case job.WorkflowConditionFailure:
+		for _, status := range upstreams {
+			if isFailedStatus(status) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, status := range upstreams {
+			if status != job.JobStatusSuccess {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// workflowStatus summarizes the status of workflow with the status of its nodes.
+func workflowStatus(nodes []*models.WorkflowNodeStatData) string {
+	status := job.JobStatusSuccess
+	for _, node := range nodes {
+		switch {
+		case node.Status == job.JobStatusSuccess || node.Status == job.JobStatusSkipped:
+		case isFailedStatus(node.Status):
+			status = job.JobStatusError
+		default:
+			return job.JobStatusRunning
+		}
+	}
+
+	return status
+}
+
+func isFailedStatus(status string) bool {
+	return status == job.JobStatusError ||
+		status == job.JobStatusStopped ||
+		status == job.JobStatusCancelled
+}
@@ -53,6 +53,12 @@ const (
 	UnAuthorizedErrorCode
 	// ResourceConflictsErrorCode is code for the error of resource conflicting
 	ResourceConflictsErrorCode
+	// LaunchWorkflowErrorCode is code for the error of launching workflow
+	LaunchWorkflowErrorCode
+	// GetWorkflowStatsErrorCode is code for the error of getting stats of workflow
+	GetWorkflowStatsErrorCode
+	// ListJobsErrorCode is code for the error of listing jobs
+	ListJobsErrorCode
 )
 
 // baseError ...
@@ -135,6 +141,21 @@ func GetJobLogError(err error) error {
 	return New(GetJobLogErrorCode, "Failed to get the job log", err.Error())
 }
 
+// LaunchWorkflowError is error wrapper for the error of launching workflow failed.
+func LaunchWorkflowError(err error) error {
+	return New(LaunchWorkflowErrorCode, "Launch workflow failed with error", err.Error())
+}
+
+// GetWorkflowStatsError is error wrapper for the error of getting workflow stats failed.
+func GetWorkflowStatsError(err error) error {
+	return New(GetWorkflowStatsErrorCode, "Get workflow stats failed with error", err.Error())
+}
+
+// ListJobsError is error wrapper for the error of listing jobs failed.
+func ListJobsError(err error) error {
+	return New(ListJobsErrorCode, "List jobs failed with error", err.Error())
+}
+
 // UnauthorizedError is error for the case of unauthorized accessing
 func UnauthorizedError(err error) error {
 	return New(UnAuthorizedErrorCode, "Unauthorized", err.Error())
@@ -251,13 +251,13 @@ func setLoggers(setter func(lg logger.Interface), jobID string) error {
 	lOptions := []logger.Option{}
 	for _, lc := range config.DefaultConfig.JobLoggerConfigs {
 		// For running job, the depth should be 5
-		if lc.Name == logger.LoggerNameFile || lc.Name == logger.LoggerNameStdOutput || lc.Name == logger.LoggerNameDB {
+		if lc.Name == logger.LoggerNameFile || lc.Name == logger.LoggerNameStdOutput || lc.Name == logger.LoggerNameDB || lc.Name == logger.LoggerNameRemote {
 			if lc.Settings == nil {
 				lc.Settings = map[string]interface{}{}
 			}
 			lc.Settings["depth"] = 5
 		}
-		if lc.Name == logger.LoggerNameFile || lc.Name == logger.LoggerNameDB {
+		if lc.Name == logger.LoggerNameFile || lc.Name == logger.LoggerNameDB || lc.Name == logger.LoggerNameRemote {
 			// Need extra param
 			fSettings := map[string]interface{}{}
 			for k, v := range lc.Settings {
@@ -268,8 +268,8 @@ func setLoggers(setter func(lg logger.Interface), jobID string) error {
 				// Append file name param
 				fSettings["filename"] = fmt.Sprintf("%s.log", jobID)
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
-			} else { // DB Logger
-				// Append DB key
+			} else { // DB or remote logger
+				// Append job ID as the key
 				fSettings["key"] = jobID
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
 			}
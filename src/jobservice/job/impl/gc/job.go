@@ -15,19 +15,25 @@
 package gc
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/garyburd/redigo/redis"
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
 	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/registryctl"
 	"github.com/goharbor/harbor/src/common/utils"
 	reg "github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/jobservice/env"
+	js_utils "github.com/goharbor/harbor/src/jobservice/job/impl/utils"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/registryctl/client"
 )
@@ -48,6 +54,14 @@ type GarbageCollector struct {
 	CoreURL           string
 	insecure          bool
 	redisURL          string
+	unlink            bool
+	dryRun            bool
+	deleteUntagged    bool
+	purgeOrphans      bool
+	adminJobID        int64
+	registryURL       string
+	tokenServiceURL   string
+	secret            string
 }
 
 // MaxFails implements the interface in job/Interface
@@ -62,6 +76,22 @@ func (gc *GarbageCollector) ShouldRetry() bool {
 
 // Validate implements the interface in job/Interface
 func (gc *GarbageCollector) Validate(params map[string]interface{}) error {
+	for _, key := range []string{"unlink", "dry_run", "delete_untagged", "purge_orphans"} {
+		if v, ok := params[key]; ok {
+			if _, ok := v.(bool); !ok {
+				return fmt.Errorf("invalid %s parameter: %v", key, v)
+			}
+		}
+	}
+	unlink, _ := params["unlink"].(bool)
+	// the garbage collection of registry has no dry run, only the pre-pass reports what would be deleted
+	if dryRun, _ := params["dry_run"].(bool); dryRun && !unlink {
+		return errors.New("dry run is only supported with the unlink pre-pass")
+	}
+	// the bundled registry doesn't support deleting the untagged manifests
+	if deleteUntagged, _ := params["delete_untagged"].(bool); deleteUntagged && !unlink {
+		return errors.New("deleting untagged manifests is only supported with the unlink pre-pass")
+	}
 	return nil
 }
 
@@ -70,6 +100,9 @@ func (gc *GarbageCollector) Run(ctx env.JobContext, params map[string]interface{
 	if err := gc.init(ctx, params); err != nil {
 		return err
 	}
+	if gc.dryRun {
+		return gc.runDryRun()
+	}
 	readOnlyCur, err := gc.getReadOnly()
 	if err != nil {
 		return err
@@ -84,16 +117,51 @@ func (gc *GarbageCollector) Run(ctx env.JobContext, params map[string]interface{
 		gc.logger.Errorf("failed to start gc as registry controller is unreachable: %v", err)
 		return err
 	}
-	gc.logger.Infof("start to run gc in job.")
+	gc.logger.Infof("start to run gc in job, unlink: %t, delete untagged: %t, purge orphans: %t.", gc.unlink, gc.deleteUntagged, gc.purgeOrphans)
+	report := &models.GCReport{
+		StartTime: time.Now(),
+	}
+	// the sizes are collected before the records are deleted by the purging and the pre-pass
+	sizes, err := blobSizes()
+	if err != nil {
+		gc.logger.Errorf("failed to get the sizes of the blobs: %v", err)
+		return err
+	}
+	// purge the orphan artifacts before the gc of registry, so that their blobs can be deleted in this run
+	if gc.purgeOrphans {
+		if err := gc.purgeOrphanArtifacts(report); err != nil {
+			return err
+		}
+	}
+	var u *unlinker
+	if gc.unlink {
+		u = gc.newUnlinker()
+		if err := u.unlink(report); err != nil {
+			gc.logger.Errorf("failed to unlink the unreferenced blobs: %v", err)
+			return err
+		}
+	}
 	gcr, err := gc.registryCtlClient.StartGC()
 	if err != nil {
 		gc.logger.Errorf("failed to get gc result: %v", err)
 		return err
 	}
+	if u != nil {
+		u.cleanRecords()
+	}
 	if err := gc.cleanCache(); err != nil {
 		return err
 	}
+	gc.refreshUsages()
 	gc.logger.Infof("GC results: status: %t, message: %s, start: %s, end: %s.", gcr.Status, gcr.Msg, gcr.StartTime, gcr.EndTime)
+	report.BlobsDeleted = gcr.BlobsDeleted
+	report.BytesFreed = gc.freedBytes(sizes, gcr.DeletedBlobs)
+	report.ManifestsDeleted += gcr.ManifestsDeleted
+	report.EndTime = time.Now()
+	report.Duration = report.EndTime.Sub(report.StartTime).Seconds()
+	if err := gc.saveReport(report); err != nil {
+		return err
+	}
 	gc.logger.Infof("success to run gc in job.")
 	return nil
 }
@@ -114,9 +182,187 @@ func (gc *GarbageCollector) init(ctx env.JobContext, params map[string]interface
 		return fmt.Errorf(errTpl, common.CoreURL)
 	}
 	gc.redisURL = params["redis_url_reg"].(string)
+
+	gc.unlink, _ = params["unlink"].(bool)
+	gc.dryRun, _ = params["dry_run"].(bool)
+	gc.deleteUntagged, _ = params["delete_untagged"].(bool)
+	gc.purgeOrphans, _ = params["purge_orphans"].(bool)
+	gc.adminJobID = parseAdminJobID(params["admin_job_id"])
+	if !gc.unlink {
+		return nil
+	}
+
+	// the unlink pre-pass accesses the registry directly
+	if v, ok := ctx.Get(common.RegistryURL); ok && len(v.(string)) > 0 {
+		gc.registryURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.RegistryURL)
+	}
+	if v, ok := ctx.Get(common.TokenServiceURL); ok && len(v.(string)) > 0 {
+		gc.tokenServiceURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.TokenServiceURL)
+	}
+	if v := os.Getenv("JOBSERVICE_SECRET"); len(v) > 0 {
+		gc.secret = v
+	} else {
+		return fmt.Errorf(errTpl, "JOBSERVICE_SECRET")
+	}
 	return nil
 }
 
+// runDryRun runs the unlink pre-pass in dry run and stores the report with the admin job,
+// the system isn't switched into read only mode as nothing is deleted
+func (gc *GarbageCollector) runDryRun() error {
+	gc.logger.Infof("start to run gc in job in dry run, delete untagged: %t, purge orphans: %t.", gc.deleteUntagged, gc.purgeOrphans)
+	report := &models.GCReport{
+		DryRun:    true,
+		StartTime: time.Now(),
+	}
+	if gc.purgeOrphans {
+		if err := gc.purgeOrphanArtifacts(report); err != nil {
+			return err
+		}
+	}
+	if err := gc.newUnlinker().unlink(report); err != nil {
+		gc.logger.Errorf("failed to run the unlink pre-pass in dry run: %v", err)
+		return err
+	}
+	report.EndTime = time.Now()
+	report.Duration = report.EndTime.Sub(report.StartTime).Seconds()
+	if err := gc.saveReport(report); err != nil {
+		return err
+	}
+	gc.logger.Infof("success to run gc in job in dry run.")
+	return nil
+}
+
+func (gc *GarbageCollector) newUnlinker() *unlinker {
+	return newUnlinker(gc.logger, gc.dryRun, gc.deleteUntagged, func(repository string) (*reg.Repository, error) {
+		return js_utils.NewRepositoryClientForJobservice(repository, gc.registryURL, gc.secret, gc.tokenServiceURL)
+	})
+}
+
+// blobSizes returns the sizes of the blobs tracked by Harbor keyed by digest
+func blobSizes() (map[string]int64, error) {
+	records, err := dao.GetArtifactBlobs("")
+	if err != nil {
+		return nil, err
+	}
+	sizes := map[string]int64{}
+	for _, record := range records {
+		sizes[record.DigestBlob] = record.Size
+	}
+	return sizes, nil
+}
+
+// freedBytes sums up the sizes of the blobs deleted by the garbage collection of registry
+func (gc *GarbageCollector) freedBytes(sizes map[string]int64, digests []string) int64 {
+	var freed int64
+	unknown := 0
+	for _, digest := range digests {
+		size, exist := sizes[digest]
+		if !exist {
+			unknown++
+			continue
+		}
+		freed += size
+	}
+	if unknown > 0 {
+		gc.logger.Warningf("the sizes of %d deleted blobs are not tracked by Harbor, they are not counted in the freed bytes", unknown)
+	}
+	return freed
+}
+
+// purgeOrphanArtifacts asks core to purge the artifacts which exist only in registry, as
+// the job service has no access to the repositories without record in Harbor
+func (gc *GarbageCollector) purgeOrphanArtifacts(report *models.GCReport) error {
+	url := fmt.Sprintf("%s/api/internal/gc/orphans/purge?dry_run=%t", gc.CoreURL, gc.dryRun)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := gc.coreclient.Do(req)
+	if err != nil {
+		gc.logger.Errorf("failed to send the purging request to %s: %v", gc.CoreURL, err)
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		gc.logger.Errorf("failed to purge the orphan artifacts: %d %s", resp.StatusCode, string(data))
+		return fmt.Errorf("failed to purge the orphan artifacts: %d", resp.StatusCode)
+	}
+
+	result := &models.OrphanPurgeResult{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return err
+	}
+	gc.logger.Infof("%d orphan artifacts purged from repositories: %v", result.ArtifactsPurged, result.Repositories)
+	report.ArtifactsPurged = result.ArtifactsPurged
+	report.RepositoriesPurged = result.Repositories
+	return nil
+}
+
+// refreshUsages asks core to recalculate the usages of all projects in background as the
+// storage released by the collection is unknown, the stale usages are served until then
+func (gc *GarbageCollector) refreshUsages() {
+	url := fmt.Sprintf("%s/api/internal/gc/usages/refresh", gc.CoreURL)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		gc.logger.Errorf("failed to create the refreshing request: %v", err)
+		return
+	}
+	resp, err := gc.coreclient.Do(req)
+	if err != nil {
+		gc.logger.Errorf("failed to send the refreshing request to %s: %v", gc.CoreURL, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		data, _ := ioutil.ReadAll(resp.Body)
+		gc.logger.Errorf("failed to refresh the usages of projects: %d %s", resp.StatusCode, string(data))
+	}
+}
+
+// saveReport logs the report and stores it with the admin job
+func (gc *GarbageCollector) saveReport(report *models.GCReport) error {
+	gc.logger.Infof("GC report: dry run: %t, blobs deleted: %d, bytes freed: %d, blobs unlinked: %d, manifests deleted: %d, "+
+		"orphan artifacts purged: %d, start: %s, end: %s, duration: %.2fs.",
+		report.DryRun, report.BlobsDeleted, report.BytesFreed, report.BlobsUnlinked, report.ManifestsDeleted,
+		report.ArtifactsPurged, report.StartTime, report.EndTime, report.Duration)
+	if gc.adminJobID <= 0 {
+		return nil
+	}
+	data, err := reportJSON(report)
+	if err != nil {
+		return err
+	}
+	if err := dao.SetAdminJobReport(gc.adminJobID, data); err != nil {
+		gc.logger.Errorf("failed to save the gc report of admin job %d: %v", gc.adminJobID, err)
+		return err
+	}
+	return nil
+}
+
+// parseAdminJobID parses the ID of the admin job from the job parameter,
+// the number is decoded as float64 from the JSON request
+func parseAdminJobID(v interface{}) int64 {
+	switch id := v.(type) {
+	case float64:
+		return int64(id)
+	case int64:
+		return id
+	case int:
+		return int64(id)
+	default:
+		return 0
+	}
+}
+
 func (gc *GarbageCollector) getReadOnly() (bool, error) {
 	cfgs := map[string]interface{}{}
 	if err := gc.coreclient.Get(fmt.Sprintf("%s/api/configs", gc.CoreURL), &cfgs); err != nil {
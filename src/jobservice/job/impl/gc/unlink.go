@@ -0,0 +1,451 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	registry_error "github.com/goharbor/harbor/src/common/utils/error"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// sweepTarget is a blob to be unlinked from the repository
+type sweepTarget struct {
+	repository string
+	digest     string
+	size       int64
+	// whether the blob is no longer referenced by any repository after unlinking it,
+	// so that it's going to be deleted by the garbage collection of registry
+	unreferenced bool
+}
+
+// unlinker is the pre-pass of the garbage collection of registry. It finds out the
+// unreferenced blobs in mark-and-sweep way based on the manifest references recorded
+// in DB and unlinks them from the repositories via the registry API, the untagged
+// manifests are deleted as well if required as the bundled registry can't delete them.
+// The storage is reclaimed by the garbage collection of registry which runs right after
+// it, and the unlinked blobs won't be served by the stale links of the repositories then.
+// It runs in the read only window of the garbage collection, so the marks can't be
+// outdated by the manifests pushed concurrently
+type unlinker struct {
+	logger         logger.Interface
+	newClient      func(repository string) (*registry.Repository, error)
+	dryRun         bool
+	deleteUntagged bool
+	startTime      time.Time
+	// the manifests still tagged, keyed by repository. The untagged manifests
+	// are live as well if they aren't going to be deleted
+	live map[string]map[string]bool
+	// the untagged manifests to be deleted, keyed by repository
+	untagged map[string]map[string]bool
+	// the blobs referenced by the live manifests, keyed by repository
+	referenced map[string]map[string]bool
+	// the repositories failed to be marked, nothing is swept in them
+	skipped map[string]bool
+	// the manifests not live anymore whose records are deleted after the collection, keyed by repository
+	deadManifests map[string][]string
+	// the repositories failed to be swept, the records of their dead manifests are kept
+	failed map[string]bool
+}
+
+func newUnlinker(logger logger.Interface, dryRun, deleteUntagged bool,
+	newClient func(repository string) (*registry.Repository, error)) *unlinker {
+	return &unlinker{
+		logger:         logger,
+		newClient:      newClient,
+		dryRun:         dryRun,
+		deleteUntagged: deleteUntagged,
+		live:           map[string]map[string]bool{},
+		untagged:       map[string]map[string]bool{},
+		referenced:     map[string]map[string]bool{},
+		skipped:        map[string]bool{},
+		deadManifests:  map[string][]string{},
+		failed:         map[string]bool{},
+	}
+}
+
+// unlink runs the mark and sweep phases and fills the counts into the report. In
+// dry run the blobs which would be deleted by the garbage collection of registry
+// and their sizes are reported as well
+func (u *unlinker) unlink(report *models.GCReport) error {
+	u.startTime = time.Now()
+	repositories, err := u.repositories()
+	if err != nil {
+		return err
+	}
+	u.logger.Infof("start to mark the blobs referenced by the manifests of %d repositories", len(repositories))
+	for _, repository := range repositories {
+		if err := u.mark(repository); err != nil {
+			u.logger.Warningf("failed to mark repository %s, skip sweeping it: %v", repository, err)
+			u.skipped[repository] = true
+		}
+	}
+
+	records, err := dao.GetArtifactBlobs("")
+	if err != nil {
+		return err
+	}
+	targets, deadManifests := u.plan(records)
+	u.deadManifests = deadManifests
+	u.logger.Infof("%d blobs are not referenced by any manifest, start to unlink them", len(targets))
+
+	// the untagged manifests are deleted before unlinking the blobs referenced by them
+	for _, repository := range sortedKeys(nonEmpty(u.untagged)) {
+		for _, digest := range sortedKeys(u.untagged[repository]) {
+			deleted, err := u.deleteManifest(repository, digest)
+			if err != nil {
+				u.logger.Errorf("failed to delete untagged manifest %s from repository %s: %v", digest, repository, err)
+				u.failed[repository] = true
+				break
+			}
+			if deleted {
+				report.ManifestsDeleted++
+			}
+		}
+	}
+
+	for _, target := range targets {
+		if u.failed[target.repository] {
+			continue
+		}
+		unlinked, err := u.sweep(target)
+		if err != nil {
+			u.logger.Errorf("failed to unlink blob %s from repository %s: %v", target.digest, target.repository, err)
+			u.failed[target.repository] = true
+			continue
+		}
+		if !unlinked {
+			continue
+		}
+		report.BlobsUnlinked++
+		if u.dryRun && target.unreferenced {
+			report.BlobsDeleted++
+			report.BytesFreed += target.size
+		}
+	}
+	return nil
+}
+
+// cleanRecords deletes the blob records of the manifests which are not live anymore,
+// it's called after the garbage collection of registry as the sizes of the deleted
+// blobs are summed up with the records. The records are kept for the repositories
+// failed to be swept, so that they can be swept again in the next run
+func (u *unlinker) cleanRecords() {
+	if u.dryRun {
+		return
+	}
+	for repository, digests := range u.deadManifests {
+		if u.failed[repository] {
+			continue
+		}
+		for _, digest := range digests {
+			if err := dao.DeleteArtifactBlobs(repository, digest); err != nil {
+				u.logger.Errorf("failed to delete the blob records of manifest %s in repository %s: %v", digest, repository, err)
+			}
+		}
+	}
+}
+
+// repositories returns the repositories recorded in Harbor and the ones which
+// have blob records, all of them need to be marked
+func (u *unlinker) repositories() ([]string, error) {
+	names := map[string]bool{}
+	repositories, err := dao.GetRepositories()
+	if err != nil {
+		return nil, err
+	}
+	for _, repository := range repositories {
+		names[repository.Name] = true
+	}
+
+	records, err := dao.GetArtifactBlobs("")
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		names[record.Repository] = true
+	}
+
+	result := []string{}
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// mark walks through the tags of the repository and marks the manifests and the blobs
+// referenced by them. The references are recorded in DB as well to cover the images
+// pushed before the references are tracked
+func (u *unlinker) mark(repository string) error {
+	client, err := u.newClient(repository)
+	if err != nil {
+		return err
+	}
+	tags, err := client.ListTag()
+	if err != nil {
+		// all the manifests of the repository are deleted
+		if !isNotFound(err) {
+			return err
+		}
+		tags = []string{}
+	}
+
+	live := map[string]bool{}
+	referenced := map[string]bool{}
+	blobs := []*models.ArtifactBlob{}
+	for _, tag := range tags {
+		digest, references, err := registry.PullManifestReferences(client, tag)
+		if err != nil {
+			// the tag is deleted during the marking
+			if isNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if live[digest] {
+			continue
+		}
+		live[digest] = true
+		for _, reference := range references {
+			referenced[reference.Digest.String()] = true
+			blobs = append(blobs, &models.ArtifactBlob{
+				Repository: repository,
+				DigestAF:   digest,
+				DigestBlob: reference.Digest.String(),
+				Size:       reference.Size,
+			})
+		}
+	}
+
+	untagged, err := u.markUntagged(client, repository, live, referenced)
+	if err != nil {
+		return err
+	}
+	u.live[repository] = live
+	u.untagged[repository] = untagged
+	u.referenced[repository] = referenced
+
+	if u.dryRun {
+		return nil
+	}
+	return dao.AddArtifactBlobs(blobs)
+}
+
+// markUntagged checks the recorded manifests which aren't tagged anymore. The ones still
+// existing in registry are returned if the untagged manifests are going to be deleted,
+// otherwise they are marked as live together with the blobs referenced by them
+func (u *unlinker) markUntagged(client *registry.Repository, repository string,
+	live, referenced map[string]bool) (map[string]bool, error) {
+	records, err := dao.GetArtifactBlobs(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	untagged := map[string]bool{}
+	checked := map[string]bool{}
+	for _, record := range records {
+		digest := record.DigestAF
+		if live[digest] {
+			referenced[record.DigestBlob] = true
+			continue
+		}
+		if checked[digest] {
+			continue
+		}
+		checked[digest] = true
+
+		_, exist, err := client.ManifestExist(digest)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			continue
+		}
+		if u.deleteUntagged {
+			untagged[digest] = true
+			continue
+		}
+		live[digest] = true
+		referenced[record.DigestBlob] = true
+	}
+	return untagged, nil
+}
+
+// deleteManifest deletes the untagged manifest from the repository
+func (u *unlinker) deleteManifest(repository, digest string) (bool, error) {
+	if !u.untagged[repository][digest] {
+		u.logger.Infof("manifest %s in repository %s is tagged again, skip it", digest, repository)
+		return false, nil
+	}
+
+	if u.dryRun {
+		u.logger.Infof("[dry run] untagged manifest %s in repository %s will be deleted", digest, repository)
+		return true, nil
+	}
+
+	client, err := u.newClient(repository)
+	if err != nil {
+		return false, err
+	}
+	if err := client.DeleteManifest(digest); err != nil {
+		// already deleted
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	u.logger.Infof("untagged manifest %s in repository %s deleted", digest, repository)
+	return true, nil
+}
+
+// plan returns the blobs to be unlinked and the manifests which are not live anymore
+// based on the marked references and the blob records
+func (u *unlinker) plan(records []*models.ArtifactBlob) ([]*sweepTarget, map[string][]string) {
+	// the blobs still referenced by any repository
+	inUse := map[string]bool{}
+	for repository, referenced := range u.referenced {
+		if u.skipped[repository] {
+			continue
+		}
+		for digest := range referenced {
+			inUse[digest] = true
+		}
+	}
+	for _, record := range records {
+		if u.skipped[record.Repository] {
+			inUse[record.DigestBlob] = true
+		}
+	}
+
+	targets := []*sweepTarget{}
+	deadManifests := map[string][]string{}
+	planned := map[string]bool{}
+	unreferenced := map[string]bool{}
+	for _, record := range records {
+		repository := record.Repository
+		if u.skipped[repository] || u.live[repository][record.DigestAF] {
+			continue
+		}
+
+		key := repository + "@" + record.DigestAF
+		if !planned[key] {
+			planned[key] = true
+			deadManifests[repository] = append(deadManifests[repository], record.DigestAF)
+		}
+
+		if u.referenced[repository][record.DigestBlob] {
+			continue
+		}
+		key = repository + "@" + record.DigestBlob
+		if planned[key] {
+			continue
+		}
+		planned[key] = true
+
+		target := &sweepTarget{
+			repository: repository,
+			digest:     record.DigestBlob,
+			size:       record.Size,
+		}
+		// the size is counted only once for the blob shared by different repositories
+		if !inUse[record.DigestBlob] && !unreferenced[record.DigestBlob] {
+			unreferenced[record.DigestBlob] = true
+			target.unreferenced = true
+		}
+		targets = append(targets, target)
+	}
+	return targets, deadManifests
+}
+
+// sweep unlinks the blob from the repository, the records are checked again before
+// unlinking as the manifests pushed after the marking may reference the blob
+func (u *unlinker) sweep(target *sweepTarget) (bool, error) {
+	if u.referenced[target.repository][target.digest] {
+		u.logger.Infof("blob %s in repository %s is referenced again, skip it", target.digest, target.repository)
+		return false, nil
+	}
+
+	records, err := dao.GetArtifactBlobsByDigest(target.repository, target.digest)
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if record.CreationTime.After(u.startTime) || u.live[target.repository][record.DigestAF] {
+			u.logger.Infof("blob %s in repository %s is referenced by manifest %s, skip it", target.digest, target.repository, record.DigestAF)
+			return false, nil
+		}
+	}
+
+	if u.dryRun {
+		u.logger.Infof("[dry run] blob %s(%d bytes) in repository %s will be unlinked", target.digest, target.size, target.repository)
+		return true, nil
+	}
+
+	client, err := u.newClient(target.repository)
+	if err != nil {
+		return false, err
+	}
+	if err := client.DeleteBlob(target.digest); err != nil {
+		// already deleted
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	u.logger.Infof("blob %s(%d bytes) in repository %s unlinked", target.digest, target.size, target.repository)
+	return true, nil
+}
+
+// nonEmpty returns the entries of the map whose values aren't empty
+func nonEmpty(m map[string]map[string]bool) map[string]bool {
+	result := map[string]bool{}
+	for key, value := range m {
+		if len(value) > 0 {
+			result[key] = true
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(*registry_error.HTTPError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// reportJSON marshals the report to be stored with the admin job
+func reportJSON(report *models.GCReport) (string, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,125 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	u := newUnlinker(nil, true, false, nil)
+	u.live = map[string]map[string]bool{
+		"library/a": {"sha256:a_live": true},
+		"library/b": {},
+	}
+	u.referenced = map[string]map[string]bool{
+		"library/a": {"sha256:shared": true, "sha256:config_live": true},
+		"library/b": {},
+	}
+	u.skipped = map[string]bool{"library/c": true}
+
+	records := []*models.ArtifactBlob{
+		// live manifest
+		{Repository: "library/a", DigestAF: "sha256:a_live", DigestBlob: "sha256:shared", Size: 100},
+		{Repository: "library/a", DigestAF: "sha256:a_live", DigestBlob: "sha256:config_live", Size: 1},
+		// dead manifest sharing a layer with the live one
+		{Repository: "library/a", DigestAF: "sha256:a_dead", DigestBlob: "sha256:shared", Size: 100},
+		{Repository: "library/a", DigestAF: "sha256:a_dead", DigestBlob: "sha256:config_dead", Size: 2},
+		// dead manifests in another repository
+		{Repository: "library/b", DigestAF: "sha256:b_dead1", DigestBlob: "sha256:shared", Size: 100},
+		{Repository: "library/b", DigestAF: "sha256:b_dead1", DigestBlob: "sha256:layer", Size: 10},
+		{Repository: "library/b", DigestAF: "sha256:b_dead2", DigestBlob: "sha256:layer", Size: 10},
+		{Repository: "library/b", DigestAF: "sha256:b_dead2", DigestBlob: "sha256:gone", Size: 50},
+		// skipped repository
+		{Repository: "library/c", DigestAF: "sha256:c_dead", DigestBlob: "sha256:skipped", Size: 1000},
+		{Repository: "library/d", DigestAF: "sha256:d_dead", DigestBlob: "sha256:skipped", Size: 1000},
+		{Repository: "library/d", DigestAF: "sha256:d_dead", DigestBlob: "sha256:gone", Size: 50},
+	}
+
+	targets, deadManifests := u.plan(records)
+	result := map[string]*sweepTarget{}
+	for _, target := range targets {
+		result[target.repository+"@"+target.digest] = target
+	}
+	// the blob referenced by multiple dead manifests is deleted once
+	require.Equal(t, 6, len(targets))
+	require.Equal(t, 6, len(result))
+
+	// counted as it isn't referenced by any live manifest
+	assert.True(t, result["library/a@sha256:config_dead"].unreferenced)
+	assert.True(t, result["library/b@sha256:layer"].unreferenced)
+	// deleted from the repository but still referenced by others
+	assert.False(t, result["library/b@sha256:shared"].unreferenced)
+	assert.False(t, result["library/d@sha256:skipped"].unreferenced)
+	assert.Equal(t, int64(10), result["library/b@sha256:layer"].size)
+	// the size of the blob deleted from multiple repositories is counted once
+	assert.True(t, result["library/b@sha256:gone"].unreferenced)
+	assert.False(t, result["library/d@sha256:gone"].unreferenced)
+
+	assert.ElementsMatch(t, []string{"sha256:a_dead"}, deadManifests["library/a"])
+	assert.ElementsMatch(t, []string{"sha256:b_dead1", "sha256:b_dead2"}, deadManifests["library/b"])
+	assert.ElementsMatch(t, []string{"sha256:d_dead"}, deadManifests["library/d"])
+	_, exist := deadManifests["library/c"]
+	assert.False(t, exist)
+}
+
+func TestParseAdminJobID(t *testing.T) {
+	assert.Equal(t, int64(1), parseAdminJobID(float64(1)))
+	assert.Equal(t, int64(2), parseAdminJobID(int64(2)))
+	assert.Equal(t, int64(0), parseAdminJobID(nil))
+	assert.Equal(t, int64(0), parseAdminJobID("1"))
+}
+
+func TestValidate(t *testing.T) {
+	gc := &GarbageCollector{}
+	assert.Nil(t, gc.Validate(map[string]interface{}{}))
+	assert.Nil(t, gc.Validate(map[string]interface{}{"unlink": true, "dry_run": true}))
+	assert.Nil(t, gc.Validate(map[string]interface{}{"unlink": true, "delete_untagged": true}))
+	assert.Nil(t, gc.Validate(map[string]interface{}{"purge_orphans": true}))
+	assert.Nil(t, gc.Validate(map[string]interface{}{"unlink": true, "purge_orphans": true}))
+	assert.NotNil(t, gc.Validate(map[string]interface{}{"dry_run": true}))
+	assert.NotNil(t, gc.Validate(map[string]interface{}{"delete_untagged": true}))
+	assert.NotNil(t, gc.Validate(map[string]interface{}{"unlink": "true"}))
+	assert.NotNil(t, gc.Validate(map[string]interface{}{"delete_untagged": "true"}))
+	assert.NotNil(t, gc.Validate(map[string]interface{}{"purge_orphans": 1}))
+}
+
+func TestFreedBytes(t *testing.T) {
+	gc := &GarbageCollector{
+		logger: backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4),
+	}
+	sizes := map[string]int64{"sha256:a": 10, "sha256:b": 20}
+	assert.Equal(t, int64(0), gc.freedBytes(sizes, nil))
+	assert.Equal(t, int64(30), gc.freedBytes(sizes, []string{"sha256:a", "sha256:b"}))
+	// the blobs not tracked are not counted
+	assert.Equal(t, int64(10), gc.freedBytes(sizes, []string{"sha256:a", "sha256:unknown"}))
+}
+
+func TestNonEmpty(t *testing.T) {
+	assert.Equal(t, map[string]bool{"library/a": true}, nonEmpty(map[string]map[string]bool{
+		"library/a": {"sha256:a": true},
+		"library/b": {},
+	}))
+}
+
+func TestSortedKeys(t *testing.T) {
+	assert.Equal(t, []string{}, sortedKeys(nil))
+	assert.Equal(t, []string{"sha256:a", "sha256:b"}, sortedKeys(map[string]bool{"sha256:b": true, "sha256:a": true}))
+}
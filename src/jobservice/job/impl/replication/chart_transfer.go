@@ -0,0 +1,432 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/goharbor/harbor/src/chartserver"
+	"github.com/goharbor/harbor/src/common"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	httpauth "github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/replication"
+)
+
+const labelPageSize = 100
+
+// ChartTransfer transfers the versions of a Helm chart from the chart repository
+// of source Harbor to the destination one, the prov files and labels of the
+// versions are transferred as well
+type ChartTransfer struct {
+	ctx          env.JobContext
+	namespace    string
+	name         string
+	versions     []string
+	srcRegistry  *chartRegistry
+	dstRegistry  *chartRegistry
+	dstProjectID int64
+	logger       logger.Interface
+	retry        bool
+}
+
+// ShouldRetry : retry if the error is network error
+func (c *ChartTransfer) ShouldRetry() bool {
+	return c.retry
+}
+
+// MaxFails ...
+func (c *ChartTransfer) MaxFails() uint {
+	return 3
+}
+
+// Validate ....
+func (c *ChartTransfer) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+// Run ...
+func (c *ChartTransfer) Run(ctx env.JobContext, params map[string]interface{}) error {
+	err := c.run(ctx, params)
+	c.retry = retry(err)
+	return err
+}
+
+func (c *ChartTransfer) run(ctx env.JobContext, params map[string]interface{}) error {
+	// initialize
+	if err := c.init(ctx, params); err != nil {
+		return err
+	}
+	// try to create project on destination registry
+	if err := c.createProject(); err != nil {
+		return err
+	}
+
+	srcVersions, err := c.srcRegistry.ListChartVersions(c.namespace, c.name)
+	if err != nil {
+		c.logger.Errorf("failed to list versions of chart %s/%s on source registry: %v", c.namespace, c.name, err)
+		return err
+	}
+	dstVersions, err := c.dstRegistry.ListChartVersions(c.namespace, c.name)
+	if err != nil {
+		c.logger.Errorf("failed to list versions of chart %s/%s on destination registry: %v", c.namespace, c.name, err)
+		return err
+	}
+	existing := map[string]bool{}
+	for _, version := range dstVersions {
+		existing[version.Version] = true
+	}
+
+	// replicate the chart versions
+	for _, version := range c.versions {
+		if canceled(c.ctx) {
+			c.logger.Warning(errCanceled.Error())
+			return errCanceled
+		}
+
+		var chartVersion *chartserver.ChartVersion
+		for _, v := range srcVersions {
+			if v.Version == version {
+				chartVersion = v
+				break
+			}
+		}
+		if chartVersion == nil {
+			err = fmt.Errorf("chart %s/%s:%s not found on source registry", c.namespace, c.name, version)
+			c.logger.Error(err)
+			return err
+		}
+
+		if existing[version] {
+			c.logger.Infof("chart %s/%s:%s already exists on the destination registry, skip",
+				c.namespace, c.name, version)
+		} else if err = c.transferChart(chartVersion); err != nil {
+			return err
+		}
+		c.transferLabels(chartVersion)
+	}
+
+	return nil
+}
+
+func (c *ChartTransfer) init(ctx env.JobContext, params map[string]interface{}) error {
+	c.logger = ctx.GetLogger()
+	c.ctx = ctx
+
+	if canceled(c.ctx) {
+		c.logger.Warning(errCanceled.Error())
+		return errCanceled
+	}
+
+	// init the chart versions that need to be replicated
+	c.namespace, c.name = utils.ParseRepository(params["chart"].(string))
+	if len(c.namespace) == 0 || len(c.name) == 0 {
+		err := fmt.Errorf("invalid chart %s", params["chart"])
+		c.logger.Error(err)
+		return err
+	}
+	if versions, ok := params["versions"]; ok {
+		for _, version := range versions.([]interface{}) {
+			c.versions = append(c.versions, version.(string))
+		}
+	}
+	if len(c.versions) == 0 {
+		err := fmt.Errorf("empty version list for chart %s/%s", c.namespace, c.name)
+		c.logger.Error(err)
+		return err
+	}
+
+	// the source is the local Harbor for push-based replication and the
+	// target Harbor for pull-based replication
+	c.srcRegistry = initChartRegistryFromParams(params, "src")
+	c.dstRegistry = initChartRegistryFromParams(params, "dst")
+
+	c.logger.Infof("initialization completed: chart: %s/%s, versions: %v, source registry: URL-%s insecure-%v, destination registry: URL-%s insecure-%v",
+		c.namespace, c.name, c.versions, c.srcRegistry.url, c.srcRegistry.insecure,
+		c.dstRegistry.url, c.dstRegistry.insecure)
+
+	return nil
+}
+
+func (c *ChartTransfer) createProject() error {
+	if canceled(c.ctx) {
+		c.logger.Warning(errCanceled.Error())
+		return errCanceled
+	}
+
+	project, err := c.srcRegistry.GetProject(c.namespace)
+	if err != nil {
+		c.logger.Errorf("failed to get project %s from source registry: %v", c.namespace, err)
+		return err
+	}
+
+	if err = c.dstRegistry.CreateProject(project); err != nil {
+		// the project may be created by other jobs or exist already
+		if e, ok := err.(*common_http.Error); !ok || e.Code != http.StatusConflict {
+			c.logger.Errorf("an error occurred while creating project %s on destination registry: %v", c.namespace, err)
+			return err
+		}
+		c.logger.Warningf("the status code is 409 when creating project %s on destination registry, try to do next step", c.namespace)
+	} else {
+		c.logger.Infof("project %s is created on destination registry", c.namespace)
+	}
+
+	// the ID of destination project is needed when transferring the project labels
+	project, err = c.dstRegistry.GetProject(c.namespace)
+	if err != nil {
+		c.logger.Errorf("failed to get project %s from destination registry: %v", c.namespace, err)
+		return err
+	}
+	c.dstProjectID = project.ProjectID
+	return nil
+}
+
+func (c *ChartTransfer) transferChart(chartVersion *chartserver.ChartVersion) error {
+	version := chartVersion.Version
+	if len(chartVersion.URLs) == 0 {
+		err := fmt.Errorf("no URL found for chart %s/%s:%s", c.namespace, c.name, version)
+		c.logger.Error(err)
+		return err
+	}
+
+	c.logger.Infof("transferring chart %s/%s:%s to the destination registry ...", c.namespace, c.name, version)
+	content, err := c.srcRegistry.DownloadChartFile(c.namespace, chartVersion.URLs[0])
+	if err != nil {
+		c.logger.Errorf("an error occurred while downloading chart %s/%s:%s from the source registry: %v",
+			c.namespace, c.name, version, err)
+		return err
+	}
+	chart := &chartserver.ChartFile{
+		Name:    path.Base(chartVersion.URLs[0]),
+		Content: content,
+	}
+
+	// the prov file only exists when the chart is signed
+	details, err := c.srcRegistry.GetChartVersionDetails(c.namespace, c.name, version)
+	if err != nil {
+		c.logger.Errorf("an error occurred while getting details of chart %s/%s:%s from the source registry: %v",
+			c.namespace, c.name, version, err)
+		return err
+	}
+	var prov *chartserver.ChartFile
+	if details.Security != nil && details.Security.Signature != nil && details.Security.Signature.Signed {
+		provPath := details.Security.Signature.Provenance
+		content, err = c.srcRegistry.DownloadChartFile(c.namespace, provPath)
+		if err != nil {
+			c.logger.Errorf("an error occurred while downloading prov file of chart %s/%s:%s from the source registry: %v",
+				c.namespace, c.name, version, err)
+			return err
+		}
+		prov = &chartserver.ChartFile{
+			Name:    path.Base(provPath),
+			Content: content,
+		}
+	}
+
+	if err = c.dstRegistry.UploadChart(c.namespace, chart, prov); err != nil {
+		c.logger.Errorf("an error occurred while uploading chart %s/%s:%s to the destination registry: %v",
+			c.namespace, c.name, version, err)
+		return err
+	}
+	c.logger.Infof("chart %s/%s:%s has been transferred to the destination registry, signed: %v",
+		c.namespace, c.name, version, prov != nil)
+	return nil
+}
+
+// transferLabels marks the labels of the source chart version to the destination one,
+// the labels which don't exist on the destination registry are created first. The
+// failures are only logged as the permission to create global labels may be absent
+func (c *ChartTransfer) transferLabels(chartVersion *chartserver.ChartVersion) {
+	for _, label := range chartVersion.Labels {
+		if err := c.transferLabel(chartVersion.Version, label); err != nil {
+			c.logger.Warningf("failed to mark label %s to chart %s/%s:%s on the destination registry: %v",
+				label.Name, c.namespace, c.name, chartVersion.Version, err)
+			continue
+		}
+		c.logger.Infof("label %s is marked to chart %s/%s:%s on the destination registry",
+			label.Name, c.namespace, c.name, chartVersion.Version)
+	}
+}
+
+func (c *ChartTransfer) transferLabel(version string, label *models.Label) error {
+	var projectID int64
+	if label.Scope == common.LabelScopeProject {
+		projectID = c.dstProjectID
+	}
+	l, err := c.dstRegistry.GetLabel(label.Name, label.Scope, projectID)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		if err = c.dstRegistry.CreateLabel(&models.Label{
+			Name:        label.Name,
+			Description: label.Description,
+			Color:       label.Color,
+			Scope:       label.Scope,
+			ProjectID:   projectID,
+		}); err != nil {
+			return err
+		}
+		if l, err = c.dstRegistry.GetLabel(label.Name, label.Scope, projectID); err != nil {
+			return err
+		}
+		if l == nil {
+			return fmt.Errorf("label %s not found after being created", label.Name)
+		}
+	}
+
+	err = c.dstRegistry.MarkChartLabel(c.namespace, c.name, version, l.ID)
+	if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusConflict {
+		// the label has already been marked to the chart
+		return nil
+	}
+	return err
+}
+
+// chartRegistry wraps the operations of the chart repository of Harbor
+type chartRegistry struct {
+	*registry
+	chartClient *chartserver.ChartClient
+}
+
+// initChartRegistryFromParams initializes the chart registry with the parameters
+// prefixed with "src" or "dst". The registry is the local Harbor if no username
+// is provided, and the secret of jobservice is used as the credential
+func initChartRegistryFromParams(params map[string]interface{}, prefix string) *chartRegistry {
+	url := params[prefix+"_registry_url"].(string)
+	insecure := params[prefix+"_registry_insecure"].(bool)
+
+	var credential auth.Credential
+	if username, ok := params[prefix+"_registry_username"].(string); ok {
+		password, _ := params[prefix+"_registry_password"].(string)
+		credential = auth.NewBasicAuthCredential(username, password)
+	} else {
+		credential = httpauth.NewSecretAuthorizer(secret())
+	}
+
+	transport := reg.GetHTTPTransport(insecure)
+	return &chartRegistry{
+		registry: &registry{
+			client: common_http.NewClient(&http.Client{
+				Transport: transport,
+			}, credential),
+			url:      strings.TrimRight(url, "/"),
+			insecure: insecure,
+			kind:     replication.AdaptorKindHarbor,
+		},
+		chartClient: chartserver.NewChartClientWithAuthorizer(transport, credential),
+	}
+}
+
+// ListChartVersions returns all the versions of the chart, an empty list
+// is returned if the chart doesn't exist
+func (c *chartRegistry) ListChartVersions(namespace, name string) (chartserver.ChartVersions, error) {
+	charts := []*chartserver.ChartInfo{}
+	if err := c.client.Get(fmt.Sprintf("%s/api/chartrepo/%s/charts", c.url, namespace), &charts); err != nil {
+		return nil, err
+	}
+	found := false
+	for _, chart := range charts {
+		if chart.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return chartserver.ChartVersions{}, nil
+	}
+
+	versions := chartserver.ChartVersions{}
+	if err := c.client.Get(fmt.Sprintf("%s/api/chartrepo/%s/charts/%s", c.url, namespace, name), &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetChartVersionDetails returns the details of the chart version
+func (c *chartRegistry) GetChartVersionDetails(namespace, name, version string) (*chartserver.ChartVersionDetails, error) {
+	details := &chartserver.ChartVersionDetails{}
+	if err := c.client.Get(fmt.Sprintf("%s/api/chartrepo/%s/charts/%s/%s",
+		c.url, namespace, name, version), details); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// DownloadChartFile downloads the chart or prov file, the filePath is the
+// URL of the file or the path relative to the chart repository
+func (c *chartRegistry) DownloadChartFile(namespace, filePath string) ([]byte, error) {
+	addr := filePath
+	if !strings.HasPrefix(filePath, "http://") && !strings.HasPrefix(filePath, "https://") {
+		addr = fmt.Sprintf("%s/chartrepo/%s/%s", c.url, namespace, strings.TrimLeft(filePath, "/"))
+	}
+	return c.chartClient.GetContent(addr)
+}
+
+// UploadChart uploads the chart and its prov file which can be nil
+func (c *chartRegistry) UploadChart(namespace string, chart, prov *chartserver.ChartFile) error {
+	return c.chartClient.UploadChart(fmt.Sprintf("%s/api/chartrepo/%s/charts", c.url, namespace), chart, prov)
+}
+
+// GetLabel returns the label with the specified name, nil is returned if the
+// label doesn't exist. The projectID is only used for the project labels
+func (c *chartRegistry) GetLabel(name, scope string, projectID int64) (*models.Label, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("scope", scope)
+	if scope == common.LabelScopeProject {
+		query.Set("project_id", fmt.Sprintf("%d", projectID))
+	}
+	query.Set("page_size", fmt.Sprintf("%d", labelPageSize))
+
+	for page := 1; ; page++ {
+		query.Set("page", fmt.Sprintf("%d", page))
+		labels := []*models.Label{}
+		if err := c.client.Get(fmt.Sprintf("%s/api/labels?%s", c.url, query.Encode()), &labels); err != nil {
+			return nil, err
+		}
+		// the name query of labels API is a fuzzy matching
+		for _, label := range labels {
+			if label.Name == name {
+				return label, nil
+			}
+		}
+		if len(labels) < labelPageSize {
+			return nil, nil
+		}
+	}
+}
+
+// CreateLabel creates the label
+func (c *chartRegistry) CreateLabel(label *models.Label) error {
+	return c.client.Post(c.url+"/api/labels", label)
+}
+
+// MarkChartLabel marks the label to the chart version
+func (c *chartRegistry) MarkChartLabel(namespace, name, version string, labelID int64) error {
+	return c.client.Post(fmt.Sprintf("%s/api/chartrepo/%s/charts/%s/%s/labels", c.url, namespace, name, version),
+		&models.Label{
+			ID: labelID,
+		})
+}
@@ -0,0 +1,248 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/chartserver"
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	jmodels "github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	helm_repo "k8s.io/helm/pkg/repo"
+)
+
+type fakeContext struct{}
+
+func (f *fakeContext) Build(dep env.JobData) (env.JobContext, error) {
+	return f, nil
+}
+
+func (f *fakeContext) Get(prop string) (interface{}, bool) {
+	return nil, false
+}
+
+func (f *fakeContext) SystemContext() context.Context {
+	return context.Background()
+}
+
+func (f *fakeContext) Checkin(status string) error {
+	return nil
+}
+
+func (f *fakeContext) OPCommand() (string, bool) {
+	return "", false
+}
+
+func (f *fakeContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeContext) LaunchJob(req jmodels.JobRequest) (jmodels.JobStats, error) {
+	return jmodels.JobStats{}, nil
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Errorf("failed to encode response: %v", err)
+	}
+}
+
+func newChartVersion(version string, labels ...*models.Label) *chartserver.ChartVersion {
+	return &chartserver.ChartVersion{
+		ChartVersion: helm_repo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "harbor",
+				Version: version,
+			},
+			URLs: []string{"charts/harbor-" + version + ".tgz"},
+		},
+		Labels: labels,
+	}
+}
+
+// newFakeSourceHarbor hosts the chart harbor with versions 0.1.0(signed) and 0.2.0
+func newFakeSourceHarbor(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []*models.Project{{ProjectID: 1, Name: "library"}})
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []*chartserver.ChartInfo{{Name: "harbor"}})
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts/harbor", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, chartserver.ChartVersions{
+			newChartVersion("0.1.0", &models.Label{
+				ID:    1,
+				Name:  "stable",
+				Scope: common.LabelScopeProject,
+			}),
+			newChartVersion("0.2.0"),
+		})
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts/harbor/0.1.0", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, &chartserver.ChartVersionDetails{
+			Security: &chartserver.SecurityReport{
+				Signature: &chartserver.DigitalSignature{
+					Signed:     true,
+					Provenance: "charts/harbor-0.1.0.tgz.prov",
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/chartrepo/library/charts/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	})
+	return httptest.NewServer(mux)
+}
+
+// fakeDestinationHarbor records the uploaded charts and marked labels
+type fakeDestinationHarbor struct {
+	*httptest.Server
+	uploaded map[string]string
+	labels   []*models.Label
+	marked   []int64
+}
+
+func newFakeDestinationHarbor(t *testing.T) *fakeDestinationHarbor {
+	h := &fakeDestinationHarbor{
+		uploaded: map[string]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []*models.Project{{ProjectID: 2, Name: "library"}})
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, []*chartserver.ChartInfo{{Name: "harbor"}})
+			return
+		}
+		for _, field := range []string{"chart", "prov"} {
+			file, header, err := r.FormFile(field)
+			if err != nil {
+				continue
+			}
+			content, _ := ioutil.ReadAll(file)
+			h.uploaded[header.Filename] = string(content)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	// the version 0.2.0 exists on the destination registry
+	mux.HandleFunc("/api/chartrepo/library/charts/harbor", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, chartserver.ChartVersions{newChartVersion("0.2.0")})
+	})
+	mux.HandleFunc("/api/labels", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			labels := []*models.Label{}
+			for _, label := range h.labels {
+				if label.Scope == r.URL.Query().Get("scope") {
+					labels = append(labels, label)
+				}
+			}
+			writeJSON(t, w, labels)
+			return
+		}
+		label := &models.Label{}
+		if err := json.NewDecoder(r.Body).Decode(label); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		label.ID = int64(len(h.labels) + 10)
+		h.labels = append(h.labels, label)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts/harbor/0.1.0/labels", func(w http.ResponseWriter, r *http.Request) {
+		label := &models.Label{}
+		if err := json.NewDecoder(r.Body).Decode(label); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.marked = append(h.marked, label.ID)
+	})
+	h.Server = httptest.NewServer(mux)
+	return h
+}
+
+func TestMaxFailsOfChartTransfer(t *testing.T) {
+	c := &ChartTransfer{}
+	assert.Equal(t, uint(3), c.MaxFails())
+}
+
+func TestValidateOfChartTransfer(t *testing.T) {
+	c := &ChartTransfer{}
+	require.Nil(t, c.Validate(nil))
+}
+
+func TestShouldRetryOfChartTransfer(t *testing.T) {
+	c := &ChartTransfer{}
+	assert.False(t, c.ShouldRetry())
+	c.retry = true
+	assert.True(t, c.ShouldRetry())
+}
+
+func TestRunOfChartTransfer(t *testing.T) {
+	src := newFakeSourceHarbor(t)
+	defer src.Close()
+	dst := newFakeDestinationHarbor(t)
+	defer dst.Close()
+
+	params := map[string]interface{}{
+		"chart":                 "library/harbor",
+		"versions":              []interface{}{"0.1.0", "0.2.0"},
+		"src_registry_url":      src.URL,
+		"src_registry_insecure": false,
+		"src_registry_username": "admin",
+		"src_registry_password": "Harbor12345",
+		"dst_registry_url":      dst.URL,
+		"dst_registry_insecure": false,
+		"dst_registry_username": "admin",
+		"dst_registry_password": "Harbor12345",
+	}
+	c := &ChartTransfer{}
+	require.Nil(t, c.Run(&fakeContext{}, params))
+
+	// only the version 0.1.0 and its prov file are uploaded
+	assert.Equal(t, map[string]string{
+		"harbor-0.1.0.tgz":      "/chartrepo/library/charts/harbor-0.1.0.tgz",
+		"harbor-0.1.0.tgz.prov": "/chartrepo/library/charts/harbor-0.1.0.tgz.prov",
+	}, dst.uploaded)
+
+	// the project label is created in the destination project and marked
+	require.Equal(t, 1, len(dst.labels))
+	assert.Equal(t, "stable", dst.labels[0].Name)
+	assert.Equal(t, int64(2), dst.labels[0].ProjectID)
+	assert.Equal(t, []int64{10}, dst.marked)
+
+	// the version doesn't exist on the source registry
+	params["versions"] = []interface{}{"1.0.0"}
+	c = &ChartTransfer{}
+	assert.NotNil(t, c.Run(&fakeContext{}, params))
+	assert.False(t, c.ShouldRetry())
+}
@@ -88,14 +88,14 @@ func (d *Deleter) init(ctx env.JobContext, params map[string]interface{}) error
 		params["dst_registry_password"].(string))
 
 	var err error
-	d.dstRegistry, err = initRegistry(url, insecure, cred, d.repository.name)
+	d.dstRegistry, err = initRegistry(registryType(params, "dst_registry_type"), url, insecure, cred, d.repository.name)
 	if err != nil {
 		d.logger.Errorf("failed to create client for destination registry: %v", err)
 		return err
 	}
 
-	d.logger.Infof("initialization completed: repository: %s, tags: %v, destination URL: %s, insecure: %v",
-		d.repository.name, d.repository.tags, d.dstRegistry.url, d.dstRegistry.insecure)
+	d.logger.Infof("initialization completed: repository: %s, tags: %v, destination URL: %s, insecure: %v, type: %s",
+		d.repository.name, d.repository.tags, d.dstRegistry.url, d.dstRegistry.insecure, d.dstRegistry.kind)
 
 	return nil
 }
@@ -16,13 +16,16 @@ package replication
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/models"
+	registry_error "github.com/goharbor/harbor/src/common/utils/error"
 	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/replication"
 )
 
 type repository struct {
@@ -36,6 +39,13 @@ type registry struct {
 	client         *common_http.Client // Harbor client
 	url            string
 	insecure       bool
+	kind           string // the kind of the registry, e.g. Harbor or DockerRegistry
+}
+
+// isHarbor returns whether the registry is a Harbor instance which
+// provides the APIs of projects and repositories besides the registry API
+func (r *registry) isHarbor() bool {
+	return len(r.kind) == 0 || r.kind == replication.AdaptorKindHarbor
 }
 
 func (r *registry) GetProject(name string) (*models.Project, error) {
@@ -85,9 +95,45 @@ func (r *registry) CreateProject(project *models.Project) error {
 }
 
 func (r *registry) DeleteRepository(repository string) error {
-	return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository)
+	if r.isHarbor() {
+		return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository)
+	}
+
+	// the registry API doesn't support deleting repository, delete all tags instead
+	tags, err := r.ListTag()
+	if err != nil {
+		return convertError(err)
+	}
+	for _, tag := range tags {
+		if err = r.DeleteImage(repository, tag); err != nil {
+			if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *registry) DeleteImage(repository, tag string) error {
-	return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository + "/tags/" + tag)
+	if r.isHarbor() {
+		return r.client.Delete(strings.TrimRight(r.url, "/") + "/api/repositories/" + repository + "/tags/" + tag)
+	}
+
+	return convertError(r.DeleteTag(tag))
+}
+
+// convert the error returned by registry client to common_http.Error
+// to make the callers handle the errors in the same way
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*registry_error.HTTPError); ok {
+		return &common_http.Error{
+			Code:    e.StatusCode,
+			Message: e.Detail,
+		}
+	}
+	return err
 }
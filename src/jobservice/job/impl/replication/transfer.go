@@ -34,6 +34,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/env"
 	job_utils "github.com/goharbor/harbor/src/jobservice/job/impl/utils"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/replication"
 )
 
 var (
@@ -119,32 +120,16 @@ func (t *Transfer) init(ctx env.JobContext, params map[string]interface{}) error
 	}
 
 	var err error
-	// init source registry client
-	srcURL := params["src_registry_url"].(string)
-	srcInsecure := params["src_registry_insecure"].(bool)
-	srcCred := httpauth.NewSecretAuthorizer(secret())
-	srcTokenServiceURL := ""
-	if stsu, ok := params["src_token_service_url"]; ok {
-		srcTokenServiceURL = stsu.(string)
-	}
-
-	if len(srcTokenServiceURL) > 0 {
-		t.srcRegistry, err = initRegistry(srcURL, srcInsecure, srcCred, t.repository.name, srcTokenServiceURL)
-	} else {
-		t.srcRegistry, err = initRegistry(srcURL, srcInsecure, srcCred, t.repository.name)
-	}
+	// init source registry client, the source is the local Harbor for push-based
+	// replication and the target registry for pull-based replication
+	t.srcRegistry, err = initRegistryFromParams(params, "src", t.repository.name)
 	if err != nil {
 		t.logger.Errorf("failed to create client for source registry: %v", err)
 		return err
 	}
 
 	// init destination registry client
-	dstURL := params["dst_registry_url"].(string)
-	dstInsecure := params["dst_registry_insecure"].(bool)
-	dstCred := auth.NewBasicAuthCredential(
-		params["dst_registry_username"].(string),
-		params["dst_registry_password"].(string))
-	t.dstRegistry, err = initRegistry(dstURL, dstInsecure, dstCred, t.repository.name)
+	t.dstRegistry, err = initRegistryFromParams(params, "dst", t.repository.name)
 	if err != nil {
 		t.logger.Errorf("failed to create client for destination registry: %v", err)
 		return err
@@ -166,17 +151,41 @@ func (t *Transfer) init(ctx env.JobContext, params map[string]interface{}) error
 		t.repository.tags = tags
 	}
 
-	t.logger.Infof("initialization completed: repository: %s, tags: %v, source registry: URL-%s insecure-%v, destination registry: URL-%s insecure-%v",
-		t.repository.name, t.repository.tags, t.srcRegistry.url, t.srcRegistry.insecure, t.dstRegistry.url, t.dstRegistry.insecure)
+	t.logger.Infof("initialization completed: repository: %s, tags: %v, source registry: URL-%s insecure-%v type-%s, destination registry: URL-%s insecure-%v type-%s",
+		t.repository.name, t.repository.tags, t.srcRegistry.url, t.srcRegistry.insecure, t.srcRegistry.kind,
+		t.dstRegistry.url, t.dstRegistry.insecure, t.dstRegistry.kind)
 
 	return nil
 }
 
-func initRegistry(url string, insecure bool, credential modifier.Modifier,
+// initRegistryFromParams initializes the registry client with the parameters prefixed
+// with "src" or "dst". The registry is the local Harbor if no username is provided,
+// and the secret of jobservice is used as the credential
+func initRegistryFromParams(params map[string]interface{}, prefix, repository string) (*registry, error) {
+	url := params[prefix+"_registry_url"].(string)
+	insecure := params[prefix+"_registry_insecure"].(bool)
+	kind := registryType(params, prefix+"_registry_type")
+
+	var credential auth.Credential
+	if username, ok := params[prefix+"_registry_username"].(string); ok {
+		password, _ := params[prefix+"_registry_password"].(string)
+		credential = auth.NewBasicAuthCredential(username, password)
+	} else {
+		credential = httpauth.NewSecretAuthorizer(secret())
+	}
+
+	if tokenServiceURL, ok := params[prefix+"_token_service_url"].(string); ok && len(tokenServiceURL) > 0 {
+		return initRegistry(kind, url, insecure, credential, repository, tokenServiceURL)
+	}
+	return initRegistry(kind, url, insecure, credential, repository)
+}
+
+func initRegistry(kind, url string, insecure bool, credential auth.Credential,
 	repository string, tokenServiceURL ...string) (*registry, error) {
 	registry := &registry{
 		url:      url,
 		insecure: insecure,
+		kind:     kind,
 	}
 
 	// use the same transport for clients connecting to docker registry and Harbor UI
@@ -188,9 +197,14 @@ func initRegistry(url string, insecure bool, credential modifier.Modifier,
 	uam := &job_utils.UserAgentModifier{
 		UserAgent: "harbor-registry-client",
 	}
+	modifiers := []modifier.Modifier{authorizer, uam}
+	// the registries other than Harbor may protect their APIs by basic authentication
+	if !registry.isHarbor() {
+		modifiers = append(modifiers, auth.NewFallbackAuthorizer(credential))
+	}
 	repositoryClient, err := reg.NewRepository(repository, url,
 		&http.Client{
-			Transport: reg.NewTransport(transport, authorizer, uam),
+			Transport: reg.NewTransport(transport, modifiers...),
 		})
 	if err != nil {
 		return nil, err
@@ -210,6 +224,11 @@ func (t *Transfer) createProject() error {
 		return errCanceled
 	}
 	p, _ := utils.ParseRepository(t.repository.name)
+	if !t.srcRegistry.isHarbor() || !t.dstRegistry.isHarbor() {
+		t.logger.Infof("the source registry is %s and the destination registry is %s, skip creating project %s",
+			t.srcRegistry.kind, t.dstRegistry.kind, p)
+		return nil
+	}
 	project, err := t.srcRegistry.GetProject(p)
 	if err != nil {
 		t.logger.Errorf("failed to get project %s from source registry: %v", p, err)
@@ -363,6 +382,15 @@ func retry(err error) bool {
 	return ok
 }
 
+// registryType returns the registry type in the parameters, the jobs submitted
+// before the registry type was introduced are all against Harbor instances
+func registryType(params map[string]interface{}, key string) string {
+	if kind, ok := params[key].(string); ok && len(kind) > 0 {
+		return kind
+	}
+	return replication.AdaptorKindHarbor
+}
+
 func secret() string {
 	return os.Getenv("JOBSERVICE_SECRET")
 }
@@ -0,0 +1,192 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/retention"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+var errCanceled = errors.New("the job is canceled")
+
+// Job applies the retention rules to the tags of a project and deletes the
+// tags which aren't retained through the API of core, so that the deletions
+// are recorded in the access log. The report is sent back by check in.
+type Job struct {
+	ctx       env.JobContext
+	logger    logger.Interface
+	client    *common_http.Client
+	coreURL   string
+	projectID int64
+	rules     []*retention.Rule
+	dryRun    bool
+}
+
+// MaxFails implements the interface in job/Interface
+func (j *Job) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (j *Job) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (j *Job) Validate(params map[string]interface{}) error {
+	if _, err := parseProjectID(params); err != nil {
+		return err
+	}
+	if _, err := parseRules(params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (j *Job) Run(ctx env.JobContext, params map[string]interface{}) error {
+	if err := j.init(ctx, params); err != nil {
+		return err
+	}
+
+	report := &retention.Report{
+		DryRun:    j.dryRun,
+		StartTime: time.Now(),
+		Deleted:   []*retention.Candidate{},
+		Failed:    []*retention.Candidate{},
+	}
+
+	candidates := []*retention.Candidate{}
+	if err := j.client.Get(fmt.Sprintf("%s/api/projects/%d/retention/candidates",
+		j.coreURL, j.projectID), &candidates); err != nil {
+		j.logger.Errorf("failed to get the candidates of project %d: %v", j.projectID, err)
+		return err
+	}
+	deleted := retention.Evaluate(j.rules, candidates, report.StartTime)
+	report.Total = len(candidates)
+	report.Retained = len(candidates) - len(deleted)
+	j.logger.Infof("%d tags in project %d, %d of them will be deleted, dry run: %v",
+		report.Total, j.projectID, len(deleted), j.dryRun)
+
+	// deleting a tag removes the manifest and the other tags referencing it
+	deletedDigests := map[string]bool{}
+	for _, candidate := range deleted {
+		if j.dryRun {
+			j.logger.Infof("[dry run] %s:%s(%s) would be deleted", candidate.Repository,
+				candidate.Tag, candidate.Digest)
+			report.Deleted = append(report.Deleted, candidate)
+			continue
+		}
+		if _, canceled := j.ctx.OPCommand(); canceled {
+			j.logger.Warning(errCanceled.Error())
+			return errCanceled
+		}
+		if deletedDigests[candidate.Repository+"@"+candidate.Digest] {
+			j.logger.Infof("%s:%s(%s) deleted along with the manifest", candidate.Repository,
+				candidate.Tag, candidate.Digest)
+			report.Deleted = append(report.Deleted, candidate)
+			continue
+		}
+		if err := j.client.Delete(fmt.Sprintf("%s/api/repositories/%s/tags/%s",
+			j.coreURL, candidate.Repository, candidate.Tag)); err != nil {
+			j.logger.Errorf("failed to delete %s:%s: %v", candidate.Repository, candidate.Tag, err)
+			report.Failed = append(report.Failed, candidate)
+			continue
+		}
+		j.logger.Infof("%s:%s(%s) deleted", candidate.Repository, candidate.Tag, candidate.Digest)
+		deletedDigests[candidate.Repository+"@"+candidate.Digest] = true
+		report.Deleted = append(report.Deleted, candidate)
+	}
+	report.EndTime = time.Now()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err = j.ctx.Checkin(string(data)); err != nil {
+		j.logger.Errorf("failed to check in the report: %v", err)
+		return err
+	}
+
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d tags are failed to be deleted", len(report.Failed))
+	}
+	return nil
+}
+
+func (j *Job) init(ctx env.JobContext, params map[string]interface{}) error {
+	j.ctx = ctx
+	j.logger = ctx.GetLogger()
+
+	if v, ok := ctx.Get(common.CoreURL); ok && len(v.(string)) > 0 {
+		j.coreURL = v.(string)
+	} else {
+		return fmt.Errorf("failed to get required property: %s", common.CoreURL)
+	}
+
+	var err error
+	if j.projectID, err = parseProjectID(params); err != nil {
+		return err
+	}
+	if j.rules, err = parseRules(params); err != nil {
+		return err
+	}
+	if dryRun, ok := params["dry_run"].(bool); ok {
+		j.dryRun = dryRun
+	}
+
+	j.client = common_http.NewClient(&http.Client{
+		Transport: reg.GetHTTPTransport(false),
+	}, auth.NewSecretAuthorizer(os.Getenv("JOBSERVICE_SECRET")))
+	return nil
+}
+
+func parseProjectID(params map[string]interface{}) (int64, error) {
+	id, ok := params["project_id"].(float64)
+	if !ok || id <= 0 {
+		return 0, fmt.Errorf("invalid project_id: %v", params["project_id"])
+	}
+	return int64(id), nil
+}
+
+// the rules are passed as JSON string
+func parseRules(params map[string]interface{}) ([]*retention.Rule, error) {
+	data, ok := params["rules"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid rules: %v", params["rules"])
+	}
+	rules := []*retention.Rule{}
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules: %v", err)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,140 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/retention"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeContext struct {
+	coreURL string
+	checkIn string
+}
+
+func (f *fakeContext) Build(dep env.JobData) (env.JobContext, error) {
+	return f, nil
+}
+
+func (f *fakeContext) Get(prop string) (interface{}, bool) {
+	if prop == common.CoreURL {
+		return f.coreURL, true
+	}
+	return nil, false
+}
+
+func (f *fakeContext) SystemContext() context.Context {
+	return context.Background()
+}
+
+func (f *fakeContext) Checkin(status string) error {
+	f.checkIn = status
+	return nil
+}
+
+func (f *fakeContext) OPCommand() (string, bool) {
+	return "", false
+}
+
+func (f *fakeContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeContext) LaunchJob(req models.JobRequest) (models.JobStats, error) {
+	return models.JobStats{}, nil
+}
+
+// fakeCore serves the candidates and records the deleted tags
+func fakeCore(t *testing.T, candidates []*retention.Candidate, deleted *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/projects/1/retention/candidates":
+			data, err := json.Marshal(candidates)
+			require.Nil(t, err)
+			w.Write(data)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/repositories/"):
+			*deleted = append(*deleted, strings.TrimPrefix(r.URL.Path, "/api/repositories/"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestValidate(t *testing.T) {
+	j := &Job{}
+	assert.NotNil(t, j.Validate(map[string]interface{}{}))
+	assert.NotNil(t, j.Validate(map[string]interface{}{
+		"project_id": float64(1),
+		"rules":      `[{"kind":"unknown"}]`,
+	}))
+	assert.Nil(t, j.Validate(map[string]interface{}{
+		"project_id": float64(1),
+		"rules":      `[{"kind":"latest_pushed","count":1}]`,
+	}))
+	assert.Equal(t, uint(1), j.MaxFails())
+	assert.False(t, j.ShouldRetry())
+}
+
+func TestRun(t *testing.T) {
+	now := time.Now()
+	candidates := []*retention.Candidate{
+		{Repository: "library/hello", Tag: "1", Digest: "sha256:1", PushTime: now.Add(-3 * time.Hour)},
+		{Repository: "library/hello", Tag: "2", Digest: "sha256:1", PushTime: now.Add(-2 * time.Hour)},
+		{Repository: "library/hello", Tag: "3", Digest: "sha256:3", PushTime: now.Add(-1 * time.Hour)},
+	}
+	params := map[string]interface{}{
+		"project_id": float64(1),
+		"rules":      `[{"kind":"latest_pushed","count":1}]`,
+		"dry_run":    true,
+	}
+
+	// dry run
+	deleted := []string{}
+	server := fakeCore(t, candidates, &deleted)
+	defer server.Close()
+	ctx := &fakeContext{coreURL: server.URL}
+	require.Nil(t, (&Job{}).Run(ctx, params))
+	assert.Equal(t, 0, len(deleted))
+	report := &retention.Report{}
+	require.Nil(t, json.Unmarshal([]byte(ctx.checkIn), report))
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Retained)
+	assert.Equal(t, 2, len(report.Deleted))
+
+	// the tags sharing the manifest are deleted with one request
+	params["dry_run"] = false
+	require.Nil(t, (&Job{}).Run(ctx, params))
+	assert.Equal(t, []string{"library/hello/tags/1"}, deleted)
+	report = &retention.Report{}
+	require.Nil(t, json.Unmarshal([]byte(ctx.checkIn), report))
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 2, len(report.Deleted))
+	assert.Equal(t, 0, len(report.Failed))
+}
@@ -0,0 +1,203 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/utils"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+var (
+	// the interval to poll the report from the scanner and the max time to wait for it
+	reportPollInterval = 5 * time.Second
+	reportTimeout      = 30 * time.Minute
+
+	errCanceled = errors.New("the job is canceled")
+)
+
+// Job is the struct to scan Harbor's Image with the scanner specified in the parameters,
+// the Clair deployed with Harbor is used if no scanner is specified
+type Job struct {
+	registryURL   string
+	secret        string
+	tokenEndpoint string
+	clairEndpoint string
+}
+
+// MaxFails implements the interface in job/Interface
+func (j *Job) MaxFails() uint {
+	return 1
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (j *Job) ShouldRetry() bool {
+	return false
+}
+
+// Validate implements the interface in job/Interface
+func (j *Job) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (j *Job) Run(ctx env.JobContext, params map[string]interface{}) error {
+	logger := ctx.GetLogger()
+	// 获取结构体中定义数据的配置信息
+	if err := j.init(ctx); err != nil {
+		logger.Errorf("Failed to initialize the job, error: %v", err)
+		return err
+	}
+
+	// 解析job 参数
+	jobParms, err := transformParam(params)
+	if err != nil {
+		logger.Errorf("Failed to prepare parms for scan job, error: %v", err)
+		return err
+	}
+	scannerName, adapter, err := j.newAdapter(jobParms, logger)
+	if err != nil {
+		logger.Errorf("Failed to create the adapter of scanner %s, error: %v", jobParms.Scanner, err)
+		return err
+	}
+	// 想要访问的存储库，job 的密码，以及 job 的 token 服务器地址作为参数来获取访问存储器的 token 信息
+	token, err := utils.GetTokenForRepo(jobParms.Repository, j.secret, j.tokenEndpoint)
+	if err != nil {
+		logger.Errorf("Failed to get token, error: %v", err)
+		return err
+	}
+	logger.Infof("Scanning %s:%s(%s) with scanner %s", jobParms.Repository, jobParms.Tag, jobParms.Digest, scannerName)
+	scanID, err := adapter.Scan(&scanner.ScanRequest{
+		Registry: &scanner.Registry{
+			URL:           j.registryURL,
+			Authorization: fmt.Sprintf("Bearer %s", token),
+		},
+		Artifact: &scanner.Artifact{
+			Repository: jobParms.Repository,
+			Tag:        jobParms.Tag,
+			Digest:     jobParms.Digest,
+			MimeType:   scanner.MimeTypeDockerManifest,
+		},
+	})
+	if err != nil {
+		logger.Errorf("Failed to submit the scan request to scanner %s, error: %v", scannerName, err)
+		return err
+	}
+	report, err := waitForReport(ctx, adapter, scanID)
+	if err != nil {
+		logger.Errorf("Failed to get the report from scanner %s, error: %v", scannerName, err)
+		return err
+	}
+	// 漏洞概要信息
+	compOverview, sev := report.Overview()
+	if err = dao.SetScanReport(&models.ScanReport{
+		Digest:  jobParms.Digest,
+		Scanner: scannerName,
+		JobID:   jobParms.JobID,
+		Sev:     int(sev),
+		Report:  report,
+	}); err != nil {
+		logger.Errorf("Failed to save the report of scanner %s, error: %v", scannerName, err)
+		return err
+	}
+	// the details of the Clair deployed with Harbor are queried by the name of
+	// the top layer, the details of the registered scanners are read from the saved report
+	detailsKey := ""
+	if len(jobParms.ScannerAdapter) == 0 {
+		detailsKey = scanID
+	}
+	// 将漏洞信息存储到数据库中
+	return dao.UpdateImgScanOverview(jobParms.Digest, detailsKey, sev, compOverview)
+}
+
+func (j *Job) init(ctx env.JobContext) error {
+	errTpl := "Failed to get required property: %s"
+	if v, ok := ctx.Get(common.RegistryURL); ok && len(v.(string)) > 0 {
+		j.registryURL = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.RegistryURL)
+	}
+
+	if v := os.Getenv("JOBSERVICE_SECRET"); len(v) > 0 {
+		j.secret = v
+	} else {
+		return fmt.Errorf(errTpl, "JOBSERVICE_SECRET")
+	}
+	if v, ok := ctx.Get(common.TokenServiceURL); ok && len(v.(string)) > 0 {
+		j.tokenEndpoint = v.(string)
+	} else {
+		return fmt.Errorf(errTpl, common.TokenServiceURL)
+	}
+	// the endpoint of Clair is only required when no scanner is specified
+	if v, ok := ctx.Get(common.ClairURL); ok {
+		j.clairEndpoint, _ = v.(string)
+	}
+	return nil
+}
+
+// newAdapter returns the name and the adapter of the scanner specified in the parameters
+func (j *Job) newAdapter(parms *job.ScanJobParms, logger logger.Interface) (string, scanner.Adapter, error) {
+	if len(parms.ScannerAdapter) > 0 {
+		adapter, err := scanner.New(parms.ScannerAdapter, parms.ScannerURL, logger)
+		return parms.Scanner, adapter, err
+	}
+	if len(j.clairEndpoint) == 0 {
+		return "", nil, fmt.Errorf("Failed to get required property: %s", common.ClairURL)
+	}
+	adapter, err := scanner.New(clair.AdapterClair, j.clairEndpoint, logger)
+	return clair.ScannerName, adapter, err
+}
+
+// waitForReport polls the report of the scanning until it's ready
+func waitForReport(ctx env.JobContext, adapter scanner.Adapter, scanID string) (*models.VulnerabilityReport, error) {
+	timeout := time.After(reportTimeout)
+	for {
+		report, err := adapter.GetReport(scanID)
+		if err != scanner.ErrReportNotReady {
+			return report, err
+		}
+		ctx.GetLogger().Debugf("The report of %s is not ready, retry after %v", scanID, reportPollInterval)
+		select {
+		case <-time.After(reportPollInterval):
+		case <-timeout:
+			return nil, fmt.Errorf("timeout waiting for the report of %s", scanID)
+		}
+		if _, canceled := ctx.OPCommand(); canceled {
+			return nil, errCanceled
+		}
+	}
+}
+
+func transformParam(params map[string]interface{}) (*job.ScanJobParms, error) {
+	res := job.ScanJobParms{}
+	parmsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(parmsBytes, &res)
+	return &res, err
+}
@@ -0,0 +1,126 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/scanner"
+	"github.com/goharbor/harbor/src/common/utils/clair"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	jmodels "github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeContext struct {
+	canceled bool
+}
+
+func (f *fakeContext) Build(dep env.JobData) (env.JobContext, error) {
+	return f, nil
+}
+
+func (f *fakeContext) Get(prop string) (interface{}, bool) {
+	return nil, false
+}
+
+func (f *fakeContext) SystemContext() context.Context {
+	return context.Background()
+}
+
+func (f *fakeContext) Checkin(status string) error {
+	return nil
+}
+
+func (f *fakeContext) OPCommand() (string, bool) {
+	return "", f.canceled
+}
+
+func (f *fakeContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeContext) LaunchJob(req jmodels.JobRequest) (jmodels.JobStats, error) {
+	return jmodels.JobStats{}, nil
+}
+
+// fakeAdapter returns the report after being polled for the specified times
+type fakeAdapter struct {
+	pending int
+}
+
+func (f *fakeAdapter) Metadata() (*scanner.Metadata, error) {
+	return &scanner.Metadata{}, nil
+}
+
+func (f *fakeAdapter) Scan(req *scanner.ScanRequest) (string, error) {
+	return "id", nil
+}
+
+func (f *fakeAdapter) GetReport(scanID string) (*models.VulnerabilityReport, error) {
+	if f.pending > 0 {
+		f.pending--
+		return nil, scanner.ErrReportNotReady
+	}
+	return &models.VulnerabilityReport{Components: 1}, nil
+}
+
+func TestNewAdapter(t *testing.T) {
+	j := &Job{}
+	// Clair is not deployed
+	_, _, err := j.newAdapter(&job.ScanJobParms{}, nil)
+	assert.NotNil(t, err)
+
+	j.clairEndpoint = "http://clair:6060"
+	name, _, err := j.newAdapter(&job.ScanJobParms{}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, clair.ScannerName, name)
+
+	name, _, err = j.newAdapter(&job.ScanJobParms{
+		Scanner:        "scanner1",
+		ScannerAdapter: scanner.AdapterGeneric,
+		ScannerURL:     "http://scanner1:8080",
+	}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, "scanner1", name)
+
+	_, _, err = j.newAdapter(&job.ScanJobParms{
+		Scanner:        "scanner2",
+		ScannerAdapter: "non-exist",
+	}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestWaitForReport(t *testing.T) {
+	interval := reportPollInterval
+	reportPollInterval = time.Millisecond
+	defer func() {
+		reportPollInterval = interval
+	}()
+
+	report, err := waitForReport(&fakeContext{}, &fakeAdapter{pending: 2}, "id")
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.Components)
+
+	_, err = waitForReport(&fakeContext{canceled: true}, &fakeAdapter{pending: 2}, "id")
+	assert.Equal(t, errCanceled, err)
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/webhook"
+	"github.com/goharbor/harbor/src/jobservice/env"
+)
+
+// the timeout of each delivery
+const timeout = 10 * time.Second
+
+// Job sends the signed payload of the event to the target of webhook policy,
+// the delivery is retried by jobservice if the target doesn't return 2xx
+type Job struct{}
+
+// MaxFails implements the interface in job/Interface
+func (j *Job) MaxFails() uint {
+	return 3
+}
+
+// ShouldRetry implements the interface in job/Interface
+func (j *Job) ShouldRetry() bool {
+	return true
+}
+
+// Validate implements the interface in job/Interface
+func (j *Job) Validate(params map[string]interface{}) error {
+	if address, ok := params["address"].(string); !ok || len(address) == 0 {
+		return errors.New("missing parameter address")
+	}
+	if _, ok := params["payload"].(string); !ok {
+		return errors.New("missing parameter payload")
+	}
+	if _, ok := params["event_type"].(string); !ok {
+		return errors.New("missing parameter event_type")
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (j *Job) Run(ctx env.JobContext, params map[string]interface{}) error {
+	logger := ctx.GetLogger()
+	address := params["address"].(string)
+	payload := []byte(params["payload"].(string))
+	eventType := params["event_type"].(string)
+	secret, _ := params["secret"].(string)
+	skipCertVerify, _ := params["skip_cert_verify"].(bool)
+
+	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventTypeHeader, eventType)
+	if len(secret) > 0 {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, payload))
+	}
+
+	client := &http.Client{
+		Transport: reg.GetHTTPTransport(skipCertVerify),
+		Timeout:   timeout,
+	}
+	logger.Infof("sending the %s event to %s", eventType, address)
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Errorf("failed to send the event to %s: %v", address, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = fmt.Errorf("unexpected status code %d returned by %s", resp.StatusCode, address)
+		logger.Error(err.Error())
+		return err
+	}
+	logger.Infof("the event is sent to %s successfully, status code: %d", address, resp.StatusCode)
+	return nil
+}
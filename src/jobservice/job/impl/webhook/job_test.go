@@ -0,0 +1,109 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/webhook"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeContext struct{}
+
+func (f *fakeContext) Build(dep env.JobData) (env.JobContext, error) {
+	return f, nil
+}
+
+func (f *fakeContext) Get(prop string) (interface{}, bool) {
+	return nil, false
+}
+
+func (f *fakeContext) SystemContext() context.Context {
+	return context.Background()
+}
+
+func (f *fakeContext) Checkin(status string) error {
+	return nil
+}
+
+func (f *fakeContext) OPCommand() (string, bool) {
+	return "", false
+}
+
+func (f *fakeContext) GetLogger() logger.Interface {
+	return backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4)
+}
+
+func (f *fakeContext) LaunchJob(req models.JobRequest) (models.JobStats, error) {
+	return models.JobStats{}, nil
+}
+
+func TestValidate(t *testing.T) {
+	j := &Job{}
+	assert.NotNil(t, j.Validate(map[string]interface{}{}))
+	assert.NotNil(t, j.Validate(map[string]interface{}{
+		"address": "http://127.0.0.1",
+	}))
+	assert.Nil(t, j.Validate(map[string]interface{}{
+		"address":    "http://127.0.0.1",
+		"payload":    "{}",
+		"event_type": "pushImage",
+	}))
+	assert.True(t, j.ShouldRetry())
+}
+
+func TestRun(t *testing.T) {
+	var body []byte
+	var signature, eventType string
+	code := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(webhook.SignatureHeader)
+		eventType = r.Header.Get(webhook.EventTypeHeader)
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	params := map[string]interface{}{
+		"address":    server.URL,
+		"payload":    `{"type":"pushImage"}`,
+		"event_type": "pushImage",
+		"secret":     "secret",
+	}
+	j := &Job{}
+	require.Nil(t, j.Run(&fakeContext{}, params))
+	assert.Equal(t, `{"type":"pushImage"}`, string(body))
+	assert.Equal(t, "pushImage", eventType)
+	assert.True(t, webhook.Verify("secret", body, signature))
+
+	// no secret
+	delete(params, "secret")
+	require.Nil(t, j.Run(&fakeContext{}, params))
+	assert.Empty(t, signature)
+
+	// failure is returned to retry
+	code = http.StatusInternalServerError
+	assert.NotNil(t, j.Run(&fakeContext{}, params))
+}
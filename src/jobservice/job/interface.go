@@ -31,6 +31,10 @@ type CheckInFunc func(message string)
 // 用来启动一个 job 中的子 job
 type LaunchJobFunc func(req models.JobRequest) (models.JobStats, error)
 
+// WorkflowNodeDoneFunc is designed to advance the workflow when the job of
+// the workflow node reaches the final status
+type WorkflowNodeDoneFunc func(workflowID, nodeID, status string) error
+
 // Interface defines the related injection and run entry methods.
 // 定义具体 job 的一些共有方法,如最大失败次数，尝试次数，验证参数合法性，运行 job
 type Interface interface {
@@ -29,4 +29,8 @@ const (
 	JobStatusSuccess = "Success"
 	// JobStatusScheduled : job status scheduled
 	JobStatusScheduled = "Scheduled"
+	// JobStatusWaiting   : the workflow node is waiting for the upstream nodes
+	JobStatusWaiting = "Waiting"
+	// JobStatusSkipped   : the workflow node is skipped as its condition is not met
+	JobStatusSkipped = "Skipped"
 )
@@ -0,0 +1,29 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+const (
+	// WorkflowConditionSuccess : run the workflow node when all the upstream nodes succeed, the default one
+	WorkflowConditionSuccess = "success"
+	// WorkflowConditionFailure : run the workflow node when any of the upstream nodes fails
+	WorkflowConditionFailure = "failure"
+	// WorkflowConditionAlways  : run the workflow node when all the upstream nodes are done
+	WorkflowConditionAlways = "always"
+
+	// ParamKeyWorkflowID   : reserved parameter to bind the job with the workflow
+	ParamKeyWorkflowID = "__workflow_id"
+	// ParamKeyWorkflowNode : reserved parameter to bind the job with the workflow node
+	ParamKeyWorkflowNode = "__workflow_node"
+)
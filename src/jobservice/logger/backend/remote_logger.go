@@ -0,0 +1,318 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	// defaultRemoteBatchSize is the default count of log entries pushed in one request
+	defaultRemoteBatchSize = 100
+	// defaultRemoteTimeout is the default timeout (seconds) of the requests sent to the log aggregator
+	defaultRemoteTimeout = 10
+	// remoteMaxBatches is the count of the batches buffered when the log aggregator is unavailable,
+	// the oldest entries are dropped if more entries are written
+	remoteMaxBatches = 10
+	// remoteRetryInterval is the interval of pushing the batches again after a failed push
+	remoteRetryInterval = 30 * time.Second
+)
+
+// RemoteLogEntry is the structured log entry exchanged with the log aggregation endpoint.
+type RemoteLogEntry struct {
+	JobID   string    `json:"job_id"`
+	Level   string    `json:"level"`
+	Time    time.Time `json:"time"`
+	Line    string    `json:"line,omitempty"`
+	Message string    `json:"message"`
+}
+
+// RemoteLogger is an implementation of logger.Interface.
+// It pushes the structured log entries to a log aggregation endpoint in batches from a background goroutine,
+// so writing the logs is never blocked by the log aggregator.
+type RemoteLogger struct {
+	backendLogger *log.Logger
+	client        *http.Client
+	endpoint      string
+	token         string
+	key           string
+	batchSize     int
+	maxBuffered   int
+	// the entries written but not received by the pusher yet
+	entries chan json.RawMessage
+	// the entries received by the pusher, only accessed by the pusher before it's stopped
+	batch   []json.RawMessage
+	dropped int32
+	lock    *sync.Mutex
+	once    *sync.Once
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// NewRemoteLogger crates a new remote logger
+// The key is the ID of the job the log entries belong to.
+func NewRemoteLogger(key string, level string, endpoint string, token string, batchSize int, timeout int, depth int) (*RemoteLogger, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty key of the remote logger")
+	}
+
+	if err := ValidateRemoteEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultRemoteBatchSize
+	}
+
+	if timeout <= 0 {
+		timeout = defaultRemoteTimeout
+	}
+
+	rl := &RemoteLogger{
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+		endpoint:    endpoint,
+		token:       token,
+		key:         key,
+		batchSize:   batchSize,
+		maxBuffered: batchSize * remoteMaxBatches,
+		entries:     make(chan json.RawMessage, batchSize*remoteMaxBatches),
+		batch:       make([]json.RawMessage, 0, batchSize),
+		lock:        new(sync.Mutex),
+		once:        new(sync.Once),
+		closing:     make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	rl.backendLogger = log.New(rl, &remoteFormatter{key}, parseLevel(level), depth)
+	go rl.loop()
+
+	return rl, nil
+}
+
+// ValidateRemoteEndpoint checks if the endpoint of the log aggregator is a valid http(s) URL
+func ValidateRemoteEndpoint(endpoint string) error {
+	if len(endpoint) == 0 {
+		return errors.New("empty endpoint of the log aggregator")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint of the log aggregator: %s", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid endpoint of the log aggregator: %s", endpoint)
+	}
+
+	return nil
+}
+
+// Write implements io.Writer.
+// It hands the formatted entries from the backend logger over to the pusher,
+// the oldest entries are dropped if the pusher falls behind.
+func (rl *RemoteLogger) Write(p []byte) (int, error) {
+	entry := make(json.RawMessage, len(p))
+	copy(entry, p)
+
+	for {
+		select {
+		case rl.entries <- entry:
+			return len(p), nil
+		default:
+		}
+
+		select {
+		case <-rl.entries:
+			rl.warnDropped()
+		default:
+		}
+	}
+}
+
+// Close stops the pusher and pushes the remaining log entries to the log aggregator,
+// the entries are kept for the next call if failed.
+// Implements logger.Closer interface
+func (rl *RemoteLogger) Close() error {
+	rl.once.Do(func() {
+		close(rl.closing)
+	})
+	<-rl.stopped
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	return rl.pushBatches(true)
+}
+
+// loop receives the written entries and pushes them once a batch is full. It stops
+// pushing until the retry interval passes after a failed push, and the entries received
+// in the meantime are buffered up to maxBuffered.
+func (rl *RemoteLogger) loop() {
+	defer close(rl.stopped)
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case entry := <-rl.entries:
+			rl.buffer(entry)
+		case <-retry:
+			retry = nil
+		case <-rl.closing:
+			// the remaining entries are pushed by Close
+			for {
+				select {
+				case entry := <-rl.entries:
+					rl.buffer(entry)
+				default:
+					return
+				}
+			}
+		}
+
+		if retry == nil {
+			if err := rl.pushBatches(false); err != nil {
+				log.Errorf("failed to push the logs to the log aggregator, retry after %v: %v", remoteRetryInterval, err)
+				retry = time.After(remoteRetryInterval)
+			}
+		}
+	}
+}
+
+// buffer appends the entry to the batch and drops the oldest ones if there are too many
+func (rl *RemoteLogger) buffer(entry json.RawMessage) {
+	rl.batch = append(rl.batch, entry)
+	if n := len(rl.batch) - rl.maxBuffered; n > 0 {
+		rl.warnDropped()
+		rl.batch = append(rl.batch[:0], rl.batch[n:]...)
+	}
+}
+
+// warnDropped logs a warning the first time the entries are dropped after a successful push
+func (rl *RemoteLogger) warnDropped() {
+	if atomic.CompareAndSwapInt32(&rl.dropped, 0, 1) {
+		log.Warningf("the log aggregator %s is unavailable, dropping the oldest log entries of %s", rl.endpoint, rl.key)
+	}
+}
+
+// pushBatches pushes the full batches, and the last partial one if all is set.
+// The entries not pushed are kept for the next try if failed.
+func (rl *RemoteLogger) pushBatches(all bool) error {
+	for len(rl.batch) >= rl.batchSize || (all && len(rl.batch) > 0) {
+		n := rl.batchSize
+		if n > len(rl.batch) {
+			n = len(rl.batch)
+		}
+		if err := rl.push(rl.batch[:n]); err != nil {
+			return err
+		}
+		rl.batch = append(rl.batch[:0], rl.batch[n:]...)
+		atomic.StoreInt32(&rl.dropped, 0)
+	}
+	return nil
+}
+
+// push sends the batch of entries to the log aggregator
+func (rl *RemoteLogger) push(batch []json.RawMessage) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, rl.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(rl.token) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", rl.token))
+	}
+
+	res, err := rl.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push logs of %s to %s failed with error: %s", rl.key, rl.endpoint, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("push logs of %s to %s failed: %d %s", rl.key, rl.endpoint, res.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Debug ...
+func (rl *RemoteLogger) Debug(v ...interface{}) {
+	rl.backendLogger.Debug(v...)
+}
+
+// Debugf with format
+func (rl *RemoteLogger) Debugf(format string, v ...interface{}) {
+	rl.backendLogger.Debugf(format, v...)
+}
+
+// Info ...
+func (rl *RemoteLogger) Info(v ...interface{}) {
+	rl.backendLogger.Info(v...)
+}
+
+// Infof with format
+func (rl *RemoteLogger) Infof(format string, v ...interface{}) {
+	rl.backendLogger.Infof(format, v...)
+}
+
+// Warning ...
+func (rl *RemoteLogger) Warning(v ...interface{}) {
+	rl.backendLogger.Warning(v...)
+}
+
+// Warningf with format
+func (rl *RemoteLogger) Warningf(format string, v ...interface{}) {
+	rl.backendLogger.Warningf(format, v...)
+}
+
+// Error ...
+func (rl *RemoteLogger) Error(v ...interface{}) {
+	rl.backendLogger.Error(v...)
+}
+
+// Errorf with format
+func (rl *RemoteLogger) Errorf(format string, v ...interface{}) {
+	rl.backendLogger.Errorf(format, v...)
+}
+
+// Fatal error
+func (rl *RemoteLogger) Fatal(v ...interface{}) {
+	rl.backendLogger.Fatal(v...)
+}
+
+// Fatalf error
+func (rl *RemoteLogger) Fatalf(format string, v ...interface{}) {
+	rl.backendLogger.Fatalf(format, v...)
+}
+
+// remoteFormatter formats the log record as a JSON encoded RemoteLogEntry
+type remoteFormatter struct {
+	key string
+}
+
+// Format implements log.Formatter
+func (rf *remoteFormatter) Format(r *log.Record) ([]byte, error) {
+	return json.Marshal(&RemoteLogEntry{
+		JobID:   rf.key,
+		Level:   levelName(r.Lvl),
+		Time:    r.Time,
+		Line:    r.Line,
+		Message: r.Msg,
+	})
+}
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test remote logger creation with invalid options
+func TestRemoteLoggerCreation(t *testing.T) {
+	if _, err := NewRemoteLogger("", "DEBUG", "http://127.0.0.1:9999", "", 10, 0, 4); err == nil {
+		t.Fatal("expect non nil error but got nil when creating remote logger without key")
+	}
+	if _, err := NewRemoteLogger("fake_job_ID", "DEBUG", "", "", 10, 0, 4); err == nil {
+		t.Fatal("expect non nil error but got nil when creating remote logger without endpoint")
+	}
+	if _, err := NewRemoteLogger("fake_job_ID", "DEBUG", "ftp://127.0.0.1", "", 10, 0, 4); err == nil {
+		t.Fatal("expect non nil error but got nil when creating remote logger with non http endpoint")
+	}
+}
+
+// Test remote logger
+func TestRemoteLogger(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		pushes  int
+		entries []*RemoteLogEntry
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer fake_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		batch := []*RemoteLogEntry{}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		pushes++
+		entries = append(entries, batch...)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	l, err := NewRemoteLogger("fake_job_ID", "INFO", server.URL, "fake_token", 2, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("TestRemoteLogger")
+	l.Info("TestRemoteLogger")
+	l.Warningf("%s", "TestRemoteLogger")
+	l.Error("TestRemoteLogger")
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if pushes != 2 {
+		t.Errorf("expect 2 pushes but got %d", pushes)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries but got %d", len(entries))
+	}
+	if entries[0].JobID != "fake_job_ID" {
+		t.Errorf("expect job ID fake_job_ID but got %s", entries[0].JobID)
+	}
+	if entries[0].Level != "INFO" || entries[1].Level != "WARNING" || entries[2].Level != "ERROR" {
+		t.Errorf("expect levels INFO, WARNING, ERROR but got %s, %s, %s", entries[0].Level, entries[1].Level, entries[2].Level)
+	}
+	if entries[2].Message != "TestRemoteLogger" {
+		t.Errorf("expect message TestRemoteLogger but got %s", entries[2].Message)
+	}
+}
+
+// Test remote logger keeps the entries if pushing failed
+func TestRemoteLoggerPushFailure(t *testing.T) {
+	var failed int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failed) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	l, err := NewRemoteLogger("fake_job_ID", "DEBUG", server.URL, "", 10, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("TestRemoteLoggerPushFailure")
+	if err := l.Close(); err == nil {
+		t.Fatal("expect non nil error but got nil when pushing to an unavailable endpoint")
+	}
+	if len(l.batch) != 1 {
+		t.Fatalf("expect 1 pending entry but got %d", len(l.batch))
+	}
+
+	atomic.StoreInt32(&failed, 0)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.batch) != 0 {
+		t.Errorf("expect no pending entry but got %d", len(l.batch))
+	}
+}
+
+// Test remote logger drops the oldest entries and backs off pushing if the endpoint is unavailable
+func TestRemoteLoggerBufferLimit(t *testing.T) {
+	var (
+		failed   int32 = 1
+		requests int32
+		lock     sync.Mutex
+		entries  []*RemoteLogEntry
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failed) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		batch := []*RemoteLogEntry{}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		entries = append(entries, batch...)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	l, err := NewRemoteLogger("fake_job_ID", "DEBUG", server.URL, "", 2, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 30; i++ {
+		l.Infof("%d", i)
+	}
+	// the pusher backs off after the first failed push
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expect 1 push before the retry interval passes but got %d", n)
+	}
+
+	atomic.StoreInt32(&failed, 0)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(entries) != 20 {
+		t.Fatalf("expect 20 entries but got %d", len(entries))
+	}
+	if entries[0].Message != "10" || entries[19].Message != "29" {
+		t.Errorf("expect the entries from 10 to 29 but got %s to %s", entries[0].Message, entries[19].Message)
+	}
+}
@@ -27,3 +27,16 @@ func TestParseLevel(t *testing.T) {
 		t.Errorf("expect level %d but got %d", log.FatalLevel, l)
 	}
 }
+
+// Test levelName
+func TestLevelName(t *testing.T) {
+	if n := levelName(log.DebugLevel); n != "DEBUG" {
+		t.Errorf("expect level name DEBUG but got %s", n)
+	}
+	if n := levelName(log.WarningLevel); n != "WARNING" {
+		t.Errorf("expect level name WARNING but got %s", n)
+	}
+	if n := levelName(log.Level(100)); n != "UNKNOWN" {
+		t.Errorf("expect level name UNKNOWN but got %s", n)
+	}
+}
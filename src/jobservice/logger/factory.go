@@ -87,3 +87,40 @@ func DBFactory(options ...OptionItem) (Interface, error) {
 
 	return backend.NewDBLogger(key, level, depth)
 }
+
+// RemoteFactory is factory of remote logger
+func RemoteFactory(options ...OptionItem) (Interface, error) {
+	var (
+		level, key, endpoint, token string
+		depth, batchSize, timeout   int
+	)
+	for _, op := range options {
+		switch op.Field() {
+		case "level":
+			level = op.String()
+		case "key":
+			key = op.String()
+		case "endpoint":
+			endpoint = op.String()
+		case "token":
+			token = op.String()
+		case "batch_size":
+			batchSize = op.Int()
+		case "timeout":
+			timeout = op.Int()
+		case "depth":
+			depth = op.Int()
+		default:
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("missing key option of the remote logger")
+	}
+
+	if len(endpoint) == 0 {
+		return nil, errors.New("missing endpoint option of the remote logger")
+	}
+
+	return backend.NewRemoteLogger(key, level, endpoint, token, batchSize, timeout, depth)
+}
@@ -72,3 +72,36 @@ func TestDBFactoryErr1(t *testing.T) {
 	_, err := DBFactory(ois...)
 	require.NotNil(t, err)
 }
+
+// TestRemoteFactory
+func TestRemoteFactory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"level", "DEBUG"})
+	ois = append(ois, OptionItem{"key", "key_remote_logger_unit_text"})
+	ois = append(ois, OptionItem{"endpoint", "http://127.0.0.1:9999/logs"})
+	ois = append(ois, OptionItem{"batch_size", 10})
+	ois = append(ois, OptionItem{"depth", 5})
+
+	_, err := RemoteFactory(ois...)
+	require.Nil(t, err)
+}
+
+// TestRemoteFactoryErr1
+func TestRemoteFactoryErr1(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"level", "DEBUG"})
+	ois = append(ois, OptionItem{"endpoint", "http://127.0.0.1:9999/logs"})
+
+	_, err := RemoteFactory(ois...)
+	require.NotNil(t, err)
+}
+
+// TestRemoteFactoryErr2
+func TestRemoteFactoryErr2(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"level", "DEBUG"})
+	ois = append(ois, OptionItem{"key", "key_remote_logger_unit_text"})
+
+	_, err := RemoteFactory(ois...)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,105 @@
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/errs"
+)
+
+const defaultRemoteTimeout = 10 * time.Second
+
+// remoteLogEntry is the structured log entry returned by the log aggregator
+type remoteLogEntry struct {
+	Level   string    `json:"level"`
+	Time    time.Time `json:"time"`
+	Line    string    `json:"line,omitempty"`
+	Message string    `json:"message"`
+}
+
+// RemoteGetter is responsible for retrieving the log data from the log aggregator
+type RemoteGetter struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+// NewRemoteGetter is constructor of RemoteGetter
+func NewRemoteGetter(endpoint string, token string, timeout int) *RemoteGetter {
+	t := defaultRemoteTimeout
+	if timeout > 0 {
+		t = time.Duration(timeout) * time.Second
+	}
+
+	return &RemoteGetter{
+		client: &http.Client{
+			Timeout: t,
+		},
+		endpoint: endpoint,
+		token:    token,
+	}
+}
+
+// Retrieve implements @Interface.Retrieve
+func (rg *RemoteGetter) Retrieve(logID string) ([]byte, error) {
+	if len(logID) == 0 {
+		return nil, errors.New("empty log identify")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?job_id=%s", rg.endpoint, url.QueryEscape(logID)), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(rg.token) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", rg.token))
+	}
+
+	res, err := rg.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errs.NoObjectFoundError(logID)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query logs of %s from %s failed: %d %s", logID, rg.endpoint, res.StatusCode, string(data))
+	}
+
+	entries := []*remoteLogEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, errs.NoObjectFoundError(logID)
+	}
+
+	// Render the entries in the same text format of the other loggers
+	buf := &bytes.Buffer{}
+	for _, e := range entries {
+		buf.WriteString(fmt.Sprintf("%s [%s] ", e.Time.Format(time.RFC3339), e.Level))
+		if len(e.Line) > 0 {
+			buf.WriteString(e.Line + " ")
+		}
+		buf.WriteString(e.Message)
+		if len(e.Message) == 0 || e.Message[len(e.Message)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.Bytes(), nil
+}
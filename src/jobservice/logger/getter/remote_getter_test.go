@@ -0,0 +1,69 @@
+package getter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/errs"
+)
+
+// Test RemoteGetter
+func TestRemoteGetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fake_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Query().Get("job_id") {
+		case "fake_job_ID":
+			entries := []*remoteLogEntry{
+				{Level: "INFO", Time: time.Now(), Line: "[a.go:1]", Message: "TestRemoteGetter"},
+				{Level: "ERROR", Time: time.Now(), Message: "TestRemoteGetter"},
+			}
+			json.NewEncoder(w).Encode(entries)
+		case "empty_job_ID":
+			w.Write([]byte("[]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rg := NewRemoteGetter(server.URL, "fake_token", 0)
+
+	if _, err := rg.Retrieve(""); err == nil {
+		t.Fatal("expect non nil error but got nil for empty log ID")
+	}
+
+	data, err := rg.Retrieve("fake_job_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 lines but got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "[INFO] [a.go:1] TestRemoteGetter") {
+		t.Errorf("unexpected log line: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "[ERROR] TestRemoteGetter") {
+		t.Errorf("unexpected log line: %s", lines[1])
+	}
+
+	if _, err := rg.Retrieve("empty_job_ID"); !errs.IsObjectNotFoundError(err) {
+		t.Errorf("expect object not found error but got %v", err)
+	}
+	if _, err := rg.Retrieve("not_existing_job_ID"); !errs.IsObjectNotFoundError(err) {
+		t.Errorf("expect object not found error but got %v", err)
+	}
+
+	rg = NewRemoteGetter(server.URL, "", 0)
+	if _, err := rg.Retrieve("fake_job_ID"); err == nil {
+		t.Error("expect non nil error but got nil when unauthorized")
+	}
+}
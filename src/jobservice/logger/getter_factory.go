@@ -3,6 +3,7 @@ package logger
 import (
 	"errors"
 
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
 	"github.com/goharbor/harbor/src/jobservice/logger/getter"
 )
 
@@ -30,3 +31,28 @@ func FileGetterFactory(options ...OptionItem) (getter.Interface, error) {
 func DBGetterFactory(options ...OptionItem) (getter.Interface, error) {
 	return getter.NewDBGetter(), nil
 }
+
+// RemoteGetterFactory creates a getter for the remote logger
+func RemoteGetterFactory(options ...OptionItem) (getter.Interface, error) {
+	var (
+		endpoint, token string
+		timeout         int
+	)
+	for _, op := range options {
+		switch op.Field() {
+		case "endpoint":
+			endpoint = op.String()
+		case "token":
+			token = op.String()
+		case "timeout":
+			timeout = op.Int()
+		default:
+		}
+	}
+
+	if err := backend.ValidateRemoteEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return getter.NewRemoteGetter(endpoint, token, timeout), nil
+}
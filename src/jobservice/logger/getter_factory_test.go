@@ -32,3 +32,21 @@ func TestDBGetterFactory(t *testing.T) {
 	_, err := DBGetterFactory(ois...)
 	require.Nil(t, err)
 }
+
+// TestRemoteGetterFactory
+func TestRemoteGetterFactory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"endpoint", "http://127.0.0.1:9999/logs"})
+
+	_, err := RemoteGetterFactory(ois...)
+	require.Nil(t, err)
+}
+
+// TestRemoteGetterFactoryErr1
+func TestRemoteGetterFactoryErr1(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"endpoint", "127.0.0.1:9999"})
+
+	_, err := RemoteGetterFactory(ois...)
+	require.NotNil(t, err)
+}
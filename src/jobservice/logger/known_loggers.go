@@ -13,6 +13,8 @@ const (
 	LoggerNameStdOutput = "STD_OUTPUT"
 	// LoggerNameDB is the unique name of the DB logger.
 	LoggerNameDB = "DB"
+	// LoggerNameRemote is the unique name of the remote logger.
+	LoggerNameRemote = "REMOTE"
 )
 
 // Declaration is used to declare a supported logger.
@@ -36,6 +38,8 @@ var knownLoggers = map[string]*Declaration{
 	LoggerNameStdOutput: {StdFactory, nil, nil, true},
 	// DB logger
 	LoggerNameDB: {DBFactory, DBSweeperFactory, DBGetterFactory, false},
+	// Remote logger pushing logs to the log aggregator
+	LoggerNameRemote: {RemoteFactory, RemoteSweeperFactory, RemoteGetterFactory, false},
 }
 
 // IsKnownLogger checks if the logger is supported with name.
@@ -102,6 +106,8 @@ func GetLoggerName(l Interface) string {
 		name = LoggerNameStdOutput
 	case *backend.FileLogger:
 		name = LoggerNameFile
+	case *backend.RemoteLogger:
+		name = LoggerNameRemote
 	default:
 		name = reflect.TypeOf(l).String()
 	}
@@ -22,6 +22,8 @@ func TestKnownLoggers(t *testing.T) {
 	// has getter
 	b = HasGetter(LoggerNameDB)
 	require.True(t, b)
+	b = HasGetter(LoggerNameRemote)
+	require.True(t, b)
 
 	// no sweeper
 	b = HasSweeper(LoggerNameStdOutput)
@@ -29,6 +31,8 @@ func TestKnownLoggers(t *testing.T) {
 	// has sweeper
 	b = HasSweeper(LoggerNameDB)
 	require.True(t, b)
+	b = HasSweeper(LoggerNameRemote)
+	require.True(t, b)
 
 	// unknown logger
 	l := KnownLoggers("unknown")
@@ -61,6 +65,10 @@ func TestGetLoggerName(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, LoggerNameFile, GetLoggerName(fileLog))
 
+	remoteLog, err := backend.NewRemoteLogger(uuid, "DEBUG", "http://127.0.0.1:9999/logs", "", 0, 0, 4)
+	require.Nil(t, err)
+	require.Equal(t, LoggerNameRemote, GetLoggerName(remoteLog))
+
 	e := &Entry{}
 	n := GetLoggerName(e)
 	require.NotNil(t, n)
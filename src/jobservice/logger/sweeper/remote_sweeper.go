@@ -0,0 +1,71 @@
+package sweeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// RemoteSweeper is used to apply the log retention on the log aggregator
+type RemoteSweeper struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	duration int
+}
+
+// NewRemoteSweeper is constructor of RemoteSweeper
+func NewRemoteSweeper(endpoint string, token string, duration int) *RemoteSweeper {
+	return &RemoteSweeper{
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+		endpoint: endpoint,
+		token:    token,
+		duration: duration,
+	}
+}
+
+// Sweep logs
+func (rs *RemoteSweeper) Sweep() (int, error) {
+	before := time.Now().Add(time.Duration(rs.duration) * oneDay * -1)
+
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s?before=%d", rs.endpoint, before.Unix()), nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(rs.token) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", rs.token))
+	}
+
+	res, err := rs.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sweep logs in %s failed before %s with error: %s", rs.endpoint, before, err)
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("sweep logs in %s failed before %s: %d %s", rs.endpoint, before, res.StatusCode, string(data))
+	}
+
+	result := struct {
+		Deleted int `json:"deleted"`
+	}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Deleted, nil
+}
+
+// Duration for sweeping
+func (rs *RemoteSweeper) Duration() int {
+	return rs.duration
+}
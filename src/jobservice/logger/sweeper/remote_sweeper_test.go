@@ -0,0 +1,51 @@
+package sweeper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Test RemoteSweeper
+func TestRemoteSweeper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		before, err := strconv.ParseInt(r.URL.Query().Get("before"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Only the logs older than 5 days can be deleted
+		if time.Now().Unix()-before < 5*24*3600 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"deleted":3}`))
+	}))
+	defer server.Close()
+
+	rs := NewRemoteSweeper(server.URL, "", 5)
+	if rs.Duration() != 5 {
+		t.Errorf("expect duration 5 but got %d", rs.Duration())
+	}
+
+	count, err := rs.Sweep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expect count 3 but got %d", count)
+	}
+
+	rs = NewRemoteSweeper(server.URL, "", 1)
+	if _, err := rs.Sweep(); err == nil {
+		t.Error("expect non nil error but got nil when the aggregator rejected the sweeping")
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"errors"
 
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
 	"github.com/goharbor/harbor/src/jobservice/logger/sweeper"
 )
 
@@ -46,3 +47,30 @@ func DBSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 
 	return sweeper.NewDBSweeper(duration), nil
 }
+
+// RemoteSweeperFactory creates the sweeper applying log retention on the log aggregator.
+func RemoteSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
+	var (
+		endpoint, token string
+		duration        = 1
+	)
+	for _, op := range options {
+		switch op.Field() {
+		case "endpoint":
+			endpoint = op.String()
+		case "token":
+			token = op.String()
+		case "duration":
+			if op.Int() > 0 {
+				duration = op.Int()
+			}
+		default:
+		}
+	}
+
+	if err := backend.ValidateRemoteEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return sweeper.NewRemoteSweeper(endpoint, token, duration), nil
+}
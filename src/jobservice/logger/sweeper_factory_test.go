@@ -32,3 +32,22 @@ func TestDBSweeperFactory(t *testing.T) {
 	_, err := DBSweeperFactory(ois...)
 	require.Nil(t, err)
 }
+
+// TestRemoteSweeperFactory
+func TestRemoteSweeperFactory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"duration", 2})
+	ois = append(ois, OptionItem{"endpoint", "http://127.0.0.1:9999/logs"})
+
+	_, err := RemoteSweeperFactory(ois...)
+	require.Nil(t, err)
+}
+
+// TestRemoteSweeperFactoryErr
+func TestRemoteSweeperFactoryErr(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"duration", 2})
+
+	_, err := RemoteSweeperFactory(ois...)
+	require.NotNil(t, err)
+}
@@ -64,6 +64,28 @@ type JobStatData struct {
 	IsMultipleExecutions bool     `json:"multiple_executions"`       // Indicate if the job has subsequent executions
 }
 
+// JobQuery represents the conditions of listing jobs.
+type JobQuery struct {
+	JobName       string
+	Status        string
+	UpstreamJobID string
+	// Scope of the enqueue time (unix seconds), 0 means no limit
+	From int64
+	To   int64
+	// Page starts from 1
+	PageNumber uint
+	PageSize   uint
+}
+
+// JobStatsList represents one page of the jobs matching the query.
+type JobStatsList struct {
+	Total int64          `json:"total"`
+	Jobs  []*JobStatData `json:"jobs"`
+	// Truncated is set if only the latest jobs are scanned for the filtered listing,
+	// the total is counted from the scanned ones then
+	Truncated bool `json:"truncated,omitempty"`
+}
+
 // JobPoolStats represents the healthy and status of all the running worker pools.
 type JobPoolStats struct {
 	Pools []*JobPoolStatsData `json:"worker_pools"`
@@ -71,12 +93,22 @@ type JobPoolStats struct {
 
 // JobPoolStatsData represent the healthy and status of the worker pool.
 type JobPoolStatsData struct {
-	WorkerPoolID string   `json:"worker_pool_id"`
-	StartedAt    int64    `json:"started_at"`
-	HeartbeatAt  int64    `json:"heartbeat_at"`
-	JobNames     []string `json:"job_names"`
-	Concurrency  uint     `json:"concurrency"`
-	Status       string   `json:"status"`
+	WorkerPoolID string          `json:"worker_pool_id"`
+	StartedAt    int64           `json:"started_at"`
+	HeartbeatAt  int64           `json:"heartbeat_at"`
+	JobNames     []string        `json:"job_names"`
+	Concurrency  uint            `json:"concurrency"`
+	Status       string          `json:"status"`
+	Jobs         []*JobUsageData `json:"jobs,omitempty"`
+}
+
+// JobUsageData represents the settings and the current usage of the job in the worker pool.
+type JobUsageData struct {
+	Name           string `json:"name"`
+	Priority       uint   `json:"priority"`
+	MaxConcurrency uint   `json:"max_concurrency"` // 0 means no limit
+	Running        int    `json:"running"`         // running jobs in the worker pool
+	Pending        int64  `json:"pending"`         // queued jobs in all the worker pools
 }
 
 // JobActionRequest defines for triggering job action like stop/cancel.
@@ -92,6 +124,51 @@ type JobStatusChange struct {
 	Metadata *JobStatData `json:"metadata,omitempty"`
 }
 
+// WorkflowRequest is the request of launching a workflow.
+type WorkflowRequest struct {
+	Workflow *WorkflowData `json:"workflow"`
+}
+
+// WorkflowData keeps the nodes of the workflow.
+type WorkflowData struct {
+	Name  string          `json:"name"`
+	Nodes []*WorkflowNode `json:"nodes"`
+}
+
+// WorkflowNode is a job of the workflow which may depend on the upstream nodes.
+type WorkflowNode struct {
+	ID        string   `json:"id"`
+	Job       *JobData `json:"job"`
+	Upstreams []string `json:"upstreams,omitempty"`
+	Condition string   `json:"condition,omitempty"` // When to run the node according to the upstream nodes
+}
+
+// WorkflowStats keeps the result of workflow launching.
+type WorkflowStats struct {
+	Stats *WorkflowStatData `json:"workflow"`
+}
+
+// WorkflowStatData keeps the stats of workflow
+type WorkflowStatData struct {
+	WorkflowID  string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Status      string                  `json:"status"`
+	EnqueueTime int64                   `json:"enqueue_time"`
+	UpdateTime  int64                   `json:"update_time"`
+	Nodes       []*WorkflowNodeStatData `json:"nodes"`
+	Definition  *WorkflowData           `json:"-"` // The submitted workflow for launching the downstream nodes
+}
+
+// WorkflowNodeStatData keeps the stats of workflow node
+type WorkflowNodeStatData struct {
+	NodeID    string   `json:"id"`
+	JobName   string   `json:"job_name"`
+	JobID     string   `json:"job_id,omitempty"`
+	Status    string   `json:"status"`
+	Upstreams []string `json:"upstreams,omitempty"`
+	Condition string   `json:"condition"`
+}
+
 // Message is designed for sub/pub messages
 type Message struct {
 	Event string
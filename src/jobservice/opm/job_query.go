@@ -0,0 +1,78 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opm
+
+import (
+	"sort"
+
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+)
+
+// matchJobQuery checks if the job stats meet all the conditions of the query
+func matchJobQuery(stats *models.JobStatData, query models.JobQuery) bool {
+	if !utils.IsEmptyStr(query.JobName) && stats.JobName != query.JobName {
+		return false
+	}
+	if !utils.IsEmptyStr(query.Status) && stats.Status != query.Status {
+		return false
+	}
+	if !utils.IsEmptyStr(query.UpstreamJobID) && stats.UpstreamJobID != query.UpstreamJobID {
+		return false
+	}
+	if query.From > 0 && stats.EnqueueTime < query.From {
+		return false
+	}
+	if query.To > 0 && stats.EnqueueTime > query.To {
+		return false
+	}
+
+	return true
+}
+
+// pageJobStats sorts the matched job stats with the latest enqueued first and returns the queried page.
+// All the matched ones are returned if the page size is not set.
+func pageJobStats(matched []*models.JobStatData, query models.JobQuery) models.JobStatsList {
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].EnqueueTime > matched[j].EnqueueTime
+	})
+
+	res := models.JobStatsList{
+		Total: int64(len(matched)),
+		Jobs:  matched,
+	}
+	if query.PageSize == 0 {
+		return res
+	}
+
+	pageNumber := query.PageNumber
+	if pageNumber == 0 {
+		pageNumber = 1
+	}
+
+	start := int64(pageNumber-1) * int64(query.PageSize)
+	if start >= res.Total {
+		res.Jobs = []*models.JobStatData{}
+		return res
+	}
+
+	end := start + int64(query.PageSize)
+	if end > res.Total {
+		end = res.Total
+	}
+	res.Jobs = matched[start:end]
+
+	return res
+}
@@ -44,6 +44,16 @@ type JobStatsManager interface {
 	// 从后端存储中获取 job 状态
 	Retrieve(jobID string) (models.JobStats, error)
 
+	// List the stats of the jobs matching the query
+	// The latest enqueued jobs are returned first
+	//
+	// query models.JobQuery : the conditions and the page of the listing
+	//
+	// Returns:
+	//  models.JobStatsList : total count and the page of the matched job stats
+	//  error               : error if meet any problems
+	List(query models.JobQuery) (models.JobStatsList, error)
+
 	// Update the properties of the job stats
 	//
 	// jobID string                  : ID of the being retried job
@@ -147,4 +157,54 @@ type JobStatsManager interface {
 	//  or a non-nil error is returned
 	// 从指定的上游 job，获取所有正在执行的 jobid
 	GetExecutions(upstreamJobID string, ranges ...Range) ([]string, error)
+
+	// SaveWorkflow persists the definition and the initial stats of the workflow.
+	// Sync method as the workflow should be existing before launching the root nodes
+	//
+	// stats models.WorkflowStats : the stats of workflow with the definition
+	//
+	// Returns:
+	//  error if meet any problems
+	SaveWorkflow(stats models.WorkflowStats) error
+
+	// RetrieveWorkflow gets the workflow stats from backend store.
+	// Only the final status of node is kept in the store.
+	//
+	// workflowID string : ID of the workflow
+	//
+	// Returns:
+	//  models.WorkflowStats : workflow stats with the definition
+	//  error                : error if meet any problems
+	RetrieveWorkflow(workflowID string) (models.WorkflowStats, error)
+
+	// ClaimWorkflowNode claims the node before launching it to make sure
+	// the node is only launched once.
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	//
+	// Returns:
+	//  true if the node is claimed by the caller
+	//  error if meet any problems
+	ClaimWorkflowNode(workflowID, nodeID string) (bool, error)
+
+	// SetWorkflowNodeJob binds the launched job with the workflow node.
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	// jobID string      : ID of the launched job
+	//
+	// Returns:
+	//  error if meet any problems
+	SetWorkflowNodeJob(workflowID, nodeID, jobID string) error
+
+	// SetWorkflowNodeStatus marks the final status of the workflow node.
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	// status string     : the final status
+	//
+	// Returns:
+	//  error if meet any problems
+	SetWorkflowNodeStatus(workflowID, nodeID, status string) error
 }
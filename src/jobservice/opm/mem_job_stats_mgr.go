@@ -0,0 +1,652 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+)
+
+const (
+	memStatsSweepInterval = time.Hour
+)
+
+// MemStatsSnapshot keeps all the data of MemJobStatsManager for persisting.
+type MemStatsSnapshot struct {
+	Stats      map[string]*models.JobStatData `json:"stats"`
+	Hooks      map[string]string              `json:"hooks"`
+	Executions map[string][]*memExecution     `json:"executions"`
+	Workflows  map[string]*memWorkflow        `json:"workflows"`
+	ExpireAt   map[string]int64               `json:"expire_at"`
+}
+
+type memExecution struct {
+	ID    string `json:"id"`
+	Score int64  `json:"score"`
+}
+
+type memWorkflow struct {
+	Stats  *models.WorkflowStatData `json:"stats"`
+	Fields map[string]string        `json:"fields"`
+}
+
+// MemJobStatsManager implements JobStatsManager in memory.
+// It's designed for the in-memory worker pool, the data can be persisted
+// with the snapshot returned by Export.
+type MemJobStatsManager struct {
+	context context.Context
+	lock    *sync.RWMutex
+	data    *MemStatsSnapshot
+	// commands are not persisted
+	commands map[string]string
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewMemJobStatsManager is constructor of MemJobStatsManager
+func NewMemJobStatsManager(ctx context.Context) *MemJobStatsManager {
+	return &MemJobStatsManager{
+		context:  ctx,
+		lock:     &sync.RWMutex{},
+		data:     newMemStatsSnapshot(),
+		commands: make(map[string]string),
+		stopChan: make(chan struct{}, 1),
+		doneChan: make(chan struct{}, 1),
+	}
+}
+
+// Start is implementation of same method in JobStatsManager interface.
+// The expired stats are swept periodically.
+func (mjs *MemJobStatsManager) Start() {
+	go func() {
+		ticker := time.NewTicker(memStatsSweepInterval)
+		defer func() {
+			ticker.Stop()
+			logger.Info("Memory job stats manager is stopped")
+		}()
+
+		for {
+			select {
+			case <-ticker.C:
+				mjs.sweep()
+			case <-mjs.stopChan:
+				mjs.doneChan <- struct{}{}
+				return
+			case <-mjs.context.Done():
+				return
+			}
+		}
+	}()
+
+	logger.Info("Memory job stats manager is started")
+}
+
+// Shutdown is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) Shutdown() {
+	mjs.stopChan <- struct{}{}
+	select {
+	case <-mjs.doneChan:
+	case <-mjs.context.Done():
+	}
+}
+
+// Export returns a deep copy of all the kept data.
+func (mjs *MemJobStatsManager) Export() *MemStatsSnapshot {
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	snapshot := newMemStatsSnapshot()
+	for id, stats := range mjs.data.Stats {
+		snapshot.Stats[id] = copyJobStatData(stats)
+	}
+	for id, hookURL := range mjs.data.Hooks {
+		snapshot.Hooks[id] = hookURL
+	}
+	for id, executions := range mjs.data.Executions {
+		snapshot.Executions[id] = append([]*memExecution{}, executions...)
+	}
+	for id, w := range mjs.data.Workflows {
+		fields := make(map[string]string, len(w.Fields))
+		for k, v := range w.Fields {
+			fields[k] = v
+		}
+		stats := *w.Stats
+		snapshot.Workflows[id] = &memWorkflow{Stats: &stats, Fields: fields}
+	}
+	for id, expireAt := range mjs.data.ExpireAt {
+		snapshot.ExpireAt[id] = expireAt
+	}
+
+	return snapshot
+}
+
+// Import replaces the kept data with the snapshot.
+func (mjs *MemJobStatsManager) Import(snapshot *MemStatsSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	data := newMemStatsSnapshot()
+	for id, stats := range snapshot.Stats {
+		data.Stats[id] = stats
+	}
+	for id, hookURL := range snapshot.Hooks {
+		data.Hooks[id] = hookURL
+	}
+	for id, executions := range snapshot.Executions {
+		data.Executions[id] = executions
+	}
+	for id, w := range snapshot.Workflows {
+		if w.Fields == nil {
+			w.Fields = make(map[string]string)
+		}
+		data.Workflows[id] = w
+	}
+	for id, expireAt := range snapshot.ExpireAt {
+		data.ExpireAt[id] = expireAt
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.data = data
+}
+
+// Save is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) Save(jobStats models.JobStats) {
+	if jobStats.Stats == nil || utils.IsEmptyStr(jobStats.Stats.JobID) {
+		logger.Error("malformed job stats object")
+		return
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.data.Stats[jobStats.Stats.JobID] = copyJobStatData(jobStats.Stats)
+	if jobStats.Stats.JobKind != job.JobKindPeriodic {
+		mjs.data.ExpireAt[jobStats.Stats.JobID] = time.Now().Unix() + jobStatsDataExpireTime
+	}
+}
+
+// Retrieve is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) Retrieve(jobID string) (models.JobStats, error) {
+	if utils.IsEmptyStr(jobID) {
+		return models.JobStats{}, errors.New("empty job ID")
+	}
+
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	stats, ok := mjs.data.Stats[jobID]
+	if !ok {
+		return models.JobStats{}, errs.NoObjectFoundError(fmt.Sprintf("job '%s'", jobID))
+	}
+
+	res := models.JobStats{
+		Stats: copyJobStatData(stats),
+	}
+	if res.Stats.IsMultipleExecutions {
+		res.Stats.Executions = mjs.executions(jobID, "-inf", "+inf")
+	}
+
+	return res, nil
+}
+
+// List is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) List(query models.JobQuery) (models.JobStatsList, error) {
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	matched := make([]*models.JobStatData, 0)
+	for _, stats := range mjs.data.Stats {
+		if matchJobQuery(stats, query) {
+			matched = append(matched, copyJobStatData(stats))
+		}
+	}
+
+	return pageJobStats(matched, query), nil
+}
+
+// Update is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) Update(jobID string, fieldAndValues ...interface{}) error {
+	if len(jobID) == 0 {
+		return errors.New("no updating job")
+	}
+
+	if len(fieldAndValues) == 0 || len(fieldAndValues)%2 != 0 {
+		return errors.New("filed and its value should be pair")
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	stats, ok := mjs.data.Stats[jobID]
+	if !ok {
+		return errs.NoObjectFoundError(fmt.Sprintf("job '%s'", jobID))
+	}
+
+	for i := 0; i < len(fieldAndValues); i += 2 {
+		field, ok := fieldAndValues[i].(string)
+		if !ok {
+			return fmt.Errorf("malformed field %v", fieldAndValues[i])
+		}
+		if err := setJobStatsField(stats, field, fieldAndValues[i+1]); err != nil {
+			return err
+		}
+	}
+	stats.UpdateTime = time.Now().Unix()
+
+	return nil
+}
+
+// SetJobStatus is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) SetJobStatus(jobID string, status string) {
+	if utils.IsEmptyStr(jobID) || utils.IsEmptyStr(status) {
+		return
+	}
+
+	args := []interface{}{"status", status}
+	if status == job.JobStatusSuccess {
+		// make sure the 'die_at' is reset in case it's a retrying job
+		args = append(args, "die_at", int64(0))
+	}
+	if err := mjs.Update(jobID, args...); err != nil {
+		logger.Errorf("Failed to set status of job %s: %s", jobID, err)
+		return
+	}
+
+	// Report status at the same time
+	mjs.reportStatus(jobID, status, "")
+}
+
+// SendCommand is implementation of same method in JobStatsManager interface.
+// The command is always kept in memory as there is only one worker pool.
+func (mjs *MemJobStatsManager) SendCommand(jobID string, command string, isCached bool) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	if command != CtlCommandStop && command != CtlCommandCancel {
+		return errors.New("unknown command")
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.commands[jobID] = command
+
+	return nil
+}
+
+// CtlCommand is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) CtlCommand(jobID string) (string, error) {
+	if utils.IsEmptyStr(jobID) {
+		return "", errors.New("empty job ID")
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	c, ok := mjs.commands[jobID]
+	if !ok {
+		return "", fmt.Errorf("no OP command fired to job %s", jobID)
+	}
+	delete(mjs.commands, jobID)
+
+	return c, nil
+}
+
+// CheckIn is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) CheckIn(jobID string, message string) {
+	if utils.IsEmptyStr(jobID) || utils.IsEmptyStr(message) {
+		return
+	}
+
+	if err := mjs.Update(jobID, "check_in", message, "check_in_at", time.Now().Unix()); err != nil {
+		logger.Errorf("Failed to check in message for job %s: %s", jobID, err)
+		return
+	}
+
+	// Report checkin message at the same time
+	mjs.reportStatus(jobID, job.JobStatusRunning, message)
+}
+
+// DieAt is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) DieAt(jobID string, dieAt int64) {
+	if utils.IsEmptyStr(jobID) || dieAt == 0 {
+		return
+	}
+
+	if err := mjs.Update(jobID, "die_at", dieAt); err != nil {
+		logger.Errorf("Failed to mark job %s dead: %s", jobID, err)
+	}
+}
+
+// RegisterHook is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) RegisterHook(jobID string, hookURL string, isCached bool) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	if !utils.IsValidURL(hookURL) {
+		return errors.New("invalid hook url")
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.data.Hooks[jobID] = hookURL
+
+	return nil
+}
+
+// GetHook is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) GetHook(jobID string) (string, error) {
+	if utils.IsEmptyStr(jobID) {
+		return "", errors.New("empty job ID")
+	}
+
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	hookURL, ok := mjs.data.Hooks[jobID]
+	if !ok {
+		return "", errs.NoObjectFoundError(fmt.Sprintf("hook of job '%s'", jobID))
+	}
+
+	return hookURL, nil
+}
+
+// ExpirePeriodicJobStats is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) ExpirePeriodicJobStats(jobID string) error {
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.data.ExpireAt[jobID] = time.Now().Unix() + jobStatsDataExpireTime
+
+	return nil
+}
+
+// AttachExecution is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) AttachExecution(upstreamJobID string, executions ...string) error {
+	if len(upstreamJobID) == 0 {
+		return errors.New("empty upstream job ID is not allowed")
+	}
+
+	if len(executions) == 0 {
+		return errors.New("no executions existing to persist")
+	}
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	baseScore := time.Now().Unix()
+	for index, execution := range executions {
+		mjs.data.Executions[upstreamJobID] = append(mjs.data.Executions[upstreamJobID], &memExecution{
+			ID:    execution,
+			Score: baseScore + int64(index),
+		})
+	}
+
+	return nil
+}
+
+// GetExecutions is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) GetExecutions(upstreamJobID string, ranges ...Range) ([]string, error) {
+	if len(upstreamJobID) == 0 {
+		return nil, errors.New("no upstream ID specified")
+	}
+
+	var start, end interface{} = "-inf", "+inf"
+	if len(ranges) >= 1 {
+		start = int64(ranges[0])
+	}
+	if len(ranges) > 1 {
+		end = int64(ranges[1])
+	}
+
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	return mjs.executions(upstreamJobID, start, end), nil
+}
+
+// SaveWorkflow is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) SaveWorkflow(stats models.WorkflowStats) error {
+	if stats.Stats == nil || stats.Stats.Definition == nil {
+		return errors.New("malformed workflow stats object")
+	}
+
+	saved := *stats.Stats
+	saved.Nodes = nil
+
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	mjs.data.Workflows[saved.WorkflowID] = &memWorkflow{
+		Stats:  &saved,
+		Fields: make(map[string]string),
+	}
+	mjs.data.ExpireAt[saved.WorkflowID] = time.Now().Unix() + jobStatsDataExpireTime
+
+	return nil
+}
+
+// RetrieveWorkflow is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) RetrieveWorkflow(workflowID string) (models.WorkflowStats, error) {
+	if utils.IsEmptyStr(workflowID) {
+		return models.WorkflowStats{}, errors.New("empty workflow ID")
+	}
+
+	mjs.lock.RLock()
+	defer mjs.lock.RUnlock()
+
+	w, ok := mjs.data.Workflows[workflowID]
+	if !ok {
+		return models.WorkflowStats{}, errs.NoObjectFoundError(fmt.Sprintf("workflow '%s'", workflowID))
+	}
+
+	stats := *w.Stats
+	for _, node := range stats.Definition.Nodes {
+		nodeStats := &models.WorkflowNodeStatData{
+			NodeID:    node.ID,
+			JobID:     w.Fields[workflowNodeField(node.ID, "job")],
+			Status:    w.Fields[workflowNodeField(node.ID, "status")],
+			Upstreams: node.Upstreams,
+			Condition: node.Condition,
+		}
+		if node.Job != nil {
+			nodeStats.JobName = node.Job.Name
+		}
+		stats.Nodes = append(stats.Nodes, nodeStats)
+	}
+
+	return models.WorkflowStats{Stats: &stats}, nil
+}
+
+// ClaimWorkflowNode is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) ClaimWorkflowNode(workflowID, nodeID string) (bool, error) {
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	w, ok := mjs.data.Workflows[workflowID]
+	if !ok {
+		return false, errs.NoObjectFoundError(fmt.Sprintf("workflow '%s'", workflowID))
+	}
+
+	field := workflowNodeField(nodeID, "claim")
+	if _, claimed := w.Fields[field]; claimed {
+		return false, nil
+	}
+	w.Fields[field] = fmt.Sprintf("%d", time.Now().Unix())
+
+	return true, nil
+}
+
+// SetWorkflowNodeJob is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) SetWorkflowNodeJob(workflowID, nodeID, jobID string) error {
+	return mjs.updateWorkflowNode(workflowID, workflowNodeField(nodeID, "job"), jobID)
+}
+
+// SetWorkflowNodeStatus is implementation of same method in JobStatsManager interface.
+func (mjs *MemJobStatsManager) SetWorkflowNodeStatus(workflowID, nodeID, status string) error {
+	return mjs.updateWorkflowNode(workflowID, workflowNodeField(nodeID, "status"), status)
+}
+
+func (mjs *MemJobStatsManager) updateWorkflowNode(workflowID string, field string, value string) error {
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	w, ok := mjs.data.Workflows[workflowID]
+	if !ok {
+		return errs.NoObjectFoundError(fmt.Sprintf("workflow '%s'", workflowID))
+	}
+
+	w.Fields[field] = value
+	w.Stats.UpdateTime = time.Now().Unix()
+	mjs.data.ExpireAt[workflowID] = time.Now().Unix() + jobStatsDataExpireTime
+
+	return nil
+}
+
+// executions returns the IDs of executions in the score scope, lock must be held by the caller.
+func (mjs *MemJobStatsManager) executions(upstreamJobID string, start, end interface{}) []string {
+	executions := append([]*memExecution{}, mjs.data.Executions[upstreamJobID]...)
+	sort.SliceStable(executions, func(i, j int) bool {
+		return executions[i].Score < executions[j].Score
+	})
+
+	ids := []string{}
+	for _, e := range executions {
+		if s, ok := start.(int64); ok && e.Score < s {
+			continue
+		}
+		if s, ok := end.(int64); ok && e.Score > s {
+			continue
+		}
+		ids = append(ids, e.ID)
+	}
+
+	return ids
+}
+
+func (mjs *MemJobStatsManager) reportStatus(jobID string, status, checkIn string) {
+	hookURL, err := mjs.GetHook(jobID)
+	if err != nil {
+		return // no hook registered
+	}
+
+	// Let it run in a separate goroutine to avoid waiting more time
+	go func() {
+		reportingStatus := models.JobStatusChange{
+			JobID:   jobID,
+			Status:  status,
+			CheckIn: checkIn,
+		}
+		if jobStats, err := mjs.Retrieve(jobID); err == nil {
+			jobStats.Stats.CheckIn = checkIn
+			jobStats.Stats.Status = status
+			reportingStatus.Metadata = jobStats.Stats
+		}
+
+		for fails := uint(0); fails < maxFails; fails++ {
+			err := DefaultHookClient.ReportStatus(hookURL, reportingStatus)
+			if err == nil {
+				return
+			}
+
+			logger.Warningf("Failed to report status of job %s with error: %s\n", jobID, err)
+			select {
+			case <-time.After(time.Duration(backoff(fails+1)) * time.Second):
+			case <-mjs.context.Done():
+				return
+			}
+		}
+	}()
+}
+
+// sweep removes the expired data
+func (mjs *MemJobStatsManager) sweep() {
+	mjs.lock.Lock()
+	defer mjs.lock.Unlock()
+
+	now := time.Now().Unix()
+	for id, expireAt := range mjs.data.ExpireAt {
+		if expireAt > now {
+			continue
+		}
+
+		delete(mjs.data.Stats, id)
+		delete(mjs.data.Hooks, id)
+		delete(mjs.data.Executions, id)
+		delete(mjs.data.Workflows, id)
+		delete(mjs.data.ExpireAt, id)
+	}
+}
+
+func newMemStatsSnapshot() *MemStatsSnapshot {
+	return &MemStatsSnapshot{
+		Stats:      make(map[string]*models.JobStatData),
+		Hooks:      make(map[string]string),
+		Executions: make(map[string][]*memExecution),
+		Workflows:  make(map[string]*memWorkflow),
+		ExpireAt:   make(map[string]int64),
+	}
+}
+
+func copyJobStatData(stats *models.JobStatData) *models.JobStatData {
+	copied := *stats
+	copied.Executions = nil
+
+	return &copied
+}
+
+func setJobStatsField(stats *models.JobStatData, field string, value interface{}) error {
+	var ok bool
+	switch field {
+	case "status":
+		stats.Status, ok = value.(string)
+	case "check_in":
+		stats.CheckIn, ok = value.(string)
+	case "upstream_job_id":
+		stats.UpstreamJobID, ok = value.(string)
+	case "check_in_at":
+		stats.CheckInAt, ok = value.(int64)
+	case "die_at":
+		stats.DieAt, ok = value.(int64)
+	case "run_at":
+		stats.RunAt, ok = value.(int64)
+	case "multiple_executions":
+		stats.IsMultipleExecutions, ok = value.(bool)
+	default:
+		return fmt.Errorf("field %s of job stats is not supported", field)
+	}
+
+	if !ok {
+		return fmt.Errorf("malformed value %v of field %s", value, field)
+	}
+
+	return nil
+}
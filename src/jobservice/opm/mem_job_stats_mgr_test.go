@@ -0,0 +1,208 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/models"
+)
+
+func TestMemJobStats(t *testing.T) {
+	mgr := NewMemJobStatsManager(context.Background())
+	mgr.Start()
+	defer mgr.Shutdown()
+
+	if _, err := mgr.Retrieve("fake_job_ID"); !errs.IsObjectNotFoundError(err) {
+		t.Fatalf("expect object not found error but got %v", err)
+	}
+
+	mgr.Save(createFakeStats())
+	mgr.SetJobStatus("fake_job_ID", job.JobStatusRunning)
+	mgr.CheckIn("fake_job_ID", "checkin")
+	mgr.DieAt("fake_job_ID", 100)
+
+	stats, err := mgr.Retrieve("fake_job_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Stats.Status != job.JobStatusRunning {
+		t.Fatalf("expect job status '%s' but got '%s'", job.JobStatusRunning, stats.Stats.Status)
+	}
+	if stats.Stats.CheckIn != "checkin" || stats.Stats.CheckInAt == 0 {
+		t.Fatalf("expect check in message 'checkin' but got '%s'", stats.Stats.CheckIn)
+	}
+	if stats.Stats.DieAt != 100 {
+		t.Fatalf("expect die at 100 but got %d", stats.Stats.DieAt)
+	}
+
+	if err := mgr.Update("fake_job_ID", "not_existing", "value"); err == nil {
+		t.Fatal("expect error of unsupported field but got nil")
+	}
+
+	if err := mgr.SendCommand("fake_job_ID", CtlCommandStop, false); err != nil {
+		t.Fatal(err)
+	}
+	if cmd, err := mgr.CtlCommand("fake_job_ID"); err != nil || cmd != CtlCommandStop {
+		t.Fatalf("expect command '%s' but got '%s', %v", CtlCommandStop, cmd, err)
+	}
+	if _, err := mgr.CtlCommand("fake_job_ID"); err == nil {
+		t.Fatal("expect error of no command but got nil")
+	}
+
+	if err := mgr.RegisterHook("fake_job_ID", "http://localhost:9999/hook", false); err != nil {
+		t.Fatal(err)
+	}
+	if hookURL, err := mgr.GetHook("fake_job_ID"); err != nil || hookURL != "http://localhost:9999/hook" {
+		t.Fatalf("expect hook 'http://localhost:9999/hook' but got '%s', %v", hookURL, err)
+	}
+}
+
+func TestMemExecutions(t *testing.T) {
+	mgr := NewMemJobStatsManager(context.Background())
+
+	mgr.Save(createFakeStats())
+	if err := mgr.Update("fake_job_ID", "multiple_executions", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.AttachExecution("fake_job_ID", "e1", "e2"); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := mgr.GetExecutions("fake_job_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "e1" || ids[1] != "e2" {
+		t.Fatalf("expect executions [e1 e2] but got %v", ids)
+	}
+
+	ids, err = mgr.GetExecutions("fake_job_ID", Range(time.Now().Unix()+10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expect no executions in the future but got %v", ids)
+	}
+
+	stats, err := mgr.Retrieve("fake_job_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Stats.Executions) != 2 {
+		t.Fatalf("expect 2 executions but got %d", len(stats.Stats.Executions))
+	}
+}
+
+func TestMemListJobs(t *testing.T) {
+	mgr := NewMemJobStatsManager(context.Background())
+
+	now := time.Now().Unix()
+	for i := 0; i < 5; i++ {
+		stats := createFakeStats()
+		stats.Stats.JobID = fmt.Sprintf("fake_job_ID_%d", i)
+		stats.Stats.JobKind = job.JobKindGeneric
+		stats.Stats.EnqueueTime = now + int64(i)
+		if i%2 == 0 {
+			stats.Stats.Status = job.JobStatusError
+		}
+		mgr.Save(stats)
+	}
+
+	res, err := mgr.List(models.JobQuery{
+		Status:   job.JobStatusError,
+		PageSize: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 3 || len(res.Jobs) != 2 || res.Jobs[0].JobID != "fake_job_ID_4" {
+		t.Fatalf("expect 2 of 3 error jobs with the latest first but got %+v", res)
+	}
+
+	res, err = mgr.List(models.JobQuery{
+		Status:     job.JobStatusError,
+		PageNumber: 2,
+		PageSize:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Jobs) != 1 || res.Jobs[0].JobID != "fake_job_ID_0" {
+		t.Fatalf("expect the earliest error job on page 2 but got %+v", res.Jobs)
+	}
+
+	res, err = mgr.List(models.JobQuery{
+		JobName: "fake_job",
+		From:    now + 1,
+		To:      now + 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 3 {
+		t.Fatalf("expect 3 jobs in the time scope but got %d", res.Total)
+	}
+
+	res, err = mgr.List(models.JobQuery{PageNumber: 3, PageSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 5 || len(res.Jobs) != 0 {
+		t.Fatalf("expect empty page of 5 jobs but got %+v", res)
+	}
+}
+
+func TestMemSnapshot(t *testing.T) {
+	mgr := NewMemJobStatsManager(context.Background())
+
+	mgr.Save(createFakeStats())
+	if err := mgr.SaveWorkflow(models.WorkflowStats{
+		Stats: &models.WorkflowStatData{
+			WorkflowID: "fake_workflow_ID",
+			Name:       "fake_workflow",
+			Definition: &models.WorkflowData{
+				Name: "fake_workflow",
+				Nodes: []*models.WorkflowNode{
+					{ID: "a", Job: &models.JobData{Name: "fake_job"}, Condition: job.WorkflowConditionSuccess},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SetWorkflowNodeStatus("fake_workflow_ID", "a", job.JobStatusSuccess); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewMemJobStatsManager(context.Background())
+	restored.Import(mgr.Export())
+
+	if _, err := restored.Retrieve("fake_job_ID"); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := restored.RetrieveWorkflow("fake_workflow_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Stats.Nodes) != 1 || stats.Stats.Nodes[0].Status != job.JobStatusSuccess {
+		t.Fatalf("expect node 'a' done but got %+v", stats.Stats.Nodes)
+	}
+}
@@ -45,6 +45,9 @@ const (
 	opUpdateStats          = "update_job_stats"
 	maxFails               = 3
 	jobStatsDataExpireTime = 60 * 60 * 24 * 5 // 5 days
+	listJobsBatchSize      = 100
+	// the max count of the latest jobs scanned when listing the jobs filtered by the fields of the stats
+	listJobsMaxScan = 10000
 
 	// CtlCommandStop : command stop
 	CtlCommandStop = "stop"
@@ -371,7 +374,12 @@ func (rjs *RedisJobStatsManager) ExpirePeriodicJobStats(jobID string) error {
 	// The periodic job (policy) is stopped/unscheduled and then
 	// the stats of periodic job now can be expired
 	key := utils.KeyJobStats(rjs.namespace, jobID)
-	_, err := conn.Do("EXPIRE", key, jobStatsDataExpireTime)
+	if err := conn.Send("EXPIRE", key, jobStatsDataExpireTime); err != nil {
+		return err
+	}
+	// The index of the periodic job can be pruned now
+	expireAt := time.Now().Unix() + jobStatsDataExpireTime
+	_, err := conn.Do("ZADD", utils.KeyJobStatsExpiry(rjs.namespace), expireAt, jobID)
 
 	return err
 }
@@ -583,6 +591,13 @@ func (rjs *RedisJobStatsManager) getJobStats(jobID string) (models.JobStats, err
 		return models.JobStats{}, errs.NoObjectFoundError(fmt.Sprintf("job '%s'", jobID))
 	}
 
+	return models.JobStats{
+		Stats: parseJobStats(vals),
+	}, nil
+}
+
+// parseJobStats converts the fields and values of the job stats hash to the job stats data
+func parseJobStats(vals []string) *models.JobStatData {
 	res := models.JobStats{
 		Stats: &models.JobStatData{},
 	}
@@ -651,7 +666,7 @@ func (rjs *RedisJobStatsManager) getJobStats(jobID string) (models.JobStats, err
 		}
 	}
 
-	return res, nil
+	return res.Stats
 }
 
 func (rjs *RedisJobStatsManager) saveJobStats(jobStats models.JobStats) error {
@@ -698,12 +713,14 @@ func (rjs *RedisJobStatsManager) saveJobStats(jobStats models.JobStats) error {
 
 	// 将 job 的stats 数据写入到 redis 中。将指定字段设置为存储在键上的散列中的各自值
 	conn.Send("HMSET", args...)
+	// Index the job by the enqueue time for listing
+	conn.Send("ZADD", utils.KeyJobStatsIndex(rjs.namespace), jobStats.Stats.EnqueueTime, jobStats.Stats.JobID)
 	// If job kind is periodic job, expire time should not be set
 	// If job kind is scheduled job, expire time should be runAt+1day
 	if jobStats.Stats.JobKind != job.JobKindPeriodic {
+		nowTime := time.Now().Unix()
 		var expireTime int64 = jobStatsDataExpireTime
 		if jobStats.Stats.JobKind == job.JobKindScheduled {
-			nowTime := time.Now().Unix()
 			future := jobStats.Stats.RunAt - nowTime
 			if future > 0 {
 				expireTime += future
@@ -711,9 +728,58 @@ func (rjs *RedisJobStatsManager) saveJobStats(jobStats models.JobStats) error {
 		}
 		expireTime += rand.Int63n(30) // Avoid lots of keys being expired at the same time
 		conn.Send("EXPIRE", key, expireTime)
+		// Track the expiry time for pruning the index
+		conn.Send("ZADD", utils.KeyJobStatsExpiry(rjs.namespace), nowTime+expireTime, jobStats.Stats.JobID)
+	}
+
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+
+	if err := rjs.pruneJobStatsIndex(conn); err != nil {
+		// Only logged
+		logger.Warningf("Prune the index of expired job stats error: %s", err)
+	}
+
+	return nil
+}
+
+// pruneJobStatsIndex removes the expired jobs from the index per the tracked expiry time,
+// at most listJobsBatchSize ones are removed each time
+func (rjs *RedisJobStatsManager) pruneJobStatsIndex(conn redis.Conn) error {
+	expiryKey := utils.KeyJobStatsExpiry(rjs.namespace)
+	ids, err := redis.Strings(conn.Do("ZRANGEBYSCORE", expiryKey, "-inf", time.Now().Unix(), "LIMIT", 0, listJobsBatchSize))
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
 	}
 
-	return conn.Flush()
+	return rjs.removeFromIndex(conn, ids)
+}
+
+// removeFromIndex removes the jobs from the index and the tracked expiry time
+func (rjs *RedisJobStatsManager) removeFromIndex(conn redis.Conn, ids []string) error {
+	indexArgs := []interface{}{utils.KeyJobStatsIndex(rjs.namespace)}
+	expiryArgs := []interface{}{utils.KeyJobStatsExpiry(rjs.namespace)}
+	for _, id := range ids {
+		indexArgs = append(indexArgs, id)
+		expiryArgs = append(expiryArgs, id)
+	}
+
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("ZREM", indexArgs...); err != nil {
+		return err
+	}
+	if err := conn.Send("ZREM", expiryArgs...); err != nil {
+		return err
+	}
+	_, err := conn.Do("EXEC")
+
+	return err
 }
 
 func (rjs *RedisJobStatsManager) saveExecutions(upstreamJobID string, executions []string) error {
@@ -841,3 +907,256 @@ func backoff(seed uint) int {
 
 	return int(math.Pow(float64(seed+1), float64(seed))) + rand.Intn(5)
 }
+
+// List is implementation of same method in JobStatsManager interface.
+// The jobs are paged with the index directly unless they are filtered by the fields of the stats,
+// in which case only the latest listJobsMaxScan jobs are scanned and the result is marked as
+// truncated if there are more. The expired jobs are pruned from the index when saving the stats,
+// the ones expired since then are cleared while listing.
+func (rjs *RedisJobStatsManager) List(query models.JobQuery) (models.JobStatsList, error) {
+	conn := rjs.redisPool.Get()
+	defer conn.Close()
+
+	indexKey := utils.KeyJobStatsIndex(rjs.namespace)
+	key, from, to := indexKey, "-inf", "+inf"
+	if !utils.IsEmptyStr(query.UpstreamJobID) {
+		// The executions of the upstream job are already kept
+		key = utils.KeyUpstreamJobAndExecutions(rjs.namespace, query.UpstreamJobID)
+	} else {
+		if query.From > 0 {
+			from = fmt.Sprintf("%d", query.From)
+		}
+		if query.To > 0 {
+			to = fmt.Sprintf("%d", query.To)
+		}
+	}
+
+	// The scores of the executions are not the enqueue time
+	filtered := !utils.IsEmptyStr(query.JobName) || !utils.IsEmptyStr(query.Status) ||
+		(key != indexKey && (query.From > 0 || query.To > 0))
+	if filtered {
+		// Latest first, one more is fetched to know if the scan is truncated
+		ids, err := redis.Strings(conn.Do("ZREVRANGEBYSCORE", key, to, from, "LIMIT", 0, listJobsMaxScan+1))
+		if err != nil {
+			return models.JobStatsList{}, err
+		}
+		truncated := len(ids) > listJobsMaxScan
+		if truncated {
+			ids = ids[:listJobsMaxScan]
+		}
+		jobs, err := rjs.getJobStatsList(conn, ids, key == indexKey)
+		if err != nil {
+			return models.JobStatsList{}, err
+		}
+
+		matched := make([]*models.JobStatData, 0)
+		for _, stats := range jobs {
+			if matchJobQuery(stats, query) {
+				matched = append(matched, stats)
+			}
+		}
+		res := pageJobStats(matched, query)
+		res.Truncated = truncated
+		return res, nil
+	}
+
+	total, err := redis.Int64(conn.Do("ZCOUNT", key, from, to))
+	if err != nil {
+		return models.JobStatsList{}, err
+	}
+	// Latest first
+	args := []interface{}{key, to, from}
+	if query.PageSize > 0 {
+		pageNumber := query.PageNumber
+		if pageNumber == 0 {
+			pageNumber = 1
+		}
+		args = append(args, "LIMIT", (pageNumber-1)*query.PageSize, query.PageSize)
+	}
+	ids, err := redis.Strings(conn.Do("ZREVRANGEBYSCORE", args...))
+	if err != nil {
+		return models.JobStatsList{}, err
+	}
+	jobs, err := rjs.getJobStatsList(conn, ids, key == indexKey)
+	if err != nil {
+		return models.JobStatsList{}, err
+	}
+
+	return models.JobStatsList{
+		// The expired ones in the page are not counted
+		Total: total - int64(len(ids)-len(jobs)),
+		Jobs:  jobs,
+	}, nil
+}
+
+// getJobStatsList gets the stats of the jobs in batches and skips the expired ones,
+// which are removed from the index if clearIndex is set
+func (rjs *RedisJobStatsManager) getJobStatsList(conn redis.Conn, ids []string, clearIndex bool) ([]*models.JobStatData, error) {
+	jobs := make([]*models.JobStatData, 0)
+	expired := make([]string, 0)
+	for start := 0; start < len(ids); start += listJobsBatchSize {
+		end := start + listJobsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		for _, id := range ids[start:end] {
+			if err := conn.Send("HGETALL", utils.KeyJobStats(rjs.namespace, id)); err != nil {
+				return nil, err
+			}
+		}
+		if err := conn.Flush(); err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids[start:end] {
+			vals, err := redis.Strings(conn.Receive())
+			if err != nil {
+				return nil, err
+			}
+
+			if len(vals) == 0 {
+				expired = append(expired, id)
+				continue
+			}
+			jobs = append(jobs, parseJobStats(vals))
+		}
+	}
+
+	if len(expired) > 0 && clearIndex {
+		if err := rjs.removeFromIndex(conn, expired); err != nil {
+			// Only logged
+			logger.Warningf("Clear the index of expired job stats error: %s", err)
+		}
+	}
+
+	return jobs, nil
+}
+
+// SaveWorkflow is implementation of same method in JobStatsManager interface.
+func (rjs *RedisJobStatsManager) SaveWorkflow(stats models.WorkflowStats) error {
+	if stats.Stats == nil || stats.Stats.Definition == nil {
+		return errors.New("malformed workflow stats object")
+	}
+
+	definition, err := json.Marshal(stats.Stats.Definition)
+	if err != nil {
+		return err
+	}
+
+	conn := rjs.redisPool.Get()
+	defer conn.Close()
+
+	key := utils.KeyWorkflow(rjs.namespace, stats.Stats.WorkflowID)
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("HMSET", key,
+		"id", stats.Stats.WorkflowID,
+		"name", stats.Stats.Name,
+		"enqueue_time", stats.Stats.EnqueueTime,
+		"update_time", stats.Stats.UpdateTime,
+		"definition", string(definition),
+	); err != nil {
+		return err
+	}
+	if err := conn.Send("EXPIRE", key, jobStatsDataExpireTime); err != nil {
+		return err
+	}
+
+	_, err = conn.Do("EXEC")
+
+	return err
+}
+
+// RetrieveWorkflow is implementation of same method in JobStatsManager interface.
+func (rjs *RedisJobStatsManager) RetrieveWorkflow(workflowID string) (models.WorkflowStats, error) {
+	if utils.IsEmptyStr(workflowID) {
+		return models.WorkflowStats{}, errors.New("empty workflow ID")
+	}
+
+	conn := rjs.redisPool.Get()
+	defer conn.Close()
+
+	key := utils.KeyWorkflow(rjs.namespace, workflowID)
+	vals, err := redis.StringMap(conn.Do("HGETALL", key))
+	if err != nil {
+		return models.WorkflowStats{}, err
+	}
+
+	if len(vals) == 0 {
+		return models.WorkflowStats{}, errs.NoObjectFoundError(fmt.Sprintf("workflow '%s'", workflowID))
+	}
+
+	definition := &models.WorkflowData{}
+	if err := json.Unmarshal([]byte(vals["definition"]), definition); err != nil {
+		return models.WorkflowStats{}, err
+	}
+
+	res := models.WorkflowStats{
+		Stats: &models.WorkflowStatData{
+			WorkflowID: vals["id"],
+			Name:       vals["name"],
+			Definition: definition,
+		},
+	}
+	res.Stats.EnqueueTime, _ = strconv.ParseInt(vals["enqueue_time"], 10, 64)
+	res.Stats.UpdateTime, _ = strconv.ParseInt(vals["update_time"], 10, 64)
+
+	for _, node := range definition.Nodes {
+		nodeStats := &models.WorkflowNodeStatData{
+			NodeID:    node.ID,
+			JobID:     vals[workflowNodeField(node.ID, "job")],
+			Status:    vals[workflowNodeField(node.ID, "status")],
+			Upstreams: node.Upstreams,
+			Condition: node.Condition,
+		}
+		if node.Job != nil {
+			nodeStats.JobName = node.Job.Name
+		}
+		res.Stats.Nodes = append(res.Stats.Nodes, nodeStats)
+	}
+
+	return res, nil
+}
+
+// ClaimWorkflowNode is implementation of same method in JobStatsManager interface.
+func (rjs *RedisJobStatsManager) ClaimWorkflowNode(workflowID, nodeID string) (bool, error) {
+	conn := rjs.redisPool.Get()
+	defer conn.Close()
+
+	key := utils.KeyWorkflow(rjs.namespace, workflowID)
+	return redis.Bool(conn.Do("HSETNX", key, workflowNodeField(nodeID, "claim"), time.Now().Unix()))
+}
+
+// SetWorkflowNodeJob is implementation of same method in JobStatsManager interface.
+func (rjs *RedisJobStatsManager) SetWorkflowNodeJob(workflowID, nodeID, jobID string) error {
+	return rjs.updateWorkflowNode(workflowID, workflowNodeField(nodeID, "job"), jobID)
+}
+
+// SetWorkflowNodeStatus is implementation of same method in JobStatsManager interface.
+func (rjs *RedisJobStatsManager) SetWorkflowNodeStatus(workflowID, nodeID, status string) error {
+	return rjs.updateWorkflowNode(workflowID, workflowNodeField(nodeID, "status"), status)
+}
+
+func (rjs *RedisJobStatsManager) updateWorkflowNode(workflowID string, field string, value string) error {
+	conn := rjs.redisPool.Get()
+	defer conn.Close()
+
+	key := utils.KeyWorkflow(rjs.namespace, workflowID)
+	exists, err := redis.Bool(conn.Do("EXISTS", key))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errs.NoObjectFoundError(fmt.Sprintf("workflow '%s'", workflowID))
+	}
+
+	_, err = conn.Do("HMSET", key, field, value, "update_time", time.Now().Unix())
+
+	return err
+}
+
+func workflowNodeField(nodeID string, prop string) string {
+	return fmt.Sprintf("node:%s:%s", nodeID, prop)
+}
@@ -279,6 +279,142 @@ func TestUpdateJobStats(t *testing.T) {
 	}
 }
 
+func TestListJobs(t *testing.T) {
+	mgr := createStatsManager(redisPool)
+	mgr.Start()
+	defer mgr.Shutdown()
+	<-time.After(200 * time.Millisecond)
+
+	// make sure data existing
+	testingStats := createFakeStats()
+	mgr.Save(testingStats)
+	<-time.After(200 * time.Millisecond)
+
+	res, err := mgr.List(models.JobQuery{
+		JobName: "fake_job",
+		Status:  "Pending",
+		From:    testingStats.Stats.EnqueueTime,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Jobs[0].JobID != "fake_job_ID" || res.Truncated {
+		t.Fatalf("expect job 'fake_job_ID' listed but got %+v", res)
+	}
+
+	// The expiry time is tracked for pruning the index
+	conn := redisPool.Get()
+	defer conn.Close()
+	expireAt, err := redis.Int64(conn.Do("ZSCORE", utils.KeyJobStatsExpiry(testingNamespace), "fake_job_ID"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expireAt <= time.Now().Unix() {
+		t.Fatalf("expect the expiry time of job 'fake_job_ID' in the future but got %d", expireAt)
+	}
+
+	res, err = mgr.List(models.JobQuery{Status: "Error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 0 {
+		t.Fatalf("expect no error jobs but got %d", res.Total)
+	}
+
+	// Paged with the index directly
+	res, err = mgr.List(models.JobQuery{PageNumber: 1, PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || len(res.Jobs) != 1 || res.Jobs[0].JobID != "fake_job_ID" {
+		t.Fatalf("expect job 'fake_job_ID' listed in the first page but got %+v", res)
+	}
+	res, err = mgr.List(models.JobQuery{PageNumber: 2, PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || len(res.Jobs) != 0 {
+		t.Fatalf("expect an empty second page but got %+v", res)
+	}
+
+	key := utils.KeyJobStats(testingNamespace, "fake_job_ID")
+	if err := clear(key, redisPool.Get()); err != nil {
+		t.Fatal(err)
+	}
+	// The index of the expired job is cleared while listing
+	if _, err := mgr.List(models.JobQuery{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := redis.Int64(conn.Do("ZSCORE", utils.KeyJobStatsExpiry(testingNamespace), "fake_job_ID")); err != redis.ErrNil {
+		t.Fatalf("expect the expiry time of job 'fake_job_ID' cleared but got error %v", err)
+	}
+	if err := clear(utils.KeyJobStatsIndex(testingNamespace), redisPool.Get()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	mgr := createStatsManager(redisPool)
+	mgr.Start()
+	defer mgr.Shutdown()
+	<-time.After(200 * time.Millisecond)
+
+	now := time.Now().Unix()
+	if err := mgr.SaveWorkflow(models.WorkflowStats{
+		Stats: &models.WorkflowStatData{
+			WorkflowID:  "fake_workflow_ID",
+			Name:        "fake_workflow",
+			EnqueueTime: now,
+			UpdateTime:  now,
+			Definition: &models.WorkflowData{
+				Name: "fake_workflow",
+				Nodes: []*models.WorkflowNode{
+					{ID: "a", Job: &models.JobData{Name: "fake_job"}, Condition: job.WorkflowConditionSuccess},
+					{ID: "b", Job: &models.JobData{Name: "fake_job"}, Upstreams: []string{"a"}, Condition: job.WorkflowConditionAlways},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if claimed, err := mgr.ClaimWorkflowNode("fake_workflow_ID", "a"); err != nil || !claimed {
+		t.Fatalf("expect node 'a' claimed but got %v, %v", claimed, err)
+	}
+	if claimed, err := mgr.ClaimWorkflowNode("fake_workflow_ID", "a"); err != nil || claimed {
+		t.Fatalf("expect node 'a' not claimed twice but got %v, %v", claimed, err)
+	}
+	if err := mgr.SetWorkflowNodeJob("fake_workflow_ID", "a", "fake_job_ID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SetWorkflowNodeStatus("fake_workflow_ID", "a", job.JobStatusSuccess); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := mgr.RetrieveWorkflow("fake_workflow_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Stats.Nodes) != 2 {
+		t.Fatalf("expect 2 nodes but got %d", len(stats.Stats.Nodes))
+	}
+	if stats.Stats.Nodes[0].JobID != "fake_job_ID" || stats.Stats.Nodes[0].Status != job.JobStatusSuccess {
+		t.Fatalf("expect node 'a' done with job 'fake_job_ID' but got %+v", stats.Stats.Nodes[0])
+	}
+	if stats.Stats.Nodes[1].Status != "" || stats.Stats.Nodes[1].Condition != job.WorkflowConditionAlways {
+		t.Fatalf("expect node 'b' not done but got %+v", stats.Stats.Nodes[1])
+	}
+
+	if err := mgr.SetWorkflowNodeStatus("not_existing", "a", job.JobStatusSuccess); err == nil {
+		t.Fatal("expect error but got nil")
+	}
+
+	key := utils.KeyWorkflow(testingNamespace, "fake_workflow_ID")
+	if err := clear(key, redisPool.Get()); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func getRedisHost() string {
 	redisHost := os.Getenv(testingRedisHost)
 	if redisHost == "" {
@@ -116,6 +116,15 @@ type Interface interface {
 	//  error           : error returned if meet any problems
 	GetJobStats(jobID string) (models.JobStats, error)
 
+	// List the stats of the jobs matching the query
+	//
+	// query models.JobQuery : the conditions and the page of the listing
+	//
+	// Returns:
+	//  models.JobStatsList : total count and the page of the matched job stats
+	//  error               : error returned if meet any problems
+	ListJobs(query models.JobQuery) (models.JobStatsList, error)
+
 	// Stop the job
 	//
 	// jobID string : ID of the enqueued job
@@ -148,4 +157,51 @@ type Interface interface {
 	// Return:
 	//  error        : error returned if meet any problems
 	RegisterHook(jobID string, hookURL string) error
+
+	// Save the workflow
+	//
+	// stats models.WorkflowStats : the stats of workflow with the definition
+	//
+	// Return:
+	//  error           : error returned if meet any problems
+	SaveWorkflow(stats models.WorkflowStats) error
+
+	// Get the stats of the specified workflow
+	//
+	// workflowID string : ID of the workflow
+	//
+	// Returns:
+	//  models.WorkflowStats : workflow stats data
+	//  error                : error returned if meet any problems
+	GetWorkflowStats(workflowID string) (models.WorkflowStats, error)
+
+	// Claim the workflow node to avoid launching it more than once
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	//
+	// Returns:
+	//  bool            : true if the node is claimed by the caller
+	//  error           : error returned if meet any problems
+	ClaimWorkflowNode(workflowID, nodeID string) (bool, error)
+
+	// Bind the launched job to the workflow node
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	// jobID string      : ID of the launched job
+	//
+	// Return:
+	//  error           : error returned if meet any problems
+	SetWorkflowNodeJob(workflowID, nodeID, jobID string) error
+
+	// Mark the final status of the workflow node
+	//
+	// workflowID string : ID of the workflow
+	// nodeID string     : ID of the workflow node
+	// status string     : the final status of the node
+	//
+	// Return:
+	//  error           : error returned if meet any problems
+	SetWorkflowNodeStatus(workflowID, nodeID, status string) error
 }
@@ -13,3 +13,1012 @@
 // limitations under the License.
 
 package pool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/config"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/jobservice/models"
+	"github.com/goharbor/harbor/src/jobservice/opm"
+	"github.com/goharbor/harbor/src/jobservice/period"
+	"github.com/goharbor/harbor/src/jobservice/utils"
+	"github.com/robfig/cron"
+)
+
+const (
+	// Interval of moving the due scheduled jobs and periodic executions to the queue
+	memPoolTickInterval = time.Second
+	// Interval of persisting the pool data if the persistence file is set
+	memPoolPersistInterval = 10 * time.Second
+)
+
+// MemWorkerPool is the pool implementation running jobs in process without any external storage.
+// The queued jobs, the periodic policies and the job stats can be persisted to a local file
+// to survive the restarting of job service.
+type MemWorkerPool struct {
+	id              string
+	context         *env.Context
+	workerCount     uint
+	persistenceFile string
+	startedAt       int64
+	statsManager    *opm.MemJobStatsManager
+	deDuplicator    DeDuplicator
+
+	// no need to sync as write once and then only read
+	// key is name of known job
+	// value is the type of known job
+	knownJobs map[string]interface{}
+	// key is name of known job
+	// value is the options the job registered with
+	jobOptions map[string]work.JobOptions
+	// key is name of known job
+	// value is the wrapper to run the job
+	handlers map[string]*RedisJob
+
+	// protect the queues below
+	lock *sync.Mutex
+	// jobs waiting for the workers in the enqueuing order
+	pending []*work.Job
+	// jobs waiting for the run time, including the retrying ones
+	scheduled map[string]*memScheduledJob
+	// jobs failed without more retries
+	dead map[string]*work.Job
+	// copies of the running jobs
+	running map[string]*work.Job
+	// running count of each job
+	runningCount map[string]uint
+	// periodic job policies
+	policies map[string]*memPeriodicPolicy
+
+	wakeup    chan struct{}
+	workersWG *sync.WaitGroup
+}
+
+type memScheduledJob struct {
+	Job   *work.Job `json:"job"`
+	RunAt int64     `json:"run_at"`
+}
+
+type memPeriodicPolicy struct {
+	Policy  *period.PeriodicJobPolicy `json:"policy"`
+	NextRun int64                     `json:"next_run"`
+}
+
+// memPoolSnapshot is the persisted data of MemWorkerPool
+type memPoolSnapshot struct {
+	Pending   []*work.Job           `json:"pending"`
+	Scheduled []*memScheduledJob    `json:"scheduled"`
+	Dead      []*work.Job           `json:"dead"`
+	Policies  []*memPeriodicPolicy  `json:"policies"`
+	Stats     *opm.MemStatsSnapshot `json:"stats"`
+}
+
+// NewMemWorkerPool is constructor of MemWorkerPool.
+// The pool data is not persisted if the persistenceFile is empty.
+func NewMemWorkerPool(ctx *env.Context, workerCount uint, persistenceFile string) *MemWorkerPool {
+	if workerCount == 0 {
+		workerCount = 1
+	}
+
+	return &MemWorkerPool{
+		id:              utils.MakeIdentifier(),
+		context:         ctx,
+		workerCount:     workerCount,
+		persistenceFile: persistenceFile,
+		statsManager:    opm.NewMemJobStatsManager(ctx.SystemContext),
+		deDuplicator:    NewMemDeDuplicator(),
+		knownJobs:       make(map[string]interface{}),
+		jobOptions:      make(map[string]work.JobOptions),
+		handlers:        make(map[string]*RedisJob),
+		lock:            &sync.Mutex{},
+		pending:         make([]*work.Job, 0),
+		scheduled:       make(map[string]*memScheduledJob),
+		dead:            make(map[string]*work.Job),
+		running:         make(map[string]*work.Job),
+		runningCount:    make(map[string]uint),
+		policies:        make(map[string]*memPeriodicPolicy),
+		wakeup:          make(chan struct{}, workerCount),
+		workersWG:       &sync.WaitGroup{},
+	}
+}
+
+// Start to serve
+// Unblock action
+func (mwp *MemWorkerPool) Start() error {
+	if mwp.context.SystemContext == nil {
+		// report and exit
+		return errors.New("Memory worker pool can not start as it's not correctly configured")
+	}
+
+	// Restore the persisted data before serving
+	if err := mwp.load(); err != nil {
+		return err
+	}
+
+	mwp.startedAt = time.Now().Unix()
+	sysCtx := mwp.context.SystemContext
+
+	// None-blocking
+	mwp.statsManager.Start()
+
+	for i := uint(0); i < mwp.workerCount; i++ {
+		mwp.workersWG.Add(1)
+		go mwp.work(sysCtx)
+	}
+
+	mwp.context.WG.Add(1)
+	go func() {
+		ticker := time.NewTicker(memPoolTickInterval)
+		defer func() {
+			ticker.Stop()
+
+			// Wait for the running jobs and then keep the left ones
+			mwp.workersWG.Wait()
+			if err := mwp.persist(); err != nil {
+				logger.Errorf("Persist memory worker pool failed with error: %s\n", err)
+			}
+
+			mwp.statsManager.Shutdown()
+			mwp.context.WG.Done()
+			logger.Infof("Memory worker pool is stopped")
+		}()
+
+		lastPersisted := time.Now()
+		for {
+			select {
+			case now := <-ticker.C:
+				mwp.promote(now)
+
+				if now.Sub(lastPersisted) >= memPoolPersistInterval {
+					if err := mwp.persist(); err != nil {
+						// Only logged
+						logger.Errorf("Persist memory worker pool failed with error: %s\n", err)
+					}
+					lastPersisted = now
+				}
+			case <-sysCtx.Done():
+				return
+			}
+		}
+	}()
+
+	logger.Infof("Memory worker pool is started with %d workers", mwp.workerCount)
+
+	return nil
+}
+
+// RegisterJob is used to register the job to the pool.
+// j is the type of job
+func (mwp *MemWorkerPool) RegisterJob(name string, j interface{}) error {
+	if utils.IsEmptyStr(name) || j == nil {
+		return errors.New("job can not be registered with empty name or nil interface")
+	}
+
+	// j must be job.Interface
+	if _, ok := j.(job.Interface); !ok {
+		return errors.New("job must implement the job.Interface")
+	}
+
+	// 1:1 constraint
+	if jInList, ok := mwp.knownJobs[name]; ok {
+		return fmt.Errorf("Job name %s has been already registered with %s", name, reflect.TypeOf(jInList).String())
+	}
+
+	// Same job implementation can be only registered with one name
+	for jName, jInList := range mwp.knownJobs {
+		jobImpl := reflect.TypeOf(j).String()
+		if reflect.TypeOf(jInList).String() == jobImpl {
+			return fmt.Errorf("Job %s has been already registered with name %s", jobImpl, jName)
+		}
+	}
+
+	// Get more info from j
+	theJ := Wrap(j)
+
+	// Apply the configured priority and concurrency limit
+	options := work.JobOptions{
+		Priority: 1, // Consistent with redis worker pool
+		MaxFails: theJ.MaxFails(),
+	}
+	if options.MaxFails == 0 {
+		options.MaxFails = 4 // Consistent with backend worker pool
+	}
+	if jobCfg := config.GetJobConfig(name); jobCfg != nil {
+		if jobCfg.Priority > 0 {
+			options.Priority = jobCfg.Priority
+		}
+		options.MaxConcurrency = jobCfg.MaxConcurrency
+	}
+
+	mwp.handlers[name] = NewRedisJob(j, mwp.context, mwp.statsManager, mwp.deDuplicator)
+	mwp.knownJobs[name] = j // keep the name of registered jobs as known jobs for future validation
+	mwp.jobOptions[name] = options
+
+	logger.Infof("Register job %s with name %s, priority %d and max concurrency %d", reflect.TypeOf(j).String(), name, options.Priority, options.MaxConcurrency)
+
+	return nil
+}
+
+// RegisterJobs is used to register multiple jobs to pool.
+func (mwp *MemWorkerPool) RegisterJobs(jobs map[string]interface{}) error {
+	if jobs == nil || len(jobs) == 0 {
+		return nil
+	}
+
+	for name, j := range jobs {
+		if err := mwp.RegisterJob(name, j); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Enqueue job
+func (mwp *MemWorkerPool) Enqueue(jobName string, params models.Parameters, isUnique bool) (models.JobStats, error) {
+	// As the job is declared to be unique,
+	// check the uniqueness of the job,
+	// if no duplicated job existing (including the running jobs),
+	// set the unique flag.
+	if isUnique {
+		if err := mwp.deDuplicator.Unique(jobName, params); err != nil {
+			return models.JobStats{}, err
+		}
+	}
+
+	j := newMemJob(jobName, params, isUnique)
+	res := generateResult(j, job.JobKindGeneric, isUnique)
+	// Save the stats before queueing to make sure the workers can update it
+	mwp.statsManager.Save(res)
+
+	mwp.lock.Lock()
+	mwp.pending = append(mwp.pending, j)
+	mwp.lock.Unlock()
+
+	mwp.notify()
+
+	return res, nil
+}
+
+// Schedule job
+func (mwp *MemWorkerPool) Schedule(jobName string, params models.Parameters, runAfterSeconds uint64, isUnique bool) (models.JobStats, error) {
+	if isUnique {
+		if err := mwp.deDuplicator.Unique(jobName, params); err != nil {
+			return models.JobStats{}, err
+		}
+	}
+
+	j := newMemJob(jobName, params, isUnique)
+	runAt := j.EnqueuedAt + int64(runAfterSeconds)
+	res := generateResult(j, job.JobKindScheduled, isUnique)
+	res.Stats.RunAt = runAt
+	mwp.statsManager.Save(res)
+
+	mwp.lock.Lock()
+	mwp.scheduled[j.ID] = &memScheduledJob{
+		Job:   j,
+		RunAt: runAt,
+	}
+	mwp.lock.Unlock()
+
+	return res, nil
+}
+
+// PeriodicallyEnqueue job
+func (mwp *MemWorkerPool) PeriodicallyEnqueue(jobName string, params models.Parameters, cronSetting string) (models.JobStats, error) {
+	if utils.IsEmptyStr(jobName) {
+		return models.JobStats{}, errors.New("empty job name is not allowed")
+	}
+	if utils.IsEmptyStr(cronSetting) {
+		return models.JobStats{}, errors.New("cron spec is not set")
+	}
+
+	schedule, err := cron.Parse(cronSetting)
+	if err != nil {
+		return models.JobStats{}, err
+	}
+
+	policy := &period.PeriodicJobPolicy{
+		JobName:       jobName,
+		JobParameters: params,
+		CronSpec:      cronSetting,
+	}
+	rawJSON, err := policy.Serialize()
+	if err != nil {
+		return models.JobStats{}, err
+	}
+
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	// Same policy can be only scheduled once
+	for id, pl := range mwp.policies {
+		existing := *pl.Policy
+		existing.PolicyID = ""
+		if existingJSON, err := existing.Serialize(); err == nil && string(existingJSON) == string(rawJSON) {
+			return models.JobStats{}, errs.ConflictError(id)
+		}
+	}
+
+	id, _ := utils.MakePeriodicPolicyUUID()
+	policy.PolicyID = id
+	nextRun := schedule.Next(time.Now()).Unix()
+
+	res := models.JobStats{
+		Stats: &models.JobStatData{
+			JobID:                id,
+			JobName:              jobName,
+			Status:               job.JobStatusPending,
+			JobKind:              job.JobKindPeriodic,
+			CronSpec:             cronSetting,
+			EnqueueTime:          time.Now().Unix(),
+			UpdateTime:           time.Now().Unix(),
+			RefLink:              fmt.Sprintf("/api/v1/jobs/%s", id),
+			RunAt:                nextRun,
+			IsMultipleExecutions: true, // True for periodic job
+		},
+	}
+	mwp.statsManager.Save(res)
+
+	mwp.policies[id] = &memPeriodicPolicy{
+		Policy:  policy,
+		NextRun: nextRun,
+	}
+
+	return res, nil
+}
+
+// Stats of pool
+func (mwp *MemWorkerPool) Stats() (models.JobPoolStats, error) {
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	jobNames := make([]string, 0, len(mwp.knownJobs))
+	for name := range mwp.knownJobs {
+		jobNames = append(jobNames, name)
+	}
+	sort.Strings(jobNames)
+
+	pendingJobs := make(map[string]int64)
+	for _, j := range mwp.pending {
+		pendingJobs[j.Name]++
+	}
+
+	usage := make([]*models.JobUsageData, 0, len(jobNames))
+	for _, name := range jobNames {
+		options := mwp.jobOptions[name]
+		usage = append(usage, &models.JobUsageData{
+			Name:           name,
+			Priority:       options.Priority,
+			MaxConcurrency: options.MaxConcurrency,
+			Running:        int(mwp.runningCount[name]),
+			Pending:        pendingJobs[name],
+		})
+	}
+
+	return models.JobPoolStats{
+		Pools: []*models.JobPoolStatsData{
+			{
+				WorkerPoolID: mwp.id,
+				StartedAt:    mwp.startedAt,
+				HeartbeatAt:  time.Now().Unix(),
+				JobNames:     jobNames,
+				Concurrency:  mwp.workerCount,
+				Status:       workerPoolStatusHealthy,
+				Jobs:         usage,
+			},
+		},
+	}, nil
+}
+
+// IsKnownJob ...
+func (mwp *MemWorkerPool) IsKnownJob(name string) (interface{}, bool) {
+	v, ok := mwp.knownJobs[name]
+	return v, ok
+}
+
+// ValidateJobParameters ...
+func (mwp *MemWorkerPool) ValidateJobParameters(jobType interface{}, params map[string]interface{}) error {
+	if jobType == nil {
+		return errors.New("nil job type")
+	}
+
+	theJ := Wrap(jobType)
+	return theJ.Validate(params)
+}
+
+// GetJobStats return the job stats of the specified enqueued job.
+func (mwp *MemWorkerPool) GetJobStats(jobID string) (models.JobStats, error) {
+	if utils.IsEmptyStr(jobID) {
+		return models.JobStats{}, errors.New("empty job ID")
+	}
+
+	return mwp.statsManager.Retrieve(jobID)
+}
+
+// StopJob will stop the job
+func (mwp *MemWorkerPool) StopJob(jobID string) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	theJob, err := mwp.statsManager.Retrieve(jobID)
+	if err != nil {
+		return err
+	}
+
+	switch theJob.Stats.JobKind {
+	case job.JobKindGeneric:
+		// Only running job can be stopped
+		if theJob.Stats.Status != job.JobStatusRunning {
+			return fmt.Errorf("job '%s' is not a running job", jobID)
+		}
+	case job.JobKindScheduled:
+		// we need to delete the scheduled job in the queue if it is not running yet
+		// otherwise, stop it.
+		if theJob.Stats.Status == job.JobStatusPending {
+			if err := mwp.removeQueuedJob(jobID); err != nil {
+				return err
+			}
+
+			// Update the job status to 'stopped'
+			mwp.statsManager.SetJobStatus(jobID, job.JobStatusStopped)
+
+			logger.Debugf("Scheduled job which plan to run at %d '%s' is stopped", theJob.Stats.RunAt, jobID)
+
+			return nil
+		}
+	case job.JobKindPeriodic:
+		// firstly delete the periodic job policy
+		mwp.lock.Lock()
+		_, ok := mwp.policies[jobID]
+		delete(mwp.policies, jobID)
+		mwp.lock.Unlock()
+		if !ok {
+			return fmt.Errorf("periodic job policy '%s' is not found", jobID)
+		}
+
+		logger.Infof("Periodic job policy %s is removed", jobID)
+
+		// secondly we need try to delete the job instances scheduled for this periodic job, a try best action
+		if err := mwp.deleteScheduledJobsOfPeriodicPolicy(jobID); err != nil {
+			// only logged
+			logger.Errorf("Errors happened when deleting jobs of periodic policy %s: %s", jobID, err)
+		}
+
+		// thirdly expire the job stats of this periodic job if exists
+		if err := mwp.statsManager.ExpirePeriodicJobStats(jobID); err != nil {
+			// only logged
+			logger.Errorf("Expire the stats of job %s failed with error: %s\n", jobID, err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("Job kind %s is not supported", theJob.Stats.JobKind)
+	}
+
+	// Check if the job has 'running' instance
+	if theJob.Stats.Status == job.JobStatusRunning {
+		// Send 'stop' ctl command to the running instance
+		if err := mwp.statsManager.SendCommand(jobID, opm.CtlCommandStop, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CancelJob will cancel the job
+func (mwp *MemWorkerPool) CancelJob(jobID string) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	theJob, err := mwp.statsManager.Retrieve(jobID)
+	if err != nil {
+		return err
+	}
+
+	switch theJob.Stats.JobKind {
+	case job.JobKindGeneric:
+		if theJob.Stats.Status != job.JobStatusRunning {
+			return fmt.Errorf("only running job can be cancelled, job '%s' seems not running now", theJob.Stats.JobID)
+		}
+
+		// Send 'cancel' ctl command to the running instance
+		if err := mwp.statsManager.SendCommand(jobID, opm.CtlCommandCancel, false); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("job kind '%s' does not support 'cancel' operation", theJob.Stats.JobKind)
+	}
+
+	return nil
+}
+
+// RetryJob retry the job
+func (mwp *MemWorkerPool) RetryJob(jobID string) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	theJob, err := mwp.statsManager.Retrieve(jobID)
+	if err != nil {
+		return err
+	}
+
+	if theJob.Stats.DieAt == 0 {
+		return fmt.Errorf("job '%s' is not a retryable job", jobID)
+	}
+
+	mwp.lock.Lock()
+	j, ok := mwp.dead[jobID]
+	if !ok {
+		mwp.lock.Unlock()
+		return fmt.Errorf("job '%s' is not found in the dead queue", jobID)
+	}
+
+	delete(mwp.dead, jobID)
+	j.Fails = 0
+	j.LastErr = ""
+	j.FailedAt = 0
+	mwp.pending = append(mwp.pending, j)
+	mwp.lock.Unlock()
+
+	mwp.notify()
+
+	return nil
+}
+
+// RegisterHook registers status hook url
+// sync method
+func (mwp *MemWorkerPool) RegisterHook(jobID string, hookURL string) error {
+	if utils.IsEmptyStr(jobID) {
+		return errors.New("empty job ID")
+	}
+
+	if !utils.IsValidURL(hookURL) {
+		return errors.New("invalid hook url")
+	}
+
+	return mwp.statsManager.RegisterHook(jobID, hookURL, false)
+}
+
+// ListJobs returns the stats of the jobs matching the query.
+func (mwp *MemWorkerPool) ListJobs(query models.JobQuery) (models.JobStatsList, error) {
+	return mwp.statsManager.List(query)
+}
+
+// SaveWorkflow persists the workflow via the stats manager.
+func (mwp *MemWorkerPool) SaveWorkflow(stats models.WorkflowStats) error {
+	return mwp.statsManager.SaveWorkflow(stats)
+}
+
+// GetWorkflowStats return the stats of the specified workflow.
+func (mwp *MemWorkerPool) GetWorkflowStats(workflowID string) (models.WorkflowStats, error) {
+	return mwp.statsManager.RetrieveWorkflow(workflowID)
+}
+
+// ClaimWorkflowNode claims the workflow node before launching it.
+func (mwp *MemWorkerPool) ClaimWorkflowNode(workflowID, nodeID string) (bool, error) {
+	return mwp.statsManager.ClaimWorkflowNode(workflowID, nodeID)
+}
+
+// SetWorkflowNodeJob binds the launched job to the workflow node.
+func (mwp *MemWorkerPool) SetWorkflowNodeJob(workflowID, nodeID, jobID string) error {
+	return mwp.statsManager.SetWorkflowNodeJob(workflowID, nodeID, jobID)
+}
+
+// SetWorkflowNodeStatus marks the final status of the workflow node.
+func (mwp *MemWorkerPool) SetWorkflowNodeStatus(workflowID, nodeID, status string) error {
+	return mwp.statsManager.SetWorkflowNodeStatus(workflowID, nodeID, status)
+}
+
+// work keeps picking up the pending jobs to run until the system context is done
+func (mwp *MemWorkerPool) work(sysCtx context.Context) {
+	defer mwp.workersWG.Done()
+
+	for {
+		select {
+		case <-sysCtx.Done():
+			return
+		default:
+		}
+
+		if j := mwp.dequeue(); j != nil {
+			mwp.process(j)
+			continue
+		}
+
+		select {
+		case <-mwp.wakeup:
+		case <-time.After(memPoolTickInterval):
+		case <-sysCtx.Done():
+			return
+		}
+	}
+}
+
+// dequeue picks up the pending job with the highest priority whose running count does not
+// reach the max concurrency. Jobs with the same priority are picked up in the enqueuing order.
+func (mwp *MemWorkerPool) dequeue() *work.Job {
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	index := -1
+	var priority uint
+	for i, j := range mwp.pending {
+		options := mwp.jobOptions[j.Name]
+		if options.MaxConcurrency > 0 && mwp.runningCount[j.Name] >= options.MaxConcurrency {
+			continue
+		}
+
+		if index < 0 || options.Priority > priority {
+			index = i
+			priority = options.Priority
+		}
+	}
+
+	if index < 0 {
+		return nil
+	}
+
+	j := mwp.pending[index]
+	mwp.pending = append(mwp.pending[:index], mwp.pending[index+1:]...)
+	mwp.runningCount[j.Name]++
+	// Keep a copy as the running one may be changed by the job wrapper
+	copied := *j
+	mwp.running[j.ID] = &copied
+
+	return j
+}
+
+// process runs the job and then retries it or puts it into the dead queue if it's failed
+func (mwp *MemWorkerPool) process(j *work.Job) {
+	var err error
+	handler, ok := mwp.handlers[j.Name]
+	if ok {
+		logger.Infof("Job incoming: %s:%s", j.Name, j.ID)
+		err = handler.Run(j)
+	} else {
+		err = fmt.Errorf("job %s is not registered", j.Name)
+		logger.Errorf("Job '%s:%s' can not be run: %s", j.Name, j.ID, err)
+	}
+
+	mwp.lock.Lock()
+	defer func() {
+		mwp.lock.Unlock()
+		// The finished job may unblock the jobs limited by the max concurrency
+		mwp.notify()
+	}()
+
+	delete(mwp.running, j.ID)
+	if mwp.runningCount[j.Name] > 0 {
+		mwp.runningCount[j.Name]--
+	}
+
+	if err == nil {
+		return
+	}
+
+	now := time.Now().Unix()
+	j.Fails++
+	j.LastErr = err.Error()
+	j.FailedAt = now
+
+	if ok && j.Fails < int64(mwp.jobOptions[j.Name].MaxFails) {
+		mwp.scheduled[j.ID] = &memScheduledJob{
+			Job:   j,
+			RunAt: now + memRetryBackoff(j.Fails),
+		}
+		return
+	}
+
+	mwp.dead[j.ID] = j
+	// Mark the dying time to make it retryable
+	mwp.statsManager.DieAt(j.ID, now)
+}
+
+// promote moves the due scheduled jobs and periodic executions to the pending queue
+func (mwp *MemWorkerPool) promote(now time.Time) {
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	due := make([]*memScheduledJob, 0)
+	for id, sj := range mwp.scheduled {
+		if sj.RunAt <= now.Unix() {
+			due = append(due, sj)
+			delete(mwp.scheduled, id)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].RunAt < due[j].RunAt
+	})
+	for _, sj := range due {
+		mwp.pending = append(mwp.pending, sj.Job)
+	}
+	promoted := len(due)
+
+	for id, pl := range mwp.policies {
+		if pl.NextRun > now.Unix() {
+			continue
+		}
+
+		schedule, err := cron.Parse(pl.Policy.CronSpec)
+		if err != nil {
+			// The cron spec should be already checked at top components.
+			// Just in cases, if error occurred, ignore it
+			logger.Errorf("[Ignore] Invalid corn spec in periodic policy %s %s: %s", pl.Policy.JobName, id, err)
+			continue
+		}
+
+		// Create an execution (job) based on the periodic job template (policy)
+		execution := &work.Job{
+			Name:       pl.Policy.JobName,
+			ID:         utils.MakeIdentifier(),
+			EnqueuedAt: pl.NextRun,
+			Args:       pl.Policy.JobParameters, // Pass parameters to scheduled job here
+		}
+		mwp.createExecution(id, execution)
+		mwp.pending = append(mwp.pending, execution)
+		promoted++
+
+		logger.Infof("Schedule job %s:%s for policy %s at %d", execution.Name, execution.ID, id, pl.NextRun)
+
+		pl.NextRun = schedule.Next(now).Unix()
+	}
+
+	for i := 0; i < promoted; i++ {
+		mwp.notify()
+	}
+}
+
+// createExecution saves the stats of the execution created for the periodic policy
+func (mwp *MemWorkerPool) createExecution(policyID string, execution *work.Job) {
+	mwp.statsManager.Save(models.JobStats{
+		Stats: &models.JobStatData{
+			JobID:         execution.ID,
+			JobName:       execution.Name,
+			Status:        job.JobStatusPending,
+			JobKind:       job.JobKindScheduled,
+			EnqueueTime:   time.Now().Unix(),
+			UpdateTime:    time.Now().Unix(),
+			RefLink:       fmt.Sprintf("/api/v1/jobs/%s", execution.ID),
+			RunAt:         execution.EnqueuedAt,
+			UpstreamJobID: policyID,
+		},
+	})
+
+	// Get web hook from the periodic job (policy)
+	if webHookURL, err := mwp.statsManager.GetHook(policyID); err == nil {
+		// Register hook for the execution
+		if err := mwp.statsManager.RegisterHook(execution.ID, webHookURL, false); err != nil {
+			// Just logged
+			logger.Errorf("Failed to register web hook '%s' for periodic job (execution) '%s' with error: %s", webHookURL, execution.ID, err)
+		}
+	}
+
+	// Link the upstream job (policy) with the created execution
+	if err := mwp.statsManager.AttachExecution(policyID, execution.ID); err != nil {
+		// Just logged it
+		logger.Errorf("Link upstream job with executions failed: %s", err)
+	}
+
+	if err := mwp.statsManager.Update(policyID, "status", job.JobStatusScheduled); err != nil {
+		logger.Errorf("Failed to update status of periodic job %s: %s", policyID, err)
+	}
+}
+
+// removeQueuedJob removes the job which is not running yet from the queues
+func (mwp *MemWorkerPool) removeQueuedJob(jobID string) error {
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	var removed *work.Job
+	if sj, ok := mwp.scheduled[jobID]; ok {
+		removed = sj.Job
+		delete(mwp.scheduled, jobID)
+	} else {
+		for i, j := range mwp.pending {
+			if j.ID == jobID {
+				removed = j
+				mwp.pending = append(mwp.pending[:i], mwp.pending[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if removed == nil {
+		return fmt.Errorf("job '%s' is not found in the queue", jobID)
+	}
+
+	if removed.Unique {
+		if err := mwp.deDuplicator.DelUniqueSign(removed.Name, removed.Args); err != nil {
+			logger.Errorf("delete job unique sign error: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// A try best method to delete the scheduled jobs of one periodic job
+func (mwp *MemWorkerPool) deleteScheduledJobsOfPeriodicPolicy(policyID string) error {
+	// If the job is still not completed after a 'periodicEnqueuerHorizon', just ignore it
+	start := (opm.Range)(time.Now().Add(-periodicEnqueuerHorizon).Unix())
+	ids, err := mwp.statsManager.GetExecutions(policyID, start)
+	if err != nil {
+		return err
+	}
+
+	multiErrs := []string{}
+	for _, id := range ids {
+		subJob, err := mwp.statsManager.Retrieve(id)
+		if err != nil {
+			multiErrs = append(multiErrs, err.Error())
+			continue // going on
+		}
+
+		switch subJob.Stats.Status {
+		case job.JobStatusRunning:
+			// Send 'stop' ctl command to the running instance
+			if err := mwp.statsManager.SendCommand(id, opm.CtlCommandStop, false); err != nil {
+				multiErrs = append(multiErrs, err.Error())
+				continue
+			}
+
+			logger.Debugf("Stop running job %s for periodic job policy %s", id, policyID)
+		case job.JobStatusPending:
+			if err := mwp.removeQueuedJob(id); err != nil {
+				multiErrs = append(multiErrs, err.Error())
+				continue // going on
+			}
+
+			mwp.statsManager.SetJobStatus(id, job.JobStatusStopped)
+			logger.Debugf("Delete queued job %s for periodic job policy %s", id, policyID)
+		}
+	}
+
+	if len(multiErrs) > 0 {
+		return errors.New(strings.Join(multiErrs, "\n"))
+	}
+
+	return nil
+}
+
+// load restores the persisted data
+func (mwp *MemWorkerPool) load() error {
+	if utils.IsEmptyStr(mwp.persistenceFile) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(mwp.persistenceFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // nothing persisted yet
+		}
+
+		return fmt.Errorf("load memory worker pool error: %s", err)
+	}
+
+	snapshot := &memPoolSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("load memory worker pool error: %s", err)
+	}
+
+	mwp.lock.Lock()
+	defer mwp.lock.Unlock()
+
+	queued := make([]*work.Job, 0, len(snapshot.Pending)+len(snapshot.Scheduled))
+	for _, j := range snapshot.Pending {
+		mwp.pending = append(mwp.pending, j)
+		queued = append(queued, j)
+	}
+	for _, sj := range snapshot.Scheduled {
+		mwp.scheduled[sj.Job.ID] = sj
+		queued = append(queued, sj.Job)
+	}
+	for _, j := range snapshot.Dead {
+		mwp.dead[j.ID] = j
+	}
+	for _, pl := range snapshot.Policies {
+		mwp.policies[pl.Policy.PolicyID] = pl
+	}
+	mwp.statsManager.Import(snapshot.Stats)
+
+	// Recover the unique signs of the queued jobs
+	for _, j := range queued {
+		if j.Unique {
+			if err := mwp.deDuplicator.Unique(j.Name, j.Args); err != nil {
+				logger.Warningf("Recover unique sign of job %s:%s error: %s", j.Name, j.ID, err)
+			}
+		}
+	}
+
+	logger.Infof("Load %d pending, %d scheduled, %d dead jobs and %d periodic policies from %s",
+		len(mwp.pending), len(mwp.scheduled), len(mwp.dead), len(mwp.policies), mwp.persistenceFile)
+
+	return nil
+}
+
+// persist writes the pool data to the persistence file.
+// The running jobs are kept as pending ones to run again after restarting.
+func (mwp *MemWorkerPool) persist() error {
+	if utils.IsEmptyStr(mwp.persistenceFile) {
+		return nil
+	}
+
+	mwp.lock.Lock()
+	snapshot := &memPoolSnapshot{
+		Pending:   make([]*work.Job, 0, len(mwp.running)+len(mwp.pending)),
+		Scheduled: make([]*memScheduledJob, 0, len(mwp.scheduled)),
+		Dead:      make([]*work.Job, 0, len(mwp.dead)),
+		Policies:  make([]*memPeriodicPolicy, 0, len(mwp.policies)),
+		Stats:     mwp.statsManager.Export(),
+	}
+	for _, j := range mwp.running {
+		snapshot.Pending = append(snapshot.Pending, j)
+	}
+	snapshot.Pending = append(snapshot.Pending, mwp.pending...)
+	for _, sj := range mwp.scheduled {
+		snapshot.Scheduled = append(snapshot.Scheduled, sj)
+	}
+	for _, j := range mwp.dead {
+		snapshot.Dead = append(snapshot.Dead, j)
+	}
+	for _, pl := range mwp.policies {
+		snapshot.Policies = append(snapshot.Policies, pl)
+	}
+	data, err := json.Marshal(snapshot)
+	mwp.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first to avoid leaving a broken file
+	tmpFile := fmt.Sprintf("%s.tmp", mwp.persistenceFile)
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, mwp.persistenceFile)
+}
+
+// notify wakes up one idle worker if existing
+func (mwp *MemWorkerPool) notify() {
+	select {
+	case mwp.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func newMemJob(jobName string, params models.Parameters, isUnique bool) *work.Job {
+	return &work.Job{
+		Name:       jobName,
+		ID:         utils.MakeIdentifier(),
+		EnqueuedAt: time.Now().Unix(),
+		Args:       params,
+		Unique:     isUnique,
+	}
+}
+
+// Same backoff with the backend worker pool
+func memRetryBackoff(fails int64) int64 {
+	return (fails * fails * fails * fails) + 15 + (rand.Int63n(30) * (fails + 1))
+}
@@ -0,0 +1,268 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/job"
+)
+
+func TestMemPoolEnqueueJob(t *testing.T) {
+	wp, cancel := createMemWorkerPool("")
+	defer cancel()
+
+	if err := wp.RegisterJob("fake_job", (*fakeJob)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	params := make(map[string]interface{})
+	params["name"] = "testing:v1"
+	res, err := wp.Enqueue("fake_job", params, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitMemJobStatus(t, wp, res.Stats.JobID, job.JobStatusSuccess)
+
+	// Unique job
+	if _, err := wp.Schedule("fake_job", params, 120, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wp.Schedule("fake_job", params, 120, true); !errs.IsConflictError(err) {
+		t.Fatalf("expect conflict error but got %v", err)
+	}
+
+	stats, err := wp.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Pools) != 1 || len(stats.Pools[0].Jobs) != 1 || stats.Pools[0].Jobs[0].Name != "fake_job" {
+		t.Fatalf("expect one pool with job 'fake_job' but got %+v", stats.Pools)
+	}
+}
+
+func TestMemPoolStopJob(t *testing.T) {
+	wp, cancel := createMemWorkerPool("")
+	defer cancel()
+
+	if err := wp.RegisterJob("fake_long_run_job", (*fakeRunnableJob)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stop generic job
+	params := make(map[string]interface{})
+	params["name"] = "testing:v1"
+	genericJob, err := wp.Enqueue("fake_long_run_job", params, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitMemJobStatus(t, wp, genericJob.Stats.JobID, job.JobStatusRunning)
+	if err := wp.StopJob(genericJob.Stats.JobID); err != nil {
+		t.Fatal(err)
+	}
+	waitMemJobStatus(t, wp, genericJob.Stats.JobID, job.JobStatusStopped)
+
+	// Stop scheduled job
+	scheduledJob, err := wp.Schedule("fake_long_run_job", params, 120, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.StopJob(scheduledJob.Stats.JobID); err != nil {
+		t.Fatal(err)
+	}
+	waitMemJobStatus(t, wp, scheduledJob.Stats.JobID, job.JobStatusStopped)
+
+	// Stop periodic job
+	periodicJob, err := wp.PeriodicallyEnqueue("fake_long_run_job", params, "10 * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wp.PeriodicallyEnqueue("fake_long_run_job", params, "10 * * * * *"); !errs.IsConflictError(err) {
+		t.Fatalf("expect conflict error but got %v", err)
+	}
+	if err := wp.StopJob(periodicJob.Stats.JobID); err != nil {
+		t.Fatal(err)
+	}
+	if len(wp.policies) != 0 {
+		t.Fatalf("expect no periodic policies but got %d", len(wp.policies))
+	}
+}
+
+func TestMemPoolRetryJob(t *testing.T) {
+	wp, cancel := createMemWorkerPool("")
+	defer cancel()
+
+	if err := wp.RegisterJob("fake_failed_job", (*fakeFailedJob)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := wp.Enqueue("fake_failed_job", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitMemJobStatus(t, wp, res.Stats.JobID, job.JobStatusError)
+
+	// Wait for the job to be put into the dead queue
+	for i := 0; i < 20; i++ {
+		if stats, err := wp.GetJobStats(res.Stats.JobID); err == nil && stats.Stats.DieAt > 0 {
+			break
+		}
+		<-time.After(100 * time.Millisecond)
+	}
+
+	if err := wp.RetryJob(res.Stats.JobID); err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.RetryJob(res.Stats.JobID); err == nil {
+		t.Fatal("expect error of retrying job not in the dead queue but got nil")
+	}
+}
+
+func TestMemPoolDequeue(t *testing.T) {
+	wp, cancel := createMemWorkerPool("")
+	defer cancel()
+
+	wp.jobOptions["low"] = work.JobOptions{Priority: 1}
+	wp.jobOptions["high"] = work.JobOptions{Priority: 100, MaxConcurrency: 1}
+	wp.pending = []*work.Job{
+		newMemJob("low", nil, false),
+		newMemJob("high", nil, false),
+		newMemJob("high", nil, false),
+	}
+
+	if j := wp.dequeue(); j == nil || j.Name != "high" {
+		t.Fatalf("expect job 'high' but got %v", j)
+	}
+	// Max concurrency of job 'high' is reached
+	if j := wp.dequeue(); j == nil || j.Name != "low" {
+		t.Fatalf("expect job 'low' but got %v", j)
+	}
+	if j := wp.dequeue(); j != nil {
+		t.Fatalf("expect no job but got %v", j)
+	}
+	if wp.runningCount["high"] != 1 || wp.runningCount["low"] != 1 {
+		t.Fatalf("expect one running job of each but got %v", wp.runningCount)
+	}
+}
+
+func TestMemPoolPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mem_pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "pool.json")
+	wp, cancel := createMemWorkerPool(file)
+	defer cancel()
+
+	params := make(map[string]interface{})
+	params["name"] = "testing:v1"
+	scheduledJob, err := wp.Schedule("fake_job", params, 120, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wp.PeriodicallyEnqueue("fake_job", params, "10 * * * * *"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wp.persist(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, cancel2 := createMemWorkerPool(file)
+	defer cancel2()
+	if err := restored.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := restored.scheduled[scheduledJob.Stats.JobID]; !ok {
+		t.Fatalf("expect scheduled job %s restored", scheduledJob.Stats.JobID)
+	}
+	if len(restored.policies) != 1 {
+		t.Fatalf("expect 1 periodic policy but got %d", len(restored.policies))
+	}
+	if _, err := restored.GetJobStats(scheduledJob.Stats.JobID); err != nil {
+		t.Fatal(err)
+	}
+	// The unique sign is recovered as well
+	if _, err := restored.Schedule("fake_job", params, 120, true); !errs.IsConflictError(err) {
+		t.Fatalf("expect conflict error but got %v", err)
+	}
+}
+
+func createMemWorkerPool(persistenceFile string) (*MemWorkerPool, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	envCtx := &env.Context{
+		SystemContext: ctx,
+		WG:            new(sync.WaitGroup),
+		ErrorChan:     make(chan error, 1),
+		JobContext:    newContext(ctx),
+	}
+
+	return NewMemWorkerPool(envCtx, 3, persistenceFile), cancel
+}
+
+func waitMemJobStatus(t *testing.T, wp *MemWorkerPool, jobID string, status string) {
+	current := ""
+	for i := 0; i < 30; i++ {
+		stats, err := wp.GetJobStats(jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current = stats.Stats.Status; current == status {
+			return
+		}
+		<-time.After(100 * time.Millisecond)
+	}
+
+	t.Fatalf("expect job %s with status '%s' but got '%s'", jobID, status, current)
+}
+
+type fakeFailedJob struct{}
+
+func (j *fakeFailedJob) MaxFails() uint {
+	return 3
+}
+
+func (j *fakeFailedJob) ShouldRetry() bool {
+	return false
+}
+
+func (j *fakeFailedJob) Validate(params map[string]interface{}) error {
+	return nil
+}
+
+func (j *fakeFailedJob) Run(ctx env.JobContext, params map[string]interface{}) error {
+	return errors.New("fake job failed")
+}
@@ -58,12 +58,17 @@ func (rj *RedisJob) Run(j *work.Job) error {
 		runningJob         job.Interface
 		err                error
 		execContext        env.JobContext
+		status             = job.JobStatusError
 	)
 
+	// The reserved workflow parameters are not passed to the job
+	workflowID, nodeID, args := extractWorkflowBinding(j.Args)
+
 	defer func() {
 		// 镜像扫描任务完成后，会执行此语句
 		if err == nil {
 			logger.Infof("Job '%s:%s' exit with success", j.Name, j.ID)
+			rj.workflowNodeDone(workflowID, nodeID, job.JobStatusSuccess)
 			return // nothing need to do
 		}
 
@@ -82,6 +87,11 @@ func (rj *RedisJob) Run(j *work.Job) error {
 				rj.statsManager.DieAt(j.ID, now)
 			}()
 		}
+
+		if status == job.JobStatusStopped || j.Fails+1 >= int64(maxFailsOf(runningJob)) {
+			// No more retries, the job is done
+			rj.workflowNodeDone(workflowID, nodeID, status)
+		}
 	}()
 
 	defer func() {
@@ -102,7 +112,7 @@ func (rj *RedisJob) Run(j *work.Job) error {
 	runningJob = Wrap(rj.job)
 
 	// 需要执行的 context，execContext 的类型为JobContext,这里面包含了 3 个执行函数
-	execContext, err = rj.buildContext(j)
+	execContext, err = rj.buildContext(j, args)
 	if err != nil {
 		buildContextFailed = true
 		goto FAILED // no need to retry
@@ -132,7 +142,7 @@ func (rj *RedisJob) Run(j *work.Job) error {
 
 	// Inject data
 	// 注入需要执行的 JobContext 以及 job 的参数
-	err = runningJob.Run(execContext, j.Args)
+	err = runningJob.Run(execContext, args)
 
 	// update the proper status
 	if err == nil {
@@ -142,12 +152,14 @@ func (rj *RedisJob) Run(j *work.Job) error {
 
 	if errs.IsJobStoppedError(err) {
 		rj.jobStopped(j.ID)
+		status = job.JobStatusStopped
 		return nil // no need to put it into the dead queue for resume
 	}
 
 	if errs.IsJobCancelledError(err) {
 		rj.jobCancelled(j.ID)
 		cancelled = true
+		status = job.JobStatusCancelled
 		return err // need to resume
 	}
 
@@ -176,12 +188,12 @@ func (rj *RedisJob) jobSucceed(jobID string) {
 	rj.statsManager.SetJobStatus(jobID, job.JobStatusSuccess)
 }
 
-func (rj *RedisJob) buildContext(j *work.Job) (env.JobContext, error) {
+func (rj *RedisJob) buildContext(j *work.Job, args map[string]interface{}) (env.JobContext, error) {
 	// Build job execution context
 	jData := env.JobData{
 		ID:        j.ID,
 		Name:      j.Name,
-		Args:      j.Args,
+		Args:      args,
 		ExtraData: make(map[string]interface{}),
 	}
 
@@ -261,10 +273,7 @@ func (rj *RedisJob) buildContext(j *work.Job) (env.JobContext, error) {
 }
 
 func (rj *RedisJob) shouldDisableRetry(j job.Interface, wj *work.Job, cancelled bool) bool {
-	maxFails := j.MaxFails()
-	if maxFails == 0 {
-		maxFails = 4 // Consistent with backend worker pool
-	}
+	maxFails := maxFailsOf(j)
 	fails := wj.Fails
 	fails++ // as the fail is not returned to backend pool yet
 
@@ -278,3 +287,46 @@ func (rj *RedisJob) shouldDisableRetry(j job.Interface, wj *work.Job, cancelled
 
 	return false
 }
+
+func (rj *RedisJob) workflowNodeDone(workflowID, nodeID, status string) {
+	if utils.IsEmptyStr(workflowID) || utils.IsEmptyStr(nodeID) {
+		return // not a job of workflow
+	}
+
+	doneFunc, ok := rj.context.SystemContext.Value(utils.CtlKeyOfWorkflowNodeDoneFunc).(job.WorkflowNodeDoneFunc)
+	if !ok {
+		logger.Errorf("No workflow node done func provided, workflow '%s' can not be advanced", workflowID)
+		return
+	}
+
+	if err := doneFunc(workflowID, nodeID, status); err != nil {
+		logger.Errorf("Advance workflow '%s' with node '%s:%s' error: %s", workflowID, nodeID, status, err)
+	}
+}
+
+func maxFailsOf(j job.Interface) uint {
+	maxFails := j.MaxFails()
+	if maxFails == 0 {
+		maxFails = 4 // Consistent with backend worker pool
+	}
+
+	return maxFails
+}
+
+// extractWorkflowBinding returns the workflow binding and the job args without the reserved workflow parameters.
+func extractWorkflowBinding(args map[string]interface{}) (string, string, map[string]interface{}) {
+	workflowID, _ := args[job.ParamKeyWorkflowID].(string)
+	nodeID, _ := args[job.ParamKeyWorkflowNode].(string)
+	if len(workflowID) == 0 && len(nodeID) == 0 {
+		return "", "", args
+	}
+
+	jobArgs := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if k != job.ParamKeyWorkflowID && k != job.ParamKeyWorkflowNode {
+			jobArgs[k] = v
+		}
+	}
+
+	return workflowID, nodeID, jobArgs
+}
@@ -91,6 +91,29 @@ func TestJobWrapper(t *testing.T) {
 	}
 }
 
+func TestExtractWorkflowBinding(t *testing.T) {
+	args := map[string]interface{}{
+		"image":                  "library/busybox",
+		job.ParamKeyWorkflowID:   "fake_workflow_ID",
+		job.ParamKeyWorkflowNode: "scan",
+	}
+
+	workflowID, nodeID, jobArgs := extractWorkflowBinding(args)
+	if workflowID != "fake_workflow_ID" || nodeID != "scan" {
+		t.Fatalf("expect binding 'fake_workflow_ID:scan' but got '%s:%s'", workflowID, nodeID)
+	}
+	if len(jobArgs) != 1 || jobArgs["image"] != "library/busybox" {
+		t.Fatalf("expect reserved parameters removed but got %v", jobArgs)
+	}
+	if len(args) != 3 {
+		t.Fatal("expect original args kept for retrying")
+	}
+
+	if workflowID, _, _ := extractWorkflowBinding(jobArgs); workflowID != "" {
+		t.Fatalf("expect no workflow binding but got '%s'", workflowID)
+	}
+}
+
 type fakeParentJob struct{}
 
 func (j *fakeParentJob) MaxFails() uint {
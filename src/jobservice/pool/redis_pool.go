@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
@@ -65,6 +66,9 @@ type GoCraftWorkPool struct {
 	// key is name of known job
 	// value is the type of known job
 	knownJobs map[string]interface{}
+	// key is name of known job
+	// value is the options the job registered with
+	jobOptions map[string]work.JobOptions
 }
 
 // RedisPoolContext ...
@@ -98,6 +102,7 @@ func NewGoCraftWorkPool(ctx *env.Context, namespace string, workerCount uint, re
 		context:       ctx,
 		statsManager:  statsMgr,
 		knownJobs:     make(map[string]interface{}),
+		jobOptions:    make(map[string]work.JobOptions),
 		messageServer: msgServer,
 		deDuplicator:  deDepulicator,
 	}
@@ -266,16 +271,29 @@ func (gcwp *GoCraftWorkPool) RegisterJob(name string, j interface{}) error {
 
 	// 给每种类型的 job 创建执行函数。提前在 redis 中注册好。当redis 中有新 job 时，只需调用对应处理函数即可
 	// 将其存储到 work pool 中
+	// Apply the configured priority and concurrency limit
+	options := work.JobOptions{
+		Priority: 1, // Consistent with backend worker pool
+		MaxFails: theJ.MaxFails(),
+	}
+	if jobCfg := config.GetJobConfig(name); jobCfg != nil {
+		if jobCfg.Priority > 0 {
+			options.Priority = jobCfg.Priority
+		}
+		options.MaxConcurrency = jobCfg.MaxConcurrency
+	}
+
 	gcwp.pool.JobWithOptions(name,
-		work.JobOptions{MaxFails: theJ.MaxFails()},
+		options,
 		func(job *work.Job) error {
 			// 执行 job 的逻辑重点
 			return redisJob.Run(job)
 		}, // Use generic handler to handle as we do not accept context with this way. 这只是测试用 ？
 	)
 	gcwp.knownJobs[name] = j // keep the name of registered jobs as known jobs for future validation
+	gcwp.jobOptions[name] = options
 
-	logger.Infof("Register job %s with name %s", reflect.TypeOf(j).String(), name)
+	logger.Infof("Register job %s with name %s, priority %d and max concurrency %d", reflect.TypeOf(j).String(), name, options.Priority, options.MaxConcurrency)
 
 	return nil
 }
@@ -417,6 +435,36 @@ func (gcwp *GoCraftWorkPool) GetJobStats(jobID string) (models.JobStats, error)
 	return gcwp.statsManager.Retrieve(jobID)
 }
 
+// ListJobs returns the stats of the jobs matching the query.
+func (gcwp *GoCraftWorkPool) ListJobs(query models.JobQuery) (models.JobStatsList, error) {
+	return gcwp.statsManager.List(query)
+}
+
+// SaveWorkflow persists the workflow via the stats manager.
+func (gcwp *GoCraftWorkPool) SaveWorkflow(stats models.WorkflowStats) error {
+	return gcwp.statsManager.SaveWorkflow(stats)
+}
+
+// GetWorkflowStats return the stats of the specified workflow.
+func (gcwp *GoCraftWorkPool) GetWorkflowStats(workflowID string) (models.WorkflowStats, error) {
+	return gcwp.statsManager.RetrieveWorkflow(workflowID)
+}
+
+// ClaimWorkflowNode claims the workflow node before launching it.
+func (gcwp *GoCraftWorkPool) ClaimWorkflowNode(workflowID, nodeID string) (bool, error) {
+	return gcwp.statsManager.ClaimWorkflowNode(workflowID, nodeID)
+}
+
+// SetWorkflowNodeJob binds the launched job to the workflow node.
+func (gcwp *GoCraftWorkPool) SetWorkflowNodeJob(workflowID, nodeID, jobID string) error {
+	return gcwp.statsManager.SetWorkflowNodeJob(workflowID, nodeID, jobID)
+}
+
+// SetWorkflowNodeStatus marks the final status of the workflow node.
+func (gcwp *GoCraftWorkPool) SetWorkflowNodeStatus(workflowID, nodeID, status string) error {
+	return gcwp.statsManager.SetWorkflowNodeStatus(workflowID, nodeID, status)
+}
+
 // Stats of pool
 func (gcwp *GoCraftWorkPool) Stats() (models.JobPoolStats, error) {
 	// Get the status of workerpool via client
@@ -426,6 +474,27 @@ func (gcwp *GoCraftWorkPool) Stats() (models.JobPoolStats, error) {
 		return models.JobPoolStats{}, err
 	}
 
+	// Usage of jobs
+	observations, err := gcwp.client.WorkerObservations()
+	if err != nil {
+		return models.JobPoolStats{}, err
+	}
+	queues, err := gcwp.client.Queues()
+	if err != nil {
+		return models.JobPoolStats{}, err
+	}
+
+	busyJobs := make(map[string]string) // worker ID -> job name
+	for _, ob := range observations {
+		if ob.IsBusy {
+			busyJobs[ob.WorkerID] = ob.JobName
+		}
+	}
+	pendingJobs := make(map[string]int64)
+	for _, q := range queues {
+		pendingJobs[q.JobName] = q.Count
+	}
+
 	// Find the heartbeat of this pool via pid
 	stats := make([]*models.JobPoolStatsData, 0)
 	for _, hb := range hbs {
@@ -444,6 +513,7 @@ func (gcwp *GoCraftWorkPool) Stats() (models.JobPoolStats, error) {
 			JobNames:     hb.JobNames,
 			Concurrency:  hb.Concurrency,
 			Status:       wPoolStatus,
+			Jobs:         gcwp.jobUsage(hb.JobNames, hb.WorkerIDs, busyJobs, pendingJobs),
 		}
 		stats = append(stats, stat)
 	}
@@ -457,6 +527,33 @@ func (gcwp *GoCraftWorkPool) Stats() (models.JobPoolStats, error) {
 	}, nil
 }
 
+// jobUsage returns the settings and usage of the jobs running by the specified workers.
+func (gcwp *GoCraftWorkPool) jobUsage(jobNames, workerIDs []string, busyJobs map[string]string, pendingJobs map[string]int64) []*models.JobUsageData {
+	running := make(map[string]int)
+	for _, workerID := range workerIDs {
+		if jobName, ok := busyJobs[workerID]; ok {
+			running[jobName]++
+		}
+	}
+
+	usage := make([]*models.JobUsageData, 0, len(jobNames))
+	for _, jobName := range jobNames {
+		data := &models.JobUsageData{
+			Name:    jobName,
+			Running: running[jobName],
+			Pending: pendingJobs[jobName],
+		}
+		// The settings are only known for the jobs registered by this pool
+		if options, ok := gcwp.jobOptions[jobName]; ok {
+			data.Priority = options.Priority
+			data.MaxConcurrency = options.MaxConcurrency
+		}
+		usage = append(usage, data)
+	}
+
+	return usage
+}
+
 // StopJob will stop the job
 func (gcwp *GoCraftWorkPool) StopJob(jobID string) error {
 	if utils.IsEmptyStr(jobID) {
@@ -21,6 +21,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gocraft/work"
+
 	"github.com/goharbor/harbor/src/jobservice/errs"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
@@ -175,6 +177,29 @@ func TestPoolStats(t *testing.T) {
 	}
 }
 
+func TestJobUsage(t *testing.T) {
+	wp, _, cancel := createRedisWorkerPool()
+	defer cancel()
+
+	wp.jobOptions["fake_job"] = work.JobOptions{Priority: 100, MaxConcurrency: 2}
+	usage := wp.jobUsage(
+		[]string{"fake_job", "fake_job_1st"},
+		[]string{"w1", "w2", "w3"},
+		map[string]string{"w1": "fake_job", "w2": "fake_job", "w4": "fake_job"},
+		map[string]int64{"fake_job": 5},
+	)
+
+	if len(usage) != 2 {
+		t.Fatalf("expect usage of 2 jobs but got %d", len(usage))
+	}
+	if usage[0].Priority != 100 || usage[0].MaxConcurrency != 2 || usage[0].Running != 2 || usage[0].Pending != 5 {
+		t.Errorf("expect priority 100, max concurrency 2, 2 running and 5 pending but got %+v", usage[0])
+	}
+	if usage[1].Running != 0 || usage[1].Pending != 0 {
+		t.Errorf("expect no running and pending jobs but got %+v", usage[1])
+	}
+}
+
 func TestStopJob(t *testing.T) {
 	wp, _, cancel := createRedisWorkerPool()
 	defer func() {
@@ -32,7 +32,9 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/job/impl"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/gc"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/replication"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/retention"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/scan"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/webhook"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/models"
 	"github.com/goharbor/harbor/src/jobservice/pool"
@@ -88,14 +90,17 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 		wpErr       error
 	)
 	// 初始化并启动工作池，当有 job 来是分配 work 来执行。
-	if config.DefaultConfig.PoolConfig.Backend == config.JobServicePoolBackendRedis {
+	switch config.DefaultConfig.PoolConfig.Backend {
+	case config.JobServicePoolBackendRedis:
 		backendPool, wpErr = bs.loadAndRunRedisWorkerPool(rootContext, config.DefaultConfig)
-		if wpErr != nil {
-			logger.Fatalf("Failed to load and run worker pool: %s\n", wpErr.Error())
-		}
-	} else {
+	case config.JobServicePoolBackendMemory:
+		backendPool, wpErr = bs.loadAndRunMemoryWorkerPool(rootContext, config.DefaultConfig)
+	default:
 		logger.Fatalf("Worker pool backend '%s' is not supported", config.DefaultConfig.PoolConfig.Backend)
 	}
+	if wpErr != nil {
+		logger.Fatalf("Failed to load and run worker pool: %s\n", wpErr.Error())
+	}
 
 	// Initialize controller
 	ctl := core.NewController(backendPool)
@@ -104,6 +109,11 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 		return ctl.LaunchJob(req)
 	}
 	rootContext.SystemContext = context.WithValue(rootContext.SystemContext, utils.CtlKeyOfLaunchJobFunc, launchJobFunc)
+	// Keep the workflow advancing func in the system context
+	var workflowNodeDoneFunc jsjob.WorkflowNodeDoneFunc = func(workflowID, nodeID, status string) error {
+		return ctl.OnWorkflowNodeDone(workflowID, nodeID, status)
+	}
+	rootContext.SystemContext = context.WithValue(rootContext.SystemContext, utils.CtlKeyOfWorkflowNodeDoneFunc, workflowNodeDoneFunc)
 
 	// Start the API server
 	apiServer := bs.loadAndRunAPIServer(rootContext, config.DefaultConfig, ctl)
@@ -204,21 +214,7 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(ctx *env.Context, cfg *config.Con
 		cfg.PoolConfig.WorkerCount,
 		redisPool)
 	// Register jobs here
-	// 注册 job 信息，在初始的过程中，会执行 DemoJob
-	if err := redisWorkerPool.RegisterJob(impl.KnownJobDemo, (*impl.DemoJob)(nil)); err != nil {
-		// exit
-		return nil, err
-	}
-	// 注册各种类型的 job，这些工作都会在启动的时候被执行一遍
-	if err := redisWorkerPool.RegisterJobs(
-		map[string]interface{}{
-			job.ImageScanJob:    (*scan.ClairJob)(nil),
-			job.ImageScanAllJob: (*scan.All)(nil),
-			job.ImageTransfer:   (*replication.Transfer)(nil),
-			job.ImageDelete:     (*replication.Deleter)(nil),
-			job.ImageReplicate:  (*replication.Replicator)(nil),
-			job.ImageGC:         (*gc.GarbageCollector)(nil),
-		}); err != nil {
+	if err := registerJobs(redisWorkerPool); err != nil {
 		// exit
 		return nil, err
 	}
@@ -230,3 +226,45 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(ctx *env.Context, cfg *config.Con
 
 	return redisWorkerPool, nil
 }
+
+// Load and run the in-memory worker pool
+func (bs *Bootstrap) loadAndRunMemoryWorkerPool(ctx *env.Context, cfg *config.Configuration) (pool.Interface, error) {
+	persistenceFile := ""
+	if cfg.PoolConfig.MemPoolCfg != nil {
+		persistenceFile = cfg.PoolConfig.MemPoolCfg.PersistenceFile
+	}
+
+	memWorkerPool := pool.NewMemWorkerPool(ctx, cfg.PoolConfig.WorkerCount, persistenceFile)
+	if err := registerJobs(memWorkerPool); err != nil {
+		// exit
+		return nil, err
+	}
+
+	if err := memWorkerPool.Start(); err != nil {
+		return nil, err
+	}
+
+	return memWorkerPool, nil
+}
+
+// Register the known jobs to the worker pool
+func registerJobs(workerPool pool.Interface) error {
+	// 注册 job 信息，在初始的过程中，会执行 DemoJob
+	if err := workerPool.RegisterJob(impl.KnownJobDemo, (*impl.DemoJob)(nil)); err != nil {
+		return err
+	}
+
+	// 注册各种类型的 job，这些工作都会在启动的时候被执行一遍
+	return workerPool.RegisterJobs(
+		map[string]interface{}{
+			job.ImageScanJob:    (*scan.Job)(nil),
+			job.ImageScanAllJob: (*scan.All)(nil),
+			job.ImageTransfer:   (*replication.Transfer)(nil),
+			job.ImageDelete:     (*replication.Deleter)(nil),
+			job.ImageReplicate:  (*replication.Replicator)(nil),
+			job.ChartTransfer:   (*replication.ChartTransfer)(nil),
+			job.ImageGC:         (*gc.GarbageCollector)(nil),
+			job.TagRetention:    (*retention.Job)(nil),
+			job.WebhookDelivery: (*webhook.Job)(nil),
+		})
+}
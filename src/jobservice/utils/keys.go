@@ -78,6 +78,17 @@ func KeyJobStats(namespace string, jobID string) string {
 	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "job_stats", jobID)
 }
 
+// KeyJobStatsIndex returns the key of the index of job stats ordered by the enqueue time
+func KeyJobStatsIndex(namespace string) string {
+	return fmt.Sprintf("%s%s", KeyNamespacePrefix(namespace), "job_stats_index")
+}
+
+// KeyJobStatsExpiry returns the key of the expiry time of the job stats in the index,
+// the periodic jobs are not included until they're expired
+func KeyJobStatsExpiry(namespace string) string {
+	return fmt.Sprintf("%s%s", KeyNamespacePrefix(namespace), "job_stats_expiry")
+}
+
 // KeyJobCtlCommands returns the key for publishing ctl commands like 'stop' etc.
 func KeyJobCtlCommands(namespace string, jobID string) string {
 	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "ctl_commands", jobID)
@@ -87,3 +98,8 @@ func KeyJobCtlCommands(namespace string, jobID string) string {
 func KeyUpstreamJobAndExecutions(namespace, upstreamJobID string) string {
 	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "executions", upstreamJobID)
 }
+
+// KeyWorkflow returns the key of workflow
+func KeyWorkflow(namespace string, workflowID string) string {
+	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "workflows", workflowID)
+}
@@ -32,6 +32,8 @@ type CtlContextKey string
 const (
 	// CtlKeyOfLaunchJobFunc is context key to keep the ctl launch job func
 	CtlKeyOfLaunchJobFunc CtlContextKey = "controller_launch_job_func"
+	// CtlKeyOfWorkflowNodeDoneFunc is context key to keep the ctl func advancing workflows
+	CtlKeyOfWorkflowNodeDoneFunc CtlContextKey = "controller_workflow_node_done_func"
 )
 
 // IsEmptyStr check if the specified str is empty (len ==0) after triming prefix and suffix spaces.
@@ -17,6 +17,7 @@ package api
 import (
 	"bytes"
 	"net/http"
+	"strings"
 	"time"
 
 	"os/exec"
@@ -26,14 +27,22 @@ import (
 
 const (
 	regConf = "/etc/registry/config.yml"
+
+	// the prefixes of the lines printed by registry for the deleted objects
+	blobDeletedPrefix     = "blob eligible for deletion"
+	manifestDeletedPrefix = "manifest eligible for deletion"
 )
 
 // GCResult ...
 type GCResult struct {
-	Status    bool      `json:"status"`
-	Msg       string    `json:"msg"`
-	StartTime time.Time `json:"starttime"`
-	EndTime   time.Time `json:"endtime"`
+	Status           bool      `json:"status"`
+	Msg              string    `json:"msg"`
+	StartTime        time.Time `json:"starttime"`
+	EndTime          time.Time `json:"endtime"`
+	BlobsDeleted     int       `json:"blobs_deleted"`
+	ManifestsDeleted int       `json:"manifests_deleted"`
+	// the digests of the blobs deleted, used to sum up the storage freed
+	DeletedBlobs []string `json:"deleted_blobs"`
 }
 
 // StartGC ...
@@ -52,10 +61,34 @@ func StartGC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gcr := GCResult{true, outBuf.String(), start, time.Now()}
+	gcr := GCResult{
+		Status:    true,
+		Msg:       outBuf.String(),
+		StartTime: start,
+		EndTime:   time.Now(),
+	}
+	gcr.DeletedBlobs, gcr.ManifestsDeleted = parseDeleted(gcr.Msg)
+	gcr.BlobsDeleted = len(gcr.DeletedBlobs)
 	if err := writeJSON(w, gcr); err != nil {
 		log.Errorf("failed to write response: %v", err)
 		return
 	}
 	log.Debugf("Successful to execute garbage collection...")
 }
+
+// parseDeleted returns the digests of the blobs and the count of the manifests
+// deleted by the garbage collection from its output
+func parseDeleted(output string) ([]string, int) {
+	blobs, manifests := []string{}, 0
+	for _, line := range strings.Split(output, "\n") {
+		if i := strings.Index(line, blobDeletedPrefix); i >= 0 {
+			digest := strings.TrimSpace(strings.TrimPrefix(line[i+len(blobDeletedPrefix):], ":"))
+			if len(digest) > 0 {
+				blobs = append(blobs, digest)
+			}
+		} else if strings.Contains(line, manifestDeletedPrefix) {
+			manifests++
+		}
+	}
+	return blobs, manifests
+}
@@ -9,9 +9,20 @@ const (
 	FilterItemKindTag = "tag"
 	// FilterItemKindLabel : Kind of filter item is 'label'
 	FilterItemKindLabel = "label"
+	// FilterItemKindResource : Kind of filter item is 'resource'
+	FilterItemKindResource = "resource"
+	// FilterItemKindChart : Kind of filter item is 'chart'
+	FilterItemKindChart = "chart"
+
+	// ResourceTypeImage : The images are replicated by the policy
+	ResourceTypeImage = "image"
+	// ResourceTypeChart : The Helm charts are replicated by the policy
+	ResourceTypeChart = "chart"
 
 	// AdaptorKindHarbor : Kind of adaptor of Harbor
 	AdaptorKindHarbor = "Harbor"
+	// AdaptorKindDockerRegistry : Kind of adaptor of the registries which speak Docker Registry HTTP API V2
+	AdaptorKindDockerRegistry = "DockerRegistry"
 
 	// TriggerKindImmediate : Kind of trigger is 'Immediate'
 	TriggerKindImmediate = "Immediate"
@@ -20,6 +31,11 @@ const (
 	// TriggerKindManual : Kind of trigger is 'Manual'
 	TriggerKindManual = "Manual"
 
+	// DirectionPush : Push the images of local projects to the targets
+	DirectionPush = "push"
+	// DirectionPull : Pull the images from the targets into local projects
+	DirectionPull = "pull"
+
 	// TriggerScheduleDaily : type of scheduling is 'Daily'
 	TriggerScheduleDaily = "Daily"
 	// TriggerScheduleWeekly : type of scheduling is 'Weekly'
@@ -21,10 +21,12 @@ import (
 
 	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/utils"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
 	"github.com/goharbor/harbor/src/replication/policy"
+	"github.com/goharbor/harbor/src/replication/registry"
 	"github.com/goharbor/harbor/src/replication/replicator"
 	"github.com/goharbor/harbor/src/replication/source"
 	"github.com/goharbor/harbor/src/replication/target"
@@ -67,6 +69,9 @@ var (
 	GlobalController Controller
 )
 
+// chartMuseumEnabled reports whether the chart repository of local Harbor is enabled
+var chartMuseumEnabled = config.WithChartMuseum
+
 // ControllerConfig includes related configurations required by the controller
 type ControllerConfig struct {
 	// The capacity of the cache storing enabled triggers
@@ -209,12 +214,6 @@ func (ctl *DefaultController) Replicate(policyID int64, metadata ...map[string]i
 		return fmt.Errorf("policy %d not found", policyID)
 	}
 
-	// prepare candidates for replication
-	candidates := getCandidates(&policy, ctl.sourcer, metadata...)
-	if len(candidates) == 0 {
-		log.Debugf("replication candidates are null, no further action needed")
-	}
-
 	targets := []*common_models.RepTarget{}
 	for _, targetID := range policy.TargetIDs {
 		target, err := ctl.targetManager.GetTarget(targetID)
@@ -230,15 +229,61 @@ func (ctl *DefaultController) Replicate(policyID int64, metadata ...map[string]i
 		return err
 	}
 
+	if policy.Direction == replication.DirectionPull {
+		return ctl.pull(&policy, opUUID, targets)
+	}
+
+	// prepare candidates for replication
+	candidates := getCandidates(&policy, ctl.sourcer, metadata...)
+	if len(candidates) == 0 {
+		log.Debugf("replication candidates are null, no further action needed")
+	}
+
 	// submit the replication
 	return ctl.replicator.Replicate(&replicator.Replication{
 		PolicyID:   policyID,
 		OpUUID:     opUUID,
 		Candidates: candidates,
 		Targets:    targets,
+		Direction:  replication.DirectionPush,
 	})
 }
 
+// pull enumerates the candidates from every target registry and submits them
+// to be replicated into the local project which has the same name as the namespace
+func (ctl *DefaultController) pull(policy *models.ReplicationPolicy, opUUID string,
+	targets []*common_models.RepTarget) error {
+	for _, target := range targets {
+		adaptor, err := registry.NewAdaptor(target)
+		if err != nil {
+			return err
+		}
+
+		candidates := getRemoteCandidates(policy, adaptor)
+		if len(candidates) == 0 {
+			log.Debugf("replication candidates from target %s are null, no further action needed", target.Name)
+			continue
+		}
+
+		if err = ctl.replicator.Replicate(&replicator.Replication{
+			PolicyID:   policy.ID,
+			OpUUID:     opUUID,
+			Candidates: candidates,
+			Targets:    []*common_models.RepTarget{target},
+			Direction:  replication.DirectionPull,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRemoteCandidates returns the candidates of pull-based replication, they
+// are enumerated and filtered through the adaptor of the target registry
+func getRemoteCandidates(policy *models.ReplicationPolicy, adaptor registry.Adaptor) []models.FilterItem {
+	return getNamespaceCandidates(policy, adaptor)
+}
+
 func getCandidates(policy *models.ReplicationPolicy, sourcer *source.Sourcer,
 	metadata ...map[string]interface{}) []models.FilterItem {
 	candidates := []models.FilterItem{}
@@ -252,47 +297,65 @@ func getCandidates(policy *models.ReplicationPolicy, sourcer *source.Sourcer,
 		}
 	}
 
+	adaptor := sourcer.GetAdaptor(replication.AdaptorKindHarbor)
 	if len(candidates) == 0 {
-		for _, namespace := range policy.Namespaces {
-			candidates = append(candidates, models.FilterItem{
-				Kind:      replication.FilterItemKindProject,
-				Value:     namespace,
-				Operation: common_models.RepOpTransfer,
-			})
-		}
+		return getNamespaceCandidates(policy, adaptor)
 	}
 
-	filterChain := buildFilterChain(policy, sourcer)
+	// the candidates provided by the metadata are images
+	if !policy.Replicates(replication.ResourceTypeImage) {
+		return []models.FilterItem{}
+	}
+	filterChain := buildFilterChain(policy, adaptor)
 
 	return filterChain.DoFilter(candidates)
 }
 
-func buildFilterChain(policy *models.ReplicationPolicy, sourcer *source.Sourcer) source.FilterChain {
-	filters := []source.Filter{}
+// getNamespaceCandidates enumerates the images and charts under the namespaces of
+// the policy through the adaptor and filters them with the filters of the policy
+func getNamespaceCandidates(policy *models.ReplicationPolicy, adaptor registry.Adaptor) []models.FilterItem {
+	namespaces := []models.FilterItem{}
+	for _, namespace := range policy.Namespaces {
+		namespaces = append(namespaces, models.FilterItem{
+			Kind:      replication.FilterItemKindProject,
+			Value:     namespace,
+			Operation: common_models.RepOpTransfer,
+		})
+	}
 
-	fm := map[string][]models.Filter{}
-	for _, filter := range policy.Filters {
-		fm[filter.Kind] = append(fm[filter.Kind], filter)
+	candidates := []models.FilterItem{}
+	if policy.Replicates(replication.ResourceTypeImage) {
+		candidates = append(candidates, buildFilterChain(policy, adaptor).DoFilter(namespaces)...)
 	}
+	// the charts are replicated from or into the chart repository of local Harbor
+	if policy.Replicates(replication.ResourceTypeChart) {
+		if chartMuseumEnabled() {
+			candidates = append(candidates, buildChartFilterChain(policy, adaptor).DoFilter(namespaces)...)
+		} else {
+			log.Debugf("chart repository is not enabled, skip replicating charts of policy %d", policy.ID)
+		}
+	}
+	return candidates
+}
+
+func buildFilterChain(policy *models.ReplicationPolicy, registry registry.Adaptor) source.FilterChain {
+	filters := []source.Filter{}
+
+	fm := groupFilters(policy)
 
-	registry := sourcer.GetAdaptor(replication.AdaptorKindHarbor)
 	// repository filter
-	pattern := ""
-	repoFilters := fm[replication.FilterItemKindRepository]
-	if len(repoFilters) > 0 {
-		pattern = repoFilters[0].Value.(string)
-	}
 	filters = append(filters,
-		source.NewRepositoryFilter(pattern, registry))
+		source.NewRepositoryFilter(filterPattern(fm, replication.FilterItemKindRepository), registry))
 	// tag filter
-	pattern = ""
-	tagFilters := fm[replication.FilterItemKindTag]
-	if len(tagFilters) > 0 {
-		pattern = tagFilters[0].Value.(string)
-	}
 	filters = append(filters,
-		source.NewTagFilter(pattern, registry))
-	// label filters
+		source.NewTagFilter(filterPattern(fm, replication.FilterItemKindTag), registry))
+	// label filters, the labels only exist in the local registry
+	if policy.Direction == replication.DirectionPull {
+		if len(fm[replication.FilterItemKindLabel]) > 0 {
+			log.Warningf("label filters are not supported by pull-based replication, ignore them")
+		}
+		return source.NewDefaultFilterChain(filters)
+	}
 	var labelID int64
 	for _, labelFilter := range fm[replication.FilterItemKindLabel] {
 		labelID = labelFilter.Value.(int64)
@@ -302,6 +365,44 @@ func buildFilterChain(policy *models.ReplicationPolicy, sourcer *source.Sourcer)
 	return source.NewDefaultFilterChain(filters)
 }
 
+// buildChartFilterChain builds the filter chain for charts, the repository and tag
+// filters of the policy are applied to the names and versions of charts
+func buildChartFilterChain(policy *models.ReplicationPolicy, registry registry.Adaptor) source.FilterChain {
+	fm := groupFilters(policy)
+
+	filters := []source.Filter{
+		source.NewChartFilter(filterPattern(fm, replication.FilterItemKindRepository),
+			filterPattern(fm, replication.FilterItemKindTag), registry),
+	}
+	// label filters, the labels only exist in the local registry
+	if policy.Direction == replication.DirectionPull {
+		return source.NewDefaultFilterChain(filters)
+	}
+	for _, labelFilter := range fm[replication.FilterItemKindLabel] {
+		filters = append(filters, source.NewLabelFilter(labelFilter.Value.(int64)))
+	}
+
+	return source.NewDefaultFilterChain(filters)
+}
+
+// groupFilters groups the filters of the policy by kind
+func groupFilters(policy *models.ReplicationPolicy) map[string][]models.Filter {
+	fm := map[string][]models.Filter{}
+	for _, filter := range policy.Filters {
+		fm[filter.Kind] = append(fm[filter.Kind], filter)
+	}
+	return fm
+}
+
+// filterPattern returns the pattern of the first filter with the specified kind
+func filterPattern(fm map[string][]models.Filter, kind string) string {
+	filters := fm[kind]
+	if len(filters) == 0 {
+		return ""
+	}
+	return filters[0].Value.(string)
+}
+
 // getOpUUID get operation uuid from metadata or generate one if none found.
 func getOpUUID(metadata ...map[string]interface{}) (string, error) {
 	if len(metadata) <= 0 {
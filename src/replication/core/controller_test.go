@@ -21,10 +21,12 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
 	"github.com/goharbor/harbor/src/replication/source"
 	"github.com/goharbor/harbor/src/replication/target"
 	"github.com/goharbor/harbor/src/replication/trigger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -34,6 +36,7 @@ func TestMain(m *testing.M) {
 		sourcer:        source.NewSourcer(),
 		triggerManager: trigger.NewManager(0),
 	}
+	chartMuseumEnabled = func() bool { return false }
 	os.Exit(m.Run())
 }
 
@@ -130,6 +133,113 @@ func TestGetCandidates(t *testing.T) {
 	assert.Equal(t, 0, len(result))
 }
 
+type fakeAdaptor struct {
+	registry.HarborAdaptor
+}
+
+func (f *fakeAdaptor) GetRepositories(namespace string) []models.Repository {
+	return []models.Repository{
+		{Name: namespace + "/hello-world"},
+		{Name: namespace + "/busybox"},
+	}
+}
+
+func (f *fakeAdaptor) GetTags(repositoryName string, namespace string) []models.Tag {
+	return []models.Tag{
+		{Name: "latest"},
+		{Name: "release-1.0"},
+	}
+}
+
+func (f *fakeAdaptor) GetCharts(namespace string) []models.Chart {
+	return []models.Chart{
+		{Name: "harbor", Version: "0.1.0"},
+		{Name: "redis", Version: "1.0.0"},
+	}
+}
+
+func TestGetRemoteCandidates(t *testing.T) {
+	policy := &models.ReplicationPolicy{
+		ID:         1,
+		Direction:  replication.DirectionPull,
+		Namespaces: []string{"library"},
+		Trigger: &models.Trigger{
+			Kind: replication.TriggerKindManual,
+		},
+	}
+
+	adaptor := &fakeAdaptor{}
+	result := getRemoteCandidates(policy, adaptor)
+	assert.Equal(t, 4, len(result))
+
+	policy.Filters = []models.Filter{
+		{
+			Kind:  replication.FilterItemKindRepository,
+			Value: "hello-*",
+		},
+		{
+			Kind:  replication.FilterItemKindTag,
+			Value: "release-*",
+		},
+		// label filter is ignored by pull-based replication
+		{
+			Kind:  replication.FilterItemKindLabel,
+			Value: int64(1),
+		},
+	}
+	result = getRemoteCandidates(policy, adaptor)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "library/hello-world:release-1.0", result[0].Value)
+}
+
+func TestGetChartCandidates(t *testing.T) {
+	chartMuseumEnabled = func() bool { return true }
+	defer func() {
+		chartMuseumEnabled = func() bool { return false }
+	}()
+
+	policy := &models.ReplicationPolicy{
+		ID:         1,
+		Direction:  replication.DirectionPull,
+		Namespaces: []string{"library"},
+		Trigger: &models.Trigger{
+			Kind: replication.TriggerKindManual,
+		},
+	}
+
+	adaptor := &fakeAdaptor{}
+	// both images and charts are replicated by default
+	result := getRemoteCandidates(policy, adaptor)
+	assert.Equal(t, 6, len(result))
+
+	// only charts
+	policy.Filters = []models.Filter{
+		{
+			Kind:  replication.FilterItemKindResource,
+			Value: replication.ResourceTypeChart,
+		},
+		{
+			Kind:  replication.FilterItemKindRepository,
+			Value: "har*",
+		},
+	}
+	result = getRemoteCandidates(policy, adaptor)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, replication.FilterItemKindChart, result[0].Kind)
+	assert.Equal(t, "library/harbor:0.1.0", result[0].Value)
+
+	// the candidates provided by metadata are images
+	metadata := map[string]interface{}{
+		"candidates": []models.FilterItem{
+			{
+				Kind:  replication.FilterItemKindTag,
+				Value: "library/hello-world:latest",
+			},
+		},
+	}
+	assert.Equal(t, 0, len(getCandidates(policy, source.NewSourcer(), metadata)))
+}
+
 func TestBuildFilterChain(t *testing.T) {
 	policy := &models.ReplicationPolicy{
 		ID: 1,
@@ -153,7 +263,7 @@ func TestBuildFilterChain(t *testing.T) {
 
 	sourcer := source.NewSourcer()
 
-	chain := buildFilterChain(policy, sourcer)
+	chain := buildFilterChain(policy, sourcer.GetAdaptor(replication.AdaptorKindHarbor))
 	assert.Equal(t, 3, len(chain.Filters()))
 }
 
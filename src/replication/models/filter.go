@@ -66,6 +66,17 @@ func (f *Filter) Valid(v *validation.Validation) {
 			return
 		}
 		f.Value = i
+	case replication.FilterItemKindResource:
+		resourceType, ok := f.Value.(string)
+		if !ok {
+			v.SetError("value", "the type of value should be string for resource filter")
+			return
+		}
+		if resourceType != replication.ResourceTypeImage &&
+			resourceType != replication.ResourceTypeChart {
+			v.SetError("value", fmt.Sprintf("invalid resource type: %s", resourceType))
+			return
+		}
 	default:
 		v.SetError("kind", fmt.Sprintf("invalid filter kind: %s", f.Kind))
 		return
@@ -58,6 +58,21 @@ func TestValid(t *testing.T) {
 			Kind:  replication.FilterItemKindLabel,
 			Value: 1,
 		}: true,
+		{
+			Kind: replication.FilterItemKindResource,
+		}: true,
+		{
+			Kind:  replication.FilterItemKindResource,
+			Value: "invalid_type",
+		}: true,
+		{
+			Kind:  replication.FilterItemKindResource,
+			Value: replication.ResourceTypeImage,
+		}: false,
+		{
+			Kind:  replication.FilterItemKindResource,
+			Value: replication.ResourceTypeChart,
+		}: false,
 	}
 
 	for filter, hasError := range cases {
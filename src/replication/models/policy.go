@@ -16,6 +16,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/goharbor/harbor/src/replication"
 )
 
 // ReplicationPolicy defines the structure of a replication policy.
@@ -25,6 +27,7 @@ type ReplicationPolicy struct {
 	Description       string
 	Filters           []Filter
 	ReplicateDeletion bool
+	Direction         string   // push or pull
 	Trigger           *Trigger // The trigger of the replication
 	ProjectIDs        []int64  // Projects attached to this policy
 	TargetIDs         []int64
@@ -33,6 +36,22 @@ type ReplicationPolicy struct {
 	UpdateTime        time.Time
 }
 
+// Replicates returns whether the resources of the specified type are replicated
+// by the policy, both images and charts are replicated if no resource filter is set
+func (r *ReplicationPolicy) Replicates(resourceType string) bool {
+	found := false
+	for _, filter := range r.Filters {
+		if filter.Kind != replication.FilterItemKindResource {
+			continue
+		}
+		found = true
+		if t, ok := filter.Value.(string); ok && t == resourceType {
+			return true
+		}
+	}
+	return !found
+}
+
 // QueryParameter defines the parameters used to do query selection.
 type QueryParameter struct {
 	// Query by page, couple with pageSize
@@ -0,0 +1,52 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicates(t *testing.T) {
+	// no resource filter
+	policy := &ReplicationPolicy{
+		Filters: []Filter{
+			{
+				Kind:  replication.FilterItemKindRepository,
+				Value: "*",
+			},
+		},
+	}
+	assert.True(t, policy.Replicates(replication.ResourceTypeImage))
+	assert.True(t, policy.Replicates(replication.ResourceTypeChart))
+
+	// only charts
+	policy.Filters = append(policy.Filters, Filter{
+		Kind:  replication.FilterItemKindResource,
+		Value: replication.ResourceTypeChart,
+	})
+	assert.False(t, policy.Replicates(replication.ResourceTypeImage))
+	assert.True(t, policy.Replicates(replication.ResourceTypeChart))
+
+	// both images and charts
+	policy.Filters = append(policy.Filters, Filter{
+		Kind:  replication.FilterItemKindResource,
+		Value: replication.ResourceTypeImage,
+	})
+	assert.True(t, policy.Replicates(replication.ResourceTypeImage))
+	assert.True(t, policy.Replicates(replication.ResourceTypeChart))
+}
@@ -32,3 +32,18 @@ type Tag struct {
 	// Extensions to provide flexibility
 	Metadata map[string]interface{}
 }
+
+// Chart keeps the info of Helm chart with specified version
+type Chart struct {
+	// Name of the chart
+	Name string
+
+	// Version of the chart
+	Version string
+
+	// Project reference of this chart belongs to
+	Namespace Namespace
+
+	// Extensions to provide flexibility
+	Metadata map[string]interface{}
+}
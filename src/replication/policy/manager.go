@@ -90,6 +90,7 @@ func convertFromPersistModel(policy *persist_models.RepPolicy) (models.Replicati
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		Direction:         policy.Direction,
 		ProjectIDs:        []int64{policy.ProjectID},
 		TargetIDs:         []int64{policy.TargetID},
 		CreationTime:      policy.CreationTime,
@@ -102,6 +103,10 @@ func convertFromPersistModel(policy *persist_models.RepPolicy) (models.Replicati
 	}
 	ply.Namespaces = []string{project.Name}
 
+	if len(ply.Direction) == 0 {
+		ply.Direction = replication.DirectionPush
+	}
+
 	if len(policy.Filters) > 0 {
 		filters := []models.Filter{}
 		if err := json.Unmarshal([]byte(policy.Filters), &filters); err != nil {
@@ -137,6 +142,7 @@ func convertToPersistModel(policy models.ReplicationPolicy) (*persist_models.Rep
 		Name:              policy.Name,
 		Description:       policy.Description,
 		ReplicateDeletion: policy.ReplicateDeletion,
+		Direction:         policy.Direction,
 		CreationTime:      policy.CreationTime,
 		UpdateTime:        policy.UpdateTime,
 	}
@@ -32,3 +32,10 @@ type Adaptor interface {
 	// Get the tag with the specified name of the repository under the namespace
 	GetTag(name string, repositoryName string, namespace string) models.Tag
 }
+
+// ChartAdaptor is implemented by the adaptors of the registries which host
+// Helm charts besides the images
+type ChartAdaptor interface {
+	// Get all the chart versions under the specified namespace
+	GetCharts(namespace string) []models.Chart
+}
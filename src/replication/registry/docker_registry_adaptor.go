@@ -0,0 +1,159 @@
+package registry
+
+import (
+	"net/http"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+)
+
+// DockerRegistryAdaptor is defined to adapt the registries which speak Docker Registry HTTP API V2,
+// the namespace is the first component of the repository name
+type DockerRegistryAdaptor struct {
+	target   *common_models.RepTarget
+	client   *http.Client
+	registry *reg.Registry
+}
+
+// NewDockerRegistryAdaptor returns an instance of DockerRegistryAdaptor
+func NewDockerRegistryAdaptor(target *common_models.RepTarget) (*DockerRegistryAdaptor, error) {
+	transport := reg.GetHTTPTransport(target.Insecure)
+	credential := auth.NewBasicAuthCredential(target.Username, target.Password)
+	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
+		Transport: transport,
+	}, credential)
+	client := &http.Client{
+		Transport: reg.NewTransport(transport, authorizer,
+			auth.NewFallbackAuthorizer(credential)),
+	}
+
+	registry, err := reg.NewRegistry(target.URL, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DockerRegistryAdaptor{
+		target:   target,
+		client:   client,
+		registry: registry,
+	}, nil
+}
+
+// Kind returns the unique kind identifier of the adaptor
+func (d *DockerRegistryAdaptor) Kind() string {
+	return replication.AdaptorKindDockerRegistry
+}
+
+// GetNamespaces is used to get all the namespaces
+func (d *DockerRegistryAdaptor) GetNamespaces() []models.Namespace {
+	repositories, err := d.catalog()
+	if err != nil {
+		return nil
+	}
+
+	namespaces := []models.Namespace{}
+	visited := map[string]bool{}
+	for _, repository := range repositories {
+		namespace, _ := utils.ParseRepository(repository)
+		if visited[namespace] {
+			continue
+		}
+		visited[namespace] = true
+		namespaces = append(namespaces, models.Namespace{
+			Name: namespace,
+		})
+	}
+	return namespaces
+}
+
+// GetNamespace is used to get the namespace with the specified name
+func (d *DockerRegistryAdaptor) GetNamespace(name string) models.Namespace {
+	for _, namespace := range d.GetNamespaces() {
+		if namespace.Name == name {
+			return namespace
+		}
+	}
+	return models.Namespace{}
+}
+
+// GetRepositories is used to get all the repositories under the specified namespace
+func (d *DockerRegistryAdaptor) GetRepositories(namespace string) []models.Repository {
+	repos, err := d.catalog()
+	if err != nil {
+		return nil
+	}
+
+	repositories := []models.Repository{}
+	for _, repo := range repos {
+		if ns, _ := utils.ParseRepository(repo); ns != namespace {
+			continue
+		}
+		repositories = append(repositories, models.Repository{
+			Name: repo,
+			Namespace: models.Namespace{
+				Name: namespace,
+			},
+		})
+	}
+	return repositories
+}
+
+// GetRepository is used to get the repository with the specified name under the specified namespace
+func (d *DockerRegistryAdaptor) GetRepository(name string, namespace string) models.Repository {
+	for _, repository := range d.GetRepositories(namespace) {
+		if repository.Name == name {
+			return repository
+		}
+	}
+	return models.Repository{}
+}
+
+// GetTags is used to get all the tags of the specified repository under the namespace
+func (d *DockerRegistryAdaptor) GetTags(repositoryName string, namespace string) []models.Tag {
+	repository, err := reg.NewRepository(repositoryName, d.target.URL, d.client)
+	if err != nil {
+		log.Errorf("failed to create repository client for %s: %v", repositoryName, err)
+		return nil
+	}
+
+	ts, err := repository.ListTag()
+	if err != nil {
+		log.Errorf("failed to get tags of repository %s from %s: %v", repositoryName, d.target.URL, err)
+		return nil
+	}
+
+	tags := []models.Tag{}
+	for _, t := range ts {
+		tags = append(tags, models.Tag{
+			Name: t,
+			Repository: models.Repository{
+				Name: repositoryName,
+			},
+		})
+	}
+	return tags
+}
+
+// GetTag is used to get the tag with the specified name of the repository under the namespace
+func (d *DockerRegistryAdaptor) GetTag(name string, repositoryName string, namespace string) models.Tag {
+	for _, tag := range d.GetTags(repositoryName, namespace) {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return models.Tag{}
+}
+
+func (d *DockerRegistryAdaptor) catalog() ([]string, error) {
+	repositories, err := d.registry.Catalog()
+	if err != nil {
+		log.Errorf("failed to get the catalog of %s: %v", d.target.URL, err)
+		return nil, err
+	}
+	return repositories, nil
+}
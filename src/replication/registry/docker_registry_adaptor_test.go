@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeDockerRegistry(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if usr, pwd, ok := r.BasicAuth(); !ok || usr != "admin" || pwd != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case "/v2/":
+		case "/v2/_catalog":
+			body = map[string][]string{
+				"repositories": {"library/hello-world", "library/busybox", "test/alpine"},
+			}
+		case "/v2/library/hello-world/tags/list":
+			body = map[string]interface{}{
+				"name": "library/hello-world",
+				"tags": []string{"latest", "1.0"},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestDockerRegistryAdaptor(t *testing.T, url string) *DockerRegistryAdaptor {
+	adaptor, err := NewDockerRegistryAdaptor(&common_models.RepTarget{
+		URL:      url,
+		Username: "admin",
+		Password: "password",
+	})
+	require.Nil(t, err)
+	return adaptor
+}
+
+func TestGetNamespacesOfDockerRegistryAdaptor(t *testing.T) {
+	server := newFakeDockerRegistry(t)
+	defer server.Close()
+	adaptor := newTestDockerRegistryAdaptor(t, server.URL)
+
+	namespaces := adaptor.GetNamespaces()
+	require.Equal(t, 2, len(namespaces))
+	assert.Equal(t, "library", namespaces[0].Name)
+	assert.Equal(t, "test", namespaces[1].Name)
+
+	assert.Equal(t, "test", adaptor.GetNamespace("test").Name)
+	assert.Equal(t, "", adaptor.GetNamespace("unknown").Name)
+}
+
+func TestGetRepositoriesOfDockerRegistryAdaptor(t *testing.T) {
+	server := newFakeDockerRegistry(t)
+	defer server.Close()
+	adaptor := newTestDockerRegistryAdaptor(t, server.URL)
+
+	repositories := adaptor.GetRepositories("library")
+	require.Equal(t, 2, len(repositories))
+	assert.Equal(t, "library/hello-world", repositories[0].Name)
+	assert.Equal(t, "library/busybox", repositories[1].Name)
+
+	assert.Equal(t, "test/alpine", adaptor.GetRepository("test/alpine", "test").Name)
+	assert.Equal(t, "", adaptor.GetRepository("test/unknown", "test").Name)
+}
+
+func TestGetTagsOfDockerRegistryAdaptor(t *testing.T) {
+	server := newFakeDockerRegistry(t)
+	defer server.Close()
+	adaptor := newTestDockerRegistryAdaptor(t, server.URL)
+
+	tags := adaptor.GetTags("library/hello-world", "library")
+	require.Equal(t, 2, len(tags))
+	assert.Equal(t, "latest", tags[0].Name)
+	assert.Equal(t, "1.0", tags[1].Name)
+
+	assert.Equal(t, "1.0", adaptor.GetTag("1.0", "library/hello-world", "library").Name)
+	assert.Equal(t, 0, len(adaptor.GetTags("library/unknown", "library")))
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication"
+)
+
+// Factory creates an adaptor for the specified target. If the target is nil,
+// the adaptor should work against the local Harbor instance
+type Factory func(target *common_models.RepTarget) (Adaptor, error)
+
+var (
+	factories = map[string]Factory{}
+	lock      = &sync.RWMutex{}
+)
+
+func init() {
+	RegisterFactory(replication.AdaptorKindHarbor, func(target *common_models.RepTarget) (Adaptor, error) {
+		if target == nil {
+			return &HarborAdaptor{}, nil
+		}
+		return NewRemoteHarborAdaptor(target)
+	})
+	RegisterFactory(replication.AdaptorKindDockerRegistry, func(target *common_models.RepTarget) (Adaptor, error) {
+		if target == nil {
+			return nil, fmt.Errorf("the target of %s adaptor can not be null", replication.AdaptorKindDockerRegistry)
+		}
+		return NewDockerRegistryAdaptor(target)
+	})
+}
+
+// RegisterFactory registers the adaptor factory with the specified kind,
+// the factory registered before with the same kind will be overridden
+func RegisterFactory(kind string, factory Factory) error {
+	if len(kind) == 0 {
+		return fmt.Errorf("empty adaptor kind")
+	}
+	if factory == nil {
+		return fmt.Errorf("empty factory for adaptor %s", kind)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	factories[kind] = factory
+	return nil
+}
+
+// GetFactory returns the adaptor factory with the specified kind
+func GetFactory(kind string) (Factory, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+	factory, exist := factories[kind]
+	if !exist {
+		return nil, fmt.Errorf("adaptor %s not supported", kind)
+	}
+	return factory, nil
+}
+
+// IsSupported checks whether the adaptor with the specified kind is registered
+func IsSupported(kind string) bool {
+	_, err := GetFactory(kind)
+	return err == nil
+}
+
+// ListKinds returns the kinds of all registered adaptors
+func ListKinds() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	kinds := []string{}
+	for kind := range factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewAdaptor creates the adaptor for the target according to its registry type,
+// the Harbor adaptor is used if the registry type is empty
+func NewAdaptor(target *common_models.RepTarget) (Adaptor, error) {
+	kind := replication.AdaptorKindHarbor
+	if target != nil && len(target.RegistryType) > 0 {
+		kind = target.RegistryType
+	}
+	factory, err := GetFactory(kind)
+	if err != nil {
+		return nil, err
+	}
+	return factory(target)
+}
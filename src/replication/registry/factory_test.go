@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"testing"
+
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFactory(t *testing.T) {
+	assert.NotNil(t, RegisterFactory("", nil))
+	assert.NotNil(t, RegisterFactory("test", nil))
+
+	factory := func(target *common_models.RepTarget) (Adaptor, error) {
+		return &HarborAdaptor{}, nil
+	}
+	require.Nil(t, RegisterFactory("test", factory))
+	defer func() {
+		lock.Lock()
+		defer lock.Unlock()
+		delete(factories, "test")
+	}()
+
+	assert.True(t, IsSupported("test"))
+	assert.Contains(t, ListKinds(), "test")
+}
+
+func TestGetFactory(t *testing.T) {
+	_, err := GetFactory("unknown")
+	assert.NotNil(t, err)
+
+	assert.True(t, IsSupported(replication.AdaptorKindHarbor))
+	assert.True(t, IsSupported(replication.AdaptorKindDockerRegistry))
+}
+
+func TestNewAdaptor(t *testing.T) {
+	// local Harbor
+	adaptor, err := NewAdaptor(nil)
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindHarbor, adaptor.Kind())
+
+	// remote Docker Registry
+	adaptor, err = NewAdaptor(&common_models.RepTarget{
+		URL:          "http://127.0.0.1",
+		RegistryType: replication.AdaptorKindDockerRegistry,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindDockerRegistry, adaptor.Kind())
+
+	// unsupported registry type
+	_, err = NewAdaptor(&common_models.RepTarget{
+		URL:          "http://127.0.0.1",
+		RegistryType: "unknown",
+	})
+	assert.NotNil(t, err)
+}
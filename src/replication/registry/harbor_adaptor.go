@@ -1,9 +1,16 @@
 package registry
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/chartserver"
 	"github.com/goharbor/harbor/src/common/dao"
 	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/utils"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
@@ -11,6 +18,11 @@ import (
 
 // TODO refacotor the methods of HarborAdaptor by caling Harbor's API
 
+var (
+	chartController *chartserver.Controller
+	chartLock       = &sync.Mutex{}
+)
+
 // HarborAdaptor is defined to adapt the Harbor registry
 type HarborAdaptor struct{}
 
@@ -81,3 +93,62 @@ func (ha *HarborAdaptor) GetTags(repositoryName string, namespace string) []mode
 func (ha *HarborAdaptor) GetTag(name string, repositoryName string, namespace string) models.Tag {
 	return models.Tag{}
 }
+
+// GetCharts is used to get all the chart versions under the specified namespace
+func (ha *HarborAdaptor) GetCharts(namespace string) []models.Chart {
+	controller, err := getChartController()
+	if err != nil {
+		log.Errorf("failed to create chart controller: %v", err)
+		return nil
+	}
+
+	infos, err := controller.ListCharts(namespace)
+	if err != nil {
+		log.Errorf("failed to get charts under namespace %s: %v", namespace, err)
+		return nil
+	}
+
+	charts := []models.Chart{}
+	for _, info := range infos {
+		versions, err := controller.GetChart(namespace, info.Name)
+		if err != nil {
+			log.Errorf("failed to get versions of chart %s/%s: %v", namespace, info.Name, err)
+			return nil
+		}
+		for _, version := range versions {
+			charts = append(charts, models.Chart{
+				Name:    info.Name,
+				Version: version.Version,
+				Namespace: models.Namespace{
+					Name: namespace,
+				},
+			})
+		}
+	}
+	return charts
+}
+
+// getChartController returns the controller talking to the chartmuseum of local Harbor,
+// it is created when the charts are listed for the first time
+func getChartController() (*chartserver.Controller, error) {
+	chartLock.Lock()
+	defer chartLock.Unlock()
+	if chartController != nil {
+		return chartController, nil
+	}
+
+	addr, err := config.GetChartMuseumEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(addr, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("malformed endpoint of chart storage server %s: %v", addr, err)
+	}
+	controller, err := chartserver.NewController(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	chartController = controller
+	return chartController, nil
+}
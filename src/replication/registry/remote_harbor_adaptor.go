@@ -0,0 +1,190 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goharbor/harbor/src/chartserver"
+	common_http "github.com/goharbor/harbor/src/common/http"
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	reg "github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+)
+
+const remoteHarborPageSize = 100
+
+// RemoteHarborAdaptor is defined to adapt the remote Harbor instances. The namespaces
+// and repositories are listed by Harbor's API as the catalog API of registry is only
+// available for system administrators, the tags are listed by the registry API
+type RemoteHarborAdaptor struct {
+	*DockerRegistryAdaptor
+	client *common_http.Client
+	url    string
+}
+
+// NewRemoteHarborAdaptor returns an instance of RemoteHarborAdaptor
+func NewRemoteHarborAdaptor(target *common_models.RepTarget) (*RemoteHarborAdaptor, error) {
+	adaptor, err := NewDockerRegistryAdaptor(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteHarborAdaptor{
+		DockerRegistryAdaptor: adaptor,
+		client: common_http.NewClient(&http.Client{
+			Transport: reg.GetHTTPTransport(target.Insecure),
+		}, auth.NewBasicAuthCredential(target.Username, target.Password)),
+		url: strings.TrimRight(target.URL, "/"),
+	}, nil
+}
+
+// Kind returns the unique kind identifier of the adaptor
+func (r *RemoteHarborAdaptor) Kind() string {
+	return replication.AdaptorKindHarbor
+}
+
+// GetNamespaces is used to get all the namespaces
+func (r *RemoteHarborAdaptor) GetNamespaces() []models.Namespace {
+	projects, err := r.getProjects("")
+	if err != nil {
+		log.Errorf("failed to get projects from %s: %v", r.url, err)
+		return nil
+	}
+
+	namespaces := []models.Namespace{}
+	for _, project := range projects {
+		namespaces = append(namespaces, models.Namespace{
+			Name: project.Name,
+		})
+	}
+	return namespaces
+}
+
+// GetNamespace is used to get the namespace with the specified name
+func (r *RemoteHarborAdaptor) GetNamespace(name string) models.Namespace {
+	project, err := r.getProject(name)
+	if err != nil {
+		log.Errorf("failed to get project %s from %s: %v", name, r.url, err)
+		return models.Namespace{}
+	}
+	if project == nil {
+		return models.Namespace{}
+	}
+	return models.Namespace{
+		Name: project.Name,
+	}
+}
+
+// GetRepositories is used to get all the repositories under the specified namespace
+func (r *RemoteHarborAdaptor) GetRepositories(namespace string) []models.Repository {
+	project, err := r.getProject(namespace)
+	if err != nil {
+		log.Errorf("failed to get project %s from %s: %v", namespace, r.url, err)
+		return nil
+	}
+	if project == nil {
+		log.Warningf("project %s not found on %s", namespace, r.url)
+		return nil
+	}
+
+	repositories := []models.Repository{}
+	for page := 1; ; page++ {
+		repos := []*common_models.RepoRecord{}
+		if err = r.client.Get(fmt.Sprintf("%s/api/repositories?project_id=%d&page=%d&page_size=%d",
+			r.url, project.ProjectID, page, remoteHarborPageSize), &repos); err != nil {
+			log.Errorf("failed to get repositories under project %s from %s: %v", namespace, r.url, err)
+			return nil
+		}
+		for _, repo := range repos {
+			repositories = append(repositories, models.Repository{
+				Name: repo.Name,
+				Namespace: models.Namespace{
+					Name: namespace,
+				},
+			})
+		}
+		if len(repos) < remoteHarborPageSize {
+			break
+		}
+	}
+	return repositories
+}
+
+// GetRepository is used to get the repository with the specified name under the specified namespace
+func (r *RemoteHarborAdaptor) GetRepository(name string, namespace string) models.Repository {
+	for _, repository := range r.GetRepositories(namespace) {
+		if repository.Name == name {
+			return repository
+		}
+	}
+	return models.Repository{}
+}
+
+// GetCharts is used to get all the chart versions under the specified namespace
+// by the chart repository API, nil is returned if the chart repository isn't enabled
+func (r *RemoteHarborAdaptor) GetCharts(namespace string) []models.Chart {
+	infos := []*chartserver.ChartInfo{}
+	if err := r.client.Get(fmt.Sprintf("%s/api/chartrepo/%s/charts", r.url, namespace), &infos); err != nil {
+		if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusNotFound {
+			log.Debugf("no charts found under namespace %s on %s", namespace, r.url)
+			return nil
+		}
+		log.Errorf("failed to get charts under namespace %s from %s: %v", namespace, r.url, err)
+		return nil
+	}
+
+	charts := []models.Chart{}
+	for _, info := range infos {
+		versions := chartserver.ChartVersions{}
+		if err := r.client.Get(fmt.Sprintf("%s/api/chartrepo/%s/charts/%s",
+			r.url, namespace, info.Name), &versions); err != nil {
+			log.Errorf("failed to get versions of chart %s/%s from %s: %v", namespace, info.Name, r.url, err)
+			return nil
+		}
+		for _, version := range versions {
+			charts = append(charts, models.Chart{
+				Name:    info.Name,
+				Version: version.Version,
+				Namespace: models.Namespace{
+					Name: namespace,
+				},
+			})
+		}
+	}
+	return charts
+}
+
+func (r *RemoteHarborAdaptor) getProjects(name string) ([]*common_models.Project, error) {
+	projects := []*common_models.Project{}
+	for page := 1; ; page++ {
+		pros := []*common_models.Project{}
+		if err := r.client.Get(fmt.Sprintf("%s/api/projects?name=%s&page=%d&page_size=%d",
+			r.url, url.QueryEscape(name), page, remoteHarborPageSize), &pros); err != nil {
+			return nil, err
+		}
+		projects = append(projects, pros...)
+		if len(pros) < remoteHarborPageSize {
+			break
+		}
+	}
+	return projects, nil
+}
+
+func (r *RemoteHarborAdaptor) getProject(name string) (*common_models.Project, error) {
+	// the name query of projects API is a fuzzy matching
+	projects, err := r.getProjects(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if project.Name == name {
+			return project, nil
+		}
+	}
+	return nil, nil
+}
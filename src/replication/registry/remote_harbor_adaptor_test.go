@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/chartserver"
+	common_models "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	helm_repo "k8s.io/helm/pkg/repo"
+)
+
+func newFakeHarbor(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, body interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		projects := []*common_models.Project{
+			{ProjectID: 1, Name: "library"},
+			{ProjectID: 2, Name: "library-test"},
+		}
+		write(w, projects)
+	})
+	mux.HandleFunc("/api/repositories", func(w http.ResponseWriter, r *http.Request) {
+		repositories := []*common_models.RepoRecord{}
+		if r.URL.Query().Get("project_id") == "1" {
+			repositories = append(repositories,
+				&common_models.RepoRecord{Name: "library/hello-world"},
+				&common_models.RepoRecord{Name: "library/busybox"})
+		}
+		write(w, repositories)
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts", func(w http.ResponseWriter, r *http.Request) {
+		write(w, []*chartserver.ChartInfo{{Name: "harbor"}})
+	})
+	mux.HandleFunc("/api/chartrepo/library/charts/harbor", func(w http.ResponseWriter, r *http.Request) {
+		versions := chartserver.ChartVersions{}
+		for _, version := range []string{"0.1.0", "0.2.0"} {
+			versions = append(versions, &chartserver.ChartVersion{
+				ChartVersion: helm_repo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "harbor",
+						Version: version,
+					},
+				},
+			})
+		}
+		write(w, versions)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRemoteHarborAdaptor(t *testing.T) {
+	server := newFakeHarbor(t)
+	defer server.Close()
+
+	adaptor, err := NewAdaptor(&common_models.RepTarget{
+		URL:          server.URL,
+		RegistryType: replication.AdaptorKindHarbor,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, replication.AdaptorKindHarbor, adaptor.Kind())
+
+	namespaces := adaptor.GetNamespaces()
+	assert.Equal(t, 2, len(namespaces))
+
+	assert.Equal(t, "library", adaptor.GetNamespace("library").Name)
+	assert.Equal(t, "", adaptor.GetNamespace("lib").Name)
+
+	repositories := adaptor.GetRepositories("library")
+	require.Equal(t, 2, len(repositories))
+	assert.Equal(t, "library/hello-world", repositories[0].Name)
+	assert.Equal(t, "library/busybox", repositories[1].Name)
+	assert.Equal(t, "library/busybox", adaptor.GetRepository("library/busybox", "library").Name)
+
+	assert.Equal(t, 0, len(adaptor.GetRepositories("library-test")))
+	assert.Equal(t, 0, len(adaptor.GetRepositories("unknown")))
+
+	chartAdaptor, ok := adaptor.(ChartAdaptor)
+	require.True(t, ok)
+	charts := chartAdaptor.GetCharts("library")
+	require.Equal(t, 2, len(charts))
+	assert.Equal(t, "harbor", charts[0].Name)
+	assert.Equal(t, "0.1.0", charts[0].Version)
+	assert.Equal(t, "0.2.0", charts[1].Version)
+	assert.Equal(t, "library", charts[1].Namespace.Name)
+
+	assert.Equal(t, 0, len(chartAdaptor.GetCharts("unknown")))
+}
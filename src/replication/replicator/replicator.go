@@ -24,6 +24,7 @@ import (
 	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/config"
+	rep "github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
 )
 
@@ -34,6 +35,7 @@ type Replication struct {
 	Candidates []models.FilterItem
 	Targets    []*common_models.RepTarget
 	Operation  string
+	Direction  string
 }
 
 // Replicator submits the replication work to the jobservice
@@ -56,6 +58,7 @@ func NewDefaultReplicator(client common_job.Client) *DefaultReplicator {
 // Replicate ...
 func (d *DefaultReplicator) Replicate(replication *Replication) error {
 	repositories := map[string][]string{}
+	charts := map[string][]string{}
 	// TODO the operation of all candidates are same for now. Update it after supporting
 	// replicate deletion
 	operation := ""
@@ -64,24 +67,16 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 		if len(strs) != 2 {
 			return fmt.Errorf("malforld image '%s'", candidate.Value)
 		}
-		repositories[strs[0]] = append(repositories[strs[0]], strs[1])
+		if candidate.Kind == rep.FilterItemKindChart {
+			charts[strs[0]] = append(charts[strs[0]], strs[1])
+		} else {
+			repositories[strs[0]] = append(repositories[strs[0]], strs[1])
+		}
 		operation = candidate.Operation
 	}
 
 	for _, target := range replication.Targets {
 		for repository, tags := range repositories {
-			// create job in database
-			id, err := dao.AddRepJob(common_models.RepJob{
-				PolicyID:   replication.PolicyID,
-				OpUUID:     replication.OpUUID,
-				Repository: repository,
-				TagList:    tags,
-				Operation:  operation,
-			})
-			if err != nil {
-				return err
-			}
-
 			// submit job to jobservice
 			log.Debugf("submiting replication job to jobservice, repository: %s, tags: %v, operation: %s, target: %s",
 				repository, tags, operation, target.URL)
@@ -89,11 +84,25 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 				Metadata: &job_models.JobMetadata{
 					JobKind: common_job.JobKindGeneric,
 				},
-				StatusHook: fmt.Sprintf("%s/service/notifications/jobs/replication/%d",
-					config.InternalCoreURL(), id),
 			}
 
-			if operation == common_models.RepOpTransfer {
+			if operation == common_models.RepOpTransfer && replication.Direction == rep.DirectionPull {
+				// pull the images from the target into the local registry
+				job.Name = common_job.ImageTransfer
+				job.Parameters = map[string]interface{}{
+					"repository":            repository,
+					"tags":                  tags,
+					"src_registry_url":      target.URL,
+					"src_registry_insecure": target.Insecure,
+					"src_registry_username": target.Username,
+					"src_registry_password": target.Password,
+					"src_registry_type":     registryType(target),
+					"dst_registry_url":      config.InternalCoreURL(),
+					"dst_registry_insecure": false,
+					"dst_token_service_url": config.InternalTokenServiceEndpoint(),
+					"dst_registry_type":     rep.AdaptorKindHarbor,
+				}
+			} else if operation == common_models.RepOpTransfer {
 				job.Name = common_job.ImageTransfer
 				job.Parameters = map[string]interface{}{
 					"repository":            repository,
@@ -105,6 +114,7 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 					"dst_registry_insecure": target.Insecure,
 					"dst_registry_username": target.Username,
 					"dst_registry_password": target.Password,
+					"dst_registry_type":     registryType(target),
 				}
 			} else {
 				job.Name = common_job.ImageDelete
@@ -115,22 +125,96 @@ func (d *DefaultReplicator) Replicate(replication *Replication) error {
 					"dst_registry_insecure": target.Insecure,
 					"dst_registry_username": target.Username,
 					"dst_registry_password": target.Password,
+					"dst_registry_type":     registryType(target),
 				}
 			}
 
-			uuid, err := d.client.SubmitJob(job)
-			if err != nil {
-				if er := dao.UpdateRepJobStatus(id, common_models.JobError); er != nil {
-					log.Errorf("failed to update the status of job %d: %s", id, er)
-				}
+			if err := d.submit(replication, repository, tags, operation, job); err != nil {
 				return err
 			}
+		}
+
+		// the charts can only be replicated between Harbor instances
+		if len(charts) > 0 && registryType(target) != rep.AdaptorKindHarbor {
+			log.Warningf("the type of target %s is %s which doesn't host charts, skip replicating charts",
+				target.Name, registryType(target))
+			continue
+		}
+		for chart, versions := range charts {
+			log.Debugf("submiting chart replication job to jobservice, chart: %s, versions: %v, target: %s",
+				chart, versions, target.URL)
+			job := &job_models.JobData{
+				Name: common_job.ChartTransfer,
+				Metadata: &job_models.JobMetadata{
+					JobKind: common_job.JobKindGeneric,
+				},
+			}
+			if replication.Direction == rep.DirectionPull {
+				// pull the charts from the target into the local chart repository
+				job.Parameters = map[string]interface{}{
+					"chart":                 chart,
+					"versions":              versions,
+					"src_registry_url":      target.URL,
+					"src_registry_insecure": target.Insecure,
+					"src_registry_username": target.Username,
+					"src_registry_password": target.Password,
+					"dst_registry_url":      config.InternalCoreURL(),
+					"dst_registry_insecure": false,
+				}
+			} else {
+				job.Parameters = map[string]interface{}{
+					"chart":                 chart,
+					"versions":              versions,
+					"src_registry_url":      config.InternalCoreURL(),
+					"src_registry_insecure": false,
+					"dst_registry_url":      target.URL,
+					"dst_registry_insecure": target.Insecure,
+					"dst_registry_username": target.Username,
+					"dst_registry_password": target.Password,
+				}
+			}
 
-			// create the mapping relationship between the jobs in database and jobservice
-			if err = dao.SetRepJobUUID(id, uuid); err != nil {
+			if err := d.submit(replication, chart, versions, operation, job); err != nil {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
+// submit creates the job in database and submits it to the jobservice
+func (d *DefaultReplicator) submit(replication *Replication, repository string,
+	tags []string, operation string, job *job_models.JobData) error {
+	// create job in database
+	id, err := dao.AddRepJob(common_models.RepJob{
+		PolicyID:   replication.PolicyID,
+		OpUUID:     replication.OpUUID,
+		Repository: repository,
+		TagList:    tags,
+		Operation:  operation,
+	})
+	if err != nil {
+		return err
+	}
+
+	job.StatusHook = fmt.Sprintf("%s/service/notifications/jobs/replication/%d",
+		config.InternalCoreURL(), id)
+	uuid, err := d.client.SubmitJob(job)
+	if err != nil {
+		if er := dao.UpdateRepJobStatus(id, common_models.JobError); er != nil {
+			log.Errorf("failed to update the status of job %d: %s", id, er)
+		}
+		return err
+	}
+
+	// create the mapping relationship between the jobs in database and jobservice
+	return dao.SetRepJobUUID(id, uuid)
+}
+
+// the targets created before the registry type was introduced are all Harbor instances
+func registryType(target *common_models.RepTarget) string {
+	if len(target.RegistryType) == 0 {
+		return rep.AdaptorKindHarbor
+	}
+	return target.RegistryType
+}
@@ -0,0 +1,66 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+)
+
+// ChartConverter implement Converter interface, convert projects to chart versions
+type ChartConverter struct {
+	registry registry.Adaptor
+}
+
+// NewChartConverter returns an instance of ChartConverter
+func NewChartConverter(registry registry.Adaptor) *ChartConverter {
+	return &ChartConverter{
+		registry: registry,
+	}
+}
+
+// Convert projects to chart versions, the value of converted items is
+// in the format of "project/chart:version"
+func (c *ChartConverter) Convert(items []models.FilterItem) []models.FilterItem {
+	result := []models.FilterItem{}
+	adaptor, ok := c.registry.(registry.ChartAdaptor)
+	if !ok {
+		log.Debugf("the %s registry doesn't host charts", c.registry.Kind())
+	}
+	for _, item := range items {
+		// just put it to the result list if the item is not a project
+		if item.Kind != replication.FilterItemKindProject {
+			result = append(result, item)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		charts := adaptor.GetCharts(item.Value)
+		for _, chart := range charts {
+			result = append(result, models.FilterItem{
+				Kind:      replication.FilterItemKindChart,
+				Value:     fmt.Sprintf("%s/%s:%s", item.Value, chart.Name, chart.Version),
+				Operation: item.Operation,
+			})
+		}
+	}
+	return result
+}
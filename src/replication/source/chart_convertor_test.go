@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartConvert(t *testing.T) {
+	items := []models.FilterItem{
+		{
+			Kind:  replication.FilterItemKindProject,
+			Value: "library",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/redis:1.0.0",
+		},
+	}
+	expected := []models.FilterItem{
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/harbor:0.1.0",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/harbor:0.2.0",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/redis:1.0.0",
+		},
+	}
+
+	converter := NewChartConverter(&fakeRegistryAdaptor{})
+	assert.EqualValues(t, expected, converter.Convert(items))
+
+	// the registry doesn't host charts
+	converter = NewChartConverter(&fakeImageRegistryAdaptor{&fakeRegistryAdaptor{}})
+	assert.EqualValues(t, expected[2:], converter.Convert(items))
+}
+
+func (f *fakeRegistryAdaptor) GetCharts(namespace string) []models.Chart {
+	return []models.Chart{
+		{
+			Name:    "harbor",
+			Version: "0.1.0",
+		},
+		{
+			Name:    "harbor",
+			Version: "0.2.0",
+		},
+	}
+}
+
+// fakeImageRegistryAdaptor only exposes the methods of registry.Adaptor
+type fakeImageRegistryAdaptor struct {
+	registry.Adaptor
+}
@@ -0,0 +1,105 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/goharbor/harbor/src/replication/registry"
+)
+
+// ChartFilter implement Filter interface to filter chart versions, the
+// repository pattern is matched with the chart name and the tag pattern
+// is matched with the chart version
+type ChartFilter struct {
+	namePattern    string
+	versionPattern string
+	converter      Converter
+}
+
+// NewChartFilter returns an instance of ChartFilter
+func NewChartFilter(namePattern, versionPattern string, registry registry.Adaptor) *ChartFilter {
+	return &ChartFilter{
+		namePattern:    namePattern,
+		versionPattern: versionPattern,
+		converter:      NewChartConverter(registry),
+	}
+}
+
+// Init ...
+func (c *ChartFilter) Init() error {
+	return nil
+}
+
+// GetConverter ...
+func (c *ChartFilter) GetConverter() Converter {
+	return c.converter
+}
+
+// DoFilter filters chart versions and drops any other resource types
+func (c *ChartFilter) DoFilter(items []models.FilterItem) []models.FilterItem {
+	candidates := []string{}
+	for _, item := range items {
+		candidates = append(candidates, item.Value)
+	}
+	log.Debugf("chart filter candidates: %v", candidates)
+
+	result := []models.FilterItem{}
+	for _, item := range items {
+		if item.Kind != replication.FilterItemKindChart {
+			log.Warningf("unsupported type %s for chart filter, drop", item.Kind)
+			continue
+		}
+
+		strs := strings.SplitN(item.Value, ":", 2)
+		if len(strs) != 2 {
+			log.Warningf("malformed chart %s, drop", item.Value)
+			continue
+		}
+		_, name := utils.ParseRepository(strs[0])
+		version := strs[1]
+
+		matched, err := match(c.namePattern, name)
+		if err != nil {
+			log.Errorf("failed to match pattern %s to value %s: %v, skip it", c.namePattern, name, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		matched, err = match(c.versionPattern, version)
+		if err != nil {
+			log.Errorf("failed to match pattern %s to value %s: %v, skip it", c.versionPattern, version, err)
+			continue
+		}
+		if matched {
+			log.Debugf("add %s to the chart filter result list", item.Value)
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// match reports whether the str matches the pattern, the empty pattern matches all
+func match(pattern, str string) (bool, error) {
+	if len(pattern) == 0 {
+		return true, nil
+	}
+	return utils.Match(pattern, str)
+}
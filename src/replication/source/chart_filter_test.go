@@ -0,0 +1,64 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartFilterInit(t *testing.T) {
+	assert.Nil(t, NewChartFilter("", "", &fakeRegistryAdaptor{}).Init())
+}
+
+func TestChartFilterGetConverter(t *testing.T) {
+	assert.NotNil(t, NewChartFilter("", "", &fakeRegistryAdaptor{}).GetConverter())
+}
+
+func TestChartFilterDoFilter(t *testing.T) {
+	items := []models.FilterItem{
+		{
+			Kind:  replication.FilterItemKindTag,
+			Value: "library/harbor:0.1.0",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/harbor:0.1.0",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/harbor:1.0.0",
+		},
+		{
+			Kind:  replication.FilterItemKindChart,
+			Value: "library/redis:1.0.0",
+		},
+	}
+
+	// empty patterns
+	result := NewChartFilter("", "", &fakeRegistryAdaptor{}).DoFilter(items)
+	assert.EqualValues(t, items[1:], result)
+
+	// name pattern
+	result = NewChartFilter("har*", "", &fakeRegistryAdaptor{}).DoFilter(items)
+	assert.EqualValues(t, items[1:3], result)
+
+	// name and version patterns
+	result = NewChartFilter("har*", "1.*", &fakeRegistryAdaptor{}).DoFilter(items)
+	assert.EqualValues(t, items[2:3], result)
+}
@@ -77,6 +77,8 @@ func hasLabel(resource models.FilterItem, labelID int64) (bool, error) {
 		rType = common.ResourceTypeRepository
 	case replication.FilterItemKindTag:
 		rType = common.ResourceTypeImage
+	case replication.FilterItemKindChart:
+		rType = common.ResourceTypeChart
 	default:
 		return false, fmt.Errorf("invalid resource type: %s", resource.Kind)
 	}
@@ -74,7 +74,7 @@ func (r *RepositoryFilter) DoFilter(items []models.FilterItem) []models.FilterIt
 		} else {
 			// trim the project
 			_, repository = utils.ParseRepository(repository)
-			matched, err := match(r.pattern, repository)
+			matched, err := utils.Match(r.pattern, repository)
 			if err != nil {
 				log.Errorf("failed to match pattern %s to value %s: %v, skip it", r.pattern, repository, err)
 				continue
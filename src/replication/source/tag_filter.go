@@ -17,6 +17,7 @@ package source
 import (
 	"strings"
 
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/models"
@@ -69,7 +70,7 @@ func (t *TagFilter) DoFilter(items []models.FilterItem) []models.FilterItem {
 		}
 
 		tag := strings.SplitN(item.Value, ":", 2)[1]
-		matched, err := match(t.pattern, tag)
+		matched, err := utils.Match(t.pattern, tag)
 		if err != nil {
 			log.Errorf("failed to match pattern %s to value %s: %v, skip it", t.pattern, tag, err)
 			continue
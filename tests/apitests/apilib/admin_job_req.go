@@ -24,9 +24,13 @@ package apilib
 
 // GCReq holds request information for admin job
 type GCReq struct {
-	Schedule *ScheduleParam `json:"schedule,omitempty"`
-	Status   string         `json:"status,omitempty"`
-	ID       int64          `json:"id,omitempty"`
+	Schedule       *ScheduleParam `json:"schedule,omitempty"`
+	Mode           string         `json:"mode,omitempty"`
+	DryRun         bool           `json:"dry_run,omitempty"`
+	DeleteUntagged bool           `json:"delete_untagged,omitempty"`
+	PurgeOrphans   bool           `json:"purge_orphans,omitempty"`
+	Status         string         `json:"status,omitempty"`
+	ID             int64          `json:"id,omitempty"`
 }
 
 // ScheduleParam ...
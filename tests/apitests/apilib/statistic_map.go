@@ -41,4 +41,13 @@ type StatisticMap struct {
 
 	// The count of the total repositories, only be seen when the user is admin.
 	TotalRepoCount int32 `json:"total_repo_count,omitempty"`
+
+	// The download count of the charts belonging to the public projects.
+	PublicChartDownloadCount int32 `json:"public_chart_download_count,omitempty"`
+
+	// The download count of the charts belonging to the private projects which the user is a member of.
+	PrivateChartDownloadCount int32 `json:"private_chart_download_count,omitempty"`
+
+	// The total download count of the charts, only be seen when the user is admin.
+	TotalChartDownloadCount int32 `json:"total_chart_download_count,omitempty"`
 }